@@ -83,7 +83,7 @@ def main():
     output_dir.mkdir(parents=True)
 
     # Create zips for each function
-    for function_name in ["router", "enricher", "strava-uploader", "mock-uploader"]:
+    for function_name in ["router", "enricher", "strava-uploader", "webhook-uploader", "intervals-icu-uploader", "hevy-uploader", "mock-uploader", "upload-status-poller", "strava-handler", "change-propagator"]:
         create_function_zip(function_name, src_dir, output_dir)
 
     print(f"All function zips created in {output_dir}")
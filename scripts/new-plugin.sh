@@ -381,9 +381,10 @@ create_destination() {
     local kebab_name=$(to_kebab_case "$name")
 
     local uploader_dir="$GO_FUNC_DIR/${kebab_name}-uploader"
+    local destination_dir="$GO_PKG_DIR/destinations/${snake_name}"
 
-    if [[ -d "$uploader_dir" ]]; then
-        echo -e "${RED}Error: Uploader already exists at $uploader_dir${NC}"
+    if [[ -d "$uploader_dir" || -d "$destination_dir" ]]; then
+        echo -e "${RED}Error: Destination already exists at $destination_dir or $uploader_dir${NC}"
         exit 1
     fi
 
@@ -393,82 +394,154 @@ create_destination() {
     # Find the Destination enum and add new value
     local last_dest_enum=$(grep -E "DESTINATION_[A-Z_]+ = [0-9]+" "$PROTO_DIR/events.proto" | \
                            grep -v MOCK | \
-                           sed 's/.*= \([0-9]*\).*/\1/' | \
+                           sed 's/.*DESTINATION_[A-Z_]* = \([0-9]*\).*/\1/' | \
                            sort -n | tail -1)
     local next_dest_enum=$((last_dest_enum + 1))
 
-    # Find DESTINATION_MOCK line and insert before it
+    # Find DESTINATION_MOCK line and insert before it (the router publishes to dest_topic)
     if grep -q "DESTINATION_MOCK" "$PROTO_DIR/events.proto"; then
-        sed -i "/DESTINATION_MOCK/i\\  DESTINATION_${upper_name} = ${next_dest_enum};" "$PROTO_DIR/events.proto"
+        sed -i "/DESTINATION_MOCK/i\\  DESTINATION_${upper_name} = ${next_dest_enum} [(dest_topic) = \"topic-job-upload-${kebab_name}\"];" "$PROTO_DIR/events.proto"
         echo -e "${GREEN}✓ Added DESTINATION_${upper_name} = ${next_dest_enum} to events.proto${NC}"
     else
         echo -e "${YELLOW}Note: Could not find DESTINATION_MOCK. Please add DESTINATION_${upper_name} manually.${NC}"
     fi
 
-    # Create directory
-    mkdir -p "$uploader_dir"
+    # Create Go destination (implements destinations.Destination)
+    mkdir -p "$destination_dir"
+    cat > "$destination_dir/${snake_name}.go" << EOF
+package ${snake_name}
+
+import (
+	"context"
+
+	"github.com/ripixel/fitglue-server/src/go/pkg/destinations"
+	"github.com/ripixel/fitglue-server/src/go/pkg/plugin"
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+)
+
+// ${pascal_name}Destination uploads activities to ${pascal_name}.
+type ${pascal_name}Destination struct{}
+
+func init() {
+	destinations.Register(&${pascal_name}Destination{})
+
+	plugin.RegisterDestination(pb.Destination_DESTINATION_${upper_name}, &pb.PluginManifest{
+		Id:                   "${snake_name}",
+		Type:                 pb.PluginType_PLUGIN_TYPE_DESTINATION,
+		Name:                 "${pascal_name}",
+		Description:          "Upload activities to ${pascal_name}",
+		Icon:                 "📤",
+		Enabled:              false, // Enable once implemented
+		RequiredIntegrations: []string{"${snake_name}"},
+	})
+}
+
+func (d *${pascal_name}Destination) Name() string {
+	return "${snake_name}"
+}
+
+func (d *${pascal_name}Destination) DestinationType() pb.Destination {
+	return pb.Destination_DESTINATION_${upper_name}
+}
+
+func (d *${pascal_name}Destination) Upload(ctx context.Context, req *destinations.Request) (*destinations.Result, error) {
+	// TODO: Implement upload to ${pascal_name}
+	// 1. Get user credentials (req.Service)
+	// 2. Convert req.Event / req.FitFile to ${pascal_name} format
+	// 3. Upload via ${pascal_name} API and return its activity ID
+	return nil, destinations.ErrNotSupported
+}
+
+func (d *${pascal_name}Destination) Update(ctx context.Context, req *destinations.Request) (*destinations.Result, error) {
+	return nil, destinations.ErrNotSupported
+}
+
+func (d *${pascal_name}Destination) Delete(ctx context.Context, req *destinations.Request) error {
+	return destinations.ErrNotSupported
+}
+
+func (d *${pascal_name}Destination) Status(ctx context.Context, req *destinations.Request) (*destinations.Result, error) {
+	return nil, destinations.ErrNotSupported
+}
+EOF
+
+    echo -e "${GREEN}✓ Created $destination_dir/${snake_name}.go${NC}"
 
-    # Create Go uploader function
+    # Create Go uploader function (the shared uploader framework does the rest)
+    mkdir -p "$uploader_dir"
     cat > "$uploader_dir/function.go" << EOF
 package ${snake_name}uploader
 
 import (
 	"context"
-	"encoding/json"
+	"fmt"
 	"log/slog"
-	"net/http"
+	"sync"
 
 	"github.com/GoogleCloudPlatform/functions-framework-go/functions"
-	shared "github.com/ripixel/fitglue-server/src/go/pkg"
-	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+	"github.com/cloudevents/sdk-go/v2/event"
+
+	"github.com/ripixel/fitglue-server/src/go/pkg/bootstrap"
+	"github.com/ripixel/fitglue-server/src/go/pkg/destinations"
+	"github.com/ripixel/fitglue-server/src/go/pkg/destinations/${snake_name}"
+	"github.com/ripixel/fitglue-server/src/go/pkg/framework"
+)
+
+var (
+	svc     *bootstrap.Service
+	svcOnce sync.Once
+	svcErr  error
 )
 
 func init() {
-	functions.HTTP("${pascal_name}Uploader", handler)
+	functions.CloudEvent("UploadTo${pascal_name}", UploadTo${pascal_name})
 }
 
-func handler(w http.ResponseWriter, r *http.Request) {
-	ctx := context.Background()
-	logger := slog.Default().With("function", "${snake_name}-uploader")
+// SetService overrides the service used by the entry points, bypassing bootstrap.NewService.
+func SetService(s *bootstrap.Service) {
+	svc = s
+}
 
-	// Decode the enriched activity from request body
-	var payload struct {
-		Activity *pb.StandardizedActivity \`json:"activity"\`
-		UserID   string                   \`json:"user_id"\`
+func initService(ctx context.Context) (*bootstrap.Service, error) {
+	if svc != nil {
+		return svc, nil
 	}
+	svcOnce.Do(func() {
+		baseSvc, err := bootstrap.NewService(ctx)
+		if err != nil {
+			slog.Error("Failed to initialize service", "error", err)
+			svcErr = err
+			return
+		}
+		svc = baseSvc
+	})
+	return svc, svcErr
+}
 
-	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
-		logger.Error("Failed to decode request", "error", err)
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
-		return
+// UploadTo${pascal_name} is the entry point
+func UploadTo${pascal_name}(ctx context.Context, e event.Event) error {
+	svc, err := initService(ctx)
+	if err != nil {
+		return fmt.Errorf("service init failed: %v", err)
 	}
+	return framework.WrapCloudEvent("${kebab_name}-uploader", svc, destinations.UploadHandler(&${snake_name}.${pascal_name}Destination{}))(ctx, e)
+}
+EOF
 
-	if payload.Activity == nil {
-		http.Error(w, "Missing activity", http.StatusBadRequest)
-		return
-	}
+    echo -e "${GREEN}✓ Created $uploader_dir/function.go${NC}"
 
-	logger.Info("Uploading activity to ${pascal_name}",
-		"activity_id", payload.Activity.SourceActivityId,
-		"user_id", payload.UserID,
-	)
+    # Add Terraform config for the upload topic and Go uploader
+    cat >> "$TERRAFORM_DIR/pubsub.tf" << EOF
 
-	// TODO: Implement upload to ${pascal_name}
-	// 1. Get user credentials from Firestore
-	// 2. Convert StandardizedActivity to ${pascal_name} format
-	// 3. Upload via ${pascal_name} API
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"success": true,
-		"message": "${pascal_name} upload not yet implemented",
-	})
+resource "google_pubsub_topic" "job_upload_${snake_name}" {
+  name    = "topic-job-upload-${kebab_name}"
+  project = var.project_id
+
+  # Enable message retention for replay (1 hour)
+  message_retention_duration = "3600s"
 }
 EOF
 
-    echo -e "${GREEN}✓ Created $uploader_dir/function.go${NC}"
-
-    # Add Terraform config for Go uploader
     cat >> "$TERRAFORM_DIR/functions.tf" << EOF
 
 # ${pascal_name} Uploader uses pre-built zip with correct structure
@@ -485,7 +558,7 @@ resource "google_cloudfunctions2_function" "${snake_name}_uploader" {
 
   build_config {
     runtime     = "go125"
-    entry_point = "${pascal_name}Uploader"
+    entry_point = "UploadTo${pascal_name}"
     source {
       storage_source {
         bucket = google_storage_bucket.source_bucket.name
@@ -501,6 +574,7 @@ resource "google_cloudfunctions2_function" "${snake_name}_uploader" {
     environment_variables = {
       GOOGLE_CLOUD_PROJECT = var.project_id
       GCS_ARTIFACT_BUCKET  = "\${var.project_id}-artifacts"
+      ENABLE_PUBLISH       = "true"
       LOG_LEVEL            = var.log_level
     }
     service_account_email = google_service_account.cloud_function_sa.email
@@ -509,12 +583,17 @@ resource "google_cloudfunctions2_function" "${snake_name}_uploader" {
   event_trigger {
     trigger_region = var.region
     event_type     = "google.cloud.pubsub.topic.v1.messagePublished"
-    pubsub_topic   = google_pubsub_topic.enriched_activity.id
+    pubsub_topic   = google_pubsub_topic.job_upload_${snake_name}.id
     retry_policy   = var.retry_policy
   }
 }
 EOF
-    echo -e "${GREEN}✓ Added Terraform config to functions.tf${NC}"
+    echo -e "${GREEN}✓ Added Terraform config to pubsub.tf and functions.tf${NC}"
+
+    # Register the destination with the upload-status-poller and change-propagator
+    local all_file="$GO_PKG_DIR/destinations/all/all.go"
+    sed -i "/pkg\/destinations\/strava\"$/a\\\t_ \"github.com/ripixel/fitglue-server/src/go/pkg/destinations/${snake_name}\"" "$all_file"
+    echo -e "${GREEN}✓ Registered destination in $all_file${NC}"
 
     # Run make generate
     echo ""
@@ -525,8 +604,8 @@ EOF
     echo -e "${GREEN}✓ Destination scaffolding complete!${NC}"
     echo ""
     echo -e "${YELLOW}Next steps:${NC}"
-    echo "  1. Implement the upload logic in $uploader_dir/function.go"
-    echo "  2. Add routing logic to router/function.go for DESTINATION_${upper_name}"
+    echo "  1. Implement Upload (and Update/Delete/Status if supported) in $destination_dir/${snake_name}.go"
+    echo "  2. Enable the manifest and add it to shared/src/plugin/registry.ts"
     echo "  3. Run 'make build' to verify"
 }
 
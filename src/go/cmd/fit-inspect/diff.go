@@ -0,0 +1,505 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/muktihari/fit/decoder"
+	"github.com/muktihari/fit/kit/datetime"
+	"github.com/muktihari/fit/kit/scaleoffset"
+	"github.com/muktihari/fit/kit/semicircles"
+	"github.com/muktihari/fit/profile"
+	"github.com/muktihari/fit/profile/basetype"
+	"github.com/muktihari/fit/profile/mesgdef"
+	"github.com/muktihari/fit/profile/typedef"
+	"github.com/muktihari/fit/proto"
+
+	"github.com/ripixel/fitglue-server/src/go/pkg/domain/file_generators"
+)
+
+// FitDiff is the result of comparing two FIT files (A = base, B = other)
+type FitDiff struct {
+	MessageCounts   []MessageCountDiff `json:"message_counts,omitempty"`
+	Summaries       []SummaryDiff      `json:"summaries,omitempty"`
+	Records         RecordDiff         `json:"records"`
+	DeveloperFields []DevFieldDiff     `json:"developer_fields,omitempty"`
+}
+
+// MessageCountDiff compares the number of messages of one type
+type MessageCountDiff struct {
+	Message string `json:"message"`
+	A       int    `json:"a"`
+	B       int    `json:"b"`
+}
+
+// SummaryDiff lists the changed fields of one session or lap message, paired by index
+type SummaryDiff struct {
+	Message string      `json:"message"`
+	Index   int         `json:"index"`
+	Fields  []FieldDiff `json:"fields"`
+}
+
+// FieldDiff is a single field value change. An empty side means the field is absent.
+type FieldDiff struct {
+	Field string `json:"field"`
+	A     string `json:"a"`
+	B     string `json:"b"`
+}
+
+// RecordDiff compares the record streams, matched by timestamp
+type RecordDiff struct {
+	A       int           `json:"a"`
+	B       int           `json:"b"`
+	Matched int           `json:"matched"`
+	OnlyA   int           `json:"only_a"`
+	OnlyB   int           `json:"only_b"`
+	Streams []StreamDelta `json:"streams,omitempty"`
+}
+
+// StreamDelta summarises the difference of one record field over matched records.
+// For GPS the delta is the distance between positions in meters.
+type StreamDelta struct {
+	Field     string  `json:"field"`
+	Matched   int     `json:"matched"`
+	MissingB  int     `json:"missing_b"` // Set in A but not in B
+	ExtraB    int     `json:"extra_b"`   // Set in B but not in A
+	MeanDelta float64 `json:"mean_delta"`
+	MaxDelta  float64 `json:"max_delta"` // Largest absolute delta
+
+	sum float64
+}
+
+// DevFieldDiff is a developer field that is missing, extra or changed in B
+type DevFieldDiff struct {
+	Message string `json:"message"`
+	Field   string `json:"field"`
+	Status  string `json:"status"` // "missing", "extra" or "changed"
+	A       string `json:"a,omitempty"`
+	B       string `json:"b,omitempty"`
+}
+
+// Empty reports whether the files have no differences
+func (d *FitDiff) Empty() bool {
+	if len(d.MessageCounts) > 0 || len(d.Summaries) > 0 || len(d.DeveloperFields) > 0 {
+		return false
+	}
+	if d.Records.OnlyA > 0 || d.Records.OnlyB > 0 {
+		return false
+	}
+	for _, s := range d.Records.Streams {
+		if s.MissingB > 0 || s.ExtraB > 0 || s.MaxDelta != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// DiffFitFiles compares two decoded FIT files
+func DiffFitFiles(a, b *proto.FIT) *FitDiff {
+	return &FitDiff{
+		MessageCounts:   diffMessageCounts(a, b),
+		Summaries:       append(diffSummaries(a, b, typedef.MesgNumSession), diffSummaries(a, b, typedef.MesgNumLap)...),
+		Records:         diffRecords(a, b),
+		DeveloperFields: diffDeveloperFields(a, b),
+	}
+}
+
+func diffMessageCounts(a, b *proto.FIT) []MessageCountDiff {
+	countA, countB := map[typedef.MesgNum]int{}, map[typedef.MesgNum]int{}
+	for _, msg := range a.Messages {
+		countA[msg.Num]++
+	}
+	for _, msg := range b.Messages {
+		countB[msg.Num]++
+	}
+
+	var nums []typedef.MesgNum
+	for num := range countA {
+		nums = append(nums, num)
+	}
+	for num := range countB {
+		if _, ok := countA[num]; !ok {
+			nums = append(nums, num)
+		}
+	}
+	sort.Slice(nums, func(i, j int) bool { return nums[i] < nums[j] })
+
+	var diffs []MessageCountDiff
+	for _, num := range nums {
+		if countA[num] != countB[num] {
+			diffs = append(diffs, MessageCountDiff{Message: num.String(), A: countA[num], B: countB[num]})
+		}
+	}
+	return diffs
+}
+
+func messagesOf(fit *proto.FIT, num typedef.MesgNum) []*proto.Message {
+	var msgs []*proto.Message
+	for i := range fit.Messages {
+		if fit.Messages[i].Num == num {
+			msgs = append(msgs, &fit.Messages[i])
+		}
+	}
+	return msgs
+}
+
+// fieldValues formats a message's fields (scaled, with units) by name
+func fieldValues(msg *proto.Message) map[string]string {
+	values := make(map[string]string, len(msg.Fields))
+	for _, field := range msg.Fields {
+		if field.Type == profile.DateTime {
+			values[field.Name] = datetime.ToTime(field.Value.Uint32()).Format(time.RFC3339)
+			continue
+		}
+		val := scaleoffset.ApplyAny(field.Value.Any(), field.Scale, field.Offset)
+		str := fmt.Sprint(val)
+		if field.Units != "" {
+			str += " " + field.Units
+		}
+		values[field.Name] = str
+	}
+	return values
+}
+
+func diffSummaries(a, b *proto.FIT, num typedef.MesgNum) []SummaryDiff {
+	msgsA, msgsB := messagesOf(a, num), messagesOf(b, num)
+	n := len(msgsA)
+	if len(msgsB) > n {
+		n = len(msgsB)
+	}
+
+	var diffs []SummaryDiff
+	for i := 0; i < n; i++ {
+		valuesA, valuesB := map[string]string{}, map[string]string{}
+		if i < len(msgsA) {
+			valuesA = fieldValues(msgsA[i])
+		}
+		if i < len(msgsB) {
+			valuesB = fieldValues(msgsB[i])
+		}
+
+		names := make(map[string]bool, len(valuesA)+len(valuesB))
+		for name := range valuesA {
+			names[name] = true
+		}
+		for name := range valuesB {
+			names[name] = true
+		}
+		sorted := make([]string, 0, len(names))
+		for name := range names {
+			sorted = append(sorted, name)
+		}
+		sort.Strings(sorted)
+
+		var fields []FieldDiff
+		for _, name := range sorted {
+			if valuesA[name] != valuesB[name] {
+				fields = append(fields, FieldDiff{Field: name, A: valuesA[name], B: valuesB[name]})
+			}
+		}
+		if len(fields) > 0 {
+			diffs = append(diffs, SummaryDiff{Message: num.String(), Index: i, Fields: fields})
+		}
+	}
+	return diffs
+}
+
+// recordStreams are the record fields compared by diffRecords, in output order
+var recordStreams = []struct {
+	name  string
+	value func(r *mesgdef.Record) (float64, bool)
+}{
+	{"heart_rate", func(r *mesgdef.Record) (float64, bool) {
+		return float64(r.HeartRate), r.HeartRate != basetype.Uint8Invalid
+	}},
+	{"power", func(r *mesgdef.Record) (float64, bool) {
+		return float64(r.Power), r.Power != basetype.Uint16Invalid
+	}},
+	{"cadence", func(r *mesgdef.Record) (float64, bool) {
+		return float64(r.Cadence), r.Cadence != basetype.Uint8Invalid
+	}},
+	{"speed", func(r *mesgdef.Record) (float64, bool) {
+		v := r.EnhancedSpeedScaled()
+		if math.IsNaN(v) {
+			v = r.SpeedScaled()
+		}
+		return v, !math.IsNaN(v)
+	}},
+	{"altitude", func(r *mesgdef.Record) (float64, bool) {
+		v := r.EnhancedAltitudeScaled()
+		if math.IsNaN(v) {
+			v = r.AltitudeScaled()
+		}
+		return v, !math.IsNaN(v)
+	}},
+}
+
+func recordsByTime(fit *proto.FIT) (map[int64]*mesgdef.Record, int) {
+	records := make(map[int64]*mesgdef.Record)
+	count := 0
+	for _, msg := range messagesOf(fit, typedef.MesgNumRecord) {
+		rec := mesgdef.NewRecord(msg)
+		count++
+		if rec.Timestamp.IsZero() {
+			continue
+		}
+		key := rec.Timestamp.Unix()
+		if _, exists := records[key]; !exists {
+			records[key] = rec
+		}
+	}
+	return records, count
+}
+
+func hasPosition(r *mesgdef.Record) bool {
+	return r.PositionLat != basetype.Sint32Invalid && r.PositionLong != basetype.Sint32Invalid
+}
+
+func diffRecords(a, b *proto.FIT) RecordDiff {
+	recsA, countA := recordsByTime(a)
+	recsB, countB := recordsByTime(b)
+	diff := RecordDiff{A: countA, B: countB}
+
+	var matched [][2]*mesgdef.Record
+	for ts, recA := range recsA {
+		if recB, ok := recsB[ts]; ok {
+			matched = append(matched, [2]*mesgdef.Record{recA, recB})
+		} else {
+			diff.OnlyA++
+		}
+	}
+	for ts := range recsB {
+		if _, ok := recsA[ts]; !ok {
+			diff.OnlyB++
+		}
+	}
+	diff.Matched = len(matched)
+
+	for _, stream := range recordStreams {
+		delta := StreamDelta{Field: stream.name}
+		for _, pair := range matched {
+			va, okA := stream.value(pair[0])
+			vb, okB := stream.value(pair[1])
+			delta.add(okA, okB, vb-va)
+		}
+		delta.finish()
+		if delta.Matched > 0 || delta.MissingB > 0 || delta.ExtraB > 0 {
+			diff.Streams = append(diff.Streams, delta)
+		}
+	}
+
+	gps := StreamDelta{Field: "position"}
+	for _, pair := range matched {
+		okA, okB := hasPosition(pair[0]), hasPosition(pair[1])
+		var dist float64
+		if okA && okB {
+			dist = haversineMeters(pair[0], pair[1])
+		}
+		gps.add(okA, okB, dist)
+	}
+	gps.finish()
+	if gps.Matched > 0 || gps.MissingB > 0 || gps.ExtraB > 0 {
+		diff.Streams = append(diff.Streams, gps)
+	}
+
+	return diff
+}
+
+// add accounts for one matched record; delta is only used when both sides have a value
+func (s *StreamDelta) add(okA, okB bool, delta float64) {
+	switch {
+	case okA && okB:
+		s.Matched++
+		s.sum += delta
+		if math.Abs(delta) > s.MaxDelta {
+			s.MaxDelta = math.Abs(delta)
+		}
+	case okA:
+		s.MissingB++
+	case okB:
+		s.ExtraB++
+	}
+}
+
+func (s *StreamDelta) finish() {
+	if s.Matched > 0 {
+		s.MeanDelta = s.sum / float64(s.Matched)
+	}
+}
+
+// haversineMeters returns the great-circle distance between two record positions
+func haversineMeters(a, b *mesgdef.Record) float64 {
+	const earthRadius = 6371000.0
+	toRad := func(semi int32) float64 { return semicircles.ToDegrees(semi) * math.Pi / 180 }
+	lat1, lon1 := toRad(a.PositionLat), toRad(a.PositionLong)
+	lat2, lon2 := toRad(b.PositionLat), toRad(b.PositionLong)
+	h := math.Pow(math.Sin((lat2-lat1)/2), 2) + math.Cos(lat1)*math.Cos(lat2)*math.Pow(math.Sin((lon2-lon1)/2), 2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(h))
+}
+
+func diffDeveloperFields(a, b *proto.FIT) []DevFieldDiff {
+	type key struct{ message, name string }
+	index := func(fit *proto.FIT) (map[key]string, []key) {
+		values := map[key]string{}
+		var order []key
+		for _, f := range file_generators.DecodeDeveloperFields(fit) {
+			k := key{f.Message, f.Name}
+			if _, exists := values[k]; !exists {
+				order = append(order, k)
+			}
+			values[k] = fmt.Sprint(f.Value)
+		}
+		return values, order
+	}
+	valuesA, orderA := index(a)
+	valuesB, orderB := index(b)
+
+	var diffs []DevFieldDiff
+	for _, k := range orderA {
+		vb, ok := valuesB[k]
+		switch {
+		case !ok:
+			diffs = append(diffs, DevFieldDiff{Message: k.message, Field: k.name, Status: "missing", A: valuesA[k]})
+		case vb != valuesA[k]:
+			diffs = append(diffs, DevFieldDiff{Message: k.message, Field: k.name, Status: "changed", A: valuesA[k], B: vb})
+		}
+	}
+	for _, k := range orderB {
+		if _, ok := valuesA[k]; !ok {
+			diffs = append(diffs, DevFieldDiff{Message: k.message, Field: k.name, Status: "extra", B: valuesB[k]})
+		}
+	}
+	return diffs
+}
+
+// WriteText prints the diff as human-readable tables
+func (d *FitDiff) WriteText(out io.Writer) {
+	if d.Empty() {
+		fmt.Fprintln(out, "No differences found.")
+		return
+	}
+
+	if len(d.MessageCounts) > 0 {
+		fmt.Fprintln(out, "Message Counts:")
+		w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "Message\tA\tB")
+		fmt.Fprintln(w, "-------\t-\t-")
+		for _, c := range d.MessageCounts {
+			fmt.Fprintf(w, "%s\t%d\t%d\n", c.Message, c.A, c.B)
+		}
+		w.Flush()
+		fmt.Fprintln(out)
+	}
+
+	for _, s := range d.Summaries {
+		fmt.Fprintf(out, "%s #%d:\n", s.Message, s.Index)
+		w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "Field\tA\tB")
+		fmt.Fprintln(w, "-----\t-\t-")
+		for _, f := range s.Fields {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", f.Field, orDash(f.A), orDash(f.B))
+		}
+		w.Flush()
+		fmt.Fprintln(out)
+	}
+
+	r := d.Records
+	fmt.Fprintf(out, "Records: A=%d B=%d matched=%d only-in-A=%d only-in-B=%d\n", r.A, r.B, r.Matched, r.OnlyA, r.OnlyB)
+	if len(r.Streams) > 0 {
+		w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "Field\tMatched\tMissing in B\tExtra in B\tMean Delta\tMax |Delta|")
+		fmt.Fprintln(w, "-----\t-------\t------------\t----------\t----------\t-----------")
+		for _, s := range r.Streams {
+			unit := ""
+			if s.Field == "position" {
+				unit = " m"
+			}
+			fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%.2f%s\t%.2f%s\n", s.Field, s.Matched, s.MissingB, s.ExtraB, s.MeanDelta, unit, s.MaxDelta, unit)
+		}
+		w.Flush()
+	}
+
+	if len(d.DeveloperFields) > 0 {
+		fmt.Fprintln(out, "\nDeveloper Fields:")
+		w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "Message\tField\tStatus\tA\tB")
+		fmt.Fprintln(w, "-------\t-----\t------\t-\t-")
+		for _, f := range d.DeveloperFields {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", f.Message, f.Field, f.Status, orDash(f.A), orDash(f.B))
+		}
+		w.Flush()
+	}
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func decodeFitFile(path string) (*proto.FIT, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	fit, err := decoder.New(bytes.NewReader(data)).Decode()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return fit, nil
+}
+
+// runDiff implements `fit-inspect diff [-json] <a.fit> <b.fit>`.
+// Exits 0 when the files match, 1 when they differ and 2 on error.
+func runDiff(args []string) int {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "Output the diff as JSON")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: fit-inspect diff [-json] <a.fit> <b.fit>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 2
+	}
+
+	a, err := decodeFitFile(fs.Arg(0))
+	if err != nil {
+		fmt.Println(err)
+		return 2
+	}
+	b, err := decodeFitFile(fs.Arg(1))
+	if err != nil {
+		fmt.Println(err)
+		return 2
+	}
+
+	diff := DiffFitFiles(a, b)
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(diff); err != nil {
+			fmt.Printf("Failed to encode diff: %v\n", err)
+			return 2
+		}
+	} else {
+		fmt.Printf("A: %s\nB: %s\n\n", fs.Arg(0), fs.Arg(1))
+		diff.WriteText(os.Stdout)
+	}
+
+	if diff.Empty() {
+		return 0
+	}
+	return 1
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/muktihari/fit/decoder"
+	"github.com/muktihari/fit/proto"
+	"github.com/ripixel/fitglue-server/src/go/pkg/domain/file_generators"
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func diffTestFit(t *testing.T, hr int32, extraRecords int, opts ...file_generators.Option) *proto.FIT {
+	t.Helper()
+	start := time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)
+	var records []*pb.Record
+	for i := 0; i < 60+extraRecords; i++ {
+		records = append(records, &pb.Record{Timestamp: timestamppb.New(start.Add(time.Duration(i) * time.Second)), HeartRate: hr})
+	}
+	activity := &pb.StandardizedActivity{
+		StartTime: timestamppb.New(start),
+		Type:      pb.ActivityType_ACTIVITY_TYPE_RUN,
+		Sessions: []*pb.Session{{
+			StartTime:        timestamppb.New(start),
+			TotalElapsedTime: 120,
+			Laps:             []*pb.Lap{{Records: records}},
+		}},
+	}
+	data, err := file_generators.GenerateFitFile(activity, opts...)
+	if err != nil {
+		t.Fatalf("GenerateFitFile failed: %v", err)
+	}
+	fit, err := decoder.New(bytes.NewReader(data)).Decode()
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	return fit
+}
+
+func TestDiffFitFiles(t *testing.T) {
+	t.Run("Identical files", func(t *testing.T) {
+		diff := DiffFitFiles(diffTestFit(t, 140, 0), diffTestFit(t, 140, 0))
+		if !diff.Empty() {
+			t.Errorf("Expected no differences, got %+v", diff)
+		}
+	})
+
+	t.Run("Differing files", func(t *testing.T) {
+		a := diffTestFit(t, 140, 0, file_generators.WithDeveloperFields(map[string]interface{}{file_generators.DevFieldPipelineID: "p1"}))
+		b := diffTestFit(t, 150, 10)
+		diff := DiffFitFiles(a, b)
+
+		if diff.Empty() {
+			t.Fatal("Expected differences")
+		}
+
+		var recordCount *MessageCountDiff
+		for i := range diff.MessageCounts {
+			if diff.MessageCounts[i].Message == "record" {
+				recordCount = &diff.MessageCounts[i]
+			}
+		}
+		if recordCount == nil || recordCount.A != 60 || recordCount.B != 70 {
+			t.Errorf("Expected Record count 60 vs 70, got %+v", diff.MessageCounts)
+		}
+
+		if diff.Records.Matched != 60 || diff.Records.OnlyB != 10 {
+			t.Errorf("Expected 60 matched and 10 extra records, got %+v", diff.Records)
+		}
+		var hr *StreamDelta
+		for i := range diff.Records.Streams {
+			if diff.Records.Streams[i].Field == "heart_rate" {
+				hr = &diff.Records.Streams[i]
+			}
+		}
+		if hr == nil || hr.MeanDelta != 10 || hr.MaxDelta != 10 {
+			t.Errorf("Expected heart rate delta of 10, got %+v", hr)
+		}
+
+		if len(diff.DeveloperFields) == 0 {
+			t.Fatal("Expected developer field differences")
+		}
+		for _, f := range diff.DeveloperFields {
+			if f.Status != "missing" {
+				t.Errorf("Expected developer field %s to be missing in B, got %s", f.Field, f.Status)
+			}
+		}
+	})
+}
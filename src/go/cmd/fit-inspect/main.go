@@ -11,6 +11,8 @@ import (
 
 	"github.com/muktihari/fit/decoder"
 	"github.com/muktihari/fit/profile/typedef"
+
+	"github.com/ripixel/fitglue-server/src/go/pkg/domain/file_generators"
 )
 
 type FieldStats struct {
@@ -92,6 +94,15 @@ func (fs *FieldStats) Avg() float64 {
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "diff":
+			os.Exit(runDiff(os.Args[2:]))
+		case "export":
+			os.Exit(runExport(os.Args[2:]))
+		}
+	}
+
 	inputPath := flag.String("input", "", "Path to FIT file")
 	verbose := flag.Bool("detailed-dump", false, "Print detailed record info")
 	flag.Parse()
@@ -160,4 +171,16 @@ func main() {
 		}
 	}
 	w.Flush()
+
+	devFields := file_generators.DecodeDeveloperFields(fitData)
+	if len(devFields) > 0 {
+		fmt.Println("\nDeveloper Fields:")
+		w = tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "Message\tField\tValue\tUnits")
+		fmt.Fprintln(w, "-------\t-----\t-----\t-----")
+		for _, f := range devFields {
+			fmt.Fprintf(w, "%s\t%s\t%v\t%s\n", f.Message, f.Name, f.Value, f.Units)
+		}
+		w.Flush()
+	}
 }
@@ -0,0 +1,173 @@
+// fitglue-local runs the Go Cloud Functions hosted by package emulator (the enricher, router,
+// every destination uploader, the upload-status-poller and the change-propagator) in a single
+// process with an in-memory Pub/Sub bus, in-memory Firestore, filesystem GCS and a stub
+// Strava API. A small HTTP API seeds users, publishes events and inspects results.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/ripixel/fitglue-server/src/go/pkg/emulator"
+	infrapubsub "github.com/ripixel/fitglue-server/src/go/pkg/infrastructure/pubsub"
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+)
+
+func main() {
+	port := flag.String("port", "8090", "Port for the control API")
+	dataDir := flag.String("data", "fitglue-local-data", "Directory used as the local blob store")
+	bucket := flag.String("bucket", emulator.DefaultBucket, "Artifact bucket name")
+	usersFlag := flag.String("users", "", "Comma-separated UserRecord JSON files to seed")
+	lagDelay := flag.Duration("lag-delay", 30*time.Second, "Delivery delay for the enrichment lag topic")
+	statusCheckDelay := flag.Duration("status-check-delay", 5*time.Second, "Delivery delay for upload status checks")
+	flag.Parse()
+
+	if envPort := os.Getenv("PORT"); envPort != "" {
+		*port = envPort
+	}
+
+	em, err := emulator.New(emulator.Options{
+		DataDir:          *dataDir,
+		Bucket:           *bucket,
+		LagDelay:         *lagDelay,
+		StatusCheckDelay: *statusCheckDelay,
+	})
+	if err != nil {
+		log.Fatalf("Failed to start emulator: %v", err)
+	}
+	defer em.Close()
+
+	if *usersFlag != "" {
+		for _, path := range strings.Split(*usersFlag, ",") {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				log.Fatalf("Failed to read user file: %v", err)
+			}
+			if _, err := seedUser(em, data); err != nil {
+				log.Fatalf("Failed to seed user from %s: %v", path, err)
+			}
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /users", func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		userID, err := seedUser(em, data)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, map[string]string{"user_id": userID})
+	})
+	mux.HandleFunc("POST /activities", func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		var payload pb.ActivityPayload
+		if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, &payload); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		msgID, err := em.PublishActivity(r.Context(), &payload)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, map[string]string{"message_id": msgID})
+	})
+	mux.HandleFunc("POST /topics/{topic}", func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		eventType := r.Header.Get("Ce-Type")
+		if eventType == "" {
+			eventType = "com.fitglue.local.publish"
+		}
+		e, err := infrapubsub.NewCloudEvent("/local", eventType, json.RawMessage(data))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		e.SetID(fmt.Sprintf("local-%d", time.Now().UnixNano()))
+		e.SetTime(time.Now())
+		msgID, err := em.Bus.PublishCloudEvent(r.Context(), r.PathValue("topic"), e)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, map[string]string{"message_id": msgID})
+	})
+	mux.HandleFunc("POST /wait", func(w http.ResponseWriter, r *http.Request) {
+		em.Wait()
+		writeJSON(w, map[string]string{"status": "idle"})
+	})
+	mux.HandleFunc("GET /messages", func(w http.ResponseWriter, r *http.Request) {
+		type message struct {
+			ID    string          `json:"id"`
+			Topic string          `json:"topic"`
+			Type  string          `json:"type"`
+			Data  json.RawMessage `json:"data"`
+		}
+		out := []message{}
+		for _, m := range em.Bus.Messages() {
+			out = append(out, message{ID: m.ID, Topic: m.Topic, Type: m.Event.Type(), Data: eventData(m.Event)})
+		}
+		writeJSON(w, out)
+	})
+	mux.HandleFunc("GET /strava/uploads", func(w http.ResponseWriter, r *http.Request) {
+		type upload struct {
+			ID          int64  `json:"id"`
+			ActivityID  int64  `json:"activity_id"`
+			Name        string `json:"name"`
+			Description string `json:"description"`
+			SportType   string `json:"sport_type"`
+			FitBytes    int    `json:"fit_bytes"`
+		}
+		out := []upload{}
+		for _, u := range em.Strava.Uploads() {
+			out = append(out, upload{ID: u.ID, ActivityID: u.ActivityID, Name: u.Name, Description: u.Description, SportType: u.SportType, FitBytes: len(u.FitData)})
+		}
+		writeJSON(w, out)
+	})
+
+	slog.Info("fitglue-local listening", "port", *port, "data_dir", *dataDir, "strava_stub", em.Strava.URL())
+	if err := http.ListenAndServe(":"+*port, mux); err != nil {
+		log.Fatalf("ListenAndServe: %v", err)
+	}
+}
+
+func seedUser(em *emulator.Emulator, data []byte) (string, error) {
+	var user pb.UserRecord
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, &user); err != nil {
+		return "", err
+	}
+	if user.UserId == "" {
+		return "", fmt.Errorf("user_id is required")
+	}
+	if err := em.DB.SetUser(context.Background(), &user); err != nil {
+		return "", err
+	}
+	slog.Info("Seeded user", "user_id", user.UserId, "pipelines", len(user.Pipelines))
+	return user.UserId, nil
+}
+
+func eventData(e event.Event) json.RawMessage {
+	if json.Valid(e.Data()) {
+		return e.Data()
+	}
+	b, _ := json.Marshal(string(e.Data()))
+	return b
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Fixture is a recorded HTTP response served in place of a real API call.
+// URL matches exactly, or as a prefix when it ends in "*". An empty Method matches any method.
+type Fixture struct {
+	Method   string            `json:"method,omitempty"`
+	URL      string            `json:"url"`
+	Status   int               `json:"status,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	Body     json.RawMessage   `json:"body,omitempty"`      // JSON value, or a JSON string served verbatim
+	BodyFile string            `json:"body_file,omitempty"` // Relative to the fixtures file
+}
+
+// FixtureTransport is an http.RoundTripper that answers requests from recorded fixtures.
+// Unmatched requests fail unless Fallback is set.
+type FixtureTransport struct {
+	Fixtures []Fixture
+	Fallback http.RoundTripper
+}
+
+// LoadFixtures reads a JSON array of fixtures, resolving body_file paths against the file's directory
+func LoadFixtures(path string) ([]Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixtures: %w", err)
+	}
+	var fixtures []Fixture
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		return nil, fmt.Errorf("failed to parse fixtures: %w", err)
+	}
+	for i := range fixtures {
+		if fixtures[i].BodyFile == "" {
+			continue
+		}
+		body, err := os.ReadFile(filepath.Join(filepath.Dir(path), fixtures[i].BodyFile))
+		if err != nil {
+			return nil, fmt.Errorf("fixture %s: %w", fixtures[i].URL, err)
+		}
+		fixtures[i].Body = body
+	}
+	return fixtures, nil
+}
+
+func (f *Fixture) matches(req *http.Request) bool {
+	if f.Method != "" && !strings.EqualFold(f.Method, req.Method) {
+		return false
+	}
+	url := req.URL.String()
+	if prefix, ok := strings.CutSuffix(f.URL, "*"); ok {
+		return strings.HasPrefix(url, prefix)
+	}
+	return url == f.URL
+}
+
+func (f *Fixture) body() []byte {
+	var s string
+	if err := json.Unmarshal(f.Body, &s); err == nil {
+		return []byte(s)
+	}
+	return f.Body
+}
+
+func (t *FixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for i := range t.Fixtures {
+		f := &t.Fixtures[i]
+		if !f.matches(req) {
+			continue
+		}
+		slog.Debug("Serving HTTP fixture", "method", req.Method, "url", req.URL.String())
+
+		status := f.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		header := make(http.Header)
+		for k, v := range f.Headers {
+			header.Set(k, v)
+		}
+		if header.Get("Content-Type") == "" {
+			header.Set("Content-Type", "application/json")
+		}
+		body := f.body()
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+			StatusCode:    status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+
+	if t.Fallback != nil {
+		return t.Fallback.RoundTrip(req)
+	}
+	return nil, fmt.Errorf("no fixture for %s %s (use -allow-network to pass through)", req.Method, req.URL.String())
+}
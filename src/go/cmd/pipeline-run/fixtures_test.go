@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFixtureTransport(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "hr.json"), []byte(`{"points":3}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fixtures := []Fixture{
+		{Method: "GET", URL: "https://api.example.com/heart/*", BodyFile: "hr.json"},
+		{Method: "POST", URL: "https://api.example.com/token", Status: 401, Body: json.RawMessage(`"unauthorized"`), Headers: map[string]string{"Content-Type": "text/plain"}},
+	}
+	data, _ := json.Marshal(fixtures)
+	path := filepath.Join(dir, "fixtures.json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadFixtures(path)
+	if err != nil {
+		t.Fatalf("LoadFixtures failed: %v", err)
+	}
+	client := &http.Client{Transport: &FixtureTransport{Fixtures: loaded}}
+
+	t.Run("Prefix match serves body file", func(t *testing.T) {
+		resp, err := client.Get("https://api.example.com/heart/2026-01-10.json")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != 200 || string(body) != `{"points":3}` || resp.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Unexpected response: %d %q %v", resp.StatusCode, body, resp.Header)
+		}
+	})
+
+	t.Run("Exact match with string body", func(t *testing.T) {
+		resp, err := client.Post("https://api.example.com/token", "application/x-www-form-urlencoded", nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != 401 || string(body) != "unauthorized" || resp.Header.Get("Content-Type") != "text/plain" {
+			t.Errorf("Unexpected response: %d %q %v", resp.StatusCode, body, resp.Header)
+		}
+	})
+
+	t.Run("Method mismatch and unknown URL fail", func(t *testing.T) {
+		if _, err := client.Get("https://api.example.com/token"); err == nil {
+			t.Error("Expected error for GET on POST-only fixture")
+		}
+		if _, err := client.Get("https://api.example.com/other"); err == nil {
+			t.Error("Expected error for unmatched URL")
+		}
+	})
+}
@@ -0,0 +1,184 @@
+// pipeline-run executes the enricher pipeline for a single activity on the local machine.
+// It loads a UserRecord and an ActivityPayload from JSON files and runs Orchestrator.Process
+// against in-memory Database/Publisher implementations and a filesystem BlobStore.
+// HTTP calls made by providers (e.g. Fitbit) can be answered from recorded fixtures.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"sort"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ripixel/fitglue-server/src/go/functions/enricher"
+	"github.com/ripixel/fitglue-server/src/go/pkg/bootstrap"
+	providers "github.com/ripixel/fitglue-server/src/go/pkg/enricher_providers"
+	"github.com/ripixel/fitglue-server/src/go/pkg/infrastructure/database"
+	infrapubsub "github.com/ripixel/fitglue-server/src/go/pkg/infrastructure/pubsub"
+	"github.com/ripixel/fitglue-server/src/go/pkg/infrastructure/secrets"
+	infrastorage "github.com/ripixel/fitglue-server/src/go/pkg/infrastructure/storage"
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+
+	// Register providers (same set as the enricher function)
+	_ "github.com/ripixel/fitglue-server/src/go/pkg/enricher_providers/activity_filter"
+	_ "github.com/ripixel/fitglue-server/src/go/pkg/enricher_providers/auto_increment"
+	_ "github.com/ripixel/fitglue-server/src/go/pkg/enricher_providers/condition_matcher"
+	_ "github.com/ripixel/fitglue-server/src/go/pkg/enricher_providers/mock"
+	_ "github.com/ripixel/fitglue-server/src/go/pkg/enricher_providers/parkrun"
+	_ "github.com/ripixel/fitglue-server/src/go/pkg/enricher_providers/user_input"
+)
+
+func main() {
+	userFile := flag.String("user", "", "Path to UserRecord JSON (pipelines and integrations)")
+	payloadFile := flag.String("payload", "", "Path to ActivityPayload JSON")
+	outDir := flag.String("out", "pipeline-run-out", "Directory used as the local blob store")
+	bucket := flag.String("bucket", "fitglue-artifacts", "Artifact bucket name")
+	fixturesFile := flag.String("fixtures", "", "Path to HTTP fixtures JSON (recorded provider API responses)")
+	allowNetwork := flag.Bool("allow-network", false, "Pass HTTP requests without a matching fixture through to the network")
+	doNotRetry := flag.Bool("do-not-retry", false, "Force partial enrichment instead of returning retryable (lag) errors")
+	verbose := flag.Bool("v", false, "Enable debug logging")
+	flag.Parse()
+
+	if *userFile == "" || *payloadFile == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	level := slog.LevelInfo
+	if *verbose {
+		level = slog.LevelDebug
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level})))
+
+	// Route all default-transport HTTP traffic through the fixtures
+	transport := &FixtureTransport{}
+	if *allowNetwork {
+		transport.Fallback = http.DefaultTransport
+	}
+	if *fixturesFile != "" {
+		fixtures, err := LoadFixtures(*fixturesFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		transport.Fixtures = fixtures
+	}
+	http.DefaultTransport = transport
+
+	var user pb.UserRecord
+	if err := readProtoJSON(*userFile, &user); err != nil {
+		log.Fatalf("Failed to load user: %v", err)
+	}
+	var payload pb.ActivityPayload
+	if err := readProtoJSON(*payloadFile, &payload); err != nil {
+		log.Fatalf("Failed to load payload: %v", err)
+	}
+	if payload.UserId == "" {
+		payload.UserId = user.UserId
+	}
+	if payload.UserId != user.UserId {
+		log.Fatalf("Payload user %q does not match user record %q", payload.UserId, user.UserId)
+	}
+
+	ctx := context.Background()
+	db := database.NewMemoryDatabase()
+	if err := db.SetUser(ctx, &user); err != nil {
+		log.Fatalf("Failed to seed user: %v", err)
+	}
+	store := &infrastorage.FileSystemStore{Root: *outDir}
+	pub := &infrapubsub.MemoryPublisher{}
+
+	svc := &bootstrap.Service{
+		DB:      db,
+		Store:   store,
+		Pub:     pub,
+		Secrets: &secrets.SecretsAdapter{},
+		Config: &bootstrap.Config{
+			ProjectID:         "fitglue-local",
+			GCSArtifactBucket: *bucket,
+		},
+	}
+
+	orchestrator := enricher.NewOrchestrator(db, store, *bucket, nil)
+	for _, provider := range providers.GetAll() {
+		if sp, ok := provider.(interface{ SetService(*bootstrap.Service) }); ok {
+			sp.SetService(svc)
+		}
+		orchestrator.Register(provider)
+	}
+
+	result, err := orchestrator.Process(ctx, &payload, "local-exec", "local-pipeline-exec", *doNotRetry)
+	if err != nil {
+		var retryable *providers.RetryableError
+		if errors.As(err, &retryable) {
+			log.Fatalf("Pipeline lagged (would be retried via the lag topic): %v", err)
+		}
+		log.Fatalf("Pipeline failed: %v", err)
+	}
+
+	printResult(result, pub, store)
+}
+
+func readProtoJSON(path string, m proto.Message) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, m)
+}
+
+func printResult(result *enricher.ProcessResult, pub *infrapubsub.MemoryPublisher, store *infrastorage.FileSystemStore) {
+	fmt.Printf("Status: %s\n\n", result.Status)
+
+	fmt.Println("Provider executions:")
+	for _, pe := range result.ProviderExecutions {
+		fmt.Printf("  %-24s %-10s %5dms", pe.ProviderName, pe.Status, pe.DurationMs)
+		if pe.Error != "" {
+			fmt.Printf("  error: %s", pe.Error)
+		}
+		fmt.Println()
+
+		keys := make([]string, 0, len(pe.Metadata))
+		for k := range pe.Metadata {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Printf("      %s: %s\n", k, pe.Metadata[k])
+		}
+	}
+
+	marshalOpts := protojson.MarshalOptions{Multiline: true, Indent: "  ", UseProtoNames: true}
+	for _, event := range result.Events {
+		data, err := marshalOpts.Marshal(event)
+		if err != nil {
+			log.Fatalf("Failed to marshal event: %v", err)
+		}
+		fmt.Printf("\nEnriched event (pipeline %s):\n%s\n", event.PipelineId, data)
+
+		if event.FitFileUri != "" {
+			path, err := store.LocalPath(event.FitFileUri)
+			if err != nil {
+				path = event.FitFileUri
+			}
+			fmt.Printf("FIT file: %s\n", path)
+		}
+	}
+	if len(result.Events) == 0 {
+		fmt.Println("\nNo enriched events (no pipeline matched, or a provider halted the pipeline)")
+	}
+
+	if msgs := pub.Messages(); len(msgs) > 0 {
+		fmt.Println("\nPublished messages:")
+		for _, m := range msgs {
+			fmt.Printf("  %s  %s  %s\n", m.ID, m.Topic, m.Event.Type())
+		}
+	}
+}
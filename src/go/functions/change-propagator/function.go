@@ -0,0 +1,56 @@
+package changepropagator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/functions"
+	"github.com/cloudevents/sdk-go/v2/event"
+
+	"github.com/ripixel/fitglue-server/src/go/pkg/bootstrap"
+	"github.com/ripixel/fitglue-server/src/go/pkg/destinations"
+	"github.com/ripixel/fitglue-server/src/go/pkg/framework"
+
+	// Register destinations
+	_ "github.com/ripixel/fitglue-server/src/go/pkg/destinations/all"
+)
+
+var (
+	svc     *bootstrap.Service
+	svcOnce sync.Once
+	svcErr  error
+)
+
+func init() {
+	functions.CloudEvent("PropagateSourceChange", PropagateSourceChange)
+}
+
+// SetService overrides the service used by the entry points, bypassing bootstrap.NewService.
+// Used by the local emulator to run the function against in-memory adapters.
+func SetService(s *bootstrap.Service) {
+	svc = s
+}
+
+func initService(ctx context.Context) (*bootstrap.Service, error) {
+	if svc != nil {
+		return svc, nil
+	}
+	svcOnce.Do(func() {
+		svc, svcErr = bootstrap.NewService(ctx)
+		if svcErr != nil {
+			slog.Error("Failed to initialize service", "error", svcErr)
+		}
+	})
+	return svc, svcErr
+}
+
+// PropagateSourceChange applies a source update or deletion to the activity's destination copies
+func PropagateSourceChange(ctx context.Context, e event.Event) error {
+	svc, err := initService(ctx)
+	if err != nil {
+		return fmt.Errorf("service init failed: %v", err)
+	}
+	return framework.WrapCloudEvent("change-propagator", svc, destinations.SourceChangeHandler())(ctx, e)
+}
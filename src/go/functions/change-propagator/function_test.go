@@ -0,0 +1,51 @@
+package changepropagator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ripixel/fitglue-server/src/go/pkg/bootstrap"
+	"github.com/ripixel/fitglue-server/src/go/pkg/infrastructure/database"
+	infrapubsub "github.com/ripixel/fitglue-server/src/go/pkg/infrastructure/pubsub"
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+)
+
+func TestPropagateSourceChange(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewMemoryDatabase()
+	db.SetUser(ctx, &pb.UserRecord{
+		UserId:             "user-1",
+		SourceChangePolicy: pb.SourceChangePolicy_SOURCE_CHANGE_POLICY_UPDATE_METADATA,
+	})
+	db.SetSynchronizedActivity(ctx, "user-1", &pb.SynchronizedActivity{
+		ActivityId:   "act-1",
+		Source:       "SOURCE_HEVY",
+		ExternalId:   "hevy-1",
+		Destinations: map[string]string{"mock": "mock-act-1"},
+	})
+	SetService(&bootstrap.Service{DB: db, Pub: &infrapubsub.MemoryPublisher{}, Config: &bootstrap.Config{}})
+	defer SetService(nil)
+
+	e, err := infrapubsub.NewCloudEvent("/integrations/hevy", "com.fitglue.activity.source_changed", &pb.SourceActivityChange{
+		UserId:     "user-1",
+		Source:     pb.ActivitySource_SOURCE_HEVY,
+		ExternalId: "hevy-1",
+		Change:     pb.SourceChangeType_SOURCE_CHANGE_TYPE_DELETED,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.SetID("change-1")
+
+	if err := PropagateSourceChange(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+
+	synced, err := db.GetSynchronizedActivity(ctx, "user-1", "act-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := synced.Destinations["mock"]; ok {
+		t.Errorf("Expected the mock copy to be deleted, got %v", synced.Destinations)
+	}
+}
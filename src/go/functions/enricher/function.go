@@ -45,6 +45,12 @@ func init() {
 	functions.HTTP("EnrichActivityHTTP", EnrichActivityHTTP)
 }
 
+// SetService overrides the service used by the entry points, bypassing bootstrap.NewService.
+// Used by the local emulator to run the function against in-memory adapters.
+func SetService(s *bootstrap.Service) {
+	svc = s
+}
+
 func initService(ctx context.Context) (*bootstrap.Service, error) {
 	if svc != nil {
 		return svc, nil
@@ -283,6 +289,8 @@ func enrichHandler(ctx context.Context, e cloudevents.Event, fwCtx *framework.Fr
 		Destinations       []string `json:"destinations"`
 		AppliedEnrichments []string `json:"applied_enrichments"`
 		FitFileURI         string   `json:"fit_file_uri,omitempty"`
+		FitValidation      string   `json:"fit_validation,omitempty"`
+		FitIssues          string   `json:"fit_issues,omitempty"`
 		PubSubMessageID    string   `json:"pubsub_message_id"`
 	}
 	publishedEvents := []PublishedEvent{}
@@ -317,6 +325,8 @@ func enrichHandler(ctx context.Context, e cloudevents.Event, fwCtx *framework.Fr
 				Destinations:       destinationsToStrings(event.Destinations),
 				AppliedEnrichments: event.AppliedEnrichments,
 				FitFileURI:         event.FitFileUri,
+				FitValidation:      event.EnrichmentMetadata["fit_validation_status"],
+				FitIssues:          event.EnrichmentMetadata["fit_validation_issues"],
 				PubSubMessageID:    msgID,
 			})
 		}
@@ -3,6 +3,7 @@ package enricher
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
 	"strings"
 	"time"
@@ -13,7 +14,9 @@ import (
 	"github.com/ripixel/fitglue-server/src/go/pkg/domain/tier"
 	providers "github.com/ripixel/fitglue-server/src/go/pkg/enricher_providers"
 	"github.com/ripixel/fitglue-server/src/go/pkg/enricher_providers/user_input"
+	infrastorage "github.com/ripixel/fitglue-server/src/go/pkg/infrastructure/storage"
 	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -24,6 +27,10 @@ type Orchestrator struct {
 	providersByName map[string]providers.Provider
 	providersByType map[pb.EnricherProviderType]providers.Provider
 	notifications   shared.NotificationService
+
+	// recordResolution is the spacing of records created when merging streams
+	// (0 = fit.DefaultRecordResolution for the activity's length)
+	recordResolution time.Duration
 }
 
 func NewOrchestrator(db shared.Database, storage shared.BlobStore, bucketName string, notifications shared.NotificationService) *Orchestrator {
@@ -37,6 +44,11 @@ func NewOrchestrator(db shared.Database, storage shared.BlobStore, bucketName st
 	}
 }
 
+// SetRecordResolution sets a fixed record resolution for stream merging and FIT generation
+func (o *Orchestrator) SetRecordResolution(resolution time.Duration) {
+	o.recordResolution = resolution
+}
+
 func (o *Orchestrator) Register(p providers.Provider) {
 	o.providersByName[p.Name()] = p
 	if t := p.ProviderType(); t != pb.EnricherProviderType_ENRICHER_PROVIDER_UNSPECIFIED {
@@ -116,6 +128,22 @@ func (o *Orchestrator) Process(ctx context.Context, payload *pb.ActivityPayload,
 	var allEvents []*pb.EnrichedActivityEvent
 	var allProviderExecutions []ProviderExecution
 
+	// Without source records, the FIT records are all merged from streams and their gaps
+	// are missing data rather than pauses. Checked before any pipeline runs, since each
+	// pipeline's stream merge writes its records into the shared session.
+	hasSourceRecords := false
+	for _, lap := range payload.StandardizedActivity.Sessions[0].Laps {
+		if len(lap.Records) > 0 {
+			hasSourceRecords = true
+			break
+		}
+	}
+
+	// The source's own metadata, recorded on each event so source edits can be propagated
+	// without overwriting what the enrichers added (enrichers mutate the shared activity)
+	source := payload.StandardizedActivity
+	sourceName, sourceDescription, sourceType := source.Name, source.Description, source.Type
+
 	// 3. Execute Each Pipeline
 	for _, pipeline := range pipelines {
 		slog.Info("Executing pipeline", "id", pipeline.ID)
@@ -264,6 +292,10 @@ func (o *Orchestrator) Process(ctx context.Context, payload *pb.ActivityPayload,
 			PipelineId:          pipeline.ID,
 			PipelineExecutionId: &pipelineExecutionID,
 			StartTime:           payload.StandardizedActivity.Sessions[0].StartTime,
+			Resync:              payload.Resync,
+			SourceName:          sourceName,
+			SourceDescription:   sourceDescription,
+			SourceType:          sourceType,
 		}
 
 		if payload.StandardizedActivity != nil {
@@ -274,7 +306,7 @@ func (o *Orchestrator) Process(ctx context.Context, payload *pb.ActivityPayload,
 
 		// Merge Streams & Metadata
 		session := payload.StandardizedActivity.Sessions[0]
-		duration := int(session.TotalElapsedTime)
+		elapsed := time.Duration(session.TotalElapsedTime * float64(time.Second))
 
 		// Ensure Laps/Records exist
 		if len(session.Laps) == 0 {
@@ -287,16 +319,15 @@ func (o *Orchestrator) Process(ctx context.Context, payload *pb.ActivityPayload,
 		}
 		lap := session.Laps[0]
 
-		// Ensure records are large enough
-		currentLen := len(lap.Records)
-		if currentLen < duration {
-			startTime := session.StartTime.AsTime()
-			// Pad with timestamp-only records
-			for k := currentLen; k < duration; k++ {
-				ts := timestamppb.New(startTime.Add(time.Duration(k) * time.Second))
-				lap.Records = append(lap.Records, &pb.Record{Timestamp: ts})
-			}
+		// Streams are merged by timestamp; records are only created where data exists
+		resolution := o.recordResolution
+		if resolution <= 0 {
+			resolution = fit.DefaultRecordResolution(elapsed)
 		}
+		merger := newStreamMerger(lap, session.StartTime.AsTime(), elapsed, resolution)
+
+		developerFields := make(map[string]interface{})
+		enricherOptions := &pb.DestinationOptions{}
 
 		for i, res := range results {
 			if res == nil {
@@ -306,39 +337,20 @@ func (o *Orchestrator) Process(ctx context.Context, payload *pb.ActivityPayload,
 			finalEvent.AppliedEnrichments = append(finalEvent.AppliedEnrichments, cfgName)
 
 			// Merge Data Streams into Records
-			if len(res.HeartRateStream) > 0 {
-				for idx, val := range res.HeartRateStream {
-					if idx < len(lap.Records) && val > 0 {
-						lap.Records[idx].HeartRate = int32(val)
-					}
-				}
-			}
-			if len(res.PowerStream) > 0 {
-				for idx, val := range res.PowerStream {
-					if idx < len(lap.Records) && val > 0 {
-						lap.Records[idx].Power = int32(val)
-					}
-				}
-			}
-			if len(res.PositionLatStream) > 0 {
-				for idx, val := range res.PositionLatStream {
-					if idx < len(lap.Records) {
-						lap.Records[idx].PositionLat = val
-					}
-				}
-			}
-			if len(res.PositionLongStream) > 0 {
-				for idx, val := range res.PositionLongStream {
-					if idx < len(lap.Records) {
-						lap.Records[idx].PositionLong = val
-					}
-				}
-			}
+			merger.Merge(res)
 
 			for k, v := range res.Metadata {
 				finalEvent.EnrichmentMetadata[k] = v
 			}
+			for k, v := range res.DeveloperFields {
+				developerFields[k] = v
+			}
+			if res.DestinationOptions != nil {
+				proto.Merge(enricherOptions, res.DestinationOptions)
+			}
 		}
+		merger.Finish()
+		finalEvent.DestinationOptions = pipeline.destinationOptions(enricherOptions)
 
 		// Always run branding provider last (unconditionally)
 		if brandingProvider, ok := o.providersByName["branding"]; ok {
@@ -360,16 +372,24 @@ func (o *Orchestrator) Process(ctx context.Context, payload *pb.ActivityPayload,
 		}
 
 		// 3c. Generate Artifacts (FIT File)
-		fitBytes, err := fit.GenerateFitFile(payload.StandardizedActivity)
+		developerFields[fit.DevFieldPipelineID] = pipeline.ID
+		developerFields[fit.DevFieldAppliedEnrichments] = strings.Join(finalEvent.AppliedEnrichments, ",")
+		fitOpts := []fit.Option{fit.WithDeveloperFields(developerFields)}
+		if o.recordResolution > 0 {
+			fitOpts = append(fitOpts, fit.WithRecordResolution(o.recordResolution))
+		}
+		if !hasSourceRecords {
+			fitOpts = append(fitOpts, fit.WithoutPauseInference())
+		}
+		objName := fmt.Sprintf("activities/%s/%s.fit", payload.UserId, finalEvent.ActivityId)
+		validationMetadata, err := o.writeFitFile(ctx, objName, payload.StandardizedActivity, fitOpts...)
+		for k, v := range validationMetadata {
+			finalEvent.EnrichmentMetadata[k] = v
+		}
 		if err != nil {
-			slog.Error("Failed to generate FIT file", "error", err) // Don't fail the whole event, just log
-		} else if len(fitBytes) > 0 {
-			objName := fmt.Sprintf("activities/%s/%s.fit", payload.UserId, finalEvent.ActivityId)
-			if err := o.storage.Write(ctx, o.bucketName, objName, fitBytes); err != nil {
-				slog.Error("Failed to write FIT file artifact", "error", err)
-			} else {
-				finalEvent.FitFileUri = fmt.Sprintf("gs://%s/%s", o.bucketName, objName)
-			}
+			slog.Error("Failed to write FIT file artifact", "error", err) // Don't fail the whole event, just log
+		} else {
+			finalEvent.FitFileUri = infrastorage.URI(o.bucketName, objName)
 		}
 
 		allEvents = append(allEvents, finalEvent)
@@ -388,9 +408,33 @@ func (o *Orchestrator) Process(ctx context.Context, payload *pb.ActivityPayload,
 }
 
 type configuredPipeline struct {
-	ID           string
-	Enrichers    []configuredEnricher
-	Destinations []pb.Destination
+	ID                 string
+	Enrichers          []configuredEnricher
+	Destinations       []pb.Destination
+	DestinationOptions map[string]*pb.DestinationOptions
+}
+
+// destinationOptions resolves the options for each of the pipeline's destinations: the options
+// set by enrichers, overridden by the pipeline's own options for that destination
+func (p configuredPipeline) destinationOptions(enricherOptions *pb.DestinationOptions) map[string]*pb.DestinationOptions {
+	resolved := make(map[string]*pb.DestinationOptions)
+	for _, d := range p.Destinations {
+		name := destinationName(d)
+		opts := proto.Clone(enricherOptions).(*pb.DestinationOptions)
+		if override, ok := p.DestinationOptions[name]; ok {
+			proto.Merge(opts, override)
+		}
+		if !proto.Equal(opts, &pb.DestinationOptions{}) {
+			resolved[name] = opts
+		}
+	}
+	return resolved
+}
+
+// destinationName is the registered name of a destination (destinations.Destination.Name()),
+// e.g. DESTINATION_STRAVA -> "strava"
+func destinationName(d pb.Destination) string {
+	return strings.ToLower(strings.TrimPrefix(d.String(), "DESTINATION_"))
 }
 
 type configuredEnricher struct {
@@ -398,6 +442,94 @@ type configuredEnricher struct {
 	TypedConfig  map[string]string
 }
 
+// writeFitFile streams the FIT file for the activity to the BlobStore, validating it as it
+// is written. If validation finds repairable issues, the activity is repaired and the file
+// written and validated once more.
+// Returned metadata describes the validation outcome for the execution record.
+func (o *Orchestrator) writeFitFile(ctx context.Context, objName string, activity *pb.StandardizedActivity, opts ...fit.Option) (map[string]string, error) {
+	report, err := o.writeFitStream(ctx, objName, activity, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if report == nil {
+		return map[string]string{"fit_validation_status": "undecodable"}, nil
+	}
+	if report.Valid() {
+		return map[string]string{"fit_validation_status": "valid"}, nil
+	}
+
+	metadata := make(map[string]string)
+	if report.Repairable() {
+		repaired := fit.RepairActivity(activity, report)
+		slog.Warn("Repairing FIT validation issues", "issues", report.Summary(), "repaired", repaired)
+		metadata["fit_validation_repairs"] = strings.Join(repaired, ",")
+
+		report, err = o.writeFitStream(ctx, objName, activity, opts...)
+		if err != nil {
+			return nil, err
+		}
+		if report == nil {
+			metadata["fit_validation_status"] = "undecodable"
+			return metadata, nil
+		}
+		if report.Valid() {
+			metadata["fit_validation_status"] = "repaired"
+			return metadata, nil
+		}
+	}
+
+	slog.Warn("FIT file has unresolved validation issues", "issues", report.Summary())
+	metadata["fit_validation_status"] = "invalid"
+	metadata["fit_validation_issues"] = report.Summary()
+	return metadata, nil
+}
+
+// writeFitStream encodes the activity to the BlobStore, teeing the final encoding pass into a
+// pipe consumed by the validator, so the file is never held in memory. The tee makes every
+// store take EncodeFitFile's two-pass path: a sizing pass, then the pass that is uploaded
+// and validated.
+// It returns an error if generation or the upload fails, and a nil report if the generated
+// file cannot be decoded.
+func (o *Orchestrator) writeFitStream(ctx context.Context, objName string, activity *pb.StandardizedActivity, opts ...fit.Option) (*fit.ValidationReport, error) {
+	// Cancelling the upload context before Close abandons a partial upload
+	uploadCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	w, err := o.storage.NewWriter(uploadCtx, o.bucketName, objName)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+	type validation struct {
+		report *fit.ValidationReport
+		err    error
+	}
+	validated := make(chan validation, 1)
+	go func() {
+		report, err := fit.ValidateFitReader(pr, activity)
+		io.Copy(io.Discard, pr) // Keep draining so the upload isn't blocked if decoding stopped early
+		validated <- validation{report, err}
+	}()
+
+	err = fit.EncodeFitFile(w, activity, append(opts, fit.WithTee(pw))...)
+	pw.CloseWithError(err)
+	v := <-validated
+	if err != nil {
+		cancel()
+		w.Close()
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	if v.err != nil {
+		slog.Error("Generated FIT file failed to decode", "error", v.err)
+		return nil, nil
+	}
+	return v.report, nil
+}
+
 func (o *Orchestrator) resolvePipelines(source pb.ActivitySource, userRec *pb.UserRecord) []configuredPipeline {
 	var pipelines []configuredPipeline
 	sourceName := source.String()
@@ -413,9 +545,10 @@ func (o *Orchestrator) resolvePipelines(source pb.ActivitySource, userRec *pb.Us
 				})
 			}
 			pipelines = append(pipelines, configuredPipeline{
-				ID:           p.Id,
-				Enrichers:    enrichers,
-				Destinations: p.Destinations,
+				ID:                 p.Id,
+				Enrichers:          enrichers,
+				Destinations:       p.Destinations,
+				DestinationOptions: p.DestinationOptions,
 			})
 		}
 	}
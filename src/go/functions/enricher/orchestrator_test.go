@@ -1,11 +1,20 @@
 package enricher
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"io"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/muktihari/fit/decoder"
+	"github.com/muktihari/fit/profile/mesgdef"
+	"github.com/muktihari/fit/profile/typedef"
+	fit "github.com/ripixel/fitglue-server/src/go/pkg/domain/file_generators"
 	providers "github.com/ripixel/fitglue-server/src/go/pkg/enricher_providers"
+	"github.com/ripixel/fitglue-server/src/go/pkg/infrastructure/storage"
 	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -42,16 +51,46 @@ func (m *MockDatabase) UpdatePendingInput(ctx context.Context, id string, data m
 func (m *MockDatabase) ListPendingInputs(ctx context.Context, userID string) ([]*pb.PendingInput, error) {
 	return nil, nil
 }
+func (m *MockDatabase) GetPendingUpload(ctx context.Context, id string) (*pb.PendingUpload, error) {
+	return nil, nil
+}
+func (m *MockDatabase) CreatePendingUpload(ctx context.Context, upload *pb.PendingUpload) error {
+	return nil
+}
+func (m *MockDatabase) UpdatePendingUpload(ctx context.Context, id string, data map[string]interface{}) error {
+	return nil
+}
 func (m *MockDatabase) GetCounter(ctx context.Context, userId string, id string) (*pb.Counter, error) {
 	return nil, nil
 }
 func (m *MockDatabase) SetCounter(ctx context.Context, userId string, counter *pb.Counter) error {
 	return nil
 }
+func (m *MockDatabase) IncrementCounter(ctx context.Context, userId string, id string, period string, activityId string, initial int64) (int64, error) {
+	return initial, nil
+}
 
 func (m *MockDatabase) SetSynchronizedActivity(ctx context.Context, userId string, activity *pb.SynchronizedActivity) error {
 	return nil
 }
+func (m *MockDatabase) UpdateSynchronizedActivity(ctx context.Context, userId string, id string, data map[string]interface{}) error {
+	return nil
+}
+func (m *MockDatabase) FindSynchronizedActivities(ctx context.Context, userId string, source string, externalId string) ([]*pb.SynchronizedActivity, error) {
+	return nil, nil
+}
+func (m *MockDatabase) DestinationExists(ctx context.Context, userId string, destination string, externalId string) (bool, error) {
+	return false, nil
+}
+func (m *MockDatabase) HasProcessedActivity(ctx context.Context, userId string, source string, externalId string) (bool, error) {
+	return false, nil
+}
+func (m *MockDatabase) MarkActivityProcessed(ctx context.Context, userId string, record *pb.ProcessedActivityRecord) error {
+	return nil
+}
+func (m *MockDatabase) FindUserByExternalID(ctx context.Context, provider string, externalId string) (string, error) {
+	return "", nil
+}
 func (m *MockDatabase) IncrementSyncCount(ctx context.Context, userID string) error {
 	return nil
 }
@@ -73,6 +112,31 @@ func (m *MockBlobStore) Write(ctx context.Context, bucket, object string, data [
 func (m *MockBlobStore) Read(ctx context.Context, bucket, object string) ([]byte, error) {
 	return nil, nil
 }
+func (m *MockBlobStore) NewWriter(ctx context.Context, bucket, object string) (io.WriteCloser, error) {
+	return &mockBlobWriter{store: m, ctx: ctx, bucket: bucket, object: object}, nil
+}
+func (m *MockBlobStore) List(ctx context.Context, bucket, prefix string) ([]string, error) {
+	return nil, nil
+}
+func (m *MockBlobStore) Delete(ctx context.Context, bucket, object string) error {
+	return nil
+}
+func (m *MockBlobStore) Exists(ctx context.Context, bucket, object string) (bool, error) {
+	return false, nil
+}
+
+// mockBlobWriter buffers written data and hands it to MockBlobStore.Write on Close
+type mockBlobWriter struct {
+	store          *MockBlobStore
+	ctx            context.Context
+	bucket, object string
+	buf            bytes.Buffer
+}
+
+func (w *mockBlobWriter) Write(p []byte) (int, error) { return w.buf.Write(p) }
+func (w *mockBlobWriter) Close() error {
+	return w.store.Write(w.ctx, w.bucket, w.object, w.buf.Bytes())
+}
 
 // MockProvider implements providers.Provider
 type MockProvider struct {
@@ -182,6 +246,9 @@ func TestOrchestrator_Process(t *testing.T) {
 		if event.Description != "Added by mock" {
 			t.Errorf("Expected description 'Added by mock', got '%s'", event.Description)
 		}
+		if event.SourceName != "Original Run" || event.SourceDescription != "" {
+			t.Errorf("Expected the source's own name and description, got %q / %q", event.SourceName, event.SourceDescription)
+		}
 		if event.EnrichmentMetadata["processed_by"] != "mock" {
 			t.Errorf("Expected metadata 'processed_by'='mock'")
 		}
@@ -347,4 +414,217 @@ func TestOrchestrator_Process(t *testing.T) {
 			}
 		}
 	})
+	t.Run("Streams FIT file to the BlobStore", func(t *testing.T) {
+		mockDB := &MockDatabase{
+			GetUserFunc: func(ctx context.Context, id string) (*pb.UserRecord, error) {
+				return &pb.UserRecord{
+					UserId: id,
+					Pipelines: []*pb.PipelineConfig{
+						{
+							Id:     "p1",
+							Source: "SOURCE_HEVY",
+							Enrichers: []*pb.EnricherConfig{
+								{ProviderType: pb.EnricherProviderType_ENRICHER_PROVIDER_MOCK},
+							},
+						},
+					},
+				}, nil
+			},
+		}
+		mockProvider := &MockProvider{
+			NameFunc: func() string { return "mock-enricher" },
+			EnrichFunc: func(ctx context.Context, activity *pb.StandardizedActivity, user *pb.UserRecord, inputConfig map[string]string, doNotRetry bool) (*providers.EnrichmentResult, error) {
+				return &providers.EnrichmentResult{HeartRateStream: []int{100, 110, 120}}, nil
+			},
+		}
+		store := &storage.FileSystemStore{Root: t.TempDir()}
+		orchestrator := NewOrchestrator(mockDB, store, "test-bucket", nil)
+		orchestrator.Register(mockProvider)
+
+		start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+		payload := &pb.ActivityPayload{
+			Source: pb.ActivitySource_SOURCE_HEVY,
+			UserId: "u1",
+			StandardizedActivity: &pb.StandardizedActivity{
+				StartTime: timestamppb.New(start),
+				Type:      pb.ActivityType_ACTIVITY_TYPE_RUN,
+				Sessions: []*pb.Session{
+					{StartTime: timestamppb.New(start), TotalElapsedTime: 600},
+				},
+			},
+		}
+
+		result, err := orchestrator.Process(ctx, payload, "exec-1", "pipe-1", false)
+		if err != nil {
+			t.Fatalf("Process failed: %v", err)
+		}
+		if len(result.Events) != 1 {
+			t.Fatalf("Expected 1 event, got %d", len(result.Events))
+		}
+		event := result.Events[0]
+		if event.EnrichmentMetadata["fit_validation_status"] != "valid" {
+			t.Errorf("Expected valid FIT file, got metadata %v", event.EnrichmentMetadata)
+		}
+
+		objName := strings.TrimPrefix(event.FitFileUri, "gs://test-bucket/")
+		if objName == event.FitFileUri {
+			t.Fatalf("Unexpected FIT file URI: %q", event.FitFileUri)
+		}
+		data, err := store.Read(ctx, "test-bucket", objName)
+		if err != nil {
+			t.Fatalf("Failed to read FIT file from store: %v", err)
+		}
+		if _, err := decoder.New(bytes.NewReader(data)).Decode(); err != nil {
+			t.Errorf("Stored FIT file failed to decode: %v", err)
+		}
+	})
+
+	t.Run("Sparse merged streams are not read as pauses", func(t *testing.T) {
+		start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+		mockProvider := &MockProvider{
+			NameFunc: func() string { return "mock-enricher" },
+			EnrichFunc: func(ctx context.Context, activity *pb.StandardizedActivity, user *pb.UserRecord, inputConfig map[string]string, doNotRetry bool) (*providers.EnrichmentResult, error) {
+				// Heart rate strap dropped out for 5 minutes
+				return &providers.EnrichmentResult{
+					HeartRateStream:  []int{100, 110, 120, 130},
+					StreamTimestamps: []time.Time{start, start.Add(time.Second), start.Add(301 * time.Second), start.Add(302 * time.Second)},
+				}, nil
+			},
+		}
+
+		// Later pipelines see the records merged by earlier ones in the shared session,
+		// so every pipeline must still treat the activity as stream-only
+		for _, pipelineCount := range []int{1, 2} {
+			t.Run(fmt.Sprintf("%d pipelines", pipelineCount), func(t *testing.T) {
+				var pipelines []*pb.PipelineConfig
+				for i := 1; i <= pipelineCount; i++ {
+					pipelines = append(pipelines, &pb.PipelineConfig{
+						Id:     fmt.Sprintf("p%d", i),
+						Source: "SOURCE_HEVY",
+						Enrichers: []*pb.EnricherConfig{
+							{ProviderType: pb.EnricherProviderType_ENRICHER_PROVIDER_MOCK},
+						},
+					})
+				}
+				mockDB := &MockDatabase{
+					GetUserFunc: func(ctx context.Context, id string) (*pb.UserRecord, error) {
+						return &pb.UserRecord{UserId: id, Pipelines: pipelines}, nil
+					},
+				}
+				var written [][]byte
+				mockStorage := &MockBlobStore{
+					WriteFunc: func(ctx context.Context, bucket, object string, data []byte) error {
+						written = append(written, data)
+						return nil
+					},
+				}
+				orchestrator := NewOrchestrator(mockDB, mockStorage, "test-bucket", nil)
+				orchestrator.Register(mockProvider)
+
+				payload := &pb.ActivityPayload{
+					Source: pb.ActivitySource_SOURCE_HEVY,
+					UserId: "u1",
+					StandardizedActivity: &pb.StandardizedActivity{
+						StartTime: timestamppb.New(start),
+						Type:      pb.ActivityType_ACTIVITY_TYPE_WEIGHT_TRAINING,
+						Sessions: []*pb.Session{
+							{StartTime: timestamppb.New(start), TotalElapsedTime: 600},
+						},
+					},
+				}
+
+				if _, err := orchestrator.Process(ctx, payload, "exec-1", "pipe-1", false); err != nil {
+					t.Fatalf("Process failed: %v", err)
+				}
+				if len(written) != pipelineCount {
+					t.Fatalf("Expected %d FIT files, got %d", pipelineCount, len(written))
+				}
+				for i, data := range written {
+					fitData, err := decoder.New(bytes.NewReader(data)).Decode()
+					if err != nil {
+						t.Fatalf("FIT file %d failed to decode: %v", i, err)
+					}
+					for _, msg := range fitData.Messages {
+						if msg.Num == typedef.MesgNumSession {
+							if timer := mesgdef.NewSession(&msg).TotalTimerTimeScaled(); timer != 600 {
+								t.Errorf("FIT file %d: expected timer time 600s, got %v", i, timer)
+							}
+						}
+					}
+				}
+			})
+		}
+	})
+
+	t.Run("Validates the FIT file while writing to a non-seekable store", func(t *testing.T) {
+		mockDB := &MockDatabase{
+			GetUserFunc: func(ctx context.Context, id string) (*pb.UserRecord, error) {
+				return &pb.UserRecord{
+					UserId:    id,
+					Pipelines: []*pb.PipelineConfig{{Id: "p1", Source: "SOURCE_HEVY"}},
+				}, nil
+			},
+		}
+		start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+		tests := []struct {
+			name       string
+			heartRate  int32
+			wantStatus string
+			wantWrites int
+		}{
+			{name: "valid", heartRate: 120, wantStatus: "valid", wantWrites: 1},
+			{name: "repaired", heartRate: 300, wantStatus: "repaired", wantWrites: 2},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var writes [][]byte
+				mockStorage := &MockBlobStore{
+					WriteFunc: func(ctx context.Context, bucket, object string, data []byte) error {
+						writes = append(writes, data)
+						return nil
+					},
+				}
+				orchestrator := NewOrchestrator(mockDB, mockStorage, "test-bucket", nil)
+
+				var records []*pb.Record
+				for i := 0; i < 10; i++ {
+					records = append(records, &pb.Record{Timestamp: timestamppb.New(start.Add(time.Duration(i) * time.Second)), HeartRate: tt.heartRate})
+				}
+				payload := &pb.ActivityPayload{
+					Source: pb.ActivitySource_SOURCE_HEVY,
+					UserId: "u1",
+					StandardizedActivity: &pb.StandardizedActivity{
+						StartTime: timestamppb.New(start),
+						Type:      pb.ActivityType_ACTIVITY_TYPE_RUN,
+						Sessions: []*pb.Session{
+							{StartTime: timestamppb.New(start), TotalElapsedTime: 10, Laps: []*pb.Lap{{Records: records}}},
+						},
+					},
+				}
+
+				result, err := orchestrator.Process(ctx, payload, "exec-1", "pipe-1", false)
+				if err != nil {
+					t.Fatalf("Process failed: %v", err)
+				}
+				event := result.Events[0]
+				if got := event.EnrichmentMetadata["fit_validation_status"]; got != tt.wantStatus {
+					t.Errorf("fit_validation_status = %q, want %q", got, tt.wantStatus)
+				}
+				if event.FitFileUri == "" {
+					t.Error("Expected a FIT file URI")
+				}
+				if len(writes) != tt.wantWrites {
+					t.Fatalf("Expected %d writes, got %d", tt.wantWrites, len(writes))
+				}
+				report, err := fit.ValidateFitFile(writes[len(writes)-1], payload.StandardizedActivity)
+				if err != nil {
+					t.Fatalf("Stored FIT file failed to decode: %v", err)
+				}
+				if !report.Valid() {
+					t.Errorf("Stored FIT file has issues: %s", report.Summary())
+				}
+			})
+		}
+	})
 }
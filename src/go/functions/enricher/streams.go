@@ -0,0 +1,118 @@
+package enricher
+
+import (
+	"sort"
+	"time"
+
+	providers "github.com/ripixel/fitglue-server/src/go/pkg/enricher_providers"
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// streamMerger merges provider data streams into a lap's records by timestamp.
+// Samples are bucketed at the given resolution: a sample updates the existing record
+// in its bucket, or creates a sparse record at the bucket start if there is none.
+// Records are only created where a stream has data.
+type streamMerger struct {
+	start      time.Time
+	end        time.Time
+	resolution time.Duration
+	lap        *pb.Lap
+	buckets    map[int64]*pb.Record
+	added      bool
+}
+
+func newStreamMerger(lap *pb.Lap, start time.Time, elapsed time.Duration, resolution time.Duration) *streamMerger {
+	if resolution <= 0 {
+		resolution = time.Second
+	}
+	m := &streamMerger{
+		start:      start,
+		end:        start.Add(elapsed),
+		resolution: resolution,
+		lap:        lap,
+		buckets:    make(map[int64]*pb.Record, len(lap.Records)),
+	}
+	for _, rec := range lap.Records {
+		if rec.Timestamp == nil {
+			continue
+		}
+		key := m.bucket(rec.Timestamp.AsTime())
+		if _, exists := m.buckets[key]; !exists {
+			m.buckets[key] = rec
+		}
+	}
+	return m
+}
+
+func (m *streamMerger) bucket(ts time.Time) int64 {
+	return int64(ts.Sub(m.start) / m.resolution)
+}
+
+// recordAt returns the record for the bucket containing ts, creating one if needed.
+// Returns nil for timestamps outside the session.
+func (m *streamMerger) recordAt(ts time.Time) *pb.Record {
+	if ts.Before(m.start) || ts.After(m.end) {
+		return nil
+	}
+	key := m.bucket(ts)
+	if rec, ok := m.buckets[key]; ok {
+		return rec
+	}
+	rec := &pb.Record{Timestamp: timestamppb.New(m.start.Add(time.Duration(key) * m.resolution))}
+	m.buckets[key] = rec
+	m.lap.Records = append(m.lap.Records, rec)
+	m.added = true
+	return rec
+}
+
+// sampleTime returns the timestamp of stream sample i: the provider's explicit
+// timestamp if given, otherwise i seconds after the session start.
+func (m *streamMerger) sampleTime(res *providers.EnrichmentResult, i int) time.Time {
+	if i < len(res.StreamTimestamps) {
+		return res.StreamTimestamps[i]
+	}
+	return m.start.Add(time.Duration(i) * time.Second)
+}
+
+// Merge applies the data streams of an enrichment result
+func (m *streamMerger) Merge(res *providers.EnrichmentResult) {
+	for i, val := range res.HeartRateStream {
+		if val > 0 {
+			if rec := m.recordAt(m.sampleTime(res, i)); rec != nil {
+				rec.HeartRate = int32(val)
+			}
+		}
+	}
+	for i, val := range res.PowerStream {
+		if val > 0 {
+			if rec := m.recordAt(m.sampleTime(res, i)); rec != nil {
+				rec.Power = int32(val)
+			}
+		}
+	}
+	for i, val := range res.PositionLatStream {
+		if val != 0 {
+			if rec := m.recordAt(m.sampleTime(res, i)); rec != nil {
+				rec.PositionLat = val
+			}
+		}
+	}
+	for i, val := range res.PositionLongStream {
+		if val != 0 {
+			if rec := m.recordAt(m.sampleTime(res, i)); rec != nil {
+				rec.PositionLong = val
+			}
+		}
+	}
+}
+
+// Finish restores timestamp order after sparse records were added
+func (m *streamMerger) Finish() {
+	if !m.added {
+		return
+	}
+	sort.SliceStable(m.lap.Records, func(i, j int) bool {
+		return m.lap.Records[i].Timestamp.AsTime().Before(m.lap.Records[j].Timestamp.AsTime())
+	})
+}
@@ -0,0 +1,95 @@
+package enricher
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	fit "github.com/ripixel/fitglue-server/src/go/pkg/domain/file_generators"
+	providers "github.com/ripixel/fitglue-server/src/go/pkg/enricher_providers"
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestStreamMerger(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("creates records only where data exists", func(t *testing.T) {
+		lap := &pb.Lap{}
+		m := newStreamMerger(lap, start, time.Hour, time.Second)
+		hr := make([]int, 3600)
+		hr[10] = 120
+		hr[3000] = 150
+		m.Merge(&providers.EnrichmentResult{HeartRateStream: hr})
+		m.Finish()
+
+		if len(lap.Records) != 2 {
+			t.Fatalf("Expected 2 sparse records, got %d", len(lap.Records))
+		}
+		if !lap.Records[1].Timestamp.AsTime().Equal(start.Add(3000*time.Second)) || lap.Records[1].HeartRate != 150 {
+			t.Errorf("Unexpected second record: %v", lap.Records[1])
+		}
+	})
+
+	t.Run("merges into existing records by timestamp", func(t *testing.T) {
+		lap := &pb.Lap{Records: []*pb.Record{
+			{Timestamp: timestamppb.New(start.Add(5 * time.Second)), PositionLat: 51.5},
+			{Timestamp: timestamppb.New(start.Add(9 * time.Second)), PositionLat: 51.6},
+		}}
+		m := newStreamMerger(lap, start, time.Minute, time.Second)
+		m.Merge(&providers.EnrichmentResult{
+			StreamTimestamps: []time.Time{start.Add(9 * time.Second), start.Add(5 * time.Second), start.Add(7 * time.Second)},
+			HeartRateStream:  []int{140, 130, 135},
+		})
+		m.Finish()
+
+		if len(lap.Records) != 3 {
+			t.Fatalf("Expected 3 records, got %d", len(lap.Records))
+		}
+		want := []int32{130, 135, 140}
+		for i, rec := range lap.Records {
+			if rec.HeartRate != want[i] {
+				t.Errorf("Record %d: expected HR %d, got %d", i, want[i], rec.HeartRate)
+			}
+		}
+	})
+
+	t.Run("buckets at resolution and ignores samples outside session", func(t *testing.T) {
+		lap := &pb.Lap{}
+		m := newStreamMerger(lap, start, 60*time.Second, 10*time.Second)
+		power := make([]int, 90)
+		for i := range power {
+			power[i] = 200
+		}
+		m.Merge(&providers.EnrichmentResult{PowerStream: power})
+		m.Finish()
+
+		// Buckets 0,10,...,60 (sample 60 is the session end)
+		if len(lap.Records) != 7 {
+			t.Errorf("Expected 7 records at 10s resolution, got %d", len(lap.Records))
+		}
+	})
+}
+
+func BenchmarkStreamMerge(b *testing.B) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	for _, hours := range []int{1, 10, 48} {
+		elapsed := time.Duration(hours) * time.Hour
+		hr := make([]int, int(elapsed.Seconds()))
+		for i := range hr {
+			hr[i] = 120 + i%40
+		}
+		b.Run(fmt.Sprintf("%dh", hours), func(b *testing.B) {
+			b.ReportAllocs()
+			var records int
+			for i := 0; i < b.N; i++ {
+				lap := &pb.Lap{}
+				m := newStreamMerger(lap, start, elapsed, fit.DefaultRecordResolution(elapsed))
+				m.Merge(&providers.EnrichmentResult{HeartRateStream: hr})
+				m.Finish()
+				records = len(lap.Records)
+			}
+			b.ReportMetric(float64(records), "records")
+		})
+	}
+}
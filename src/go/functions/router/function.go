@@ -26,6 +26,12 @@ func init() {
 	functions.CloudEvent("RouteActivity", RouteActivity)
 }
 
+// SetService overrides the service used by the entry points, bypassing bootstrap.NewService.
+// Used by the local emulator to run the function against in-memory adapters.
+func SetService(s *bootstrap.Service) {
+	svc = s
+}
+
 func initService(ctx context.Context) (*bootstrap.Service, error) {
 	if svc != nil {
 		return svc, nil
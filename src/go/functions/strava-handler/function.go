@@ -0,0 +1,389 @@
+package stravahandler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/functions"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	shared "github.com/ripixel/fitglue-server/src/go/pkg"
+	"github.com/ripixel/fitglue-server/src/go/pkg/bootstrap"
+	"github.com/ripixel/fitglue-server/src/go/pkg/framework"
+	"github.com/ripixel/fitglue-server/src/go/pkg/infrastructure/oauth"
+	infrapubsub "github.com/ripixel/fitglue-server/src/go/pkg/infrastructure/pubsub"
+	stravaapi "github.com/ripixel/fitglue-server/src/go/pkg/integrations/strava"
+	"github.com/ripixel/fitglue-server/src/go/pkg/sources/strava"
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+)
+
+const (
+	// verifyTokenSecret holds the token Strava echoes back when validating the webhook subscription
+	verifyTokenSecret = "strava-webhook-verify-token"
+	// subscriptionIDSecret holds the ID of FitGlue's webhook subscription; events carrying any
+	// other subscription_id are rejected
+	subscriptionIDSecret = "strava-webhook-subscription-id"
+)
+
+// supportedAspects are the activity event aspect types the handler acts on
+var supportedAspects = map[string]bool{"create": true, "update": true, "delete": true}
+
+var (
+	svc     *bootstrap.Service
+	svcOnce sync.Once
+	svcErr  error
+)
+
+func init() {
+	// HTTP handler for the Strava webhook subscription (validation GET and event POST)
+	functions.HTTP("StravaWebhook", StravaWebhook)
+}
+
+// SetService overrides the service used by the entry points, bypassing bootstrap.NewService.
+// Used by the local emulator to run the function against in-memory adapters.
+func SetService(s *bootstrap.Service) {
+	svc = s
+}
+
+func initService(ctx context.Context) (*bootstrap.Service, error) {
+	if svc != nil {
+		return svc, nil
+	}
+	svcOnce.Do(func() {
+		svc, svcErr = bootstrap.NewService(ctx)
+		if svcErr != nil {
+			slog.Error("Failed to initialize service", "error", svcErr)
+		}
+	})
+	return svc, svcErr
+}
+
+// StravaWebhook is the HTTP entry point for Strava push subscriptions.
+// GET requests validate the subscription; POST requests deliver activity events.
+// Strava retries events up to three times when the response isn't a 200.
+func StravaWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	svc, err := initService(ctx)
+	if err != nil {
+		slog.Error("Service init failed", "error", err)
+		http.Error(w, fmt.Sprintf("service init failed: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		validateSubscription(w, r, svc)
+	case http.MethodPost:
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to read request body: %v", err), http.StatusBadRequest)
+			return
+		}
+		defer r.Body.Close()
+
+		// The endpoint is public: only accept events for FitGlue's own subscription
+		if code, err := checkSubscription(r.Context(), svc, body); err != nil {
+			slog.Warn("Rejected Strava webhook event", "error", err)
+			http.Error(w, err.Error(), code)
+			return
+		}
+
+		e, err := infrapubsub.NewCloudEvent(
+			infrapubsub.GetCloudEventSource(pb.CloudEventSource_CLOUD_EVENT_SOURCE_STRAVA),
+			infrapubsub.GetCloudEventType(pb.CloudEventType_CLOUD_EVENT_TYPE_STRAVA_WEBHOOK),
+			json.RawMessage(body),
+		)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to build event: %v", err), http.StatusBadRequest)
+			return
+		}
+		e.SetID(fmt.Sprintf("strava-%d", time.Now().UnixNano()))
+		e.SetTime(time.Now())
+
+		if err := framework.WrapCloudEvent("strava-handler", svc, webhookHandler(nil))(ctx, e); err != nil {
+			slog.Error("Handler failed, returning 500 for retry", "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"status":"ok"}`))
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// validateSubscription answers Strava's subscription challenge when the verify token matches
+func validateSubscription(w http.ResponseWriter, r *http.Request, svc *bootstrap.Service) {
+	q := r.URL.Query()
+	if q.Get("hub.mode") != "subscribe" {
+		http.Error(w, "invalid hub.mode", http.StatusBadRequest)
+		return
+	}
+	expected, err := svc.Secrets.GetSecret(r.Context(), svc.Config.ProjectID, verifyTokenSecret)
+	if err != nil {
+		slog.Error("Failed to load verify token", "error", err)
+		http.Error(w, "failed to load verify token", http.StatusInternalServerError)
+		return
+	}
+	if q.Get("hub.verify_token") != expected {
+		http.Error(w, "invalid verify token", http.StatusForbidden)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"hub.challenge": q.Get("hub.challenge")})
+}
+
+// checkSubscription verifies that the event belongs to the configured webhook subscription,
+// returning the HTTP status to respond with when it doesn't
+func checkSubscription(ctx context.Context, svc *bootstrap.Service, body []byte) (int, error) {
+	var webhook strava.WebhookEvent
+	if err := json.Unmarshal(body, &webhook); err != nil {
+		return http.StatusBadRequest, fmt.Errorf("invalid webhook event: %w", err)
+	}
+	expected, err := svc.Secrets.GetSecret(ctx, svc.Config.ProjectID, subscriptionIDSecret)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to load subscription id: %w", err)
+	}
+	if strconv.FormatInt(webhook.SubscriptionID, 10) != strings.TrimSpace(expected) {
+		return http.StatusForbidden, fmt.Errorf("unknown subscription_id %d", webhook.SubscriptionID)
+	}
+	return http.StatusOK, nil
+}
+
+// ownedBy reports whether the fetched activity belongs to the athlete the event was sent for
+func ownedBy(act *stravaapi.DetailedActivity, ownerID int64) bool {
+	return act.Athlete != nil && act.Athlete.Id != nil && *act.Athlete.Id == ownerID
+}
+
+// webhookHandler ingests newly created Strava activities into the user's Strava pipelines,
+// and forwards updates and deletions of ingested activities to the change-propagator.
+// httpClient overrides the per-user OAuth client (for testing).
+func webhookHandler(httpClient *http.Client) framework.HandlerFunc {
+	return func(ctx context.Context, e event.Event, fwCtx *framework.FrameworkContext) (interface{}, error) {
+		var webhook strava.WebhookEvent
+		if err := json.Unmarshal(e.Data(), &webhook); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal webhook event: %w", err)
+		}
+		externalID := strconv.FormatInt(webhook.ObjectID, 10)
+		logger := fwCtx.Logger.With("strava_activity_id", externalID, "aspect_type", webhook.AspectType)
+
+		if webhook.ObjectType != "activity" || !supportedAspects[webhook.AspectType] {
+			logger.Info("Ignoring webhook event", "object_type", webhook.ObjectType)
+			return skipped("unsupported event"), nil
+		}
+
+		// 1. Resolve the FitGlue user from the athlete ID
+		userID, err := fwCtx.Service.DB.FindUserByExternalID(ctx, strava.Name, strconv.FormatInt(webhook.OwnerID, 10))
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve user: %w", err)
+		}
+		if userID == "" {
+			logger.Warn("No user mapped to Strava athlete", "owner_id", webhook.OwnerID)
+			return skipped("unknown athlete"), nil
+		}
+		logger = logger.With("user_id", userID)
+
+		user, err := fwCtx.Service.DB.GetUser(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user: %w", err)
+		}
+		if !user.GetIntegrations().GetStrava().GetEnabled() {
+			logger.Info("Strava integration not enabled")
+			return skipped("integration disabled"), nil
+		}
+		if !hasStravaPipeline(user) {
+			logger.Info("No pipeline uses Strava as a source")
+			return skipped("no strava pipeline"), nil
+		}
+
+		// 2. Loop prevention: activities FitGlue uploaded to Strava for this user
+		isOwnUpload, err := fwCtx.Service.DB.DestinationExists(ctx, userID, strava.Name, externalID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check destinations: %w", err)
+		}
+		if isOwnUpload {
+			logger.Info("Activity was uploaded by FitGlue, skipping")
+			return skipped("fitglue upload"), nil
+		}
+
+		// 3. Deduplication (Strava can deliver the same event more than once)
+		processed, err := fwCtx.Service.DB.HasProcessedActivity(ctx, userID, strava.Name, externalID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check processed activities: %w", err)
+		}
+		if webhook.AspectType != "create" {
+			// Only activities FitGlue ingested have destination copies to keep in step
+			if !processed {
+				logger.Info("Activity was never ingested, ignoring change")
+				return skipped("not ingested"), nil
+			}
+			return publishChange(ctx, fwCtx, httpClient, &webhook, userID, logger)
+		}
+		if processed {
+			logger.Info("Activity already processed, skipping")
+			return skipped("already processed"), nil
+		}
+
+		// 4. Fetch the activity and its streams
+		act, streams, err := strava.Fetch(ctx, stravaClient(fwCtx, httpClient, userID), webhook.ObjectID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch activity: %w", err)
+		}
+		if !ownedBy(act, webhook.OwnerID) {
+			logger.Warn("Activity does not belong to the event's athlete, rejecting", "owner_id", webhook.OwnerID)
+			return skipped("athlete mismatch"), nil
+		}
+		// The sync record may not exist yet if the upload is still being recorded
+		if strava.IsFitGlueUpload(act) {
+			logger.Info("Activity external_id marks a FitGlue upload, skipping")
+			return skipped("fitglue upload"), nil
+		}
+
+		// 5. Map and publish
+		std := strava.MapActivity(act, streams, userID)
+		payload := &pb.ActivityPayload{
+			Source:               pb.ActivitySource_SOURCE_STRAVA,
+			UserId:               userID,
+			Timestamp:            timestamppb.Now(),
+			OriginalPayloadJson:  string(e.Data()),
+			StandardizedActivity: std,
+			PipelineExecutionId:  &fwCtx.PipelineExecutionId,
+		}
+		out, err := infrapubsub.NewCloudEvent(
+			infrapubsub.GetCloudEventSource(pb.CloudEventSource_CLOUD_EVENT_SOURCE_STRAVA),
+			infrapubsub.GetCloudEventType(pb.CloudEventType_CLOUD_EVENT_TYPE_ACTIVITY_CREATED),
+			payload,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build activity event: %w", err)
+		}
+		out.SetID(fmt.Sprintf("strava-%s-%d", externalID, time.Now().UnixNano()))
+		out.SetTime(time.Now())
+		messageID, err := fwCtx.Service.Pub.PublishCloudEvent(ctx, shared.TopicRawActivity, out)
+		if err != nil {
+			return nil, fmt.Errorf("failed to publish activity: %w", err)
+		}
+
+		// 6. Mark processed
+		if err := fwCtx.Service.DB.MarkActivityProcessed(ctx, userID, &pb.ProcessedActivityRecord{
+			Source:      strava.Name,
+			ExternalId:  externalID,
+			ProcessedAt: timestamppb.Now(),
+		}); err != nil {
+			logger.Warn("Failed to mark activity processed", "error", err)
+		}
+
+		logger.Info("Published Strava activity", "message_id", messageID, "records", countRecords(std))
+		return map[string]interface{}{
+			"status":      "SUCCESS",
+			"activity_id": externalID,
+			"message_id":  messageID,
+		}, nil
+	}
+}
+
+// publishChange publishes a SourceActivityChange for an update or deletion of an ingested activity.
+// Updates carry the re-fetched activity so the change-propagator can copy or re-run it.
+// Deletions are only published once Strava confirms the activity is gone (404).
+func publishChange(ctx context.Context, fwCtx *framework.FrameworkContext, httpClient *http.Client, webhook *strava.WebhookEvent, userID string, logger *slog.Logger) (interface{}, error) {
+	externalID := strconv.FormatInt(webhook.ObjectID, 10)
+	change := &pb.SourceActivityChange{
+		UserId:              userID,
+		Source:              pb.ActivitySource_SOURCE_STRAVA,
+		ExternalId:          externalID,
+		Change:              pb.SourceChangeType_SOURCE_CHANGE_TYPE_DELETED,
+		PipelineExecutionId: &fwCtx.PipelineExecutionId,
+	}
+	switch webhook.AspectType {
+	case "delete":
+		// Only trust a deletion Strava confirms, so a forged event can't delete destination copies
+		exists, err := strava.Exists(ctx, stravaClient(fwCtx, httpClient, userID), webhook.ObjectID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check activity: %w", err)
+		}
+		if exists {
+			logger.Warn("Deleted activity still exists on Strava, rejecting")
+			return skipped("activity not deleted"), nil
+		}
+	case "update":
+		act, streams, err := strava.Fetch(ctx, stravaClient(fwCtx, httpClient, userID), webhook.ObjectID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch activity: %w", err)
+		}
+		if !ownedBy(act, webhook.OwnerID) {
+			logger.Warn("Activity does not belong to the event's athlete, rejecting", "owner_id", webhook.OwnerID)
+			return skipped("athlete mismatch"), nil
+		}
+		change.Change = pb.SourceChangeType_SOURCE_CHANGE_TYPE_UPDATED
+		change.StandardizedActivity = strava.MapActivity(act, streams, userID)
+	}
+
+	out, err := infrapubsub.NewCloudEvent(
+		infrapubsub.GetCloudEventSource(pb.CloudEventSource_CLOUD_EVENT_SOURCE_STRAVA),
+		infrapubsub.GetCloudEventType(pb.CloudEventType_CLOUD_EVENT_TYPE_SOURCE_ACTIVITY_CHANGED),
+		change,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build change event: %w", err)
+	}
+	out.SetID(fmt.Sprintf("strava-%s-%s-%d", externalID, webhook.AspectType, time.Now().UnixNano()))
+	out.SetTime(time.Now())
+	out.SetExtension("pipeline_execution_id", fwCtx.PipelineExecutionId)
+	messageID, err := fwCtx.Service.Pub.PublishCloudEvent(ctx, shared.TopicSourceChange, out)
+	if err != nil {
+		return nil, fmt.Errorf("failed to publish change: %w", err)
+	}
+
+	logger.Info("Published Strava activity change", "message_id", messageID, "change", change.Change.String())
+	return map[string]interface{}{
+		"status":      "SUCCESS",
+		"activity_id": externalID,
+		"change":      change.Change.String(),
+		"message_id":  messageID,
+	}, nil
+}
+
+// stravaClient returns the user's OAuth client, or the override when set
+func stravaClient(fwCtx *framework.FrameworkContext, httpClient *http.Client, userID string) *http.Client {
+	if httpClient != nil {
+		return httpClient
+	}
+	tokenSource := oauth.NewFirestoreTokenSource(fwCtx.Service, userID, "strava")
+	return oauth.NewClientWithUsageTracking(tokenSource, fwCtx.Service, userID, "strava")
+}
+
+func skipped(reason string) map[string]interface{} {
+	return map[string]interface{}{
+		"status": "SKIPPED",
+		"reason": reason,
+	}
+}
+
+func hasStravaPipeline(user *pb.UserRecord) bool {
+	for _, p := range user.GetPipelines() {
+		if p.GetSource() == pb.ActivitySource_SOURCE_STRAVA.String() {
+			return true
+		}
+	}
+	return false
+}
+
+func countRecords(std *pb.StandardizedActivity) int {
+	n := 0
+	for _, s := range std.GetSessions() {
+		for _, l := range s.GetLaps() {
+			n += len(l.GetRecords())
+		}
+	}
+	return n
+}
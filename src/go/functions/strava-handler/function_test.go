@@ -0,0 +1,329 @@
+package stravahandler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	shared "github.com/ripixel/fitglue-server/src/go/pkg"
+	"github.com/ripixel/fitglue-server/src/go/pkg/bootstrap"
+	"github.com/ripixel/fitglue-server/src/go/pkg/framework"
+	"github.com/ripixel/fitglue-server/src/go/pkg/infrastructure/database"
+	infrapubsub "github.com/ripixel/fitglue-server/src/go/pkg/infrastructure/pubsub"
+	"github.com/ripixel/fitglue-server/src/go/pkg/testing/mocks"
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+)
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func jsonResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+	}
+}
+
+func TestWebhookHandler(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		webhook     string
+		externalID  string
+		athleteID   int64 // Fetched activity's athlete (99 when unset)
+		deleted     bool  // The activity is gone from Strava (404)
+		setup       func(db *database.MemoryDatabase)
+		wantStatus  string
+		wantPublish bool
+		wantChange  pb.SourceChangeType // Expected change forwarded to the change-propagator
+	}{
+		{
+			name:        "New activity is published",
+			webhook:     `{"object_type": "activity", "object_id": 42, "aspect_type": "create", "owner_id": 99}`,
+			wantStatus:  "SUCCESS",
+			wantPublish: true,
+		},
+		{
+			name:       "Unknown athlete is skipped",
+			webhook:    `{"object_type": "activity", "object_id": 42, "aspect_type": "create", "owner_id": 1}`,
+			wantStatus: "SKIPPED",
+		},
+		{
+			name:       "Athlete events are skipped",
+			webhook:    `{"object_type": "athlete", "object_id": 99, "aspect_type": "update", "owner_id": 99, "updates": {"authorized": "false"}}`,
+			wantStatus: "SKIPPED",
+		},
+		{
+			name:    "Activity uploaded by FitGlue is skipped",
+			webhook: `{"object_type": "activity", "object_id": 42, "aspect_type": "create", "owner_id": 99}`,
+			setup: func(db *database.MemoryDatabase) {
+				db.SetSynchronizedActivity(ctx, "user-1", &pb.SynchronizedActivity{
+					ActivityId:   "fg-1",
+					Destinations: map[string]string{"strava": "42"},
+				})
+			},
+			wantStatus: "SKIPPED",
+		},
+		{
+			name:       "Activity with FitGlue external_id is skipped",
+			webhook:    `{"object_type": "activity", "object_id": 42, "aspect_type": "create", "owner_id": 99}`,
+			externalID: "fitglue-fg-1.fit",
+			wantStatus: "SKIPPED",
+		},
+		{
+			name:       "Activity of another athlete is rejected",
+			webhook:    `{"object_type": "activity", "object_id": 42, "aspect_type": "create", "owner_id": 99}`,
+			athleteID:  7,
+			wantStatus: "SKIPPED",
+		},
+		{
+			name:    "Redelivered event is skipped",
+			webhook: `{"object_type": "activity", "object_id": 42, "aspect_type": "create", "owner_id": 99}`,
+			setup: func(db *database.MemoryDatabase) {
+				db.MarkActivityProcessed(ctx, "user-1", &pb.ProcessedActivityRecord{Source: "strava", ExternalId: "42"})
+			},
+			wantStatus: "SKIPPED",
+		},
+		{
+			name:    "Update to an ingested activity is forwarded",
+			webhook: `{"object_type": "activity", "object_id": 42, "aspect_type": "update", "owner_id": 99, "updates": {"title": "Lunch Ride"}}`,
+			setup: func(db *database.MemoryDatabase) {
+				db.MarkActivityProcessed(ctx, "user-1", &pb.ProcessedActivityRecord{Source: "strava", ExternalId: "42"})
+			},
+			wantStatus: "SUCCESS",
+			wantChange: pb.SourceChangeType_SOURCE_CHANGE_TYPE_UPDATED,
+		},
+		{
+			name:      "Update to another athlete's activity is rejected",
+			webhook:   `{"object_type": "activity", "object_id": 42, "aspect_type": "update", "owner_id": 99, "updates": {"title": "Lunch Ride"}}`,
+			athleteID: 7,
+			setup: func(db *database.MemoryDatabase) {
+				db.MarkActivityProcessed(ctx, "user-1", &pb.ProcessedActivityRecord{Source: "strava", ExternalId: "42"})
+			},
+			wantStatus: "SKIPPED",
+		},
+		{
+			name:    "Deletion of an ingested activity is forwarded",
+			webhook: `{"object_type": "activity", "object_id": 42, "aspect_type": "delete", "owner_id": 99}`,
+			deleted: true,
+			setup: func(db *database.MemoryDatabase) {
+				db.MarkActivityProcessed(ctx, "user-1", &pb.ProcessedActivityRecord{Source: "strava", ExternalId: "42"})
+			},
+			wantStatus: "SUCCESS",
+			wantChange: pb.SourceChangeType_SOURCE_CHANGE_TYPE_DELETED,
+		},
+		{
+			name:    "Deletion of an activity that still exists is rejected",
+			webhook: `{"object_type": "activity", "object_id": 42, "aspect_type": "delete", "owner_id": 99}`,
+			setup: func(db *database.MemoryDatabase) {
+				db.MarkActivityProcessed(ctx, "user-1", &pb.ProcessedActivityRecord{Source: "strava", ExternalId: "42"})
+			},
+			wantStatus: "SKIPPED",
+		},
+		{
+			name:       "Change to an activity never ingested is skipped",
+			webhook:    `{"object_type": "activity", "object_id": 42, "aspect_type": "delete", "owner_id": 99}`,
+			wantStatus: "SKIPPED",
+		},
+		{
+			name:    "Update to a FitGlue upload is skipped",
+			webhook: `{"object_type": "activity", "object_id": 42, "aspect_type": "update", "owner_id": 99, "updates": {"title": "Lunch Ride"}}`,
+			setup: func(db *database.MemoryDatabase) {
+				db.SetSynchronizedActivity(ctx, "user-1", &pb.SynchronizedActivity{
+					ActivityId:   "fg-1",
+					Destinations: map[string]string{"strava": "42"},
+				})
+			},
+			wantStatus: "SKIPPED",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := database.NewMemoryDatabase()
+			db.SetUser(ctx, &pb.UserRecord{
+				UserId:       "user-1",
+				Integrations: &pb.UserIntegrations{Strava: &pb.StravaIntegration{Enabled: true, AthleteId: 99}},
+				Pipelines:    []*pb.PipelineConfig{{Id: "p1", Source: "SOURCE_STRAVA", Destinations: []pb.Destination{pb.Destination_DESTINATION_MOCK}}},
+			})
+			db.SetIntegrationIdentity(ctx, "strava", "99", "user-1")
+			if tt.setup != nil {
+				tt.setup(db)
+			}
+			pub := &infrapubsub.MemoryPublisher{}
+			fwCtx := &framework.FrameworkContext{
+				Service:             &bootstrap.Service{DB: db, Pub: pub, Config: &bootstrap.Config{}},
+				Logger:              slog.Default(),
+				PipelineExecutionId: "pe-1",
+			}
+
+			client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				if tt.deleted {
+					return jsonResponse(404, `{"message": "Record Not Found"}`), nil
+				}
+				if strings.HasSuffix(req.URL.Path, "/streams") {
+					return jsonResponse(200, `{"time": {"data": [0, 1]}, "heartrate": {"data": [100, 110]}}`), nil
+				}
+				athleteID := tt.athleteID
+				if athleteID == 0 {
+					athleteID = 99
+				}
+				act := map[string]interface{}{
+					"id": 42, "name": "Lunch Ride", "sport_type": "Ride", "start_date": "2026-01-10T12:00:00Z",
+					"athlete": map[string]interface{}{"id": athleteID},
+				}
+				if tt.externalID != "" {
+					act["external_id"] = tt.externalID
+				}
+				body, _ := json.Marshal(act)
+				return jsonResponse(200, string(body)), nil
+			})}
+
+			e, _ := infrapubsub.NewCloudEvent("/integrations/strava", "com.fitglue.strava.webhook", json.RawMessage(tt.webhook))
+			out, err := webhookHandler(client)(ctx, e, fwCtx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := out.(map[string]interface{})["status"]; got != tt.wantStatus {
+				t.Errorf("Status = %v, want %v (%v)", got, tt.wantStatus, out)
+			}
+
+			msgs := pub.Messages()
+			if tt.wantChange != pb.SourceChangeType_SOURCE_CHANGE_TYPE_UNSPECIFIED {
+				if len(msgs) != 1 || msgs[0].Topic != shared.TopicSourceChange {
+					t.Fatalf("Unexpected messages: %+v", msgs)
+				}
+				var change pb.SourceActivityChange
+				if err := protojson.Unmarshal(msgs[0].Event.Data(), &change); err != nil {
+					t.Fatal(err)
+				}
+				if change.Change != tt.wantChange || change.UserId != "user-1" || change.ExternalId != "42" || change.Source != pb.ActivitySource_SOURCE_STRAVA {
+					t.Errorf("Unexpected change: %+v", &change)
+				}
+				if (change.StandardizedActivity.GetName() == "Lunch Ride") != (tt.wantChange == pb.SourceChangeType_SOURCE_CHANGE_TYPE_UPDATED) {
+					t.Errorf("Unexpected change activity: %+v", change.StandardizedActivity)
+				}
+				return
+			}
+			if !tt.wantPublish {
+				if len(msgs) != 0 {
+					t.Errorf("Expected no messages, got %d", len(msgs))
+				}
+				return
+			}
+			if len(msgs) != 1 || msgs[0].Topic != shared.TopicRawActivity {
+				t.Fatalf("Unexpected messages: %+v", msgs)
+			}
+			var payload pb.ActivityPayload
+			if err := protojson.Unmarshal(msgs[0].Event.Data(), &payload); err != nil {
+				t.Fatal(err)
+			}
+			std := payload.StandardizedActivity
+			if payload.Source != pb.ActivitySource_SOURCE_STRAVA || payload.GetPipelineExecutionId() != "pe-1" ||
+				std.ExternalId != "42" || std.Type != pb.ActivityType_ACTIVITY_TYPE_RIDE || len(std.Sessions[0].Laps[0].Records) != 2 {
+				t.Errorf("Unexpected payload: %+v", &payload)
+			}
+			if processed, _ := db.HasProcessedActivity(ctx, "user-1", "strava", "42"); !processed {
+				t.Error("Activity not marked processed")
+			}
+		})
+	}
+}
+
+func TestStravaWebhookValidation(t *testing.T) {
+	SetService(&bootstrap.Service{
+		DB:      database.NewMemoryDatabase(),
+		Secrets: &mocks.MockSecretStore{GetSecretFunc: func(ctx context.Context, projectID, name string) (string, error) { return "s3cret", nil }},
+		Config:  &bootstrap.Config{},
+	})
+	defer SetService(nil)
+
+	tests := []struct {
+		name     string
+		token    string
+		wantCode int
+	}{
+		{name: "Matching verify token echoes the challenge", token: "s3cret", wantCode: http.StatusOK},
+		{name: "Wrong verify token is rejected", token: "nope", wantCode: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			StravaWebhook(rec, httptest.NewRequest("GET", "/?hub.mode=subscribe&hub.challenge=abc&hub.verify_token="+tt.token, nil))
+			if rec.Code != tt.wantCode {
+				t.Fatalf("Status code = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if tt.wantCode == http.StatusOK && !strings.Contains(rec.Body.String(), `"hub.challenge":"abc"`) {
+				t.Errorf("Unexpected body: %s", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestStravaWebhookSubscription(t *testing.T) {
+	pub := &infrapubsub.MemoryPublisher{}
+	SetService(&bootstrap.Service{
+		DB:  database.NewMemoryDatabase(),
+		Pub: pub,
+		Secrets: &mocks.MockSecretStore{GetSecretFunc: func(ctx context.Context, projectID, name string) (string, error) {
+			if name != subscriptionIDSecret {
+				t.Errorf("Unexpected secret %q", name)
+			}
+			return "120475", nil
+		}},
+		Config: &bootstrap.Config{},
+	})
+	defer SetService(nil)
+
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+	}{
+		{
+			name:     "Event for the configured subscription is accepted",
+			body:     `{"object_type": "activity", "object_id": 42, "aspect_type": "create", "owner_id": 1, "subscription_id": 120475}`,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Event for another subscription is rejected",
+			body:     `{"object_type": "activity", "object_id": 42, "aspect_type": "create", "owner_id": 1, "subscription_id": 1}`,
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "Event without a subscription is rejected",
+			body:     `{"object_type": "activity", "object_id": 42, "aspect_type": "create", "owner_id": 1}`,
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "Malformed event is rejected",
+			body:     `not json`,
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			StravaWebhook(rec, httptest.NewRequest("POST", "/", strings.NewReader(tt.body)))
+			if rec.Code != tt.wantCode {
+				t.Fatalf("Status code = %d, want %d (%s)", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if len(pub.Messages()) != 0 {
+				t.Errorf("Expected no messages, got %d", len(pub.Messages()))
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package uploadstatuspoller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/functions"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+
+	"github.com/ripixel/fitglue-server/src/go/pkg/bootstrap"
+	"github.com/ripixel/fitglue-server/src/go/pkg/destinations"
+	"github.com/ripixel/fitglue-server/src/go/pkg/framework"
+	infrapubsub "github.com/ripixel/fitglue-server/src/go/pkg/infrastructure/pubsub"
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+
+	// Register destinations
+	_ "github.com/ripixel/fitglue-server/src/go/pkg/destinations/all"
+)
+
+var (
+	svc     *bootstrap.Service
+	svcOnce sync.Once
+	svcErr  error
+)
+
+func init() {
+	// HTTP handler for the push subscription - returns HTTP 500 while uploads are still processing
+	functions.HTTP("CheckUploadStatusHTTP", CheckUploadStatusHTTP)
+}
+
+// SetService overrides the service used by the entry points, bypassing bootstrap.NewService.
+// Used by the local emulator to run the function against in-memory adapters.
+func SetService(s *bootstrap.Service) {
+	svc = s
+}
+
+func initService(ctx context.Context) (*bootstrap.Service, error) {
+	if svc != nil {
+		return svc, nil
+	}
+	svcOnce.Do(func() {
+		svc, svcErr = bootstrap.NewService(ctx)
+		if svcErr != nil {
+			slog.Error("Failed to initialize service", "error", svcErr)
+		}
+	})
+	return svc, svcErr
+}
+
+// CheckUploadStatus checks a pending upload; an error means it should be checked again later
+func CheckUploadStatus(ctx context.Context, e cloudevents.Event) error {
+	svc, err := initService(ctx)
+	if err != nil {
+		return fmt.Errorf("service init failed: %v", err)
+	}
+	return framework.WrapCloudEvent("upload-status-poller", svc, destinations.StatusCheckHandler())(ctx, e)
+}
+
+// CheckUploadStatusHTTP is the HTTP handler for the status-check push subscription.
+// Returning HTTP 500 while an upload is still processing makes Pub/Sub NACK and redeliver
+// the check with the subscription's backoff.
+func CheckUploadStatusHTTP(w http.ResponseWriter, r *http.Request) {
+	event, err := cehttp.NewEventFromHTTPRequest(r)
+	if err != nil {
+		// Fall back to Pub/Sub push message format
+		event, err = parseCloudEventFromPubSubPush(r)
+		if err != nil {
+			slog.Error("Failed to parse event from request", "error", err)
+			http.Error(w, fmt.Sprintf("failed to parse event: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	if err := CheckUploadStatus(r.Context(), *event); err != nil {
+		slog.Error("Status check not resolved, returning 500 for retry", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"status":"ok"}`))
+}
+
+// parseCloudEventFromPubSubPush parses a CloudEvent from a Pub/Sub push message
+// ({"message": {"data": "base64...", "attributes": {...}}, "subscription": "..."}).
+func parseCloudEventFromPubSubPush(r *http.Request) (*cloudevents.Event, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	defer r.Body.Close()
+
+	var pushMsg struct {
+		Message struct {
+			Data       []byte            `json:"data"`
+			Attributes map[string]string `json:"attributes"`
+			MessageID  string            `json:"messageId"`
+		} `json:"message"`
+	}
+	if err := json.Unmarshal(body, &pushMsg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal push message: %w", err)
+	}
+	if len(pushMsg.Message.Data) == 0 {
+		return nil, fmt.Errorf("no data in push message")
+	}
+
+	// The data is normally the published CloudEvent; otherwise wrap the raw payload
+	var event cloudevents.Event
+	if err := json.Unmarshal(pushMsg.Message.Data, &event); err == nil && event.Type() != "" {
+		return &event, nil
+	}
+
+	event = cloudevents.NewEvent()
+	event.SetID(pushMsg.Message.MessageID)
+	event.SetSource(infrapubsub.GetCloudEventSource(pb.CloudEventSource_CLOUD_EVENT_SOURCE_UPLOADER))
+	event.SetType(infrapubsub.GetCloudEventType(pb.CloudEventType_CLOUD_EVENT_TYPE_UPLOAD_STATUS_CHECK))
+	event.SetData(cloudevents.ApplicationJSON, pushMsg.Message.Data)
+	for k, v := range pushMsg.Message.Attributes {
+		event.SetExtension(k, v)
+	}
+	return &event, nil
+}
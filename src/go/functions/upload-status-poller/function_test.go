@@ -0,0 +1,91 @@
+package uploadstatuspoller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/ripixel/fitglue-server/src/go/pkg/bootstrap"
+	"github.com/ripixel/fitglue-server/src/go/pkg/infrastructure/database"
+	infrapubsub "github.com/ripixel/fitglue-server/src/go/pkg/infrastructure/pubsub"
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+)
+
+func TestCheckUploadStatusHTTP(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		destination string
+		wantCode    int
+		wantStatus  pb.PendingUpload_Status
+	}{
+		{
+			name:        "Resolved upload is acknowledged",
+			destination: "mock",
+			wantCode:    http.StatusOK,
+			wantStatus:  pb.PendingUpload_STATUS_COMPLETED,
+		},
+		{
+			// No Strava integration for the user, so the status check itself fails
+			name:        "Unresolved upload returns 500 for redelivery",
+			destination: "strava",
+			wantCode:    http.StatusInternalServerError,
+			wantStatus:  pb.PendingUpload_STATUS_PROCESSING,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := database.NewMemoryDatabase()
+			SetService(&bootstrap.Service{DB: db, Config: &bootstrap.Config{}})
+			defer SetService(nil)
+
+			pending := &pb.PendingUpload{
+				Id:            tt.destination + "-123",
+				UserId:        "user-1",
+				Destination:   tt.destination,
+				UploadId:      "123",
+				Status:        pb.PendingUpload_STATUS_PROCESSING,
+				OriginalEvent: &pb.EnrichedActivityEvent{ActivityId: "a1", UserId: "user-1"},
+				CreatedAt:     timestamppb.New(time.Now()),
+			}
+			db.CreatePendingUpload(ctx, pending)
+
+			// Pub/Sub push envelope around the published CloudEvent
+			e, err := infrapubsub.NewCloudEvent("/core/uploader", "com.fitglue.upload.status_check", &pb.UploadStatusCheck{
+				PendingUploadId: pending.Id,
+				UserId:          "user-1",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			e.SetID("check-1")
+			eventBytes, _ := json.Marshal(e)
+			body, _ := json.Marshal(map[string]interface{}{
+				"message":      map[string]interface{}{"data": eventBytes, "messageId": "msg-1"},
+				"subscription": "projects/test/subscriptions/sub-upload-status-check",
+			})
+
+			rec := httptest.NewRecorder()
+			CheckUploadStatusHTTP(rec, httptest.NewRequest("POST", "/", bytes.NewReader(body)))
+			if rec.Code != tt.wantCode {
+				t.Errorf("Status code = %d, want %d (%s)", rec.Code, tt.wantCode, rec.Body.String())
+			}
+
+			got, err := db.GetPendingUpload(ctx, pending.Id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Status != tt.wantStatus {
+				t.Errorf("Pending status = %v, want %v", got.Status, tt.wantStatus)
+			}
+		})
+	}
+}
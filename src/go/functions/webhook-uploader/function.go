@@ -0,0 +1,57 @@
+package webhookuploader
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/functions"
+	"github.com/cloudevents/sdk-go/v2/event"
+
+	"github.com/ripixel/fitglue-server/src/go/pkg/bootstrap"
+	"github.com/ripixel/fitglue-server/src/go/pkg/destinations"
+	"github.com/ripixel/fitglue-server/src/go/pkg/destinations/webhook"
+	"github.com/ripixel/fitglue-server/src/go/pkg/framework"
+)
+
+var (
+	svc     *bootstrap.Service
+	svcOnce sync.Once
+	svcErr  error
+)
+
+func init() {
+	functions.CloudEvent("UploadToWebhook", UploadToWebhook)
+}
+
+// SetService overrides the service used by the entry points, bypassing bootstrap.NewService.
+// Used by the local emulator to run the function against in-memory adapters.
+func SetService(s *bootstrap.Service) {
+	svc = s
+}
+
+func initService(ctx context.Context) (*bootstrap.Service, error) {
+	if svc != nil {
+		return svc, nil
+	}
+	svcOnce.Do(func() {
+		baseSvc, err := bootstrap.NewService(ctx)
+		if err != nil {
+			slog.Error("Failed to initialize service", "error", err)
+			svcErr = err
+			return
+		}
+		svc = baseSvc
+	})
+	return svc, svcErr
+}
+
+// UploadToWebhook is the entry point
+func UploadToWebhook(ctx context.Context, e event.Event) error {
+	svc, err := initService(ctx)
+	if err != nil {
+		return fmt.Errorf("service init failed: %v", err)
+	}
+	return framework.WrapCloudEvent("webhook-uploader", svc, destinations.UploadHandler(&webhook.WebhookDestination{}))(ctx, e)
+}
@@ -3,13 +3,19 @@ package shared
 const (
 	ProjectID = "fitglue-project" // Can be overridden by env var in main if needed
 
-	TopicRawActivity      = "topic-raw-activity"
-	TopicEnrichedActivity = "topic-enriched-activity"
-	TopicJobUploadStrava  = "topic-job-upload-strava"
-	TopicFitbitUpdates    = "topic-fitbit-updates"
-	TopicEnrichmentLag    = "topic-enrichment-lag"
+	TopicRawActivity       = "topic-raw-activity"
+	TopicEnrichedActivity  = "topic-enriched-activity"
+	TopicJobUploadStrava   = "topic-job-upload-strava"
+	TopicFitbitUpdates     = "topic-fitbit-updates"
+	TopicEnrichmentLag     = "topic-enrichment-lag"
+	TopicUploadStatusCheck = "topic-upload-status-check"
+	TopicSourceChange      = "topic-source-activity-change"
 
 	CollectionUsers      = "users"
 	CollectionCursors    = "cursors"
 	CollectionExecutions = "executions"
 )
+
+// UploadExternalIDPrefix prefixes the external ID FitGlue gives its destination uploads (e.g. the
+// Strava uploads API external_id), so sources can recognise and skip activities FitGlue created
+const UploadExternalIDPrefix = "fitglue-"
@@ -0,0 +1,11 @@
+// Package all registers every destination. Functions that look destinations up by name
+// (the upload-status-poller and change-propagator) import it, so they can't miss one.
+package all
+
+import (
+	_ "github.com/ripixel/fitglue-server/src/go/pkg/destinations/hevy"
+	_ "github.com/ripixel/fitglue-server/src/go/pkg/destinations/intervals_icu"
+	_ "github.com/ripixel/fitglue-server/src/go/pkg/destinations/mock"
+	_ "github.com/ripixel/fitglue-server/src/go/pkg/destinations/strava"
+	_ "github.com/ripixel/fitglue-server/src/go/pkg/destinations/webhook"
+)
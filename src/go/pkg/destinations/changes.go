@@ -0,0 +1,322 @@
+package destinations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"cloud.google.com/go/firestore"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	shared "github.com/ripixel/fitglue-server/src/go/pkg"
+	"github.com/ripixel/fitglue-server/src/go/pkg/framework"
+	infrapubsub "github.com/ripixel/fitglue-server/src/go/pkg/infrastructure/pubsub"
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+)
+
+// Per-copy outcomes recorded in the change-propagator's outputs
+const (
+	changeDeleted      = "deleted"
+	changeUpdated      = "updated"
+	changeNotSupported = "not_supported"
+	changeUnchanged    = "unchanged"
+)
+
+// SourceChangeHandler returns the framework handler for the change-propagator: it applies a
+// SourceActivityChange to the destination copies recorded in the activity's
+// SynchronizedActivity records, according to the user's SourceChangePolicy.
+//   - IGNORE (the default) leaves the destinations unchanged.
+//   - UPDATE_METADATA applies the source's name, description and type edits with
+//     Destination.Update. Only fields the source changed are touched, and what enrichers
+//     appended to them (name suffixes, description sections) is kept; a field an enricher
+//     replaced outright keeps the pipeline's value. Enrichers are not re-run.
+//   - FULL_RESYNC re-runs the pipeline with ActivityPayload.resync set, so each uploader
+//     updates its existing copy with the re-enriched activity.
+//
+// Under both UPDATE_METADATA and FULL_RESYNC a source deletion calls Destination.Delete.
+// Destinations that can't update or delete (ErrNotSupported) keep their copy as it is.
+// Any other failure fails the execution so Pub/Sub redelivers the change; copies already
+// deleted are removed from their SynchronizedActivity, so a redelivery only retries the rest.
+func SourceChangeHandler() framework.HandlerFunc {
+	return func(ctx context.Context, e event.Event, fwCtx *framework.FrameworkContext) (interface{}, error) {
+		var change pb.SourceActivityChange
+		unmarshaler := protojson.UnmarshalOptions{DiscardUnknown: true}
+		if err := unmarshaler.Unmarshal(e.Data(), &change); err != nil {
+			return nil, fmt.Errorf("protojson.Unmarshal: %w", err)
+		}
+
+		outputs := map[string]interface{}{
+			"source":      change.Source.String(),
+			"external_id": change.ExternalId,
+			"change":      change.Change.String(),
+		}
+
+		user, err := fwCtx.Service.DB.GetUser(ctx, change.UserId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user: %w", err)
+		}
+		policy := user.SourceChangePolicy
+		outputs["policy"] = policy.String()
+		if policy == pb.SourceChangePolicy_SOURCE_CHANGE_POLICY_UNSPECIFIED || policy == pb.SourceChangePolicy_SOURCE_CHANGE_POLICY_IGNORE {
+			fwCtx.Logger.Info("Source changes are ignored for this user")
+			outputs["status"] = "SKIPPED"
+			return outputs, nil
+		}
+
+		synced, err := fwCtx.Service.DB.FindSynchronizedActivities(ctx, change.UserId, change.Source.String(), change.ExternalId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up synchronized activities: %w", err)
+		}
+		if len(synced) == 0 {
+			fwCtx.Logger.Info("Activity was never synchronized, nothing to propagate")
+			outputs["status"] = "SKIPPED"
+			return outputs, nil
+		}
+
+		switch change.Change {
+		case pb.SourceChangeType_SOURCE_CHANGE_TYPE_DELETED:
+			return deleteCopies(ctx, fwCtx, change.UserId, synced, outputs)
+		case pb.SourceChangeType_SOURCE_CHANGE_TYPE_UPDATED:
+			if change.StandardizedActivity == nil {
+				return nil, fmt.Errorf("update for %s has no activity", change.ExternalId)
+			}
+			if policy == pb.SourceChangePolicy_SOURCE_CHANGE_POLICY_FULL_RESYNC {
+				return resyncActivity(ctx, fwCtx, &change, outputs)
+			}
+			return updateCopies(ctx, fwCtx, user, change.StandardizedActivity, synced, outputs)
+		default:
+			return nil, fmt.Errorf("unknown source change type %v", change.Change)
+		}
+	}
+}
+
+// deleteCopies deletes every destination copy of the activity
+func deleteCopies(ctx context.Context, fwCtx *framework.FrameworkContext, userID string, synced []*pb.SynchronizedActivity, outputs map[string]interface{}) (interface{}, error) {
+	results := make(map[string]string)
+	var failed []string
+	for _, s := range synced {
+		for _, name := range sortedDestinations(s) {
+			externalID := s.Destinations[name]
+			key := name + ":" + externalID
+			dest, ok := GetByName(name)
+			if !ok {
+				results[key] = changeNotSupported
+				continue
+			}
+
+			err := dest.Delete(ctx, &Request{
+				Event:      syncedEvent(userID, s),
+				ExternalID: externalID,
+				Service:    fwCtx.Service,
+				Logger:     fwCtx.Logger,
+			})
+			switch {
+			case errors.Is(err, ErrNotSupported):
+				fwCtx.Logger.Info("Destination cannot delete activities, leaving it", "destination", name, "external_id", externalID)
+				results[key] = changeNotSupported
+			case err != nil:
+				fwCtx.Logger.Error("Delete failed", "destination", name, "external_id", externalID, "error", err)
+				results[key] = err.Error()
+				failed = append(failed, key)
+			default:
+				results[key] = changeDeleted
+				if err := fwCtx.Service.DB.UpdateSynchronizedActivity(ctx, userID, s.ActivityId, map[string]interface{}{
+					"destinations": map[string]interface{}{name: firestore.Delete},
+				}); err != nil {
+					fwCtx.Logger.Error("Failed to remove deleted destination from synchronized activity", "activity_id", s.ActivityId, "error", err)
+				}
+			}
+		}
+	}
+	return changeOutputs(outputs, results, failed)
+}
+
+// updateCopies applies the source's name, description and type edits to every destination copy
+func updateCopies(ctx context.Context, fwCtx *framework.FrameworkContext, user *pb.UserRecord, act *pb.StandardizedActivity, synced []*pb.SynchronizedActivity, outputs map[string]interface{}) (interface{}, error) {
+	results := make(map[string]string)
+	var failed []string
+	for _, s := range synced {
+		ev := syncedEvent(user.UserId, s)
+		changed := applySourceEdits(ev, s, act)
+		ev.DestinationOptions = pipelineDestinationOptions(user, s.PipelineId)
+
+		updated := false
+		for _, name := range sortedDestinations(s) {
+			externalID := s.Destinations[name]
+			key := name + ":" + externalID
+			if !changed {
+				results[key] = changeUnchanged
+				continue
+			}
+			dest, ok := GetByName(name)
+			if !ok {
+				results[key] = changeNotSupported
+				continue
+			}
+
+			res, err := dest.Update(ctx, &Request{
+				Event:      ev,
+				ExternalID: externalID,
+				Service:    fwCtx.Service,
+				Logger:     fwCtx.Logger,
+			})
+			switch {
+			case errors.Is(err, ErrNotSupported):
+				results[key] = changeNotSupported
+			case err != nil:
+				fwCtx.Logger.Error("Update failed", "destination", name, "external_id", externalID, "error", err)
+				results[key] = err.Error()
+				failed = append(failed, key)
+			case res.Status == StatusFailed:
+				results[key] = res.Error
+				failed = append(failed, key)
+			default:
+				results[key] = changeUpdated
+				updated = true
+			}
+		}
+
+		if updated {
+			if err := fwCtx.Service.DB.UpdateSynchronizedActivity(ctx, user.UserId, s.ActivityId, map[string]interface{}{
+				"title":              ev.Name,
+				"description":        ev.Description,
+				"type":               int32(ev.ActivityType),
+				"source_title":       act.Name,
+				"source_description": act.Description,
+				"source_type":        int32(act.Type),
+				"synced_at":          timestamppb.Now().AsTime(),
+			}); err != nil {
+				fwCtx.Logger.Error("Failed to update synchronized activity", "activity_id", s.ActivityId, "error", err)
+			}
+		}
+	}
+	return changeOutputs(outputs, results, failed)
+}
+
+// applySourceEdits updates ev (built from s) with the fields the source changed since s was
+// synced, keeping what the pipeline added, and reports whether any field changed. Fields
+// the pipeline replaced outright, and fields of copies synced before the source's own
+// metadata was recorded, are left as they are.
+func applySourceEdits(ev *pb.EnrichedActivityEvent, s *pb.SynchronizedActivity, act *pb.StandardizedActivity) bool {
+	changed := false
+	// Enrichers append name suffixes directly and description sections after a blank line
+	if name, ok := rebaseSourceText(ev.Name, s.SourceTitle, act.Name, ""); ok {
+		ev.Name, changed = name, true
+	}
+	if description, ok := rebaseSourceText(ev.Description, s.SourceDescription, act.Description, "\n\n"); ok {
+		ev.Description, changed = description, true
+	}
+	if s.SourceType != pb.ActivityType_ACTIVITY_TYPE_UNSPECIFIED && s.SourceType != act.Type && ev.ActivityType == s.SourceType {
+		ev.ActivityType, changed = act.Type, true
+	}
+	return changed
+}
+
+// rebaseSourceText replaces the old source value at the start of the pipeline's value with the
+// new one, keeping what enrichers appended after it (separated by sep). It reports false if the
+// source value is unknown or unchanged, or if the pipeline's value doesn't start with it.
+func rebaseSourceText(current string, oldSource *string, newSource, sep string) (string, bool) {
+	if oldSource == nil || *oldSource == newSource {
+		return current, false
+	}
+	old := *oldSource
+	if old == "" {
+		// Nothing to replace: only appended sections can be kept apart from a new value
+		if sep == "" && current != "" {
+			return current, false
+		}
+		if current == "" {
+			return newSource, true
+		}
+		return newSource + sep + current, true
+	}
+	if !strings.HasPrefix(current, old) {
+		return current, false
+	}
+	rest := current[len(old):]
+	if newSource == "" {
+		return strings.TrimPrefix(rest, sep), true
+	}
+	return newSource + rest, true
+}
+
+// resyncActivity re-runs the user's pipelines for the updated activity
+func resyncActivity(ctx context.Context, fwCtx *framework.FrameworkContext, change *pb.SourceActivityChange, outputs map[string]interface{}) (interface{}, error) {
+	payload := &pb.ActivityPayload{
+		Source:               change.Source,
+		UserId:               change.UserId,
+		Timestamp:            timestamppb.Now(),
+		StandardizedActivity: change.StandardizedActivity,
+		Metadata:             map[string]string{"source_change": change.Change.String()},
+		PipelineExecutionId:  &fwCtx.PipelineExecutionId,
+		Resync:               true,
+	}
+	e, err := infrapubsub.NewCloudEvent(
+		infrapubsub.GetCloudEventSource(pb.CloudEventSource_CLOUD_EVENT_SOURCE_CHANGE_PROPAGATOR),
+		infrapubsub.GetCloudEventType(pb.CloudEventType_CLOUD_EVENT_TYPE_ACTIVITY_CREATED),
+		payload,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resync event: %w", err)
+	}
+	e.SetExtension("pipeline_execution_id", fwCtx.PipelineExecutionId)
+
+	messageID, err := fwCtx.Service.Pub.PublishCloudEvent(ctx, shared.TopicRawActivity, e)
+	if err != nil {
+		return nil, fmt.Errorf("failed to publish resync: %w", err)
+	}
+	fwCtx.Logger.Info("Published activity for resync", "message_id", messageID)
+
+	outputs["status"] = "SUCCESS"
+	outputs["resync_message_id"] = messageID
+	return outputs, nil
+}
+
+// changeOutputs records per-copy results and fails the execution if any copy failed
+func changeOutputs(outputs map[string]interface{}, results map[string]string, failed []string) (interface{}, error) {
+	outputs["destinations"] = results
+	if len(failed) > 0 {
+		outputs["status"] = "FAILED"
+		return outputs, fmt.Errorf("failed to propagate source change to %v", failed)
+	}
+	outputs["status"] = "SUCCESS"
+	return outputs, nil
+}
+
+// syncedEvent rebuilds the EnrichedActivityEvent fields a destination needs from a SynchronizedActivity
+func syncedEvent(userID string, s *pb.SynchronizedActivity) *pb.EnrichedActivityEvent {
+	return &pb.EnrichedActivityEvent{
+		ActivityId:   s.ActivityId,
+		UserId:       userID,
+		PipelineId:   s.PipelineId,
+		Name:         s.Title,
+		Description:  s.Description,
+		ActivityType: s.Type,
+		StartTime:    s.StartTime,
+		Source:       pb.ActivitySource(pb.ActivitySource_value[s.Source]),
+	}
+}
+
+// pipelineDestinationOptions returns the destination options of a user's pipeline (nil if it no longer exists)
+func pipelineDestinationOptions(user *pb.UserRecord, pipelineID string) map[string]*pb.DestinationOptions {
+	for _, p := range user.Pipelines {
+		if p.Id == pipelineID {
+			return p.DestinationOptions
+		}
+	}
+	return nil
+}
+
+// sortedDestinations returns a SynchronizedActivity's destination names in a stable order
+func sortedDestinations(s *pb.SynchronizedActivity) []string {
+	names := make([]string, 0, len(s.Destinations))
+	for name := range s.Destinations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,302 @@
+package destinations
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	shared "github.com/ripixel/fitglue-server/src/go/pkg"
+	"github.com/ripixel/fitglue-server/src/go/pkg/bootstrap"
+	"github.com/ripixel/fitglue-server/src/go/pkg/framework"
+	"github.com/ripixel/fitglue-server/src/go/pkg/infrastructure/database"
+	infrapubsub "github.com/ripixel/fitglue-server/src/go/pkg/infrastructure/pubsub"
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+)
+
+// changeDestination records Update and Delete calls
+type changeDestination struct {
+	name      string
+	deleteErr error
+	updateErr error
+	deleted   []string
+	updated   []*Request
+}
+
+func (d *changeDestination) Name() string { return d.name }
+func (d *changeDestination) DestinationType() pb.Destination {
+	return pb.Destination_DESTINATION_UNSPECIFIED
+}
+func (d *changeDestination) Upload(context.Context, *Request) (*Result, error) {
+	return nil, ErrNotSupported
+}
+func (d *changeDestination) Status(context.Context, *Request) (*Result, error) {
+	return nil, ErrNotSupported
+}
+func (d *changeDestination) Update(ctx context.Context, req *Request) (*Result, error) {
+	if d.updateErr != nil {
+		return nil, d.updateErr
+	}
+	d.updated = append(d.updated, req)
+	return &Result{Status: StatusComplete, ExternalID: req.ExternalID}, nil
+}
+func (d *changeDestination) Delete(ctx context.Context, req *Request) error {
+	if d.deleteErr != nil {
+		return d.deleteErr
+	}
+	d.deleted = append(d.deleted, req.ExternalID)
+	return nil
+}
+
+func TestSourceChangeHandler(t *testing.T) {
+	ctx := context.Background()
+	edited := &pb.StandardizedActivity{ExternalId: "hevy-1", Name: "Leg Day (edited)", Description: "PB squat", Type: pb.ActivityType_ACTIVITY_TYPE_WEIGHT_TRAINING}
+	// The enrichers' suffix and description section are kept
+	wantName, wantDescription := "Leg Day (edited) #12", "PB squat\n\nVolume: 12,000kg"
+
+	tests := []struct {
+		name        string
+		policy      pb.SourceChangePolicy
+		change      pb.SourceChangeType
+		deleteErr   error
+		updateErr   error
+		wantErr     bool
+		wantStatus  string
+		wantDeleted []string
+		wantUpdated bool
+		wantResync  bool
+		wantDests   map[string]string // SynchronizedActivity destinations afterwards
+	}{
+		{
+			name:       "Ignored by default",
+			change:     pb.SourceChangeType_SOURCE_CHANGE_TYPE_DELETED,
+			wantStatus: "SKIPPED",
+			wantDests:  map[string]string{"alpha": "a-1", "beta": "b-1"},
+		},
+		{
+			name:        "Deletion removes destination copies",
+			policy:      pb.SourceChangePolicy_SOURCE_CHANGE_POLICY_UPDATE_METADATA,
+			change:      pb.SourceChangeType_SOURCE_CHANGE_TYPE_DELETED,
+			wantStatus:  "SUCCESS",
+			wantDeleted: []string{"a-1", "b-1"},
+			wantDests:   map[string]string{},
+		},
+		{
+			name:       "Destinations that cannot delete keep their copy",
+			policy:     pb.SourceChangePolicy_SOURCE_CHANGE_POLICY_FULL_RESYNC,
+			change:     pb.SourceChangeType_SOURCE_CHANGE_TYPE_DELETED,
+			deleteErr:  ErrNotSupported,
+			wantStatus: "SUCCESS",
+			wantDests:  map[string]string{"alpha": "a-1", "beta": "b-1"},
+		},
+		{
+			name:       "Failed deletion is retried",
+			policy:     pb.SourceChangePolicy_SOURCE_CHANGE_POLICY_UPDATE_METADATA,
+			change:     pb.SourceChangeType_SOURCE_CHANGE_TYPE_DELETED,
+			deleteErr:  errors.New("unavailable"),
+			wantErr:    true,
+			wantStatus: "FAILED",
+			wantDests:  map[string]string{"alpha": "a-1", "beta": "b-1"},
+		},
+		{
+			name:        "Metadata update applies the source's edits",
+			policy:      pb.SourceChangePolicy_SOURCE_CHANGE_POLICY_UPDATE_METADATA,
+			change:      pb.SourceChangeType_SOURCE_CHANGE_TYPE_UPDATED,
+			wantStatus:  "SUCCESS",
+			wantUpdated: true,
+			wantDests:   map[string]string{"alpha": "a-1", "beta": "b-1"},
+		},
+		{
+			name:       "Full resync re-runs the pipeline",
+			policy:     pb.SourceChangePolicy_SOURCE_CHANGE_POLICY_FULL_RESYNC,
+			change:     pb.SourceChangeType_SOURCE_CHANGE_TYPE_UPDATED,
+			wantStatus: "SUCCESS",
+			wantResync: true,
+			wantDests:  map[string]string{"alpha": "a-1", "beta": "b-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ClearRegistry()
+			defer ClearRegistry()
+			alpha := &changeDestination{name: "alpha", deleteErr: tt.deleteErr, updateErr: tt.updateErr}
+			beta := &changeDestination{name: "beta", deleteErr: tt.deleteErr, updateErr: tt.updateErr}
+			Register(alpha)
+			Register(beta)
+
+			db := database.NewMemoryDatabase()
+			db.SetUser(ctx, &pb.UserRecord{
+				UserId:             "u1",
+				SourceChangePolicy: tt.policy,
+				Pipelines: []*pb.PipelineConfig{{
+					Id:                 "p1",
+					Source:             "SOURCE_HEVY",
+					DestinationOptions: map[string]*pb.DestinationOptions{"alpha": {GearId: "g1"}},
+				}},
+			})
+			sourceTitle, sourceDescription := "Leg Day", "Heavy"
+			db.SetSynchronizedActivity(ctx, "u1", &pb.SynchronizedActivity{
+				ActivityId:        "act-1",
+				Title:             "Leg Day #12",
+				Description:       "Heavy\n\nVolume: 12,000kg",
+				Type:              pb.ActivityType_ACTIVITY_TYPE_WORKOUT,
+				Source:            "SOURCE_HEVY",
+				ExternalId:        "hevy-1",
+				PipelineId:        "p1",
+				Destinations:      map[string]string{"alpha": "a-1", "beta": "b-1"},
+				SourceTitle:       &sourceTitle,
+				SourceDescription: &sourceDescription,
+				SourceType:        pb.ActivityType_ACTIVITY_TYPE_WORKOUT,
+			})
+			// Another source activity's copies are left alone
+			db.SetSynchronizedActivity(ctx, "u1", &pb.SynchronizedActivity{
+				ActivityId:   "act-2",
+				Source:       "SOURCE_HEVY",
+				ExternalId:   "hevy-2",
+				PipelineId:   "p1",
+				Destinations: map[string]string{"alpha": "a-2"},
+			})
+
+			pub := &infrapubsub.MemoryPublisher{}
+			fwCtx := &framework.FrameworkContext{
+				Service:             &bootstrap.Service{DB: db, Pub: pub},
+				Logger:              slog.Default(),
+				PipelineExecutionId: "pe1",
+			}
+
+			change := &pb.SourceActivityChange{UserId: "u1", Source: pb.ActivitySource_SOURCE_HEVY, ExternalId: "hevy-1", Change: tt.change}
+			if tt.change == pb.SourceChangeType_SOURCE_CHANGE_TYPE_UPDATED {
+				change.StandardizedActivity = edited
+			}
+			e := event.New()
+			e.SetType("com.fitglue.activity.source_changed")
+			e.SetSource("/test")
+			data, _ := protojson.Marshal(change)
+			e.SetData(event.ApplicationJSON, data)
+
+			out, err := SourceChangeHandler()(ctx, e, fwCtx)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := out.(map[string]interface{})["status"]; got != tt.wantStatus {
+				t.Errorf("Status = %v, want %v (%v)", got, tt.wantStatus, out)
+			}
+
+			deleted := append(alpha.deleted, beta.deleted...)
+			if len(deleted) != len(tt.wantDeleted) {
+				t.Errorf("Deleted %v, want %v", deleted, tt.wantDeleted)
+			}
+
+			if tt.wantUpdated {
+				if len(alpha.updated) != 1 || len(beta.updated) != 1 {
+					t.Fatalf("Expected one update per destination, got %d and %d", len(alpha.updated), len(beta.updated))
+				}
+				req := alpha.updated[0]
+				if req.ExternalID != "a-1" || req.Event.Name != wantName || req.Event.Description != wantDescription || req.Event.ActivityType != edited.Type || req.Event.DestinationOptions["alpha"].GetGearId() != "g1" {
+					t.Errorf("Unexpected update request: %+v", req)
+				}
+			} else if len(alpha.updated)+len(beta.updated) > 0 {
+				t.Error("Expected no updates")
+			}
+
+			msgs := pub.Messages()
+			if tt.wantResync {
+				if len(msgs) != 1 || msgs[0].Topic != shared.TopicRawActivity {
+					t.Fatalf("Expected one raw activity message, got %+v", msgs)
+				}
+				var payload pb.ActivityPayload
+				if err := protojson.Unmarshal(msgs[0].Event.Data(), &payload); err != nil {
+					t.Fatal(err)
+				}
+				if !payload.Resync || payload.StandardizedActivity.GetName() != edited.Name || payload.Source != pb.ActivitySource_SOURCE_HEVY {
+					t.Errorf("Unexpected resync payload: %+v", &payload)
+				}
+			} else if len(msgs) != 0 {
+				t.Errorf("Expected no messages, got %d", len(msgs))
+			}
+
+			synced, err := db.GetSynchronizedActivity(ctx, "u1", "act-1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(synced.Destinations) != len(tt.wantDests) {
+				t.Errorf("Destinations = %v, want %v", synced.Destinations, tt.wantDests)
+			}
+			if tt.wantUpdated && (synced.Title != wantName || synced.GetSourceTitle() != edited.Name || synced.SourceType != edited.Type) {
+				t.Errorf("Unexpected synchronized activity after update: %+v", synced)
+			}
+			if other, _ := db.GetSynchronizedActivity(ctx, "u1", "act-2"); other.Destinations["alpha"] != "a-2" {
+				t.Errorf("Unrelated activity changed: %+v", other)
+			}
+		})
+	}
+}
+
+func TestApplySourceEdits(t *testing.T) {
+	str := func(s string) *string { return &s }
+	act := &pb.StandardizedActivity{Name: "Evening Run", Description: "Easy", Type: pb.ActivityType_ACTIVITY_TYPE_RUN}
+
+	tests := []struct {
+		name        string
+		synced      *pb.SynchronizedActivity
+		wantChanged bool
+		wantName    string
+		wantDesc    string
+		wantType    pb.ActivityType
+	}{
+		{
+			name:        "Suffix and appended sections are kept",
+			synced:      &pb.SynchronizedActivity{Title: "Morning Run (Parkrun #4)", Description: "Tempo\n\nPowered by FitGlue", Type: pb.ActivityType_ACTIVITY_TYPE_WALK, SourceTitle: str("Morning Run"), SourceDescription: str("Tempo"), SourceType: pb.ActivityType_ACTIVITY_TYPE_WALK},
+			wantChanged: true,
+			wantName:    "Evening Run (Parkrun #4)",
+			wantDesc:    "Easy\n\nPowered by FitGlue",
+			wantType:    pb.ActivityType_ACTIVITY_TYPE_RUN,
+		},
+		{
+			name:        "Description added to an enriched-only description",
+			synced:      &pb.SynchronizedActivity{Title: "Evening Run", Description: "Powered by FitGlue", Type: pb.ActivityType_ACTIVITY_TYPE_RUN, SourceTitle: str("Evening Run"), SourceDescription: str(""), SourceType: pb.ActivityType_ACTIVITY_TYPE_RUN},
+			wantChanged: true,
+			wantName:    "Evening Run",
+			wantDesc:    "Easy\n\nPowered by FitGlue",
+			wantType:    pb.ActivityType_ACTIVITY_TYPE_RUN,
+		},
+		{
+			name:        "Values the pipeline replaced are kept",
+			synced:      &pb.SynchronizedActivity{Title: "Parkrun #4", Description: "Tempo", Type: pb.ActivityType_ACTIVITY_TYPE_TRAIL_RUN, SourceTitle: str("Morning Run"), SourceDescription: str("Tempo"), SourceType: pb.ActivityType_ACTIVITY_TYPE_WALK},
+			wantChanged: true,
+			wantName:    "Parkrun #4",
+			wantDesc:    "Easy",
+			wantType:    pb.ActivityType_ACTIVITY_TYPE_TRAIL_RUN,
+		},
+		{
+			name:     "Unchanged source fields are not pushed",
+			synced:   &pb.SynchronizedActivity{Title: "Evening Run #2", Description: "Easy", Type: pb.ActivityType_ACTIVITY_TYPE_RUN, SourceTitle: str("Evening Run"), SourceDescription: str("Easy"), SourceType: pb.ActivityType_ACTIVITY_TYPE_RUN},
+			wantName: "Evening Run #2",
+			wantDesc: "Easy",
+			wantType: pb.ActivityType_ACTIVITY_TYPE_RUN,
+		},
+		{
+			name:     "Copies synced without source metadata are left alone",
+			synced:   &pb.SynchronizedActivity{Title: "Morning Run #2", Description: "Tempo", Type: pb.ActivityType_ACTIVITY_TYPE_WALK},
+			wantName: "Morning Run #2",
+			wantDesc: "Tempo",
+			wantType: pb.ActivityType_ACTIVITY_TYPE_WALK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ev := syncedEvent("u1", tt.synced)
+			if changed := applySourceEdits(ev, tt.synced, act); changed != tt.wantChanged {
+				t.Errorf("changed = %v, want %v", changed, tt.wantChanged)
+			}
+			if ev.Name != tt.wantName || ev.Description != tt.wantDesc || ev.ActivityType != tt.wantType {
+				t.Errorf("Got %q / %q / %v, want %q / %q / %v", ev.Name, ev.Description, ev.ActivityType, tt.wantName, tt.wantDesc, tt.wantType)
+			}
+		})
+	}
+}
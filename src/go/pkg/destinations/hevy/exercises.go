@@ -0,0 +1,267 @@
+package hevy
+
+import (
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/ripixel/fitglue-server/src/go/pkg/domain/file_generators"
+	"github.com/ripixel/fitglue-server/src/go/pkg/enricher_providers/muscle_heatmap"
+	hevyapi "github.com/ripixel/fitglue-server/src/go/pkg/integrations/hevy"
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+)
+
+// unknownExercise names sets without an exercise name, as the Hevy source does
+const unknownExercise = "Unknown Exercise"
+
+// equipmentSuffix matches Hevy's "Bench Press (Barbell)" title style
+var equipmentSuffix = regexp.MustCompile(`^(.+?)\s*\(([^)]+)\)$`)
+
+// exercise is a Hevy exercise rebuilt from the activity's flat list of StrengthSets
+type exercise struct {
+	name       string
+	supersetID string
+	sets       []*pb.StrengthSet
+}
+
+// groupExercises rebuilds Hevy exercises from StrengthSets, reversing the Hevy source's
+// flattening: consecutive sets of the same exercise form one exercise. Within a superset
+// (a run of sets sharing a superset ID) sets may alternate between exercises, so each set
+// joins the superset's exercise of the same name.
+func groupExercises(sets []*pb.StrengthSet) []*exercise {
+	var exercises []*exercise
+	runStart, runSuperset := 0, ""
+	for _, set := range sets {
+		name := set.ExerciseName
+		if name == "" {
+			name = unknownExercise
+		}
+		if set.SupersetId != runSuperset {
+			runSuperset = set.SupersetId
+			runStart = len(exercises)
+		}
+
+		var ex *exercise
+		if runSuperset != "" {
+			for _, e := range exercises[runStart:] {
+				if e.name == name {
+					ex = e
+					break
+				}
+			}
+		} else if n := len(exercises); n > runStart && exercises[n-1].name == name {
+			ex = exercises[n-1]
+		}
+		if ex == nil {
+			ex = &exercise{name: name, supersetID: runSuperset}
+			exercises = append(exercises, ex)
+		}
+		ex.sets = append(ex.sets, set)
+	}
+	return exercises
+}
+
+// notes returns the exercise's notes (the Hevy source copies them onto every set)
+func (e *exercise) notes() string {
+	for _, s := range e.sets {
+		if s.Notes != "" {
+			return s.Notes
+		}
+	}
+	return ""
+}
+
+// hevySets converts the exercise's sets, keeping their set types
+func (e *exercise) hevySets() []hevyapi.PostWorkoutsRequestSet {
+	sets := make([]hevyapi.PostWorkoutsRequestSet, 0, len(e.sets))
+	for _, s := range e.sets {
+		setType := hevyapi.PostWorkoutsRequestSetType(file_generators.NormalizeSetType(s.SetType))
+		set := hevyapi.PostWorkoutsRequestSet{Type: &setType}
+		if s.Reps > 0 {
+			set.Reps = intPtr(int(s.Reps))
+		}
+		if s.WeightKg > 0 {
+			weight := float32(s.WeightKg)
+			set.WeightKg = &weight
+		}
+		if s.DurationSeconds > 0 {
+			set.DurationSeconds = intPtr(int(s.DurationSeconds))
+		}
+		if s.DistanceMeters > 0 {
+			set.DistanceMeters = intPtr(int(math.Round(s.DistanceMeters)))
+		}
+		sets = append(sets, set)
+	}
+	return sets
+}
+
+// customExercise describes the exercise as a Hevy custom exercise template, for exercises
+// without a matching template. Muscle groups come from the sets, falling back to the
+// muscle_heatmap taxonomy.
+func (e *exercise) customExercise() hevyapi.CreateCustomExerciseRequestBody {
+	primary := e.sets[0].PrimaryMuscleGroup
+	secondary := e.sets[0].SecondaryMuscleGroups
+	if primary == pb.MuscleGroup_MUSCLE_GROUP_UNSPECIFIED {
+		lookup := muscle_heatmap.LookupExercise(e.name)
+		primary, secondary = lookup.Primary, lookup.Secondary
+	}
+
+	title := e.name
+	muscle := muscleGroup(primary)
+	others := make([]hevyapi.MuscleGroup, 0, len(secondary))
+	for _, g := range secondary {
+		others = append(others, muscleGroup(g))
+	}
+	exerciseType := e.exerciseType()
+	equipment := equipmentCategory(e.name)
+
+	var body hevyapi.CreateCustomExerciseRequestBody
+	body.Exercise.Title = &title
+	body.Exercise.MuscleGroup = &muscle
+	body.Exercise.OtherMuscles = &others
+	body.Exercise.ExerciseType = &exerciseType
+	body.Exercise.EquipmentCategory = &equipment
+	return body
+}
+
+// exerciseType picks the Hevy exercise type from the values the sets record
+func (e *exercise) exerciseType() hevyapi.CustomExerciseType {
+	var weight, reps, duration, distance bool
+	for _, s := range e.sets {
+		weight = weight || s.WeightKg > 0
+		reps = reps || s.Reps > 0
+		duration = duration || s.DurationSeconds > 0
+		distance = distance || s.DistanceMeters > 0
+	}
+	switch {
+	case distance:
+		return hevyapi.DistanceDuration
+	case weight && !reps && duration:
+		return hevyapi.WeightDuration
+	case weight:
+		return hevyapi.WeightReps
+	case reps:
+		return hevyapi.RepsOnly
+	case duration:
+		return hevyapi.Duration
+	default:
+		return hevyapi.WeightReps
+	}
+}
+
+// templateCatalog matches exercise names to the user's Hevy exercise templates
+type templateCatalog struct {
+	templates []hevyapi.ExerciseTemplate
+	byTitle   map[string]string // Normalized title -> template ID
+	// byCanonical maps muscle_heatmap canonical names to the best matching template,
+	// built on first use as it runs the fuzzy matcher over every template
+	byCanonical map[string]canonicalTemplate
+}
+
+type canonicalTemplate struct {
+	id         string
+	confidence float64
+}
+
+func newTemplateCatalog(templates []hevyapi.ExerciseTemplate) *templateCatalog {
+	c := &templateCatalog{templates: templates, byTitle: make(map[string]string)}
+	for _, t := range templates {
+		if t.Id == nil || t.Title == nil {
+			continue
+		}
+		for _, title := range titleForms(*t.Title) {
+			key := muscle_heatmap.NormalizeName(title)
+			if _, ok := c.byTitle[key]; !ok {
+				c.byTitle[key] = *t.Id
+			}
+		}
+	}
+	return c
+}
+
+// add registers a custom template created for an unmatched exercise
+func (c *templateCatalog) add(id, title string) {
+	c.byTitle[muscle_heatmap.NormalizeName(title)] = id
+}
+
+// match returns the template ID for an exercise name: by title ("Barbell Bench Press" also
+// matches "Bench Press (Barbell)"), then by the muscle_heatmap taxonomy, which resolves
+// aliases, abbreviations and near-miss spellings to a canonical exercise on both sides.
+func (c *templateCatalog) match(name string) (string, bool) {
+	if id, ok := c.byTitle[muscle_heatmap.NormalizeName(name)]; ok {
+		return id, true
+	}
+	lookup := muscle_heatmap.LookupExercise(name)
+	if !lookup.Matched {
+		return "", false
+	}
+	c.indexCanonical()
+	t, ok := c.byCanonical[lookup.CanonicalName]
+	return t.id, ok
+}
+
+func (c *templateCatalog) indexCanonical() {
+	if c.byCanonical != nil {
+		return
+	}
+	c.byCanonical = make(map[string]canonicalTemplate)
+	for _, t := range c.templates {
+		if t.Id == nil || t.Title == nil {
+			continue
+		}
+		for _, title := range titleForms(*t.Title) {
+			lookup := muscle_heatmap.LookupExercise(title)
+			if !lookup.Matched {
+				continue
+			}
+			if best, ok := c.byCanonical[lookup.CanonicalName]; !ok || lookup.Confidence > best.confidence {
+				c.byCanonical[lookup.CanonicalName] = canonicalTemplate{id: *t.Id, confidence: lookup.Confidence}
+			}
+		}
+	}
+}
+
+// titleForms returns a Hevy title with its equipment-first form when it has an equipment
+// suffix: "Bench Press (Barbell)" -> "Barbell Bench Press"
+func titleForms(title string) []string {
+	if m := equipmentSuffix.FindStringSubmatch(title); m != nil {
+		return []string{title, m[2] + " " + m[1]}
+	}
+	return []string{title}
+}
+
+// muscleGroup maps to Hevy's muscle group names, which match the enum's suffix
+func muscleGroup(g pb.MuscleGroup) hevyapi.MuscleGroup {
+	if g == pb.MuscleGroup_MUSCLE_GROUP_UNSPECIFIED {
+		return hevyapi.MuscleGroupOther
+	}
+	return hevyapi.MuscleGroup(strings.ToLower(strings.TrimPrefix(g.String(), "MUSCLE_GROUP_")))
+}
+
+// equipmentCategory guesses a custom exercise's equipment from its name
+func equipmentCategory(name string) hevyapi.EquipmentCategory {
+	words := strings.Fields(muscle_heatmap.NormalizeName(name))
+	for _, w := range words {
+		switch w {
+		case "barbell", "bb", "ez":
+			return hevyapi.EquipmentCategoryBarbell
+		case "dumbbell", "db":
+			return hevyapi.EquipmentCategoryDumbbell
+		case "kettlebell", "kb":
+			return hevyapi.EquipmentCategoryKettlebell
+		case "machine", "cable", "smith":
+			return hevyapi.EquipmentCategoryMachine
+		case "band", "banded":
+			return hevyapi.EquipmentCategoryResistanceBand
+		case "plate":
+			return hevyapi.EquipmentCategoryPlate
+		case "trx", "suspension":
+			return hevyapi.EquipmentCategorySuspension
+		}
+	}
+	return hevyapi.EquipmentCategoryOther
+}
+
+func intPtr(v int) *int {
+	return &v
+}
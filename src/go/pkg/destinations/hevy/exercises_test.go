@@ -0,0 +1,95 @@
+package hevy
+
+import (
+	"fmt"
+	"testing"
+
+	hevyapi "github.com/ripixel/fitglue-server/src/go/pkg/integrations/hevy"
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+)
+
+func TestGroupExercises(t *testing.T) {
+	set := func(name, superset string) *pb.StrengthSet {
+		return &pb.StrengthSet{ExerciseName: name, SupersetId: superset}
+	}
+
+	tests := []struct {
+		name string
+		sets []*pb.StrengthSet
+		want []string // "name/superset:sets"
+	}{
+		{
+			name: "Consecutive sets form one exercise",
+			sets: []*pb.StrengthSet{set("Squat", ""), set("Squat", ""), set("Bench Press", ""), set("Squat", "")},
+			want: []string{"Squat/:2", "Bench Press/:1", "Squat/:1"},
+		},
+		{
+			name: "Alternating superset sets join their exercise",
+			sets: []*pb.StrengthSet{
+				set("Curl", "0"), set("Pushdown", "0"), set("Curl", "0"), set("Pushdown", "0"),
+				set("Plank", ""),
+			},
+			want: []string{"Curl/0:2", "Pushdown/0:2", "Plank/:1"},
+		},
+		{
+			name: "Separate supersets keep separate exercises",
+			sets: []*pb.StrengthSet{set("Curl", "0"), set("Curl", "1"), set("Curl", "1")},
+			want: []string{"Curl/0:1", "Curl/1:2"},
+		},
+		{
+			name: "Unnamed sets",
+			sets: []*pb.StrengthSet{set("", "")},
+			want: []string{unknownExercise + "/:1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, ex := range groupExercises(tt.sets) {
+				got = append(got, fmt.Sprintf("%s/%s:%d", ex.name, ex.supersetID, len(ex.sets)))
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("got %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestTemplateCatalogMatch(t *testing.T) {
+	template := func(id, title string) hevyapi.ExerciseTemplate {
+		return hevyapi.ExerciseTemplate{Id: &id, Title: &title}
+	}
+	catalog := newTemplateCatalog([]hevyapi.ExerciseTemplate{
+		template("79D0BB3A", "Bench Press (Barbell)"),
+		template("3601968B", "Bench Press (Dumbbell)"),
+		template("D04AC939", "Squat (Barbell)"),
+		template("B5D3A742", "Lateral Raise (Dumbbell)"),
+		template("custom-1", "Sled Push"),
+	})
+
+	tests := []struct {
+		name   string
+		want   string
+		wantOK bool
+	}{
+		{name: "Bench Press (Barbell)", want: "79D0BB3A", wantOK: true}, // Exact title
+		{name: "barbell bench press", want: "79D0BB3A", wantOK: true},   // Equipment-first title
+		{name: "DB Bench Press", want: "3601968B", wantOK: true},        // Taxonomy alias
+		{name: "Flat Bench", want: "79D0BB3A", wantOK: true},            // Taxonomy alias of Bench Press
+		{name: "sled push", want: "custom-1", wantOK: true},             // Custom exercise
+		{name: "Underwater Basket Weaving", wantOK: false},
+	}
+	for _, tt := range tests {
+		got, ok := catalog.match(tt.name)
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("match(%q) = %q, %v; want %q, %v", tt.name, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
@@ -0,0 +1,569 @@
+package hevy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/ripixel/fitglue-server/src/go/pkg/destinations"
+	"github.com/ripixel/fitglue-server/src/go/pkg/domain/file_parsers"
+	"github.com/ripixel/fitglue-server/src/go/pkg/infrastructure/oauth"
+	hevyapi "github.com/ripixel/fitglue-server/src/go/pkg/integrations/hevy"
+	"github.com/ripixel/fitglue-server/src/go/pkg/plugin"
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+)
+
+const (
+	defaultBaseURL = "https://api.hevyapp.com"
+	defaultTimeout = 60 * time.Second
+
+	// templatePageSize is the largest page GET /v1/exercise_templates allows
+	templatePageSize = 100
+
+	// sourceName is the Hevy source's connector name, used for processed-activity dedup
+	sourceName = "hevy"
+)
+
+// HevyDestination creates Hevy workouts from strength activities, authenticated with the API
+// key in the user's integrations.hevy.
+//
+// The activity's StrengthSets (from the event's activity data, falling back to the FIT file)
+// are grouped back into exercises, keeping supersets and set types. Each exercise is matched
+// to one of the user's exercise templates (see templateCatalog.match). Exercises without a
+// match get a custom template when the pipeline's create_custom_exercises option is set, and
+// are left out of the workout (reported in hevy_unmatched_exercises) otherwise. The created
+// workout is marked processed for the Hevy source so its webhook doesn't ingest it again.
+type HevyDestination struct {
+	// HTTPClient overrides the default client (60s timeout, tracks integration usage)
+	HTTPClient *http.Client
+	// BaseURL overrides https://api.hevyapp.com (for testing)
+	BaseURL string
+}
+
+func init() {
+	destinations.Register(&HevyDestination{})
+
+	plugin.RegisterDestination(pb.Destination_DESTINATION_HEVY, &pb.PluginManifest{
+		Id:                   "hevy",
+		Type:                 pb.PluginType_PLUGIN_TYPE_DESTINATION,
+		Name:                 "Hevy",
+		Description:          "Log strength workouts in Hevy",
+		Icon:                 "🏋️",
+		Enabled:              true,
+		RequiredIntegrations: []string{"hevy"},
+	})
+}
+
+func (d *HevyDestination) Name() string {
+	return "hevy"
+}
+
+func (d *HevyDestination) DestinationType() pb.Destination {
+	return pb.Destination_DESTINATION_HEVY
+}
+
+// workoutFields is the generated PostWorkoutsRequestBody.Workout struct
+type workoutFields = struct {
+	Description *string                                `json:"description"`
+	EndTime     *string                                `json:"end_time,omitempty"`
+	Exercises   *[]hevyapi.PostWorkoutsRequestExercise `json:"exercises,omitempty"`
+	IsPrivate   *bool                                  `json:"is_private,omitempty"`
+	StartTime   *string                                `json:"start_time,omitempty"`
+	Title       *string                                `json:"title,omitempty"`
+}
+
+var (
+	// errNotConfigured means the user has no enabled Hevy integration
+	errNotConfigured = errors.New("hevy integration not configured")
+	// errNoExercises means none of the activity's exercises could be logged in Hevy
+	errNoExercises = errors.New("no exercises match a hevy exercise template")
+)
+
+// apiError is a failed Hevy request
+type apiError struct {
+	op     string
+	status int
+	body   string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("hevy %s returned %d: %s", e.op, e.status, e.body)
+}
+
+func (d *HevyDestination) Upload(ctx context.Context, req *destinations.Request) (*destinations.Result, error) {
+	ev := req.Event
+	if ev.Source == pb.ActivitySource_SOURCE_HEVY {
+		// The workout is already in Hevy
+		req.Logger.Info("Skipping Hevy upload of a Hevy workout", "external_id", ev.GetActivityData().GetExternalId())
+		return &destinations.Result{
+			Status:   destinations.StatusComplete,
+			Metadata: map[string]interface{}{"hevy_skipped": "activity came from Hevy"},
+		}, nil
+	}
+	sets := strengthSets(req)
+	if len(sets) == 0 {
+		return &destinations.Result{Status: destinations.StatusFailed, Error: "activity has no strength sets to log in Hevy"}, nil
+	}
+	apiKey, err := d.apiKey(ctx, req)
+	if failed := rejected(err); failed != nil {
+		return failed, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	client, err := d.client(req)
+	if err != nil {
+		return nil, err
+	}
+
+	exercises, metadata, err := d.buildExercises(ctx, client, apiKey, req, sets)
+	if failed := rejected(err); failed != nil {
+		return failed, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	start, end := workoutTimes(ev, sets)
+	body := workoutBody(ev.Name, ev.Description, start, end, exercises)
+
+	req.Logger.Info("Creating Hevy workout", "title", ev.Name, "exercises", len(exercises))
+	resp, err := client.PostV1WorkoutsWithResponse(ctx, &hevyapi.PostV1WorkoutsParams{ApiKey: apiKey}, body)
+	if err != nil {
+		return nil, fmt.Errorf("hevy create workout request failed: %w", err)
+	}
+	err = check("create workout", resp.HTTPResponse, resp.Body)
+	if failed := rejected(err); failed != nil {
+		return failed, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	workoutID := createdWorkoutID(resp.Body)
+	if workoutID == "" {
+		return nil, fmt.Errorf("hevy create workout returned no workout id")
+	}
+
+	// Stop the Hevy source re-ingesting the workout when Hevy's webhook fires for it
+	if err := req.Service.DB.MarkActivityProcessed(ctx, ev.UserId, &pb.ProcessedActivityRecord{
+		Source:      sourceName,
+		ExternalId:  workoutID,
+		ProcessedAt: timestamppb.Now(),
+	}); err != nil {
+		req.Logger.Warn("Failed to mark Hevy workout processed", "workout_id", workoutID, "error", err)
+	}
+
+	metadata["hevy_workout_id"] = workoutID
+	return &destinations.Result{Status: destinations.StatusComplete, ExternalID: workoutID, Metadata: metadata}, nil
+}
+
+// Update replaces the workout's title and description. Its exercises are rebuilt when the
+// event carries strength sets (a resync) and kept as they are otherwise.
+func (d *HevyDestination) Update(ctx context.Context, req *destinations.Request) (*destinations.Result, error) {
+	workoutID, err := uuid.Parse(req.ExternalID)
+	if err != nil {
+		return &destinations.Result{Status: destinations.StatusFailed, Error: fmt.Sprintf("invalid hevy workout id %q", req.ExternalID)}, nil
+	}
+	apiKey, err := d.apiKey(ctx, req)
+	if failed := rejected(err); failed != nil {
+		return failed, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	client, err := d.client(req)
+	if err != nil {
+		return nil, err
+	}
+	ev := req.Event
+
+	existing, err := client.GetV1WorkoutsWorkoutIdWithResponse(ctx, workoutID, &hevyapi.GetV1WorkoutsWorkoutIdParams{ApiKey: apiKey})
+	if err != nil {
+		return nil, fmt.Errorf("hevy get workout request failed: %w", err)
+	}
+	err = check("get workout", existing.HTTPResponse, existing.Body)
+	if failed := rejected(err); failed != nil {
+		return failed, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if existing.JSON200 == nil {
+		return nil, fmt.Errorf("hevy get workout returned no workout")
+	}
+	w := existing.JSON200
+
+	metadata := map[string]interface{}{}
+	var exercises []hevyapi.PostWorkoutsRequestExercise
+	start, end := parseTime(w.StartTime), parseTime(w.EndTime)
+	if sets := strengthSets(req); len(sets) > 0 {
+		exercises, metadata, err = d.buildExercises(ctx, client, apiKey, req, sets)
+		if failed := rejected(err); failed != nil {
+			return failed, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		start, end = workoutTimes(ev, sets)
+	} else {
+		exercises = existingExercises(w)
+	}
+
+	title := ev.Name
+	if title == "" && w.Title != nil {
+		title = *w.Title
+	}
+	resp, err := client.PutV1WorkoutsWorkoutIdWithResponse(ctx, workoutID, &hevyapi.PutV1WorkoutsWorkoutIdParams{ApiKey: apiKey},
+		workoutBody(title, ev.Description, start, end, exercises))
+	if err != nil {
+		return nil, fmt.Errorf("hevy update workout request failed: %w", err)
+	}
+	err = check("update workout", resp.HTTPResponse, resp.Body)
+	if failed := rejected(err); failed != nil {
+		return failed, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &destinations.Result{Status: destinations.StatusComplete, ExternalID: req.ExternalID, Metadata: metadata}, nil
+}
+
+// Delete is not supported: the Hevy API can't delete workouts
+func (d *HevyDestination) Delete(ctx context.Context, req *destinations.Request) error {
+	return destinations.ErrNotSupported
+}
+
+func (d *HevyDestination) Status(ctx context.Context, req *destinations.Request) (*destinations.Result, error) {
+	return nil, destinations.ErrNotSupported
+}
+
+// buildExercises groups the sets into Hevy exercises and resolves their exercise templates.
+// Exercises without a match get a custom template if the pipeline opted in, and are
+// skipped otherwise.
+func (d *HevyDestination) buildExercises(ctx context.Context, client *hevyapi.ClientWithResponses, apiKey uuid.UUID, req *destinations.Request, sets []*pb.StrengthSet) ([]hevyapi.PostWorkoutsRequestExercise, map[string]interface{}, error) {
+	templates, err := listTemplates(ctx, client, apiKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	catalog := newTemplateCatalog(templates)
+	createCustom := req.Event.GetDestinationOptions()[d.Name()].GetCreateCustomExercises()
+
+	var created, unmatched []string
+	supersets := make(map[string]int)
+	grouped := groupExercises(sets)
+	exercises := make([]hevyapi.PostWorkoutsRequestExercise, 0, len(grouped))
+	for _, ex := range grouped {
+		templateID, ok := catalog.match(ex.name)
+		if !ok && !createCustom {
+			req.Logger.Info("Skipping exercise with no Hevy template", "title", ex.name, "sets", len(ex.sets))
+			unmatched = append(unmatched, ex.name)
+			continue
+		}
+		if !ok {
+			templateID, err = createTemplate(ctx, client, apiKey, ex)
+			if err != nil {
+				return nil, nil, err
+			}
+			req.Logger.Info("Created Hevy custom exercise", "title", ex.name, "template_id", templateID)
+			catalog.add(templateID, ex.name)
+			created = append(created, ex.name)
+		}
+
+		hevySets := ex.hevySets()
+		out := hevyapi.PostWorkoutsRequestExercise{ExerciseTemplateId: &templateID, Sets: &hevySets}
+		if notes := ex.notes(); notes != "" {
+			out.Notes = &notes
+		}
+		if ex.supersetID != "" {
+			id, ok := supersets[ex.supersetID]
+			if !ok {
+				id = len(supersets)
+				supersets[ex.supersetID] = id
+			}
+			out.SupersetId = &id
+		}
+		exercises = append(exercises, out)
+	}
+
+	metadata := map[string]interface{}{"hevy_exercises": len(exercises)}
+	if len(created) > 0 {
+		metadata["hevy_created_exercises"] = created
+	}
+	if len(unmatched) > 0 {
+		metadata["hevy_unmatched_exercises"] = unmatched
+	}
+	if len(exercises) == 0 {
+		return nil, nil, fmt.Errorf("%w: %s", errNoExercises, strings.Join(unmatched, ", "))
+	}
+	return exercises, metadata, nil
+}
+
+// listTemplates returns all of the user's exercise templates, including custom exercises
+func listTemplates(ctx context.Context, client *hevyapi.ClientWithResponses, apiKey uuid.UUID) ([]hevyapi.ExerciseTemplate, error) {
+	var templates []hevyapi.ExerciseTemplate
+	for page := 1; ; page++ {
+		params := &hevyapi.GetV1ExerciseTemplatesParams{Page: intPtr(page), PageSize: intPtr(templatePageSize), ApiKey: apiKey}
+		resp, err := client.GetV1ExerciseTemplatesWithResponse(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("hevy list exercise templates request failed: %w", err)
+		}
+		if err := check("list exercise templates", resp.HTTPResponse, resp.Body); err != nil {
+			return nil, err
+		}
+		if resp.JSON200 == nil || resp.JSON200.ExerciseTemplates == nil || len(*resp.JSON200.ExerciseTemplates) == 0 {
+			return templates, nil
+		}
+		templates = append(templates, *resp.JSON200.ExerciseTemplates...)
+		if resp.JSON200.PageCount == nil || page >= *resp.JSON200.PageCount {
+			return templates, nil
+		}
+	}
+}
+
+// createTemplate creates a custom exercise template for the exercise, returning its ID
+func createTemplate(ctx context.Context, client *hevyapi.ClientWithResponses, apiKey uuid.UUID, ex *exercise) (string, error) {
+	// Read the raw response: custom template IDs aren't always numeric, as the generated type expects
+	resp, err := client.PostV1ExerciseTemplates(ctx, &hevyapi.PostV1ExerciseTemplatesParams{ApiKey: apiKey}, ex.customExercise())
+	if err != nil {
+		return "", fmt.Errorf("hevy create exercise request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read hevy create exercise response: %w", err)
+	}
+	if err := check("create exercise", resp, body); err != nil {
+		return "", err
+	}
+	id := jsonID(body)
+	if id == "" {
+		return "", fmt.Errorf("hevy create exercise returned no id for %q", ex.name)
+	}
+	return id, nil
+}
+
+// apiKey returns the user's Hevy API key
+func (d *HevyDestination) apiKey(ctx context.Context, req *destinations.Request) (uuid.UUID, error) {
+	user, err := req.Service.DB.GetUser(ctx, req.Event.UserId)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	integration := user.GetIntegrations().GetHevy()
+	if !integration.GetEnabled() || integration.GetApiKey() == "" {
+		return uuid.Nil, fmt.Errorf("%w for user %s", errNotConfigured, req.Event.UserId)
+	}
+	key, err := uuid.Parse(integration.GetApiKey())
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid hevy api key for user %s: %w", req.Event.UserId, err)
+	}
+	return key, nil
+}
+
+// client returns the generated Hevy API client, tracking integration usage by default
+func (d *HevyDestination) client(req *destinations.Request) (*hevyapi.ClientWithResponses, error) {
+	httpClient := d.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{
+			Timeout: defaultTimeout,
+			Transport: &oauth.UsageTrackingTransport{
+				Service:  req.Service,
+				UserID:   req.Event.UserId,
+				Provider: "hevy",
+			},
+		}
+	}
+	baseURL := d.BaseURL
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	return hevyapi.NewClientWithResponses(baseURL, hevyapi.WithHTTPClient(httpClient))
+}
+
+// strengthSets returns the activity's sets from the event's activity data, falling back to
+// the FIT file (where enrichers may have merged sets into another source's activity)
+func strengthSets(req *destinations.Request) []*pb.StrengthSet {
+	if sets := sessionSets(req.Event.GetActivityData()); len(sets) > 0 {
+		return sets
+	}
+	if req.FitFile == nil {
+		return nil
+	}
+	parsed, err := file_parsers.ParseFitFile(bytes.NewReader(req.FitFile))
+	if err != nil {
+		req.Logger.Warn("Failed to parse FIT file for strength sets", "error", err)
+		return nil
+	}
+	return sessionSets(parsed)
+}
+
+func sessionSets(act *pb.StandardizedActivity) []*pb.StrengthSet {
+	var sets []*pb.StrengthSet
+	for _, s := range act.GetSessions() {
+		sets = append(sets, s.StrengthSets...)
+	}
+	return sets
+}
+
+// workoutTimes returns the workout's start and end: the sessions' elapsed time, or the end
+// of the last set when the source records none
+func workoutTimes(ev *pb.EnrichedActivityEvent, sets []*pb.StrengthSet) (time.Time, time.Time) {
+	sessions := ev.GetActivityData().GetSessions()
+	start := ev.GetStartTime().AsTime()
+	if ev.StartTime == nil && len(sessions) > 0 && sessions[0].StartTime != nil {
+		start = sessions[0].StartTime.AsTime()
+	}
+
+	var elapsed float64
+	for _, s := range sessions {
+		elapsed += s.TotalElapsedTime
+	}
+	end := start.Add(time.Duration(math.Round(elapsed)) * time.Second)
+	if elapsed == 0 {
+		for _, s := range sets {
+			if s.StartTime == nil {
+				continue
+			}
+			if setEnd := s.StartTime.AsTime().Add(time.Duration(s.DurationSeconds) * time.Second); setEnd.After(end) {
+				end = setEnd
+			}
+		}
+	}
+	return start, end
+}
+
+func workoutBody(title, description string, start, end time.Time, exercises []hevyapi.PostWorkoutsRequestExercise) hevyapi.PostWorkoutsRequestBody {
+	if title == "" {
+		title = "Workout"
+	}
+	w := &workoutFields{Title: &title, Exercises: &exercises}
+	if description != "" {
+		w.Description = &description
+	}
+	if !start.IsZero() {
+		startTime := start.UTC().Format(time.RFC3339)
+		endTime := end.UTC().Format(time.RFC3339)
+		w.StartTime, w.EndTime = &startTime, &endTime
+	}
+	return hevyapi.PostWorkoutsRequestBody{Workout: w}
+}
+
+// existingExercises converts a fetched workout's exercises back to request exercises
+func existingExercises(w *hevyapi.Workout) []hevyapi.PostWorkoutsRequestExercise {
+	if w.Exercises == nil {
+		return nil
+	}
+	exercises := make([]hevyapi.PostWorkoutsRequestExercise, 0, len(*w.Exercises))
+	for _, ex := range *w.Exercises {
+		out := hevyapi.PostWorkoutsRequestExercise{ExerciseTemplateId: ex.ExerciseTemplateId, Notes: ex.Notes}
+		if ex.SupersetId != nil {
+			out.SupersetId = intPtr(int(*ex.SupersetId))
+		}
+		sets := []hevyapi.PostWorkoutsRequestSet{}
+		if ex.Sets != nil {
+			for _, s := range *ex.Sets {
+				set := hevyapi.PostWorkoutsRequestSet{
+					CustomMetric:    s.CustomMetric,
+					Reps:            roundPtr(s.Reps),
+					DurationSeconds: roundPtr(s.DurationSeconds),
+					DistanceMeters:  roundPtr(s.DistanceMeters),
+					WeightKg:        s.WeightKg,
+				}
+				if s.Type != nil {
+					setType := hevyapi.PostWorkoutsRequestSetType(*s.Type)
+					set.Type = &setType
+				}
+				if s.Rpe != nil {
+					rpe := hevyapi.PostWorkoutsRequestSetRpe(*s.Rpe)
+					set.Rpe = &rpe
+				}
+				sets = append(sets, set)
+			}
+		}
+		out.Sets = &sets
+		exercises = append(exercises, out)
+	}
+	return exercises
+}
+
+// check returns an *apiError for non-2xx responses
+func check(op string, resp *http.Response, body []byte) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	if len(body) > 1024 {
+		body = body[:1024]
+	}
+	return &apiError{op: op, status: resp.StatusCode, body: string(body)}
+}
+
+// rejected turns a 4xx response, a missing integration or an activity with no loggable
+// exercises into a FAILED result. Other errors (including 429 and 5xx) are returned as errors
+// so the upload is retried.
+func rejected(err error) *destinations.Result {
+	if errors.Is(err, errNotConfigured) || errors.Is(err, errNoExercises) {
+		return &destinations.Result{Status: destinations.StatusFailed, Error: err.Error()}
+	}
+	apiErr, ok := err.(*apiError)
+	if !ok || apiErr.status >= 500 || apiErr.status == http.StatusTooManyRequests {
+		return nil
+	}
+	return &destinations.Result{Status: destinations.StatusFailed, Error: apiErr.Error()}
+}
+
+// createdWorkoutID reads the new workout's ID, returned either as the workout or wrapped in
+// {"workout": [...]}
+func createdWorkoutID(body []byte) string {
+	var wrapped struct {
+		Workout json.RawMessage `json:"workout"`
+	}
+	if err := json.Unmarshal(body, &wrapped); err == nil && len(wrapped.Workout) > 0 {
+		var list []json.RawMessage
+		if err := json.Unmarshal(wrapped.Workout, &list); err == nil {
+			if len(list) == 0 {
+				return ""
+			}
+			return jsonID(list[0])
+		}
+		return jsonID(wrapped.Workout)
+	}
+	return jsonID(body)
+}
+
+// jsonID returns the "id" of a JSON object, accepting string or numeric IDs
+func jsonID(body []byte) string {
+	var resp struct {
+		ID json.RawMessage `json:"id"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil || len(resp.ID) == 0 || string(resp.ID) == "null" {
+		return ""
+	}
+	var id string
+	if err := json.Unmarshal(resp.ID, &id); err == nil {
+		return id
+	}
+	return string(resp.ID)
+}
+
+func parseTime(s *string) time.Time {
+	if s == nil {
+		return time.Time{}
+	}
+	t, _ := time.Parse(time.RFC3339, *s)
+	return t
+}
+
+func roundPtr(v *float32) *int {
+	if v == nil {
+		return nil
+	}
+	return intPtr(int(math.Round(float64(*v))))
+}
@@ -0,0 +1,341 @@
+package hevy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/ripixel/fitglue-server/src/go/pkg/bootstrap"
+	"github.com/ripixel/fitglue-server/src/go/pkg/destinations"
+	"github.com/ripixel/fitglue-server/src/go/pkg/infrastructure/database"
+	hevyapi "github.com/ripixel/fitglue-server/src/go/pkg/integrations/hevy"
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+)
+
+const (
+	testAPIKey    = "6f1c2b8e-3d4a-4f5b-9c6d-7e8f9a0b1c2d"
+	testWorkoutID = "b459cba5-cd6d-463c-abd6-54f8eafcadcb"
+)
+
+// stubAPI is a stub Hevy API serving two pages of exercise templates and recording requests
+type stubAPI struct {
+	t               *testing.T
+	workoutStatus   int // Response status for POST/PUT /v1/workouts (200/201 when unset)
+	createdExercise []hevyapi.CreateCustomExerciseRequestBody
+	workouts        []hevyapi.PostWorkoutsRequestBody
+	calls           int
+}
+
+func (s *stubAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.calls++
+	if r.Header.Get("api-key") != testAPIKey {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/exercise_templates":
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		templates := map[int]string{
+			1: `[{"id": "79D0BB3A", "title": "Bench Press (Barbell)"}, {"id": "37FCC2BB", "title": "Bicep Curl (Dumbbell)"}]`,
+			2: `[{"id": "94B7239B", "title": "Triceps Pushdown"}]`,
+		}[page]
+		if templates == "" {
+			templates = "[]"
+		}
+		io.WriteString(w, `{"page": `+strconv.Itoa(page)+`, "page_count": 2, "exercise_templates": `+templates+`}`)
+
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/exercise_templates":
+		var body hevyapi.CreateCustomExerciseRequestBody
+		json.NewDecoder(r.Body).Decode(&body)
+		s.createdExercise = append(s.createdExercise, body)
+		io.WriteString(w, `{"id": "f7d3e1a0-custom"}`)
+
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/workouts/"+testWorkoutID:
+		io.WriteString(w, `{"id": "`+testWorkoutID+`", "title": "Old", "start_time": "2026-01-05T18:00:00Z", "end_time": "2026-01-05T19:00:00Z",
+			"exercises": [{"exercise_template_id": "79D0BB3A", "superset_id": null, "sets": [{"type": "warmup", "reps": 10, "weight_kg": 40}]}]}`)
+
+	case (r.Method == http.MethodPost && r.URL.Path == "/v1/workouts") ||
+		(r.Method == http.MethodPut && r.URL.Path == "/v1/workouts/"+testWorkoutID):
+		var body hevyapi.PostWorkoutsRequestBody
+		json.NewDecoder(r.Body).Decode(&body)
+		s.workouts = append(s.workouts, body)
+		if s.workoutStatus != 0 {
+			w.WriteHeader(s.workoutStatus)
+			io.WriteString(w, `{"error": "nope"}`)
+			return
+		}
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+			io.WriteString(w, `{"workout": [{"id": "`+testWorkoutID+`", "title": "Leg Day"}]}`)
+			return
+		}
+		io.WriteString(w, `{"id": "`+testWorkoutID+`"}`)
+
+	default:
+		s.t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestHevyDestination(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2026, 1, 5, 18, 0, 0, 0, time.UTC)
+	strengthEvent := func() *pb.EnrichedActivityEvent {
+		return &pb.EnrichedActivityEvent{
+			ActivityId:   "act-1",
+			UserId:       "u1",
+			Name:         "Arms",
+			Description:  "Pump",
+			ActivityType: pb.ActivityType_ACTIVITY_TYPE_WEIGHT_TRAINING,
+			Source:       pb.ActivitySource_SOURCE_FITBIT,
+			StartTime:    timestamppb.New(start),
+			ActivityData: &pb.StandardizedActivity{Sessions: []*pb.Session{{
+				StartTime:        timestamppb.New(start),
+				TotalElapsedTime: 3600,
+				StrengthSets: []*pb.StrengthSet{
+					{ExerciseName: "Barbell Bench Press", Reps: 10, WeightKg: 40, SetType: "warmup", Notes: "Elbows in"},
+					{ExerciseName: "Barbell Bench Press", Reps: 5, WeightKg: 80, SetType: "failure"},
+					{ExerciseName: "Dumbbell Curl", Reps: 12, WeightKg: 12, SupersetId: "7"},
+					{ExerciseName: "Triceps Pushdown", Reps: 12, WeightKg: 30, SupersetId: "7"},
+					{ExerciseName: "Dumbbell Curl", Reps: 10, WeightKg: 12, SupersetId: "7", SetType: "dropset"},
+					{ExerciseName: "Triceps Pushdown", Reps: 10, WeightKg: 30, SupersetId: "7"},
+					{ExerciseName: "Sled Drag", DistanceMeters: 20.4, DurationSeconds: 30, PrimaryMuscleGroup: pb.MuscleGroup_MUSCLE_GROUP_QUADRICEPS},
+				},
+			}}},
+		}
+	}
+
+	setup := func(t *testing.T, api *stubAPI, ev *pb.EnrichedActivityEvent) (*HevyDestination, *destinations.Request, *database.MemoryDatabase) {
+		api.t = t
+		server := httptest.NewServer(api)
+		t.Cleanup(server.Close)
+		db := database.NewMemoryDatabase()
+		db.SetUser(ctx, &pb.UserRecord{
+			UserId:       "u1",
+			Integrations: &pb.UserIntegrations{Hevy: &pb.HevyIntegration{Enabled: true, ApiKey: testAPIKey}},
+		})
+		return &HevyDestination{HTTPClient: server.Client(), BaseURL: server.URL}, &destinations.Request{
+			Event:      ev,
+			ExternalID: testWorkoutID,
+			Service:    &bootstrap.Service{DB: db},
+			Logger:     slog.Default(),
+		}, db
+	}
+
+	t.Run("Upload creates the workout with matched and custom exercises", func(t *testing.T) {
+		api := &stubAPI{}
+		ev := strengthEvent()
+		ev.DestinationOptions = map[string]*pb.DestinationOptions{"hevy": {CreateCustomExercises: proto.Bool(true)}}
+		dest, req, db := setup(t, api, ev)
+
+		res, err := dest.Upload(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Status != destinations.StatusComplete || res.ExternalID != testWorkoutID || res.Metadata["hevy_workout_id"] != testWorkoutID {
+			t.Fatalf("Unexpected result: %+v", res)
+		}
+
+		// Unmatched exercise became a custom template
+		if len(api.createdExercise) != 1 {
+			t.Fatalf("Expected one custom exercise, got %d", len(api.createdExercise))
+		}
+		custom := api.createdExercise[0].Exercise
+		if *custom.Title != "Sled Drag" || *custom.MuscleGroup != hevyapi.MuscleGroupQuadriceps || *custom.ExerciseType != hevyapi.DistanceDuration {
+			t.Errorf("Unexpected custom exercise: %+v", custom)
+		}
+
+		if len(api.workouts) != 1 {
+			t.Fatalf("Expected one workout, got %d", len(api.workouts))
+		}
+		w := api.workouts[0].Workout
+		if *w.Title != "Arms" || *w.Description != "Pump" || *w.StartTime != "2026-01-05T18:00:00Z" || *w.EndTime != "2026-01-05T19:00:00Z" {
+			t.Errorf("Unexpected workout: title=%v start=%v end=%v", *w.Title, *w.StartTime, *w.EndTime)
+		}
+
+		type wantExercise struct {
+			template string
+			superset *int
+			types    []hevyapi.PostWorkoutsRequestSetType
+		}
+		zero := 0
+		want := []wantExercise{
+			{"79D0BB3A", nil, []hevyapi.PostWorkoutsRequestSetType{"warmup", "failure"}},
+			{"37FCC2BB", &zero, []hevyapi.PostWorkoutsRequestSetType{"normal", "dropset"}},
+			{"94B7239B", &zero, []hevyapi.PostWorkoutsRequestSetType{"normal", "normal"}},
+			{"f7d3e1a0-custom", nil, []hevyapi.PostWorkoutsRequestSetType{"normal"}},
+		}
+		exercises := *w.Exercises
+		if len(exercises) != len(want) {
+			t.Fatalf("Expected %d exercises, got %d", len(want), len(exercises))
+		}
+		for i, ex := range exercises {
+			if *ex.ExerciseTemplateId != want[i].template || (ex.SupersetId == nil) != (want[i].superset == nil) ||
+				(ex.SupersetId != nil && *ex.SupersetId != *want[i].superset) {
+				t.Errorf("Exercise %d: template=%s superset=%v, want %+v", i, *ex.ExerciseTemplateId, ex.SupersetId, want[i])
+			}
+			sets := *ex.Sets
+			if len(sets) != len(want[i].types) {
+				t.Fatalf("Exercise %d: %d sets, want %d", i, len(sets), len(want[i].types))
+			}
+			for j, s := range sets {
+				if *s.Type != want[i].types[j] {
+					t.Errorf("Exercise %d set %d: type %s, want %s", i, j, *s.Type, want[i].types[j])
+				}
+			}
+		}
+		bench := (*exercises[0].Sets)[1]
+		if *exercises[0].Notes != "Elbows in" || *bench.Reps != 5 || *bench.WeightKg != 80 {
+			t.Errorf("Unexpected bench press: notes=%v set=%+v", exercises[0].Notes, bench)
+		}
+		sled := (*exercises[3].Sets)[0]
+		if *sled.DistanceMeters != 20 || *sled.DurationSeconds != 30 || sled.Reps != nil || sled.WeightKg != nil {
+			t.Errorf("Unexpected sled drag set: %+v", sled)
+		}
+
+		// The Hevy source must not ingest the workout again
+		if processed, _ := db.HasProcessedActivity(ctx, "u1", "hevy", testWorkoutID); !processed {
+			t.Error("Expected the workout to be marked processed for the Hevy source")
+		}
+	})
+
+	t.Run("Unmatched exercises are skipped and reported by default", func(t *testing.T) {
+		api := &stubAPI{}
+		dest, req, _ := setup(t, api, strengthEvent())
+
+		res, err := dest.Upload(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Status != destinations.StatusComplete {
+			t.Fatalf("Unexpected result: %+v", res)
+		}
+		if len(api.createdExercise) != 0 {
+			t.Errorf("Expected no custom exercises, got %+v", api.createdExercise)
+		}
+		if unmatched, _ := res.Metadata["hevy_unmatched_exercises"].([]string); len(unmatched) != 1 || unmatched[0] != "Sled Drag" {
+			t.Errorf("Expected Sled Drag to be reported unmatched, got %v", res.Metadata)
+		}
+		if exercises := *api.workouts[0].Workout.Exercises; len(exercises) != 3 {
+			t.Errorf("Expected 3 matched exercises, got %d", len(exercises))
+		}
+	})
+
+	t.Run("Activity without matched exercises fails", func(t *testing.T) {
+		api := &stubAPI{}
+		ev := strengthEvent()
+		ev.ActivityData.Sessions[0].StrengthSets = ev.ActivityData.Sessions[0].StrengthSets[6:]
+		dest, req, _ := setup(t, api, ev)
+
+		res, err := dest.Upload(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Status != destinations.StatusFailed || len(api.workouts) != 0 {
+			t.Errorf("Expected a failed upload without a workout, got %+v", res)
+		}
+	})
+
+	t.Run("Disabled integration fails without retrying", func(t *testing.T) {
+		api := &stubAPI{}
+		dest, req, db := setup(t, api, strengthEvent())
+		db.SetUser(ctx, &pb.UserRecord{UserId: "u1", Integrations: &pb.UserIntegrations{Hevy: &pb.HevyIntegration{ApiKey: testAPIKey}}})
+
+		res, err := dest.Upload(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Status != destinations.StatusFailed || api.calls != 0 {
+			t.Errorf("Expected a failed upload, got %+v (%d calls)", res, api.calls)
+		}
+	})
+
+	t.Run("Hevy workouts are not uploaded back", func(t *testing.T) {
+		api := &stubAPI{}
+		ev := strengthEvent()
+		ev.Source = pb.ActivitySource_SOURCE_HEVY
+		dest, req, _ := setup(t, api, ev)
+
+		res, err := dest.Upload(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Status != destinations.StatusComplete || res.ExternalID != "" || api.calls != 0 {
+			t.Errorf("Expected a skipped upload, got %+v (%d calls)", res, api.calls)
+		}
+	})
+
+	t.Run("Activity without strength sets fails", func(t *testing.T) {
+		api := &stubAPI{}
+		ev := strengthEvent()
+		ev.ActivityData.Sessions[0].StrengthSets = nil
+		dest, req, _ := setup(t, api, ev)
+
+		res, err := dest.Upload(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Status != destinations.StatusFailed || api.calls != 0 {
+			t.Errorf("Expected a failed upload, got %+v (%d calls)", res, api.calls)
+		}
+	})
+
+	t.Run("Rejected workout fails and server errors are retried", func(t *testing.T) {
+		api := &stubAPI{workoutStatus: http.StatusBadRequest}
+		dest, req, _ := setup(t, api, strengthEvent())
+		res, err := dest.Upload(ctx, req)
+		if err != nil || res.Status != destinations.StatusFailed {
+			t.Errorf("Expected a failed result, got %+v, %v", res, err)
+		}
+
+		api = &stubAPI{workoutStatus: http.StatusBadGateway}
+		dest, req, _ = setup(t, api, strengthEvent())
+		if _, err := dest.Upload(ctx, req); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+
+	t.Run("Metadata update keeps the workout's exercises", func(t *testing.T) {
+		api := &stubAPI{}
+		dest, req, _ := setup(t, api, &pb.EnrichedActivityEvent{UserId: "u1", Name: "Renamed", Description: "New"})
+
+		res, err := dest.Update(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Status != destinations.StatusComplete || res.ExternalID != testWorkoutID {
+			t.Fatalf("Unexpected result: %+v", res)
+		}
+		w := api.workouts[0].Workout
+		if *w.Title != "Renamed" || *w.Description != "New" || *w.StartTime != "2026-01-05T18:00:00Z" {
+			t.Errorf("Unexpected workout: %+v", w)
+		}
+		exercises := *w.Exercises
+		if len(exercises) != 1 || *exercises[0].ExerciseTemplateId != "79D0BB3A" {
+			t.Fatalf("Expected the existing exercise, got %+v", exercises)
+		}
+		set := (*exercises[0].Sets)[0]
+		if *set.Type != "warmup" || *set.Reps != 10 || *set.WeightKg != 40 {
+			t.Errorf("Unexpected set: %+v", set)
+		}
+	})
+
+	t.Run("Delete is not supported", func(t *testing.T) {
+		dest := &HevyDestination{}
+		if err := dest.Delete(ctx, &destinations.Request{}); !errors.Is(err, destinations.ErrNotSupported) {
+			t.Errorf("Delete = %v, want ErrNotSupported", err)
+		}
+	})
+}
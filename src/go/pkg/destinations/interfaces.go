@@ -0,0 +1,78 @@
+package destinations
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/ripixel/fitglue-server/src/go/pkg/bootstrap"
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+)
+
+// ErrNotSupported is returned by destinations that cannot perform an operation
+// (e.g. Strava's API does not allow deleting activities).
+var ErrNotSupported = errors.New("operation not supported by destination")
+
+// UploadStatus is the state of an activity at a destination
+type UploadStatus string
+
+const (
+	StatusComplete   UploadStatus = "COMPLETE"   // ExternalID is the destination's activity ID
+	StatusProcessing UploadStatus = "PROCESSING" // Accepted; UploadID can be checked with Status
+	StatusFailed     UploadStatus = "FAILED"     // Rejected by the destination (see Result.Error)
+)
+
+// Request carries everything a destination needs for one operation.
+type Request struct {
+	Event *pb.EnrichedActivityEvent
+	// FitFile is the generated FIT file (Upload only; nil if the event has no fit_file_uri)
+	FitFile []byte
+	// ExternalID is the destination's activity ID (Update, Delete, Status)
+	ExternalID string
+	// UploadID is the destination's reference for an upload still processing (Status)
+	UploadID string
+
+	Service *bootstrap.Service
+	Logger  *slog.Logger
+}
+
+// Result represents the outcome of a destination operation.
+type Result struct {
+	Status     UploadStatus
+	ExternalID string // Destination activity ID, once known
+	UploadID   string // Destination upload reference, if uploads are asynchronous
+	Error      string // Destination-reported failure reason (StatusFailed)
+
+	// Extra outputs recorded on the execution (e.g. "strava_upload_id")
+	Metadata map[string]interface{}
+}
+
+// Destination defines the interface for an upload target.
+type Destination interface {
+	// Name returns the unique identifier for the destination (e.g., "strava").
+	// It is also the key under SynchronizedActivity.destinations.
+	Name() string
+
+	// DestinationType returns the protobuf enum type for this destination
+	DestinationType() pb.Destination
+
+	// Upload creates the activity at the destination.
+	Upload(ctx context.Context, req *Request) (*Result, error)
+
+	// Update replaces the metadata (title, description, type) of req.ExternalID.
+	Update(ctx context.Context, req *Request) (*Result, error)
+
+	// Delete removes req.ExternalID from the destination.
+	Delete(ctx context.Context, req *Request) error
+
+	// Status checks an upload that was still processing (req.UploadID).
+	Status(ctx context.Context, req *Request) (*Result, error)
+}
+
+// PendingDeadliner is implemented by destinations whose pending uploads may stay
+// PROCESSING for longer than MaxPendingAge.
+type PendingDeadliner interface {
+	// PendingDeadline returns how long the status poller keeps checking uploadID.
+	PendingDeadline(uploadID string) time.Duration
+}
@@ -0,0 +1,278 @@
+package intervals_icu
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"time"
+
+	shared "github.com/ripixel/fitglue-server/src/go/pkg"
+	"github.com/ripixel/fitglue-server/src/go/pkg/destinations"
+	"github.com/ripixel/fitglue-server/src/go/pkg/domain/activity"
+	"github.com/ripixel/fitglue-server/src/go/pkg/plugin"
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+)
+
+const (
+	defaultBaseURL = "https://intervals.icu"
+	defaultTimeout = 60 * time.Second
+
+	// apiKeyUser is the basic auth username intervals.icu expects with a personal API key
+	apiKeyUser = "API_KEY"
+)
+
+// IntervalsIcuDestination uploads FIT files to intervals.icu, authenticated with the athlete ID
+// and API key in the user's integrations.intervals_icu. Uploads are processed synchronously, so
+// the returned activity ID is recorded straight away.
+type IntervalsIcuDestination struct {
+	// HTTPClient overrides the default client (60s timeout)
+	HTTPClient *http.Client
+	// BaseURL overrides https://intervals.icu (for testing)
+	BaseURL string
+}
+
+func init() {
+	destinations.Register(&IntervalsIcuDestination{})
+
+	plugin.RegisterDestination(pb.Destination_DESTINATION_INTERVALS_ICU, &pb.PluginManifest{
+		Id:                   "intervals_icu",
+		Type:                 pb.PluginType_PLUGIN_TYPE_DESTINATION,
+		Name:                 "intervals.icu",
+		Description:          "Upload activities to intervals.icu",
+		Icon:                 "📈",
+		Enabled:              true,
+		RequiredIntegrations: []string{"intervals_icu"},
+	})
+}
+
+func (d *IntervalsIcuDestination) Name() string {
+	return "intervals_icu"
+}
+
+func (d *IntervalsIcuDestination) DestinationType() pb.Destination {
+	return pb.Destination_DESTINATION_INTERVALS_ICU
+}
+
+// uploadResponse is the body of POST /api/v1/athlete/{id}/activities
+type uploadResponse struct {
+	ID         string `json:"id"`
+	Activities []struct {
+		ID string `json:"id"`
+	} `json:"activities"`
+}
+
+// activityUpdate is the body of PUT /api/v1/activity/{id}; unset fields are left unchanged
+type activityUpdate struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type,omitempty"`
+}
+
+// errNotConfigured means the user has no enabled intervals.icu integration. Uploads fail
+// rather than retry, as redelivery can't fix it.
+var errNotConfigured = errors.New("intervals.icu integration not configured")
+
+// apiError is a failed intervals.icu request
+type apiError struct {
+	status int
+	body   string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("intervals.icu returned %d: %s", e.status, e.body)
+}
+
+func (d *IntervalsIcuDestination) Upload(ctx context.Context, req *destinations.Request) (*destinations.Result, error) {
+	if req.FitFile == nil {
+		return nil, fmt.Errorf("no FIT file to upload")
+	}
+	creds, err := d.credentials(ctx, req)
+	if errors.Is(err, errNotConfigured) {
+		return &destinations.Result{Status: destinations.StatusFailed, Error: err.Error()}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	ev := req.Event
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, _ := writer.CreateFormFile("file", ev.ActivityId+".fit")
+	part.Write(req.FitFile)
+	writer.Close()
+
+	query := url.Values{}
+	// Prefixed like Strava uploads so FitGlue's own uploads are recognisable
+	query.Set("external_id", shared.UploadExternalIDPrefix+ev.ActivityId+".fit")
+	if ev.Name != "" {
+		query.Set("name", ev.Name)
+	}
+	if ev.Description != "" {
+		query.Set("description", ev.Description)
+	}
+
+	req.Logger.Info("Uploading to intervals.icu", "title", ev.Name, "type", ev.ActivityType)
+	var uploaded uploadResponse
+	err = d.do(ctx, creds, http.MethodPost, "/api/v1/athlete/"+url.PathEscape(creds.GetAthleteId())+"/activities?"+query.Encode(), writer.FormDataContentType(), body, &uploaded)
+	if failed := rejected(err); failed != nil {
+		return failed, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	activityID := uploaded.ID
+	if len(uploaded.Activities) > 0 && uploaded.Activities[0].ID != "" {
+		activityID = uploaded.Activities[0].ID
+	}
+	if activityID == "" {
+		return nil, fmt.Errorf("intervals.icu upload returned no activity id")
+	}
+	res := &destinations.Result{
+		Status:     destinations.StatusComplete,
+		ExternalID: activityID,
+		Metadata:   map[string]interface{}{"intervals_icu_activity_id": activityID},
+	}
+
+	// The upload API takes no type, so set it on the new activity
+	if ev.ActivityType != pb.ActivityType_ACTIVITY_TYPE_UNSPECIFIED {
+		if err := d.updateActivity(ctx, creds, activityID, &activityUpdate{Type: activityType(ev.ActivityType)}); err != nil {
+			// The activity exists; failing would upload it again
+			req.Logger.Warn("Failed to set activity type after upload", "activity_id", activityID, "error", err)
+			res.Metadata["intervals_icu_type_error"] = err.Error()
+		}
+	}
+	return res, nil
+}
+
+// Update replaces the activity's name, description and type
+func (d *IntervalsIcuDestination) Update(ctx context.Context, req *destinations.Request) (*destinations.Result, error) {
+	creds, err := d.credentials(ctx, req)
+	if errors.Is(err, errNotConfigured) {
+		return &destinations.Result{Status: destinations.StatusFailed, Error: err.Error()}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	ev := req.Event
+	update := &activityUpdate{Name: ev.Name, Description: ev.Description}
+	if ev.ActivityType != pb.ActivityType_ACTIVITY_TYPE_UNSPECIFIED {
+		update.Type = activityType(ev.ActivityType)
+	}
+	err = d.updateActivity(ctx, creds, req.ExternalID, update)
+	if failed := rejected(err); failed != nil {
+		return failed, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &destinations.Result{Status: destinations.StatusComplete, ExternalID: req.ExternalID}, nil
+}
+
+// Delete removes the activity; one already gone counts as deleted
+func (d *IntervalsIcuDestination) Delete(ctx context.Context, req *destinations.Request) error {
+	creds, err := d.credentials(ctx, req)
+	if errors.Is(err, errNotConfigured) {
+		// Without credentials the copy can't be deleted; leave it rather than retry
+		return fmt.Errorf("%w: %v", destinations.ErrNotSupported, err)
+	}
+	if err != nil {
+		return err
+	}
+	err = d.do(ctx, creds, http.MethodDelete, "/api/v1/activity/"+url.PathEscape(req.ExternalID), "", nil, nil)
+	if apiErr, ok := err.(*apiError); ok && apiErr.status == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+func (d *IntervalsIcuDestination) Status(ctx context.Context, req *destinations.Request) (*destinations.Result, error) {
+	return nil, destinations.ErrNotSupported
+}
+
+// credentials returns the user's intervals.icu integration
+func (d *IntervalsIcuDestination) credentials(ctx context.Context, req *destinations.Request) (*pb.IntervalsIcuIntegration, error) {
+	user, err := req.Service.DB.GetUser(ctx, req.Event.UserId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	creds := user.GetIntegrations().GetIntervalsIcu()
+	if !creds.GetEnabled() || creds.GetAthleteId() == "" || creds.GetApiKey() == "" {
+		return nil, fmt.Errorf("%w for user %s", errNotConfigured, req.Event.UserId)
+	}
+	return creds, nil
+}
+
+func (d *IntervalsIcuDestination) updateActivity(ctx context.Context, creds *pb.IntervalsIcuIntegration, activityID string, update *activityUpdate) error {
+	payload, err := json.Marshal(update)
+	if err != nil {
+		return err
+	}
+	return d.do(ctx, creds, http.MethodPut, "/api/v1/activity/"+url.PathEscape(activityID), "application/json", bytes.NewReader(payload), nil)
+}
+
+// do sends an authenticated API request, decoding the JSON response into out when given.
+// Non-2xx responses are returned as *apiError.
+func (d *IntervalsIcuDestination) do(ctx context.Context, creds *pb.IntervalsIcuIntegration, method, path, contentType string, body io.Reader, out interface{}) error {
+	httpReq, err := http.NewRequestWithContext(ctx, method, d.baseURL()+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.SetBasicAuth(apiKeyUser, creds.GetApiKey())
+	if contentType != "" {
+		httpReq.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := d.client().Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("intervals.icu request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &apiError{status: resp.StatusCode, body: string(respBody)}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode intervals.icu response: %w", err)
+	}
+	return nil
+}
+
+// rejected turns a 4xx response into a FAILED result. Other errors (including 429 and 5xx)
+// are returned as errors so the upload is retried.
+func rejected(err error) *destinations.Result {
+	apiErr, ok := err.(*apiError)
+	if !ok || apiErr.status >= 500 || apiErr.status == http.StatusTooManyRequests {
+		return nil
+	}
+	return &destinations.Result{Status: destinations.StatusFailed, Error: apiErr.Error()}
+}
+
+func (d *IntervalsIcuDestination) client() *http.Client {
+	if d.HTTPClient != nil {
+		return d.HTTPClient
+	}
+	return &http.Client{Timeout: defaultTimeout}
+}
+
+func (d *IntervalsIcuDestination) baseURL() string {
+	if d.BaseURL != "" {
+		return d.BaseURL
+	}
+	return defaultBaseURL
+}
+
+// activityType maps to intervals.icu's activity types, which follow Strava's sport types
+func activityType(t pb.ActivityType) string {
+	return activity.GetStravaActivityType(t)
+}
@@ -0,0 +1,221 @@
+package intervals_icu
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ripixel/fitglue-server/src/go/pkg/bootstrap"
+	"github.com/ripixel/fitglue-server/src/go/pkg/destinations"
+	"github.com/ripixel/fitglue-server/src/go/pkg/infrastructure/database"
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+)
+
+// apiCall is a request received by the stub intervals.icu API
+type apiCall struct {
+	method string
+	path   string
+	query  map[string][]string
+	file   string                 // Uploaded FIT file
+	update map[string]interface{} // PUT body
+}
+
+// stubAPI serves the intervals.icu endpoints used by the destination, responding with the
+// configured status per method (200 when unset)
+func stubAPI(t *testing.T, statuses map[string]int, calls *[]apiCall) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "API_KEY" || pass != "key-123" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		call := apiCall{method: r.Method, path: r.URL.Path, query: r.URL.Query()}
+		switch r.Method {
+		case http.MethodPost:
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				t.Errorf("Expected multipart upload: %v", err)
+			} else if f, err := r.MultipartForm.File["file"][0].Open(); err == nil {
+				b, _ := io.ReadAll(f)
+				call.file = string(b)
+			}
+		case http.MethodPut:
+			json.NewDecoder(r.Body).Decode(&call.update)
+		}
+		*calls = append(*calls, call)
+
+		if status, ok := statuses[r.Method]; ok && status != http.StatusOK {
+			w.WriteHeader(status)
+			io.WriteString(w, `{"status": "error"}`)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodPost:
+			io.WriteString(w, `{"icu_athlete_id": "i42", "id": "i900", "activities": [{"icu_athlete_id": "i42", "id": "i900"}]}`)
+		case http.MethodPut:
+			io.WriteString(w, `{"id": "i900"}`)
+		}
+	}))
+}
+
+func TestIntervalsIcuDestination(t *testing.T) {
+	ctx := context.Background()
+	event := &pb.EnrichedActivityEvent{
+		ActivityId:   "act-1",
+		UserId:       "u1",
+		Name:         "Leg Day",
+		Description:  "Squats",
+		ActivityType: pb.ActivityType_ACTIVITY_TYPE_WEIGHT_TRAINING,
+	}
+
+	newRequest := func(baseURL string, enabled bool) (*IntervalsIcuDestination, *destinations.Request) {
+		db := database.NewMemoryDatabase()
+		db.SetUser(ctx, &pb.UserRecord{
+			UserId: "u1",
+			Integrations: &pb.UserIntegrations{IntervalsIcu: &pb.IntervalsIcuIntegration{
+				Enabled:   enabled,
+				AthleteId: "i42",
+				ApiKey:    "key-123",
+			}},
+		})
+		return &IntervalsIcuDestination{BaseURL: baseURL}, &destinations.Request{
+			Event:      event,
+			FitFile:    []byte("FIT"),
+			ExternalID: "i900",
+			Service:    &bootstrap.Service{DB: db},
+			Logger:     slog.Default(),
+		}
+	}
+
+	uploadTests := []struct {
+		name          string
+		statuses      map[string]int
+		wantErr       bool
+		wantStatus    destinations.UploadStatus
+		wantTypeError bool
+	}{
+		{
+			name:       "Upload sets name, description and type",
+			wantStatus: destinations.StatusComplete,
+		},
+		{
+			name:          "Failed type update keeps the upload",
+			statuses:      map[string]int{http.MethodPut: http.StatusInternalServerError},
+			wantStatus:    destinations.StatusComplete,
+			wantTypeError: true,
+		},
+		{
+			name:       "Rejected upload fails",
+			statuses:   map[string]int{http.MethodPost: http.StatusUnprocessableEntity},
+			wantStatus: destinations.StatusFailed,
+		},
+		{
+			name:     "Server error is retried",
+			statuses: map[string]int{http.MethodPost: http.StatusBadGateway},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range uploadTests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []apiCall
+			server := stubAPI(t, tt.statuses, &calls)
+			defer server.Close()
+			dest, req := newRequest(server.URL, true)
+
+			res, err := dest.Upload(ctx, req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if res.Status != tt.wantStatus {
+				t.Fatalf("Unexpected result: %+v", res)
+			}
+
+			upload := calls[0]
+			if upload.path != "/api/v1/athlete/i42/activities" || upload.file != "FIT" ||
+				upload.query["name"][0] != "Leg Day" || upload.query["description"][0] != "Squats" ||
+				upload.query["external_id"][0] != "fitglue-act-1.fit" {
+				t.Errorf("Unexpected upload: %+v", upload)
+			}
+			if tt.wantStatus == destinations.StatusFailed {
+				if len(calls) != 1 || res.Error == "" {
+					t.Errorf("Expected a failed upload without a type update, got %+v (%d calls)", res, len(calls))
+				}
+				return
+			}
+
+			if res.ExternalID != "i900" || res.Metadata["intervals_icu_activity_id"] != "i900" {
+				t.Errorf("Unexpected result: %+v", res)
+			}
+			if len(calls) != 2 || calls[1].path != "/api/v1/activity/i900" || calls[1].update["type"] != "WeightTraining" {
+				t.Errorf("Expected type update, got %+v", calls)
+			}
+			if _, ok := res.Metadata["intervals_icu_type_error"]; ok != tt.wantTypeError {
+				t.Errorf("Unexpected metadata: %v", res.Metadata)
+			}
+		})
+	}
+
+	t.Run("Update replaces name, description and type", func(t *testing.T) {
+		var calls []apiCall
+		server := stubAPI(t, nil, &calls)
+		defer server.Close()
+		dest, req := newRequest(server.URL, true)
+
+		res, err := dest.Update(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Status != destinations.StatusComplete || res.ExternalID != "i900" {
+			t.Errorf("Unexpected result: %+v", res)
+		}
+		if len(calls) != 1 || calls[0].method != http.MethodPut ||
+			calls[0].update["name"] != "Leg Day" || calls[0].update["description"] != "Squats" || calls[0].update["type"] != "WeightTraining" {
+			t.Errorf("Unexpected update: %+v", calls)
+		}
+	})
+
+	t.Run("Delete removes the activity", func(t *testing.T) {
+		for _, status := range []int{http.StatusOK, http.StatusNotFound} {
+			var calls []apiCall
+			server := stubAPI(t, map[string]int{http.MethodDelete: status}, &calls)
+			dest, req := newRequest(server.URL, true)
+
+			if err := dest.Delete(ctx, req); err != nil {
+				t.Errorf("Delete with status %d: %v", status, err)
+			}
+			if len(calls) != 1 || calls[0].method != http.MethodDelete || calls[0].path != "/api/v1/activity/i900" {
+				t.Errorf("Unexpected delete: %+v", calls)
+			}
+			server.Close()
+		}
+	})
+
+	t.Run("Disabled integration fails without retrying or a request", func(t *testing.T) {
+		var calls []apiCall
+		server := stubAPI(t, nil, &calls)
+		defer server.Close()
+		dest, req := newRequest(server.URL, false)
+
+		res, err := dest.Upload(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Status != destinations.StatusFailed {
+			t.Errorf("Expected a failed result, got %+v", res)
+		}
+		if err := dest.Delete(ctx, req); !errors.Is(err, destinations.ErrNotSupported) {
+			t.Errorf("Delete = %v, want ErrNotSupported", err)
+		}
+		if len(calls) != 0 {
+			t.Errorf("Expected no requests, got %+v", calls)
+		}
+	})
+}
@@ -0,0 +1,75 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ripixel/fitglue-server/src/go/pkg/destinations"
+	"github.com/ripixel/fitglue-server/src/go/pkg/plugin"
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+)
+
+// MockDestination simulates a destination for testing the full pipeline.
+// Uploads complete immediately with the external ID "mock-{activity_id}".
+type MockDestination struct{}
+
+func init() {
+	destinations.Register(&MockDestination{})
+
+	plugin.RegisterDestination(pb.Destination_DESTINATION_MOCK, &pb.PluginManifest{
+		Id:          "mock",
+		Type:        pb.PluginType_PLUGIN_TYPE_DESTINATION,
+		Name:        "Mock",
+		Description: "Testing destination that records uploads without sending them anywhere",
+		Icon:        "🧪",
+		Enabled:     false, // Testing only
+	})
+}
+
+func (d *MockDestination) Name() string {
+	return "mock"
+}
+
+func (d *MockDestination) DestinationType() pb.Destination {
+	return pb.Destination_DESTINATION_MOCK
+}
+
+func (d *MockDestination) Upload(ctx context.Context, req *destinations.Request) (*destinations.Result, error) {
+	ev := req.Event
+	req.Logger.Info("Mock upload received",
+		"activity_id", ev.ActivityId,
+		"pipeline_id", ev.PipelineId,
+		"user_id", ev.UserId,
+		"name", ev.Name,
+		"type", ev.ActivityType,
+		"source", ev.Source,
+		"destinations", ev.Destinations,
+	)
+
+	externalID := fmt.Sprintf("mock-%s", ev.ActivityId)
+	return &destinations.Result{
+		Status:     destinations.StatusComplete,
+		ExternalID: externalID,
+		Metadata: map[string]interface{}{
+			"mock_external_id": externalID,
+		},
+	}, nil
+}
+
+func (d *MockDestination) Update(ctx context.Context, req *destinations.Request) (*destinations.Result, error) {
+	req.Logger.Info("Mock update received", "external_id", req.ExternalID, "name", req.Event.Name)
+	return &destinations.Result{Status: destinations.StatusComplete, ExternalID: req.ExternalID}, nil
+}
+
+func (d *MockDestination) Delete(ctx context.Context, req *destinations.Request) error {
+	req.Logger.Info("Mock delete received", "external_id", req.ExternalID)
+	return nil
+}
+
+func (d *MockDestination) Status(ctx context.Context, req *destinations.Request) (*destinations.Result, error) {
+	externalID := req.ExternalID
+	if externalID == "" {
+		externalID = fmt.Sprintf("mock-%s", req.Event.ActivityId)
+	}
+	return &destinations.Result{Status: destinations.StatusComplete, ExternalID: externalID, UploadID: req.UploadID}, nil
+}
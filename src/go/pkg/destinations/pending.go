@@ -0,0 +1,206 @@
+package destinations
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	shared "github.com/ripixel/fitglue-server/src/go/pkg"
+	"github.com/ripixel/fitglue-server/src/go/pkg/execution"
+	"github.com/ripixel/fitglue-server/src/go/pkg/framework"
+	infrapubsub "github.com/ripixel/fitglue-server/src/go/pkg/infrastructure/pubsub"
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+)
+
+// MaxPendingAge is how long an upload may stay PROCESSING before the poller gives up on it,
+// unless the destination sets its own deadline (see PendingDeadliner)
+const MaxPendingAge = time.Hour
+
+// pendingDeadline returns how long the poller keeps checking uploadID at dest
+func pendingDeadline(dest Destination, uploadID string) time.Duration {
+	if d, ok := dest.(PendingDeadliner); ok {
+		return d.PendingDeadline(uploadID)
+	}
+	return MaxPendingAge
+}
+
+// PendingUploadID is the PendingUpload document ID for a destination upload
+func PendingUploadID(destName, uploadID string) string {
+	return destName + "-" + uploadID
+}
+
+// TrackPendingUpload persists a PendingUpload for an upload the destination is still processing
+// and publishes the first status check for the upload-status-poller. Returns the PendingUpload ID.
+func TrackPendingUpload(ctx context.Context, fwCtx *framework.FrameworkContext, destName string, eventPayload *pb.EnrichedActivityEvent, uploadID string) (string, error) {
+	now := timestamppb.Now()
+	pending := &pb.PendingUpload{
+		Id:                  PendingUploadID(destName, uploadID),
+		UserId:              eventPayload.UserId,
+		Destination:         destName,
+		UploadId:            uploadID,
+		Status:              pb.PendingUpload_STATUS_PROCESSING,
+		ExecutionId:         fwCtx.ExecutionID,
+		PipelineExecutionId: fwCtx.PipelineExecutionId,
+		OriginalEvent:       eventPayload,
+		CreatedAt:           now,
+		UpdatedAt:           now,
+	}
+	if err := fwCtx.Service.DB.CreatePendingUpload(ctx, pending); err != nil {
+		return "", fmt.Errorf("failed to persist pending upload: %w", err)
+	}
+
+	check := &pb.UploadStatusCheck{
+		PendingUploadId:     pending.Id,
+		UserId:              pending.UserId,
+		PipelineExecutionId: pending.PipelineExecutionId,
+	}
+	checkEvent, err := infrapubsub.NewCloudEvent(
+		infrapubsub.GetCloudEventSource(pb.CloudEventSource_CLOUD_EVENT_SOURCE_UPLOADER),
+		infrapubsub.GetCloudEventType(pb.CloudEventType_CLOUD_EVENT_TYPE_UPLOAD_STATUS_CHECK),
+		check,
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to create status check event: %w", err)
+	}
+	checkEvent.SetExtension("pipeline_execution_id", pending.PipelineExecutionId)
+
+	if _, err := fwCtx.Service.Pub.PublishCloudEvent(ctx, shared.TopicUploadStatusCheck, checkEvent); err != nil {
+		return "", fmt.Errorf("failed to publish status check: %w", err)
+	}
+
+	fwCtx.Logger.Info("Tracking pending upload", "pending_upload_id", pending.Id)
+	return pending.Id, nil
+}
+
+// StatusCheckHandler returns the framework handler for the upload-status-poller: it checks a
+// PendingUpload with its destination and, once the upload resolves, records the
+// SynchronizedActivity and updates the original uploader execution.
+// While the upload is still processing the handler returns an error, so Pub/Sub redelivers the
+// check with backoff; after MaxPendingAge (or the destination's PendingDeadline) the upload
+// is marked FAILED.
+func StatusCheckHandler() framework.HandlerFunc {
+	return func(ctx context.Context, e event.Event, fwCtx *framework.FrameworkContext) (interface{}, error) {
+		var check pb.UploadStatusCheck
+		unmarshaler := protojson.UnmarshalOptions{DiscardUnknown: true}
+		if err := unmarshaler.Unmarshal(e.Data(), &check); err != nil {
+			return nil, fmt.Errorf("protojson.Unmarshal: %w", err)
+		}
+
+		pending, err := fwCtx.Service.DB.GetPendingUpload(ctx, check.PendingUploadId)
+		if status.Code(err) == codes.NotFound {
+			fwCtx.Logger.Warn("Pending upload not found", "pending_upload_id", check.PendingUploadId)
+			return map[string]interface{}{"status": "SKIPPED", "pending_upload_id": check.PendingUploadId}, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load pending upload: %w", err)
+		}
+		if pending.Status != pb.PendingUpload_STATUS_PROCESSING {
+			// Duplicate delivery of a check that already resolved the upload
+			return map[string]interface{}{
+				"status":            "SKIPPED",
+				"pending_upload_id": pending.Id,
+				"upload_status":     pending.Status.String(),
+			}, nil
+		}
+		if pending.OriginalEvent == nil {
+			pending.OriginalEvent = &pb.EnrichedActivityEvent{UserId: pending.UserId}
+		}
+
+		attempts := pending.Attempts + 1
+		dest, ok := GetByName(pending.Destination)
+		if !ok {
+			return resolvePendingUpload(ctx, fwCtx, pending, attempts, &Result{
+				Status:   StatusFailed,
+				UploadID: pending.UploadId,
+				Error:    fmt.Sprintf("unknown destination %q", pending.Destination),
+			})
+		}
+
+		fwCtx.Logger.Info("Checking upload status", "destination", dest.Name(), "upload_id", pending.UploadId, "attempt", attempts)
+		res, err := dest.Status(ctx, &Request{
+			Event:    pending.OriginalEvent,
+			UploadID: pending.UploadId,
+			Service:  fwCtx.Service,
+			Logger:   fwCtx.Logger,
+		})
+		if err == nil && res.Status != StatusProcessing {
+			return resolvePendingUpload(ctx, fwCtx, pending, attempts, res)
+		}
+
+		if deadline := pendingDeadline(dest, pending.UploadId); time.Since(pending.CreatedAt.AsTime()) > deadline {
+			fwCtx.Logger.Warn("Giving up on pending upload", "pending_upload_id", pending.Id, "attempts", attempts)
+			return resolvePendingUpload(ctx, fwCtx, pending, attempts, &Result{
+				Status:   StatusFailed,
+				UploadID: pending.UploadId,
+				Error:    fmt.Sprintf("upload still processing after %s", deadline),
+			})
+		}
+
+		if updateErr := fwCtx.Service.DB.UpdatePendingUpload(ctx, pending.Id, map[string]interface{}{
+			"attempts":   attempts,
+			"updated_at": time.Now(),
+		}); updateErr != nil {
+			fwCtx.Logger.Warn("Failed to record status check attempt", "error", updateErr)
+		}
+
+		outputs := map[string]interface{}{
+			"status":            "STATUS_LAGGED_RETRY",
+			"pending_upload_id": pending.Id,
+			"attempts":          attempts,
+		}
+		if err != nil {
+			return outputs, fmt.Errorf("%s status check failed: %w", dest.Name(), err)
+		}
+		return outputs, fmt.Errorf("%s upload %s still processing (status=STATUS_LAGGED_RETRY)", dest.Name(), pending.UploadId)
+	}
+}
+
+// resolvePendingUpload records the final result of a pending upload: the SynchronizedActivity
+// (when complete), the PendingUpload itself and the original uploader execution.
+func resolvePendingUpload(ctx context.Context, fwCtx *framework.FrameworkContext, pending *pb.PendingUpload, attempts int32, res *Result) (interface{}, error) {
+	db := fwCtx.Service.DB
+	outputs := uploadOutputs(pending.Destination, pending.OriginalEvent, res)
+	outputs["pending_upload_id"] = pending.Id
+
+	now := time.Now()
+	update := map[string]interface{}{
+		"attempts":     attempts,
+		"updated_at":   now,
+		"completed_at": now,
+	}
+	if res.Status == StatusComplete {
+		if res.ExternalID != "" {
+			RecordSync(ctx, fwCtx, pending.Destination, pending.OriginalEvent, res.ExternalID)
+		}
+		update["status"] = int32(pb.PendingUpload_STATUS_COMPLETED)
+		update["external_id"] = res.ExternalID
+	} else {
+		update["status"] = int32(pb.PendingUpload_STATUS_FAILED)
+		update["error"] = res.Error
+	}
+	if err := db.UpdatePendingUpload(ctx, pending.Id, update); err != nil {
+		// Retry the check: recording the sync again is harmless
+		return outputs, fmt.Errorf("failed to update pending upload: %w", err)
+	}
+
+	if pending.ExecutionId != "" {
+		var logErr error
+		if res.Status == StatusComplete {
+			logErr = execution.LogSuccess(ctx, db, pending.ExecutionId, outputs)
+		} else {
+			logErr = execution.LogFailure(ctx, db, pending.ExecutionId, fmt.Errorf("%s upload failed: %s", pending.Destination, res.Error), outputs)
+		}
+		if logErr != nil {
+			fwCtx.Logger.Warn("Failed to update uploader execution", "execution_id", pending.ExecutionId, "error", logErr)
+		}
+	}
+
+	fwCtx.Logger.Info("Pending upload resolved", "pending_upload_id", pending.Id, "status", res.Status, "external_id", res.ExternalID)
+	return outputs, nil
+}
@@ -0,0 +1,210 @@
+package destinations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/ripixel/fitglue-server/src/go/pkg/bootstrap"
+	"github.com/ripixel/fitglue-server/src/go/pkg/framework"
+	"github.com/ripixel/fitglue-server/src/go/pkg/infrastructure/database"
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+)
+
+func TestStatusCheckHandler(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		destination string
+		status      *Result
+		statusErr   error
+		age         time.Duration
+		deadline    time.Duration // Destination's PendingDeadline (0 = not a PendingDeadliner)
+		resolved    bool          // PendingUpload already COMPLETED
+		wantErr     bool          // Check should be redelivered
+		wantPending pb.PendingUpload_Status
+		wantExec    pb.ExecutionStatus // Expected uploader execution status (UNKNOWN = untouched)
+		wantSynced  string
+	}{
+		{
+			name:        "Completed upload records sync and updates execution",
+			destination: "fake",
+			status:      &Result{Status: StatusComplete, ExternalID: "act-1", UploadID: "up-1"},
+			wantPending: pb.PendingUpload_STATUS_COMPLETED,
+			wantExec:    pb.ExecutionStatus_STATUS_SUCCESS,
+			wantSynced:  "act-1",
+		},
+		{
+			name:        "Still processing is retried",
+			destination: "fake",
+			status:      &Result{Status: StatusProcessing, UploadID: "up-1"},
+			wantErr:     true,
+			wantPending: pb.PendingUpload_STATUS_PROCESSING,
+		},
+		{
+			name:        "Status error is retried",
+			destination: "fake",
+			statusErr:   errors.New("strava unavailable"),
+			wantErr:     true,
+			wantPending: pb.PendingUpload_STATUS_PROCESSING,
+		},
+		{
+			name:        "Processing error fails the upload",
+			destination: "fake",
+			status:      &Result{Status: StatusFailed, UploadID: "up-1", Error: "duplicate of activity 42"},
+			wantPending: pb.PendingUpload_STATUS_FAILED,
+			wantExec:    pb.ExecutionStatus_STATUS_FAILED,
+		},
+		{
+			name:        "Gives up after MaxPendingAge",
+			destination: "fake",
+			status:      &Result{Status: StatusProcessing, UploadID: "up-1"},
+			age:         MaxPendingAge + time.Minute,
+			wantPending: pb.PendingUpload_STATUS_FAILED,
+			wantExec:    pb.ExecutionStatus_STATUS_FAILED,
+		},
+		{
+			name:        "Destination deadline keeps checking past MaxPendingAge",
+			destination: "fake",
+			status:      &Result{Status: StatusProcessing, UploadID: "up-1"},
+			age:         MaxPendingAge + time.Minute,
+			deadline:    24 * time.Hour,
+			wantErr:     true,
+			wantPending: pb.PendingUpload_STATUS_PROCESSING,
+		},
+		{
+			name:        "Completion after MaxPendingAge within the destination deadline records sync",
+			destination: "fake",
+			status:      &Result{Status: StatusComplete, ExternalID: "act-1", UploadID: "up-1"},
+			age:         3 * time.Hour,
+			deadline:    24 * time.Hour,
+			wantPending: pb.PendingUpload_STATUS_COMPLETED,
+			wantExec:    pb.ExecutionStatus_STATUS_SUCCESS,
+			wantSynced:  "act-1",
+		},
+		{
+			name:        "Gives up after the destination deadline",
+			destination: "fake",
+			status:      &Result{Status: StatusProcessing, UploadID: "up-1"},
+			age:         25 * time.Hour,
+			deadline:    24 * time.Hour,
+			wantPending: pb.PendingUpload_STATUS_FAILED,
+			wantExec:    pb.ExecutionStatus_STATUS_FAILED,
+		},
+		{
+			name:        "Unknown destination fails the upload",
+			destination: "nowhere",
+			wantPending: pb.PendingUpload_STATUS_FAILED,
+			wantExec:    pb.ExecutionStatus_STATUS_FAILED,
+		},
+		{
+			name:        "Resolved upload is skipped",
+			destination: "fake",
+			status:      &Result{Status: StatusFailed},
+			resolved:    true,
+			wantPending: pb.PendingUpload_STATUS_COMPLETED,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ClearRegistry()
+			defer ClearRegistry()
+			fake := &fakeDestination{status: tt.status, statusErr: tt.statusErr}
+			if tt.deadline > 0 {
+				Register(&deadlineDestination{fakeDestination: fake, deadline: tt.deadline})
+			} else {
+				Register(fake)
+			}
+
+			db := database.NewMemoryDatabase()
+			db.SetExecution(ctx, &pb.ExecutionRecord{ExecutionId: "exec1", Status: pb.ExecutionStatus_STATUS_WAITING})
+
+			pending := &pb.PendingUpload{
+				Id:            PendingUploadID(tt.destination, "up-1"),
+				UserId:        "u1",
+				Destination:   tt.destination,
+				UploadId:      "up-1",
+				Status:        pb.PendingUpload_STATUS_PROCESSING,
+				ExecutionId:   "exec1",
+				OriginalEvent: &pb.EnrichedActivityEvent{ActivityId: "a1", UserId: "u1", Name: "Morning Run"},
+				CreatedAt:     timestamppb.New(time.Now().Add(-tt.age)),
+			}
+			if tt.resolved {
+				pending.Status = pb.PendingUpload_STATUS_COMPLETED
+			}
+			db.CreatePendingUpload(ctx, pending)
+
+			fwCtx := &framework.FrameworkContext{
+				Service:             &bootstrap.Service{DB: db},
+				Logger:              slog.Default(),
+				PipelineExecutionId: "pe1",
+			}
+			e := event.New()
+			e.SetType("com.fitglue.upload.status_check")
+			e.SetSource("/test")
+			data, _ := json.Marshal(map[string]string{"pending_upload_id": pending.Id, "user_id": "u1"})
+			e.SetData(event.ApplicationJSON, data)
+
+			_, err := StatusCheckHandler()(ctx, e, fwCtx)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			got, err := db.GetPendingUpload(ctx, pending.Id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Status != tt.wantPending {
+				t.Errorf("Pending status = %v, want %v", got.Status, tt.wantPending)
+			}
+			if !tt.resolved && got.Attempts != 1 {
+				t.Errorf("Attempts = %d, want 1", got.Attempts)
+			}
+			if tt.wantPending == pb.PendingUpload_STATUS_FAILED && got.Error == "" {
+				t.Error("Expected failure reason on pending upload")
+			}
+
+			exec, err := db.GetExecution(ctx, "exec1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantExec := tt.wantExec
+			if wantExec == pb.ExecutionStatus_STATUS_UNKNOWN {
+				wantExec = pb.ExecutionStatus_STATUS_WAITING
+			}
+			if exec.Status != wantExec {
+				t.Errorf("Execution status = %v, want %v", exec.Status, wantExec)
+			}
+
+			synced, err := db.GetSynchronizedActivity(ctx, "u1", "a1")
+			if tt.wantSynced == "" {
+				if err == nil {
+					t.Errorf("Expected no synchronized activity, got %+v", synced)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected synchronized activity: %v", err)
+			}
+			if synced.Destinations["fake"] != tt.wantSynced || synced.PipelineExecutionId != "pe1" {
+				t.Errorf("Unexpected synchronized activity: %+v", synced)
+			}
+		})
+	}
+}
+
+// deadlineDestination is a fakeDestination with its own pending deadline
+type deadlineDestination struct {
+	*fakeDestination
+	deadline time.Duration
+}
+
+func (d *deadlineDestination) PendingDeadline(string) time.Duration { return d.deadline }
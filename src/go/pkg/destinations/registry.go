@@ -0,0 +1,73 @@
+package destinations
+
+import (
+	"log"
+	"sync"
+
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+)
+
+var (
+	registryMu   sync.RWMutex
+	registry     = make(map[string]Destination)
+	typeRegistry = make(map[pb.Destination]Destination)
+)
+
+// Register adds a destination to the registry.
+// Ideally called in init() functions of destinations.
+func Register(d Destination) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	name := d.Name()
+	if _, exists := registry[name]; exists {
+		log.Panicf("Destination already registered for name: %s", name)
+	}
+	registry[name] = d
+
+	t := d.DestinationType()
+	if t != pb.Destination_DESTINATION_UNSPECIFIED {
+		if _, exists := typeRegistry[t]; exists {
+			log.Panicf("Destination already registered for type: %v", t)
+		}
+		typeRegistry[t] = d
+	}
+}
+
+// GetAll returns all registered destinations.
+func GetAll() []Destination {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	destinations := make([]Destination, 0, len(registry))
+	for _, d := range registry {
+		destinations = append(destinations, d)
+	}
+	return destinations
+}
+
+// GetByName returns a specific destination by name.
+func GetByName(name string) (Destination, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	d, ok := registry[name]
+	return d, ok
+}
+
+// GetByType returns a specific destination by type.
+func GetByType(t pb.Destination) (Destination, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	d, ok := typeRegistry[t]
+	return d, ok
+}
+
+// ClearRegistry removes all destinations (useful for tests)
+func ClearRegistry() {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry = make(map[string]Destination)
+	typeRegistry = make(map[pb.Destination]Destination)
+}
@@ -0,0 +1,35 @@
+package destinations
+
+import (
+	"testing"
+
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+)
+
+func TestRegistry(t *testing.T) {
+	ClearRegistry()
+	defer ClearRegistry()
+
+	dest := &fakeDestination{}
+	Register(dest)
+
+	if got, ok := GetByName("fake"); !ok || got != dest {
+		t.Errorf("GetByName: expected fake destination, got %v", got)
+	}
+	if got, ok := GetByType(pb.Destination_DESTINATION_MOCK); !ok || got != dest {
+		t.Errorf("GetByType: expected fake destination, got %v", got)
+	}
+	if _, ok := GetByType(pb.Destination_DESTINATION_STRAVA); ok {
+		t.Error("GetByType: expected no strava destination")
+	}
+	if all := GetAll(); len(all) != 1 {
+		t.Errorf("GetAll: expected 1 destination, got %d", len(all))
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected duplicate registration to panic")
+		}
+	}()
+	Register(&fakeDestination{})
+}
@@ -0,0 +1,135 @@
+package strava
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ripixel/fitglue-server/src/go/pkg/destinations"
+	stravaapi "github.com/ripixel/fitglue-server/src/go/pkg/integrations/strava"
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+)
+
+// Tolerances for matching an enriched activity to one the watch already synced to Strava
+const (
+	matchStartTolerance    = 5 * time.Minute
+	matchDurationTolerance = 0.1 // Fraction of the activity's elapsed time
+	matchMinDuration       = time.Minute
+)
+
+// matchUploadPrefix marks the UploadID of an update-mode activity that has no Strava match yet.
+// The upload-status-poller calls Status with it, which retries the match until the watch syncs.
+const matchUploadPrefix = "match-"
+
+func isMatchUploadID(uploadID string) bool {
+	return strings.HasPrefix(uploadID, matchUploadPrefix)
+}
+
+// MatchPendingDeadline is how long the status poller keeps looking for the watch's own upload
+// of an update-mode activity. Watches can sync hours after the activity, so this is far longer
+// than destinations.MaxPendingAge; sub-upload-status-check retains checks for slightly longer.
+const MatchPendingDeadline = 24 * time.Hour
+
+// PendingDeadline gives update-mode matches MatchPendingDeadline, and FIT uploads the default
+func (d *StravaDestination) PendingDeadline(uploadID string) time.Duration {
+	if isMatchUploadID(uploadID) {
+		return MatchPendingDeadline
+	}
+	return destinations.MaxPendingAge
+}
+
+// updateExisting finds the athlete's Strava activity matching the event by start time and
+// duration and patches it in place. Without a match the result is PROCESSING, so the
+// status poller keeps looking until the watch's own upload appears.
+func (d *StravaDestination) updateExisting(ctx context.Context, req *destinations.Request) (*destinations.Result, error) {
+	ev := req.Event
+	if ev.StartTime == nil {
+		return &destinations.Result{Status: destinations.StatusFailed, Error: "update mode requires the activity start time"}, nil
+	}
+
+	api, err := d.apiClient(req)
+	if err != nil {
+		return nil, err
+	}
+	start := ev.StartTime.AsTime()
+	after := int(start.Add(-matchStartTolerance).Unix())
+	before := int(start.Add(matchStartTolerance).Unix())
+	perPage := 30
+	resp, err := api.GetLoggedInAthleteActivitiesWithResponse(ctx, &stravaapi.GetLoggedInAthleteActivitiesParams{
+		After:   &after,
+		Before:  &before,
+		PerPage: &perPage,
+	})
+	if err != nil {
+		req.Logger.Error("Strava API Error", "error", err)
+		return nil, fmt.Errorf("Strava API Error: %w", err)
+	}
+	if resp.StatusCode() >= 400 || resp.JSON200 == nil {
+		req.Logger.Error("Strava request failed", "method", "GET", "path", "/athlete/activities", "status", resp.StatusCode(), "body", string(resp.Body))
+		return nil, fmt.Errorf("strava request failed: status %d", resp.StatusCode())
+	}
+
+	match := matchActivity(*resp.JSON200, start, elapsedTime(ev))
+	if match == nil {
+		req.Logger.Info("No matching Strava activity yet", "start_time", start)
+		return &destinations.Result{
+			Status:   destinations.StatusProcessing,
+			UploadID: matchUploadPrefix + ev.ActivityId,
+			Metadata: map[string]interface{}{"strava_mode": "update_existing"},
+		}, nil
+	}
+
+	externalID := strconv.FormatInt(*match.Id, 10)
+	updateReq := *req
+	updateReq.ExternalID = externalID
+	res, err := d.Update(ctx, &updateReq)
+	if err != nil {
+		return nil, err
+	}
+	req.Logger.Info("Updated existing Strava activity", "activity_id", externalID)
+	res.UploadID = req.UploadID
+	res.Metadata = map[string]interface{}{
+		"strava_mode":        "update_existing",
+		"strava_activity_id": *match.Id,
+	}
+	return res, nil
+}
+
+// matchActivity returns the activity starting closest to start whose elapsed time agrees with
+// elapsed (when known), or nil
+func matchActivity(activities []stravaapi.SummaryActivity, start time.Time, elapsed time.Duration) *stravaapi.SummaryActivity {
+	var best *stravaapi.SummaryActivity
+	bestOffset := matchStartTolerance + 1
+	for i := range activities {
+		a := &activities[i]
+		if a.Id == nil || a.StartDate == nil {
+			continue
+		}
+		offset := a.StartDate.Sub(start).Abs()
+		if offset > matchStartTolerance {
+			continue
+		}
+		if elapsed > 0 && a.ElapsedTime != nil {
+			tolerance := max(time.Duration(float64(elapsed)*matchDurationTolerance), matchMinDuration)
+			if (time.Duration(*a.ElapsedTime)*time.Second - elapsed).Abs() > tolerance {
+				continue
+			}
+		}
+		if offset < bestOffset {
+			best, bestOffset = a, offset
+		}
+	}
+	return best
+}
+
+// elapsedTime is the activity's total elapsed time across sessions (0 when unknown)
+func elapsedTime(ev *pb.EnrichedActivityEvent) time.Duration {
+	var seconds float64
+	for _, s := range ev.GetActivityData().GetSessions() {
+		seconds += s.TotalElapsedTime
+	}
+	return time.Duration(math.Round(seconds)) * time.Second
+}
@@ -0,0 +1,425 @@
+package strava
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	shared "github.com/ripixel/fitglue-server/src/go/pkg"
+	"github.com/ripixel/fitglue-server/src/go/pkg/destinations"
+	"github.com/ripixel/fitglue-server/src/go/pkg/domain/activity"
+	"github.com/ripixel/fitglue-server/src/go/pkg/infrastructure/oauth"
+	stravaapi "github.com/ripixel/fitglue-server/src/go/pkg/integrations/strava"
+	"github.com/ripixel/fitglue-server/src/go/pkg/plugin"
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+)
+
+const apiBase = "https://www.strava.com/api/v3"
+
+// Defaults for the soft poll in Upload
+const (
+	defaultSoftPollTimeout  = 15 * time.Second
+	defaultSoftPollInterval = 2 * time.Second
+)
+
+// duplicatePattern matches Strava's duplicate upload error, which is either plain text
+// ("activity.fit duplicate of activity 123") or an HTML link to the existing activity
+var duplicatePattern = regexp.MustCompile(`duplicate of (?:activity |<a href=['"]/activities/)(\d+)`)
+
+// StravaDestination uploads FIT files to Strava through the uploads API.
+type StravaDestination struct {
+	// HTTPClient overrides the per-user OAuth client (for testing)
+	HTTPClient *http.Client
+
+	// SoftPollTimeout bounds how long Upload waits for Strava to process the file before
+	// returning PROCESSING, leaving the rest to the upload-status-poller.
+	// Zero uses the default (15s); negative skips the soft poll.
+	SoftPollTimeout time.Duration
+	// SoftPollInterval is the delay between status checks during the soft poll (default 2s)
+	SoftPollInterval time.Duration
+}
+
+func init() {
+	destinations.Register(&StravaDestination{})
+
+	plugin.RegisterDestination(pb.Destination_DESTINATION_STRAVA, &pb.PluginManifest{
+		Id:                   "strava",
+		Type:                 pb.PluginType_PLUGIN_TYPE_DESTINATION,
+		Name:                 "Strava",
+		Description:          "Upload activities to Strava",
+		Icon:                 "🚴",
+		Enabled:              true,
+		RequiredIntegrations: []string{"strava"},
+	})
+}
+
+func (d *StravaDestination) Name() string {
+	return "strava"
+}
+
+func (d *StravaDestination) DestinationType() pb.Destination {
+	return pb.Destination_DESTINATION_STRAVA
+}
+
+// client returns the OAuth client (handles auth + token refresh) for the event's user
+func (d *StravaDestination) client(req *destinations.Request) *http.Client {
+	if d.HTTPClient != nil {
+		return d.HTTPClient
+	}
+	tokenSource := oauth.NewFirestoreTokenSource(req.Service, req.Event.UserId, "strava")
+	return oauth.NewClientWithUsageTracking(tokenSource, req.Service, req.Event.UserId, "strava")
+}
+
+// options returns the pipeline's Strava options for the event (empty when none are set)
+func (d *StravaDestination) options(ev *pb.EnrichedActivityEvent) *pb.DestinationOptions {
+	if opts, ok := ev.GetDestinationOptions()[d.Name()]; ok && opts != nil {
+		return opts
+	}
+	return &pb.DestinationOptions{}
+}
+
+// apiClient returns the generated Strava API client for the event's user
+func (d *StravaDestination) apiClient(req *destinations.Request) (*stravaapi.ClientWithResponses, error) {
+	return stravaapi.NewClientWithResponses(apiBase, stravaapi.WithHTTPClient(d.client(req)))
+}
+
+func (d *StravaDestination) Upload(ctx context.Context, req *destinations.Request) (*destinations.Result, error) {
+	// Update mode: enrich the activity the watch already synced instead of uploading a copy
+	if d.options(req.Event).GetMode() == pb.DestinationMode_DESTINATION_MODE_UPDATE_EXISTING {
+		return d.updateExisting(ctx, req)
+	}
+	if req.FitFile == nil {
+		return nil, fmt.Errorf("no FIT file to upload")
+	}
+	ev := req.Event
+
+	// Build multipart form data
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, _ := writer.CreateFormFile("file", "activity.fit")
+	part.Write(req.FitFile)
+	writer.WriteField("data_type", "fit")
+	// Prefixed so the Strava source recognises FitGlue's own uploads and doesn't re-ingest them
+	writer.WriteField("external_id", shared.UploadExternalIDPrefix+ev.ActivityId+".fit")
+	if ev.Name != "" {
+		writer.WriteField("name", ev.Name)
+	}
+	if ev.Description != "" {
+		writer.WriteField("description", ev.Description)
+	}
+	if ev.ActivityType != pb.ActivityType_ACTIVITY_TYPE_UNSPECIFIED {
+		stravaType := activity.GetStravaActivityType(ev.ActivityType)
+		writer.WriteField("sport_type", stravaType)
+		writer.WriteField("activity_type", stravaType) // Legacy fallback
+	}
+	opts := d.options(ev)
+	if opts.Trainer != nil {
+		writer.WriteField("trainer", formFlag(opts.GetTrainer()))
+	}
+	if opts.Commute != nil {
+		writer.WriteField("commute", formFlag(opts.GetCommute()))
+	}
+	writer.Close()
+
+	// Log what we're uploading for debugging
+	req.Logger.Info("Uploading to Strava",
+		"title", ev.Name,
+		"type", ev.ActivityType,
+		"description_length", len(ev.Description),
+		"description_preview", truncateString(ev.Description, 200),
+	)
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", apiBase+"/uploads", body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", writer.FormDataContentType())
+
+	client := d.client(req)
+	var uploadResp stravaUploadResponse
+	if err := doJSON(client, httpReq, &uploadResp, req.Logger); err != nil {
+		return nil, err
+	}
+
+	req.Logger.Info("Upload initiated", "upload_id", uploadResp.ID, "status", uploadResp.Status)
+
+	// Soft Poll: Wait briefly for completion, which covers most uploads.
+	// Anything slower is returned as PROCESSING and resolved by the upload-status-poller.
+	if uploadResp.ActivityID == 0 && uploadResp.Error == "" && d.softPollTimeout() > 0 {
+		finalResp, err := d.waitForUploadCompletion(ctx, client, uploadResp.ID, req.Logger)
+		if err != nil {
+			// Log warning but return PROCESSING so the status poller picks it up
+			req.Logger.Warn("Soft polling finished without final ID (async processing continues)", "error", err)
+		} else {
+			uploadResp = *finalResp
+		}
+	}
+
+	return d.finishUpload(ctx, req, uploadResp.result())
+}
+
+func (d *StravaDestination) Status(ctx context.Context, req *destinations.Request) (*destinations.Result, error) {
+	if isMatchUploadID(req.UploadID) {
+		return d.updateExisting(ctx, req)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", apiBase+"/uploads/"+req.UploadID, nil)
+	if err != nil {
+		return nil, err
+	}
+	var status stravaUploadResponse
+	if err := doJSON(d.client(req), httpReq, &status, req.Logger); err != nil {
+		return nil, err
+	}
+	return d.finishUpload(ctx, req, status.result())
+}
+
+// finishUpload completes a processed upload: options the uploads API cannot set are applied to
+// the new activity, and duplicates are handled according to the pipeline's policy
+func (d *StravaDestination) finishUpload(ctx context.Context, req *destinations.Request, res *destinations.Result) (*destinations.Result, error) {
+	if res.Status == destinations.StatusComplete && needsActivityUpdate(d.options(req.Event)) {
+		updateReq := *req
+		updateReq.ExternalID = res.ExternalID
+		if _, err := d.Update(ctx, &updateReq); err != nil {
+			// The activity exists; failing would re-upload it as a duplicate
+			req.Logger.Warn("Failed to apply activity options after upload", "activity_id", res.ExternalID, "error", err)
+			res.Metadata["strava_options_error"] = err.Error()
+		}
+		return res, nil
+	}
+	return d.resolveDuplicate(ctx, req, res)
+}
+
+// needsActivityUpdate reports whether opts set anything only an activity update can apply
+func needsActivityUpdate(opts *pb.DestinationOptions) bool {
+	return opts.GetGearId() != "" || opts.HideFromHome != nil || opts.GetVisibility() != pb.ActivityVisibility_ACTIVITY_VISIBILITY_UNSPECIFIED
+}
+
+// resolveDuplicate applies the pipeline's duplicate policy when Strava rejected the upload as a
+// duplicate of an existing activity. LINK and UPDATE turn the failure into a completed upload of
+// the existing activity, so it is recorded as the synced copy.
+func (d *StravaDestination) resolveDuplicate(ctx context.Context, req *destinations.Request, res *destinations.Result) (*destinations.Result, error) {
+	if res.Status != destinations.StatusFailed {
+		return res, nil
+	}
+	existingID := duplicateActivityID(res.Error)
+	if existingID == "" {
+		return res, nil
+	}
+	res.Metadata["strava_duplicate_of"] = existingID
+
+	policy := d.options(req.Event).GetDuplicatePolicy()
+	switch policy {
+	case pb.DuplicatePolicy_DUPLICATE_POLICY_LINK:
+	case pb.DuplicatePolicy_DUPLICATE_POLICY_UPDATE:
+		updateReq := *req
+		updateReq.ExternalID = existingID
+		if _, err := d.Update(ctx, &updateReq); err != nil {
+			return nil, fmt.Errorf("failed to update duplicate activity %s: %w", existingID, err)
+		}
+	default:
+		return res, nil
+	}
+
+	req.Logger.Info("Upload is a duplicate, linking existing Strava activity", "activity_id", existingID, "policy", policy)
+	res.Metadata["strava_duplicate_policy"] = policy.String()
+	res.Status = destinations.StatusComplete
+	res.ExternalID = existingID
+	res.Error = ""
+	return res, nil
+}
+
+// duplicateActivityID returns the existing activity ID from a duplicate upload error, or ""
+func duplicateActivityID(uploadErr string) string {
+	if m := duplicatePattern.FindStringSubmatch(uploadErr); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// Update replaces the activity's name, description and sport type, plus the pipeline's
+// configured gear, flags and visibility
+func (d *StravaDestination) Update(ctx context.Context, req *destinations.Request) (*destinations.Result, error) {
+	activityID, err := strconv.ParseInt(req.ExternalID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid strava activity id %q: %w", req.ExternalID, err)
+	}
+	api, err := d.apiClient(req)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(d.activityUpdate(req.Event))
+	if err != nil {
+		return nil, err
+	}
+	resp, err := api.UpdateActivityByIdWithBodyWithResponse(ctx, activityID, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		req.Logger.Error("Strava API Error", "error", err)
+		return nil, fmt.Errorf("Strava API Error: %w", err)
+	}
+	if resp.StatusCode() >= 400 {
+		req.Logger.Error("Strava request failed", "method", "PUT", "activity_id", activityID, "status", resp.StatusCode(), "body", string(resp.Body))
+		return nil, fmt.Errorf("strava request failed: status %d", resp.StatusCode())
+	}
+	return &destinations.Result{Status: destinations.StatusComplete, ExternalID: req.ExternalID}, nil
+}
+
+// activityUpdateBody is the UpdateActivityById body plus visibility, which the Strava API
+// accepts but its OpenAPI spec omits
+type activityUpdateBody struct {
+	stravaapi.UpdateActivityByIdJSONBody
+	Visibility *string `json:"visibility,omitempty"`
+}
+
+// stravaVisibility maps ActivityVisibility to Strava's visibility values
+var stravaVisibility = map[pb.ActivityVisibility]string{
+	pb.ActivityVisibility_ACTIVITY_VISIBILITY_EVERYONE:       "everyone",
+	pb.ActivityVisibility_ACTIVITY_VISIBILITY_FOLLOWERS_ONLY: "followers_only",
+	pb.ActivityVisibility_ACTIVITY_VISIBILITY_ONLY_ME:        "only_me",
+}
+
+// activityUpdate builds the UpdateActivityById body for an enriched event
+func (d *StravaDestination) activityUpdate(ev *pb.EnrichedActivityEvent) activityUpdateBody {
+	opts := d.options(ev)
+	update := activityUpdateBody{UpdateActivityByIdJSONBody: stravaapi.UpdateActivityByIdJSONBody{
+		Name:         &ev.Name,
+		Description:  &ev.Description,
+		Trainer:      opts.Trainer,
+		Commute:      opts.Commute,
+		HideFromHome: opts.HideFromHome,
+	}}
+	if ev.ActivityType != pb.ActivityType_ACTIVITY_TYPE_UNSPECIFIED {
+		sportType := stravaapi.UpdateActivityByIdJSONBodySportType(activity.GetStravaActivityType(ev.ActivityType))
+		update.SportType = &sportType
+	}
+	if gearID := opts.GetGearId(); gearID != "" {
+		update.GearId = &gearID
+	}
+	if visibility, ok := stravaVisibility[opts.GetVisibility()]; ok {
+		update.Visibility = &visibility
+	}
+	return update
+}
+
+// formFlag renders a boolean uploads API form field
+func formFlag(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+// Delete is not possible: Strava's API does not allow deleting activities
+func (d *StravaDestination) Delete(ctx context.Context, req *destinations.Request) error {
+	return destinations.ErrNotSupported
+}
+
+type stravaUploadResponse struct {
+	ID         int64  `json:"id"`
+	ExternalID string `json:"external_id"`
+	ActivityID int64  `json:"activity_id"`
+	Status     string `json:"status"`
+	Error      string `json:"error"`
+}
+
+func (r *stravaUploadResponse) result() *destinations.Result {
+	res := &destinations.Result{
+		Status:   destinations.StatusProcessing,
+		UploadID: strconv.FormatInt(r.ID, 10),
+		Error:    r.Error,
+		Metadata: map[string]interface{}{
+			"strava_upload_id":   r.ID,
+			"strava_activity_id": r.ActivityID,
+			"strava_status":      r.Status,
+		},
+	}
+	switch {
+	case r.Error != "":
+		res.Status = destinations.StatusFailed
+	case r.ActivityID != 0:
+		res.Status = destinations.StatusComplete
+		res.ExternalID = strconv.FormatInt(r.ActivityID, 10)
+	}
+	return res
+}
+
+// doJSON executes a Strava API request and decodes the JSON response
+func doJSON(client *http.Client, req *http.Request, out interface{}, logger *slog.Logger) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		logger.Error("Strava API Error", "error", err)
+		return fmt.Errorf("Strava API Error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		logger.Error("Strava request failed", "method", req.Method, "path", req.URL.Path, "status", resp.StatusCode, "body", string(bodyBytes))
+		return fmt.Errorf("strava request failed: status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode strava response: %w", err)
+	}
+	return nil
+}
+
+func (d *StravaDestination) softPollTimeout() time.Duration {
+	if d.SoftPollTimeout == 0 {
+		return defaultSoftPollTimeout
+	}
+	return d.SoftPollTimeout
+}
+
+func (d *StravaDestination) waitForUploadCompletion(ctx context.Context, client *http.Client, uploadID int64, logger *slog.Logger) (*stravaUploadResponse, error) {
+	interval := d.SoftPollInterval
+	if interval <= 0 {
+		interval = defaultSoftPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	timeout := time.After(d.softPollTimeout())
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timeout:
+			return nil, fmt.Errorf("timeout waiting for upload processing")
+		case <-ticker.C:
+			req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/uploads/%d", apiBase, uploadID), nil)
+			if err != nil {
+				return nil, err
+			}
+
+			var status stravaUploadResponse
+			if err := doJSON(client, req, &status, logger); err != nil {
+				logger.Warn("Failed to poll upload status", "error", err)
+				continue
+			}
+
+			logger.Info("Polled upload status", "status", status.Status, "activity_id", status.ActivityID, "error", status.Error)
+
+			if status.ActivityID != 0 || status.Error != "" {
+				return &status, nil
+			}
+			// Continue polling if still processing (activity_id == 0 and no error)
+		}
+	}
+}
+
+// truncateString truncates a string to maxLen characters, adding "..." if truncated
+func truncateString(s string, maxLen int) string {
+	if len(s) <= maxLen {
+		return s
+	}
+	return s[:maxLen] + "..."
+}
@@ -0,0 +1,298 @@
+package strava
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/ripixel/fitglue-server/src/go/pkg/destinations"
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+)
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func jsonResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+	}
+}
+
+func TestStravaDestination(t *testing.T) {
+	ctx := context.Background()
+	event := &pb.EnrichedActivityEvent{
+		UserId:       "u1",
+		Name:         "Leg Day",
+		Description:  "Squats",
+		ActivityType: pb.ActivityType_ACTIVITY_TYPE_WEIGHT_TRAINING,
+	}
+
+	t.Run("Upload completing immediately", func(t *testing.T) {
+		dest := &StravaDestination{HTTPClient: &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			return jsonResponse(201, `{"id": 5, "activity_id": 77, "status": "Your activity is ready."}`), nil
+		})}}
+		res, err := dest.Upload(ctx, &destinations.Request{Event: event, FitFile: []byte("FIT"), Logger: slog.Default()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Status != destinations.StatusComplete || res.ExternalID != "77" || res.UploadID != "5" {
+			t.Errorf("Unexpected result: %+v", res)
+		}
+	})
+
+	t.Run("Upload with activity flags", func(t *testing.T) {
+		commute := true
+		flagged := &pb.EnrichedActivityEvent{
+			UserId: "u1",
+			Name:   "Ride to work",
+			DestinationOptions: map[string]*pb.DestinationOptions{
+				"strava": {Commute: &commute, Visibility: pb.ActivityVisibility_ACTIVITY_VISIBILITY_FOLLOWERS_ONLY},
+			},
+		}
+		var form map[string][]string
+		var update map[string]interface{}
+		dest := &StravaDestination{HTTPClient: &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Method == "PUT" {
+				json.NewDecoder(req.Body).Decode(&update)
+				return jsonResponse(200, `{"id": 77}`), nil
+			}
+			req.ParseMultipartForm(1 << 20)
+			form = req.MultipartForm.Value
+			return jsonResponse(201, `{"id": 5, "activity_id": 77, "status": "Your activity is ready."}`), nil
+		})}}
+		res, err := dest.Upload(ctx, &destinations.Request{Event: flagged, FitFile: []byte("FIT"), Logger: slog.Default()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Status != destinations.StatusComplete || res.ExternalID != "77" {
+			t.Errorf("Unexpected result: %+v", res)
+		}
+		if got := form["commute"]; len(got) != 1 || got[0] != "1" || form["trainer"] != nil {
+			t.Errorf("Unexpected upload flags: %v", form)
+		}
+		// Visibility is not an upload field, so the new activity is updated
+		if update["visibility"] != "followers_only" || update["commute"] != true {
+			t.Errorf("Unexpected update body: %v", update)
+		}
+	})
+
+	t.Run("Upload rejected by Strava", func(t *testing.T) {
+		dest := &StravaDestination{HTTPClient: &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			return jsonResponse(201, `{"id": 5, "error": "duplicate of activity 12", "status": "There was an error processing your activity."}`), nil
+		})}}
+		res, err := dest.Upload(ctx, &destinations.Request{Event: event, FitFile: []byte("FIT"), Logger: slog.Default()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Status != destinations.StatusFailed || res.Error != "duplicate of activity 12" || res.ExternalID != "" {
+			t.Errorf("Unexpected result: %+v", res)
+		}
+	})
+
+	t.Run("Duplicate policies", func(t *testing.T) {
+		tests := []struct {
+			name         string
+			uploadErr    string
+			policy       pb.DuplicatePolicy
+			wantStatus   destinations.UploadStatus
+			wantExternal string
+			wantUpdate   bool
+		}{
+			{name: "Default fails", uploadErr: "duplicate of activity 12", wantStatus: destinations.StatusFailed},
+			{name: "Link", uploadErr: "duplicate of activity 12", policy: pb.DuplicatePolicy_DUPLICATE_POLICY_LINK, wantStatus: destinations.StatusComplete, wantExternal: "12"},
+			{name: "Update from HTML error", uploadErr: "activity.fit duplicate of <a href='/activities/34' target='_blank'>Leg Day</a>", policy: pb.DuplicatePolicy_DUPLICATE_POLICY_UPDATE, wantStatus: destinations.StatusComplete, wantExternal: "34", wantUpdate: true},
+			{name: "Other errors still fail", uploadErr: "Improperly formatted data.", policy: pb.DuplicatePolicy_DUPLICATE_POLICY_LINK, wantStatus: destinations.StatusFailed},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var update map[string]string
+				dest := &StravaDestination{HTTPClient: &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+					if req.Method == "PUT" {
+						if req.URL.Path != "/api/v3/activities/"+tt.wantExternal {
+							t.Errorf("Unexpected update path: %s", req.URL.Path)
+						}
+						json.NewDecoder(req.Body).Decode(&update)
+						return jsonResponse(200, `{"id": 1}`), nil
+					}
+					body, _ := json.Marshal(map[string]interface{}{"id": 5, "error": tt.uploadErr, "status": "There was an error processing your activity."})
+					return jsonResponse(201, string(body)), nil
+				})}}
+				ev := &pb.EnrichedActivityEvent{
+					UserId:       "u1",
+					Name:         "Leg Day",
+					ActivityType: pb.ActivityType_ACTIVITY_TYPE_WEIGHT_TRAINING,
+					DestinationOptions: map[string]*pb.DestinationOptions{
+						"strava": {DuplicatePolicy: tt.policy, GearId: "g7"},
+					},
+				}
+
+				res, err := dest.Upload(ctx, &destinations.Request{Event: ev, FitFile: []byte("FIT"), Logger: slog.Default()})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if res.Status != tt.wantStatus || res.ExternalID != tt.wantExternal {
+					t.Errorf("Unexpected result: %+v", res)
+				}
+				if (update != nil) != tt.wantUpdate {
+					t.Fatalf("Update sent = %v, want %v", update != nil, tt.wantUpdate)
+				}
+				if tt.wantUpdate && (update["name"] != "Leg Day" || update["gear_id"] != "g7") {
+					t.Errorf("Unexpected update body: %v", update)
+				}
+			})
+		}
+	})
+
+	t.Run("Status", func(t *testing.T) {
+		dest := &StravaDestination{HTTPClient: &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Method != "GET" || req.URL.Path != "/api/v3/uploads/5" {
+				t.Errorf("Unexpected request: %s %s", req.Method, req.URL.Path)
+			}
+			return jsonResponse(200, `{"id": 5, "status": "Your activity is still being processed."}`), nil
+		})}}
+		res, err := dest.Status(ctx, &destinations.Request{Event: event, UploadID: "5", Logger: slog.Default()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Status != destinations.StatusProcessing || res.ExternalID != "" {
+			t.Errorf("Unexpected result: %+v", res)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		var body map[string]string
+		dest := &StravaDestination{HTTPClient: &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Method != "PUT" || req.URL.Path != "/api/v3/activities/77" {
+				t.Errorf("Unexpected request: %s %s", req.Method, req.URL.Path)
+			}
+			json.NewDecoder(req.Body).Decode(&body)
+			return jsonResponse(200, `{"id": 77}`), nil
+		})}}
+		res, err := dest.Update(ctx, &destinations.Request{Event: event, ExternalID: "77", Logger: slog.Default()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Status != destinations.StatusComplete || res.ExternalID != "77" {
+			t.Errorf("Unexpected result: %+v", res)
+		}
+		if body["name"] != "Leg Day" || body["description"] != "Squats" || body["sport_type"] != "WeightTraining" {
+			t.Errorf("Unexpected update body: %v", body)
+		}
+	})
+
+	t.Run("Update API error", func(t *testing.T) {
+		dest := &StravaDestination{HTTPClient: &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			return jsonResponse(404, `{"message": "Record Not Found"}`), nil
+		})}}
+		if _, err := dest.Update(ctx, &destinations.Request{Event: event, ExternalID: "77", Logger: slog.Default()}); err == nil {
+			t.Error("Expected error for 404")
+		}
+	})
+
+	t.Run("Update mode", func(t *testing.T) {
+		start := time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)
+		updateEvent := &pb.EnrichedActivityEvent{
+			ActivityId:   "a1",
+			UserId:       "u1",
+			Name:         "Leg Day",
+			ActivityType: pb.ActivityType_ACTIVITY_TYPE_WEIGHT_TRAINING,
+			StartTime:    timestamppb.New(start),
+			ActivityData: &pb.StandardizedActivity{Sessions: []*pb.Session{{TotalElapsedTime: 3600}}},
+			DestinationOptions: map[string]*pb.DestinationOptions{
+				"strava": {Mode: pb.DestinationMode_DESTINATION_MODE_UPDATE_EXISTING},
+			},
+		}
+
+		tests := []struct {
+			name         string
+			uploadID     string // Set when called by the status poller
+			activities   string
+			wantStatus   destinations.UploadStatus
+			wantExternal string
+			wantUploadID string
+		}{
+			{
+				name:         "Closest activity with matching duration is updated",
+				activities:   `[{"id": 1, "start_date": "2026-01-10T09:04:00Z", "elapsed_time": 3600}, {"id": 2, "start_date": "2026-01-10T09:01:00Z", "elapsed_time": 3700}, {"id": 3, "start_date": "2026-01-10T09:00:30Z", "elapsed_time": 1200}]`,
+				wantStatus:   destinations.StatusComplete,
+				wantExternal: "2",
+			},
+			{
+				name:         "No match is left processing for the poller",
+				activities:   `[{"id": 3, "start_date": "2026-01-10T09:00:30Z", "elapsed_time": 1200}]`,
+				wantStatus:   destinations.StatusProcessing,
+				wantUploadID: "match-a1",
+			},
+			{
+				name:         "Poller rematches",
+				uploadID:     "match-a1",
+				activities:   `[{"id": 4, "start_date": "2026-01-10T08:59:00Z", "elapsed_time": 3590}]`,
+				wantStatus:   destinations.StatusComplete,
+				wantExternal: "4",
+				wantUploadID: "match-a1",
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var updatedPath string
+				dest := &StravaDestination{HTTPClient: &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+					switch {
+					case req.Method == "GET" && req.URL.Path == "/api/v3/athlete/activities":
+						return jsonResponse(200, tt.activities), nil
+					case req.Method == "PUT":
+						updatedPath = req.URL.Path
+						return jsonResponse(200, `{"id": 1}`), nil
+					}
+					t.Errorf("Unexpected request: %s %s", req.Method, req.URL.Path)
+					return jsonResponse(404, `{}`), nil
+				})}}
+
+				req := &destinations.Request{Event: updateEvent, UploadID: tt.uploadID, Logger: slog.Default()}
+				var res *destinations.Result
+				var err error
+				if tt.uploadID != "" {
+					res, err = dest.Status(ctx, req)
+				} else {
+					res, err = dest.Upload(ctx, req)
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if res.Status != tt.wantStatus || res.ExternalID != tt.wantExternal || res.UploadID != tt.wantUploadID {
+					t.Errorf("Unexpected result: %+v", res)
+				}
+				if tt.wantExternal != "" && updatedPath != "/api/v3/activities/"+tt.wantExternal {
+					t.Errorf("Updated %q, want activity %s", updatedPath, tt.wantExternal)
+				}
+			})
+		}
+	})
+
+	t.Run("Matches wait longer than uploads", func(t *testing.T) {
+		dest := &StravaDestination{}
+		if got := dest.PendingDeadline("match-a1"); got != MatchPendingDeadline {
+			t.Errorf("Match deadline = %s, want %s", got, MatchPendingDeadline)
+		}
+		if got := dest.PendingDeadline("12345"); got != destinations.MaxPendingAge {
+			t.Errorf("Upload deadline = %s, want %s", got, destinations.MaxPendingAge)
+		}
+	})
+
+	t.Run("Delete is not supported", func(t *testing.T) {
+		dest := &StravaDestination{}
+		if err := dest.Delete(ctx, &destinations.Request{Event: event, ExternalID: "77"}); !errors.Is(err, destinations.ErrNotSupported) {
+			t.Errorf("Expected ErrNotSupported, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,223 @@
+package destinations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/ripixel/fitglue-server/src/go/pkg/domain/activity"
+	"github.com/ripixel/fitglue-server/src/go/pkg/framework"
+	infrastorage "github.com/ripixel/fitglue-server/src/go/pkg/infrastructure/storage"
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+)
+
+// UploadHandler returns the framework handler shared by all uploader functions:
+// it decodes the EnrichedActivityEvent, loads the FIT artifact, calls dest.Upload,
+// records the SynchronizedActivity and returns the execution outputs.
+// Uploads the destination is still processing are tracked as a PendingUpload and the
+// execution is left WAITING until the upload-status-poller resolves them.
+// Resynced events (a source update re-running the pipeline) update the activity's existing
+// copy at the destination instead of uploading a new one.
+func UploadHandler(dest Destination) framework.HandlerFunc {
+	return func(ctx context.Context, e event.Event, fwCtx *framework.FrameworkContext) (interface{}, error) {
+		eventPayload, err := DecodeEvent(e)
+		if err != nil {
+			return nil, err
+		}
+
+		fwCtx.Logger.Info("Starting upload", "destination", dest.Name(), "activity_id", eventPayload.ActivityId, "pipeline_id", eventPayload.PipelineId)
+
+		req := &Request{
+			Event:   eventPayload,
+			Service: fwCtx.Service,
+			Logger:  fwCtx.Logger,
+		}
+		if eventPayload.FitFileUri != "" {
+			if req.FitFile, err = LoadFitFile(ctx, fwCtx, eventPayload.FitFileUri); err != nil {
+				return nil, err
+			}
+		}
+
+		if eventPayload.Resync {
+			existing, err := findSyncedCopy(ctx, fwCtx, dest.Name(), eventPayload)
+			if err != nil {
+				return nil, err
+			}
+			if existing != nil {
+				return resyncHandler(ctx, fwCtx, dest, req, existing)
+			}
+			// Not delivered here before (e.g. the destination was added to the pipeline since): upload it
+		}
+
+		res, err := dest.Upload(ctx, req)
+		if err != nil {
+			fwCtx.Logger.Error("Upload failed", "destination", dest.Name(), "error", err)
+			return nil, fmt.Errorf("%s upload failed: %w", dest.Name(), err)
+		}
+
+		fwCtx.Logger.Info("Upload complete", "destination", dest.Name(), "status", res.Status, "external_id", res.ExternalID, "upload_id", res.UploadID)
+
+		if res.ExternalID != "" {
+			RecordSync(ctx, fwCtx, dest.Name(), eventPayload, res.ExternalID)
+		}
+
+		outputs := uploadOutputs(dest.Name(), eventPayload, res)
+
+		if res.Status == StatusFailed {
+			return outputs, fmt.Errorf("%s upload failed: %s", dest.Name(), res.Error)
+		}
+
+		if res.Status == StatusProcessing && res.UploadID != "" {
+			pendingID, err := TrackPendingUpload(ctx, fwCtx, dest.Name(), eventPayload, res.UploadID)
+			if err != nil {
+				// The upload was accepted, so don't fail (and retry) the execution: that would upload it twice
+				fwCtx.Logger.Error("Failed to track pending upload", "destination", dest.Name(), "upload_id", res.UploadID, "error", err)
+			} else {
+				outputs["status"] = "WAITING"
+				outputs["pending_upload_id"] = pendingID
+			}
+		}
+		return outputs, nil
+	}
+}
+
+// findSyncedCopy returns the SynchronizedActivity recording the event's source activity at
+// destName for the event's pipeline, or nil if it was never delivered there
+func findSyncedCopy(ctx context.Context, fwCtx *framework.FrameworkContext, destName string, eventPayload *pb.EnrichedActivityEvent) (*pb.SynchronizedActivity, error) {
+	synced, err := fwCtx.Service.DB.FindSynchronizedActivities(ctx, eventPayload.UserId, eventPayload.Source.String(), eventPayload.GetActivityData().GetExternalId())
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up synchronized activities: %w", err)
+	}
+	for _, s := range synced {
+		if s.PipelineId == eventPayload.PipelineId && s.Destinations[destName] != "" {
+			return s, nil
+		}
+	}
+	return nil, nil
+}
+
+// resyncHandler updates the destination's existing copy of a resynced activity instead of
+// uploading a new one, keeping the original SynchronizedActivity
+func resyncHandler(ctx context.Context, fwCtx *framework.FrameworkContext, dest Destination, req *Request, existing *pb.SynchronizedActivity) (interface{}, error) {
+	eventPayload := req.Event
+	eventPayload.ActivityId = existing.ActivityId
+	req.ExternalID = existing.Destinations[dest.Name()]
+
+	fwCtx.Logger.Info("Resyncing existing activity", "destination", dest.Name(), "external_id", req.ExternalID)
+	res, err := dest.Update(ctx, req)
+	if errors.Is(err, ErrNotSupported) {
+		fwCtx.Logger.Info("Destination cannot update activities, leaving it unchanged", "destination", dest.Name())
+		return map[string]interface{}{
+			"status":      "SKIPPED",
+			"destination": dest.Name(),
+			"external_id": req.ExternalID,
+			"activity_id": existing.ActivityId,
+			"resync":      true,
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s update failed: %w", dest.Name(), err)
+	}
+	if res.ExternalID == "" {
+		res.ExternalID = req.ExternalID
+	}
+
+	if res.Status != StatusFailed {
+		RecordSync(ctx, fwCtx, dest.Name(), eventPayload, res.ExternalID)
+	}
+	outputs := uploadOutputs(dest.Name(), eventPayload, res)
+	outputs["resync"] = true
+	if res.Status == StatusFailed {
+		return outputs, fmt.Errorf("%s update failed: %s", dest.Name(), res.Error)
+	}
+	return outputs, nil
+}
+
+// uploadOutputs builds the execution outputs for an upload result
+func uploadOutputs(destName string, eventPayload *pb.EnrichedActivityEvent, res *Result) map[string]interface{} {
+	outputs := map[string]interface{}{
+		"status":        "SUCCESS",
+		"destination":   destName,
+		"upload_status": string(res.Status),
+		"external_id":   res.ExternalID,
+		"activity_id":   eventPayload.ActivityId,
+		"pipeline_id":   eventPayload.PipelineId,
+		"fit_file_uri":  eventPayload.FitFileUri,
+		"activity_name": eventPayload.Name,
+		"activity_type": activity.GetStravaActivityType(eventPayload.ActivityType),
+		"description":   eventPayload.Description,
+	}
+	if res.UploadID != "" {
+		outputs["upload_id"] = res.UploadID
+	}
+	if res.Error != "" {
+		outputs["upload_error"] = res.Error
+	}
+	for k, v := range res.Metadata {
+		outputs[k] = v
+	}
+	if res.Status == StatusFailed {
+		outputs["status"] = "FAILED"
+	}
+	return outputs
+}
+
+// DecodeEvent unmarshals an EnrichedActivityEvent from a CloudEvent.
+// protojson handles enum strings, which json.Unmarshal (used by DataAs) rejects for int32 fields.
+func DecodeEvent(e event.Event) (*pb.EnrichedActivityEvent, error) {
+	var eventPayload pb.EnrichedActivityEvent
+	unmarshaler := protojson.UnmarshalOptions{
+		DiscardUnknown: true,
+		AllowPartial:   true,
+	}
+	if err := unmarshaler.Unmarshal(e.Data(), &eventPayload); err != nil {
+		return nil, fmt.Errorf("protojson.Unmarshal: %w", err)
+	}
+	return &eventPayload, nil
+}
+
+// LoadFitFile reads a FIT artifact from the service's BlobStore (the URI names the bucket)
+func LoadFitFile(ctx context.Context, fwCtx *framework.FrameworkContext, uri string) ([]byte, error) {
+	bucketName, objectName, err := infrastorage.ParseURI(uri)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fit_file_uri: %w", err)
+	}
+	data, err := fwCtx.Service.Store.Read(ctx, bucketName, objectName)
+	if err != nil {
+		fwCtx.Logger.Error("Artifact read error", "uri", uri, "error", err)
+		return nil, fmt.Errorf("artifact read error: %w", err)
+	}
+	return data, nil
+}
+
+// RecordSync persists the SynchronizedActivity for a completed upload. Failures are logged
+// but not returned: the upload itself succeeded, this is just recording history.
+func RecordSync(ctx context.Context, fwCtx *framework.FrameworkContext, destName string, eventPayload *pb.EnrichedActivityEvent, externalID string) {
+	syncedActivity := &pb.SynchronizedActivity{
+		ActivityId:          eventPayload.ActivityId,
+		Title:               eventPayload.Name,
+		Description:         eventPayload.Description,
+		Type:                eventPayload.ActivityType,
+		Source:              eventPayload.Source.String(), // Original event source (FROM webhook trigger)
+		StartTime:           eventPayload.StartTime,
+		SyncedAt:            timestamppb.Now(),
+		PipelineId:          eventPayload.PipelineId,
+		PipelineExecutionId: fwCtx.PipelineExecutionId, // Link to execution trace
+		ExternalId:          eventPayload.GetActivityData().GetExternalId(),
+		Destinations: map[string]string{
+			destName: externalID,
+		},
+		SourceTitle:       &eventPayload.SourceName,
+		SourceDescription: &eventPayload.SourceDescription,
+		SourceType:        eventPayload.SourceType,
+	}
+	if err := fwCtx.Service.DB.SetSynchronizedActivity(ctx, eventPayload.UserId, syncedActivity); err != nil {
+		fwCtx.Logger.Error("Failed to persist synchronized activity", "error", err)
+		return
+	}
+	fwCtx.Logger.Info("Persisted synchronized activity", "activity_id", eventPayload.ActivityId, "destination", destName)
+}
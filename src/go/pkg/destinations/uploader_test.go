@@ -0,0 +1,284 @@
+package destinations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+
+	shared "github.com/ripixel/fitglue-server/src/go/pkg"
+	"github.com/ripixel/fitglue-server/src/go/pkg/bootstrap"
+	"github.com/ripixel/fitglue-server/src/go/pkg/framework"
+	"github.com/ripixel/fitglue-server/src/go/pkg/infrastructure/database"
+	infrapubsub "github.com/ripixel/fitglue-server/src/go/pkg/infrastructure/pubsub"
+	infrastorage "github.com/ripixel/fitglue-server/src/go/pkg/infrastructure/storage"
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+)
+
+// fakeDestination records the Upload request and returns canned results
+type fakeDestination struct {
+	result *Result
+	err    error
+	got    *Request
+
+	status    *Result // Returned by Status
+	statusErr error
+	update    *Result // Returned by Update (nil = not supported)
+}
+
+func (d *fakeDestination) Name() string                           { return "fake" }
+func (d *fakeDestination) DestinationType() pb.Destination        { return pb.Destination_DESTINATION_MOCK }
+func (d *fakeDestination) Delete(context.Context, *Request) error { return ErrNotSupported }
+func (d *fakeDestination) Update(ctx context.Context, req *Request) (*Result, error) {
+	if d.update == nil {
+		return nil, ErrNotSupported
+	}
+	d.got = req
+	return d.update, nil
+}
+func (d *fakeDestination) Status(context.Context, *Request) (*Result, error) {
+	if d.status == nil && d.statusErr == nil {
+		return nil, ErrNotSupported
+	}
+	return d.status, d.statusErr
+}
+func (d *fakeDestination) Upload(ctx context.Context, req *Request) (*Result, error) {
+	d.got = req
+	return d.result, d.err
+}
+
+func TestUploadHandler(t *testing.T) {
+	ctx := context.Background()
+	payload := &pb.EnrichedActivityEvent{
+		ActivityId:   "a1",
+		UserId:       "u1",
+		PipelineId:   "p1",
+		Name:         "Morning Run",
+		ActivityType: pb.ActivityType_ACTIVITY_TYPE_RUN,
+		Source:       pb.ActivitySource_SOURCE_HEVY,
+		FitFileUri:   infrastorage.URI("artifacts", "activities/u1/a1.fit"),
+	}
+
+	tests := []struct {
+		name        string
+		result      *Result
+		err         error
+		noFit       bool
+		wantErr     bool
+		wantStatus  string
+		wantSynced  string // Expected destinations["fake"] ("" = no record)
+		wantPending bool   // Expect a PendingUpload and a published status check
+	}{
+		{
+			name:       "Completed upload records sync",
+			result:     &Result{Status: StatusComplete, ExternalID: "ext-1", Metadata: map[string]interface{}{"fake_id": 1}},
+			wantStatus: "SUCCESS",
+			wantSynced: "ext-1",
+		},
+		{
+			name:        "Processing upload is tracked for the status poller",
+			result:      &Result{Status: StatusProcessing, UploadID: "up-1"},
+			wantStatus:  "WAITING",
+			wantPending: true,
+		},
+		{
+			name:       "Destination rejection fails the execution",
+			result:     &Result{Status: StatusFailed, Error: "duplicate"},
+			wantErr:    true,
+			wantStatus: "FAILED",
+		},
+		{
+			name:    "Upload error fails the execution",
+			err:     errors.New("boom"),
+			wantErr: true,
+		},
+		{
+			name:    "Missing artifact fails before upload",
+			noFit:   true,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := database.NewMemoryDatabase()
+			store := infrastorage.NewMemoryStore()
+			if !tt.noFit {
+				store.Write(ctx, "artifacts", "activities/u1/a1.fit", []byte("FIT"))
+			}
+			pub := &infrapubsub.MemoryPublisher{}
+			dest := &fakeDestination{result: tt.result, err: tt.err}
+			fwCtx := &framework.FrameworkContext{
+				Service:             &bootstrap.Service{DB: db, Store: store, Pub: pub},
+				Logger:              slog.Default(),
+				ExecutionID:         "exec1",
+				PipelineExecutionId: "pe1",
+			}
+
+			e := event.New()
+			e.SetType("com.fitglue.activity.enriched")
+			e.SetSource("/test")
+			data, _ := json.Marshal(map[string]interface{}{
+				"activity_id":   payload.ActivityId,
+				"user_id":       payload.UserId,
+				"pipeline_id":   payload.PipelineId,
+				"name":          payload.Name,
+				"activity_type": "ACTIVITY_TYPE_RUN", // Enum strings must decode
+				"source":        "SOURCE_HEVY",
+				"fit_file_uri":  payload.FitFileUri,
+			})
+			e.SetData(event.ApplicationJSON, data)
+
+			out, err := UploadHandler(dest)(ctx, e, fwCtx)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.noFit {
+				if dest.got != nil {
+					t.Error("Expected Upload not to be called")
+				}
+				return
+			}
+			if dest.got == nil || string(dest.got.FitFile) != "FIT" || dest.got.Event.ActivityType != pb.ActivityType_ACTIVITY_TYPE_RUN {
+				t.Fatalf("Unexpected upload request: %+v", dest.got)
+			}
+
+			if tt.wantStatus != "" {
+				outputs := out.(map[string]interface{})
+				if outputs["status"] != tt.wantStatus || outputs["destination"] != "fake" {
+					t.Errorf("Unexpected outputs: %v", outputs)
+				}
+				if tt.result.Metadata != nil && outputs["fake_id"] != 1 {
+					t.Errorf("Expected destination metadata in outputs, got %v", outputs)
+				}
+			}
+
+			pending, pendingErr := db.GetPendingUpload(ctx, "fake-up-1")
+			if tt.wantPending {
+				if pendingErr != nil {
+					t.Fatalf("Expected pending upload: %v", pendingErr)
+				}
+				if pending.Status != pb.PendingUpload_STATUS_PROCESSING || pending.ExecutionId != "exec1" || pending.OriginalEvent.GetActivityId() != "a1" {
+					t.Errorf("Unexpected pending upload: %+v", pending)
+				}
+				msgs := pub.Messages()
+				if len(msgs) != 1 || msgs[0].Topic != shared.TopicUploadStatusCheck {
+					t.Errorf("Expected one status check message, got %+v", msgs)
+				}
+			} else if pendingErr == nil {
+				t.Errorf("Expected no pending upload, got %+v", pending)
+			}
+
+			synced, err := db.GetSynchronizedActivity(ctx, "u1", "a1")
+			if tt.wantSynced == "" {
+				if err == nil {
+					t.Errorf("Expected no synchronized activity, got %+v", synced)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected synchronized activity: %v", err)
+			}
+			if synced.Destinations["fake"] != tt.wantSynced || synced.PipelineExecutionId != "pe1" || synced.Source != "SOURCE_HEVY" {
+				t.Errorf("Unexpected synchronized activity: %+v", synced)
+			}
+		})
+	}
+}
+
+func TestUploadHandler_Resync(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name         string
+		synced       bool // The source activity was already delivered to the destination
+		update       *Result
+		wantStatus   string
+		wantUpdate   bool
+		wantActivity string // SynchronizedActivity holding destinations["fake"]
+	}{
+		{
+			name:         "Existing copy is updated in place",
+			synced:       true,
+			update:       &Result{Status: StatusComplete},
+			wantStatus:   "SUCCESS",
+			wantUpdate:   true,
+			wantActivity: "a-original",
+		},
+		{
+			name:         "Destination without update keeps its copy",
+			synced:       true,
+			wantStatus:   "SKIPPED",
+			wantActivity: "a-original",
+		},
+		{
+			name:         "Activity never delivered is uploaded",
+			wantStatus:   "SUCCESS",
+			wantActivity: "a-resync",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := database.NewMemoryDatabase()
+			store := infrastorage.NewMemoryStore()
+			store.Write(ctx, "artifacts", "activities/u1/a-resync.fit", []byte("FIT"))
+			if tt.synced {
+				db.SetSynchronizedActivity(ctx, "u1", &pb.SynchronizedActivity{
+					ActivityId:   "a-original",
+					Source:       "SOURCE_HEVY",
+					ExternalId:   "hevy-1",
+					PipelineId:   "p1",
+					Destinations: map[string]string{"fake": "ext-1"},
+				})
+			}
+			dest := &fakeDestination{result: &Result{Status: StatusComplete, ExternalID: "ext-new"}, update: tt.update}
+			fwCtx := &framework.FrameworkContext{
+				Service: &bootstrap.Service{DB: db, Store: store, Pub: &infrapubsub.MemoryPublisher{}},
+				Logger:  slog.Default(),
+			}
+
+			e := event.New()
+			e.SetType("com.fitglue.activity.enriched")
+			e.SetSource("/test")
+			data, _ := json.Marshal(map[string]interface{}{
+				"activity_id":   "a-resync",
+				"user_id":       "u1",
+				"pipeline_id":   "p1",
+				"name":          "Leg Day (edited)",
+				"source":        "SOURCE_HEVY",
+				"fit_file_uri":  infrastorage.URI("artifacts", "activities/u1/a-resync.fit"),
+				"activity_data": map[string]interface{}{"external_id": "hevy-1"},
+				"resync":        true,
+			})
+			e.SetData(event.ApplicationJSON, data)
+
+			out, err := UploadHandler(dest)(ctx, e, fwCtx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := out.(map[string]interface{})["status"]; got != tt.wantStatus {
+				t.Errorf("Status = %v, want %v", got, tt.wantStatus)
+			}
+			if tt.wantUpdate && (dest.got == nil || dest.got.ExternalID != "ext-1" || dest.got.Event.Name != "Leg Day (edited)") {
+				t.Errorf("Unexpected update request: %+v", dest.got)
+			}
+
+			synced, err := db.GetSynchronizedActivity(ctx, "u1", tt.wantActivity)
+			if err != nil {
+				t.Fatalf("Expected synchronized activity %s: %v", tt.wantActivity, err)
+			}
+			if synced.Destinations["fake"] == "" || synced.ExternalId != "hevy-1" {
+				t.Errorf("Unexpected synchronized activity: %+v", synced)
+			}
+			if tt.synced {
+				if _, err := db.GetSynchronizedActivity(ctx, "u1", "a-resync"); err == nil {
+					t.Error("Resync should not create a second synchronized activity")
+				}
+			}
+		})
+	}
+}
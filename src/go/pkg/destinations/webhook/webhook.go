@@ -0,0 +1,356 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/ripixel/fitglue-server/src/go/pkg/destinations"
+	"github.com/ripixel/fitglue-server/src/go/pkg/plugin"
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+)
+
+// SecretPrefix names the user's signing secret in the SecretStore ("webhook-signing-secret-{user_id}")
+const SecretPrefix = "webhook-signing-secret-"
+
+// Headers sent with every delivery
+const (
+	SignatureHeader = "X-FitGlue-Signature-256" // "sha256=" + hex HMAC of "{timestamp}.{body}"
+	TimestampHeader = "X-FitGlue-Timestamp"     // Unix seconds, part of the signed payload
+	EventHeader     = "X-FitGlue-Event"         // One of the Event* values
+	DeliveryHeader  = "X-FitGlue-Delivery"      // FitGlue activity ID
+)
+
+// Event types sent in EventHeader
+const (
+	EventActivityCreated = "activity.created"
+	EventActivityUpdated = "activity.updated"
+	EventActivityDeleted = "activity.deleted"
+)
+
+// Defaults for delivery retries
+const (
+	defaultMaxAttempts = 4
+	defaultBackoff     = time.Second
+	defaultTimeout     = 30 * time.Second
+)
+
+// maxResponseBody bounds how much of the endpoint's response is read
+const maxResponseBody = 64 << 10
+
+// errBlockedAddress is returned when the endpoint resolves to an address FitGlue won't call
+var errBlockedAddress = errors.New("webhook endpoint resolves to a non-public address")
+
+// WebhookDestination POSTs activities to the user's own endpoint (UserIntegrations.webhook).
+// The body is the EnrichedActivityEvent as protojson, or multipart/form-data with "event" and
+// "file" parts when the integration includes the FIT file. Each request is signed with
+// HMAC-SHA256 using the user's secret (see Sign).
+//
+// Endpoints must use https. The default client refuses to connect to loopback, link-local,
+// private and unspecified addresses, checked after DNS resolution so a rebinding host can't
+// reach internal services.
+//
+// Any 2xx response completes the delivery. The endpoint may return {"id": "..."} to name its
+// copy, otherwise the FitGlue activity ID is recorded. Connection errors, 429 and 5xx responses
+// are retried with exponential backoff; other responses fail the delivery.
+type WebhookDestination struct {
+	// HTTPClient overrides the default client (30s timeout, public addresses only)
+	HTTPClient *http.Client
+	// MaxAttempts bounds deliveries per operation (default 4)
+	MaxAttempts int
+	// Backoff is the delay before the first retry, doubled for each retry after it (default 1s)
+	Backoff time.Duration
+}
+
+func init() {
+	destinations.Register(&WebhookDestination{})
+
+	plugin.RegisterDestination(pb.Destination_DESTINATION_WEBHOOK, &pb.PluginManifest{
+		Id:                   "webhook",
+		Type:                 pb.PluginType_PLUGIN_TYPE_DESTINATION,
+		Name:                 "Webhook",
+		Description:          "Send activities to your own HTTP endpoint",
+		Icon:                 "🪝",
+		Enabled:              true,
+		RequiredIntegrations: []string{"webhook"},
+	})
+}
+
+func (d *WebhookDestination) Name() string {
+	return "webhook"
+}
+
+func (d *WebhookDestination) DestinationType() pb.Destination {
+	return pb.Destination_DESTINATION_WEBHOOK
+}
+
+func (d *WebhookDestination) Upload(ctx context.Context, req *destinations.Request) (*destinations.Result, error) {
+	res, err := d.deliver(ctx, req, EventActivityCreated, req.FitFile)
+	if err != nil || res.Status == destinations.StatusFailed {
+		return res, err
+	}
+	if res.ExternalID == "" {
+		res.ExternalID = req.Event.ActivityId
+	}
+	return res, nil
+}
+
+// Update re-sends the activity (with its FIT file on a resync)
+func (d *WebhookDestination) Update(ctx context.Context, req *destinations.Request) (*destinations.Result, error) {
+	res, err := d.deliver(ctx, req, EventActivityUpdated, req.FitFile)
+	if err != nil || res.Status == destinations.StatusFailed {
+		return res, err
+	}
+	res.ExternalID = req.ExternalID
+	return res, nil
+}
+
+// Delete notifies the endpoint that the activity was deleted at its source
+func (d *WebhookDestination) Delete(ctx context.Context, req *destinations.Request) error {
+	res, err := d.deliver(ctx, req, EventActivityDeleted, nil)
+	if err != nil {
+		return err
+	}
+	if res.Status == destinations.StatusFailed {
+		return fmt.Errorf("webhook delete failed: %s", res.Error)
+	}
+	return nil
+}
+
+func (d *WebhookDestination) Status(ctx context.Context, req *destinations.Request) (*destinations.Result, error) {
+	return nil, destinations.ErrNotSupported
+}
+
+// Sign returns the SignatureHeader value for a delivery: the hex HMAC-SHA256 of
+// "{timestamp}.{body}" keyed by the user's secret. Receivers recompute it to verify a request.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// deliver signs and POSTs the event to the user's endpoint, retrying transient failures.
+// fitFile is attached when the integration asks for it.
+func (d *WebhookDestination) deliver(ctx context.Context, req *destinations.Request, eventType string, fitFile []byte) (*destinations.Result, error) {
+	userID := req.Event.UserId
+	user, err := req.Service.DB.GetUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	integration := user.GetIntegrations().GetWebhook()
+	if !integration.GetEnabled() || integration.GetUrl() == "" {
+		return &destinations.Result{Status: destinations.StatusFailed, Error: "webhook integration not configured"}, nil
+	}
+	if err := ValidateURL(integration.GetUrl()); err != nil {
+		return &destinations.Result{Status: destinations.StatusFailed, Error: err.Error()}, nil
+	}
+	secret, err := req.Service.Secrets.GetSecret(ctx, req.Service.Config.ProjectID, SecretPrefix+userID)
+	if status.Code(err) == codes.NotFound {
+		return &destinations.Result{Status: destinations.StatusFailed, Error: "webhook signing secret not found; re-enable the webhook integration"}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load signing secret: %w", err)
+	}
+
+	if !integration.GetIncludeFitFile() {
+		fitFile = nil
+	}
+	body, contentType, err := buildBody(req.Event, fitFile)
+	if err != nil {
+		return nil, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signature := Sign(secret, timestamp, body)
+
+	maxAttempts := d.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+	backoff := d.Backoff
+	if backoff <= 0 {
+		backoff = defaultBackoff
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			backoff *= 2
+		}
+
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, integration.GetUrl(), bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook url: %w", err)
+		}
+		httpReq.Header.Set("Content-Type", contentType)
+		httpReq.Header.Set(SignatureHeader, signature)
+		httpReq.Header.Set(TimestampHeader, timestamp)
+		httpReq.Header.Set(EventHeader, eventType)
+		httpReq.Header.Set(DeliveryHeader, req.Event.ActivityId)
+
+		resp, err := d.client().Do(httpReq)
+		if errors.Is(err, errBlockedAddress) {
+			return &destinations.Result{Status: destinations.StatusFailed, Error: errBlockedAddress.Error()}, nil
+		}
+		if err != nil {
+			req.Logger.Warn("Webhook delivery failed", "attempt", attempt, "error", err)
+			lastErr = err
+			continue
+		}
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+		resp.Body.Close()
+
+		metadata := map[string]interface{}{
+			"webhook_status_code": resp.StatusCode,
+			"webhook_attempts":    attempt,
+		}
+		switch {
+		case resp.StatusCode >= 200 && resp.StatusCode < 300:
+			req.Logger.Info("Webhook delivered", "event", eventType, "status_code", resp.StatusCode, "attempt", attempt)
+			return &destinations.Result{
+				Status:     destinations.StatusComplete,
+				ExternalID: responseID(respBody),
+				Metadata:   metadata,
+			}, nil
+		case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+			req.Logger.Warn("Webhook endpoint unavailable", "attempt", attempt, "status_code", resp.StatusCode)
+			lastErr = fmt.Errorf("webhook returned %d: %s", resp.StatusCode, truncate(string(respBody), 200))
+			if attempt == maxAttempts {
+				return &destinations.Result{Status: destinations.StatusFailed, Error: lastErr.Error(), Metadata: metadata}, nil
+			}
+		default:
+			return &destinations.Result{
+				Status:   destinations.StatusFailed,
+				Error:    fmt.Sprintf("webhook returned %d: %s", resp.StatusCode, truncate(string(respBody), 200)),
+				Metadata: metadata,
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("webhook delivery failed after %d attempts: %w", maxAttempts, lastErr)
+}
+
+func (d *WebhookDestination) client() *http.Client {
+	if d.HTTPClient != nil {
+		return d.HTTPClient
+	}
+	dialer := &net.Dialer{Timeout: defaultTimeout, Control: publicAddressOnly}
+	return &http.Client{
+		Timeout: defaultTimeout,
+		// No proxy, so every connection goes through the dialer's address check
+		Transport: &http.Transport{DialContext: dialer.DialContext, TLSHandshakeTimeout: 10 * time.Second},
+		CheckRedirect: func(r *http.Request, via []*http.Request) error {
+			if r.URL.Scheme != "https" {
+				return fmt.Errorf("webhook redirected to non-https url")
+			}
+			if len(via) >= 10 {
+				return fmt.Errorf("stopped after 10 redirects")
+			}
+			return nil
+		},
+	}
+}
+
+// ValidateURL checks that a webhook endpoint is an absolute https URL
+func ValidateURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid webhook url: %w", err)
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("webhook url must be an absolute https url")
+	}
+	return nil
+}
+
+// publicAddressOnly is a net.Dialer Control hook that refuses non-public addresses.
+// It runs on the resolved IP of each connection attempt.
+func publicAddressOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() {
+		return errBlockedAddress
+	}
+	return nil
+}
+
+// buildBody encodes the event as protojson, wrapped in multipart/form-data with the FIT file when given
+func buildBody(ev *pb.EnrichedActivityEvent, fitFile []byte) ([]byte, string, error) {
+	eventJSON, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(ev)
+	if err != nil {
+		return nil, "", fmt.Errorf("protojson.Marshal: %w", err)
+	}
+	if fitFile == nil {
+		return eventJSON, "application/json", nil
+	}
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	eventHeader := textproto.MIMEHeader{}
+	eventHeader.Set("Content-Disposition", `form-data; name="event"`)
+	eventHeader.Set("Content-Type", "application/json")
+	part, err := writer.CreatePart(eventHeader)
+	if err != nil {
+		return nil, "", err
+	}
+	part.Write(eventJSON)
+	part, err = writer.CreateFormFile("file", ev.ActivityId+".fit")
+	if err != nil {
+		return nil, "", err
+	}
+	part.Write(fitFile)
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+	return body.Bytes(), writer.FormDataContentType(), nil
+}
+
+// responseID returns the "id" the endpoint returned for its copy, if any
+func responseID(body []byte) string {
+	var resp struct {
+		ID json.RawMessage `json:"id"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil || len(resp.ID) == 0 || string(resp.ID) == "null" {
+		return ""
+	}
+	// Accept string or numeric IDs
+	var id string
+	if err := json.Unmarshal(resp.ID, &id); err == nil {
+		return id
+	}
+	return string(resp.ID)
+}
+
+func truncate(s string, maxLen int) string {
+	if len(s) <= maxLen {
+		return s
+	}
+	return s[:maxLen] + "..."
+}
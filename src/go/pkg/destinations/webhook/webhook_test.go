@@ -0,0 +1,272 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/ripixel/fitglue-server/src/go/pkg/bootstrap"
+	"github.com/ripixel/fitglue-server/src/go/pkg/destinations"
+	"github.com/ripixel/fitglue-server/src/go/pkg/infrastructure/database"
+	"github.com/ripixel/fitglue-server/src/go/pkg/testing/mocks"
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+)
+
+// delivery is a request received by the stub endpoint
+type delivery struct {
+	header http.Header
+	body   []byte
+}
+
+func TestWebhookDestination(t *testing.T) {
+	ctx := context.Background()
+	event := &pb.EnrichedActivityEvent{
+		ActivityId:   "act-1",
+		UserId:       "u1",
+		Name:         "Leg Day",
+		ActivityType: pb.ActivityType_ACTIVITY_TYPE_WEIGHT_TRAINING,
+	}
+
+	tests := []struct {
+		name           string
+		disabled       bool
+		insecure       bool // Endpoint configured with http://
+		noSecret       bool // Signing secret never provisioned
+		defaultClient  bool // Use the default client, which refuses the loopback test server
+		includeFit     bool
+		statuses       []int  // Responses in order; the last repeats
+		respBody       string // Body of 2xx responses
+		delete         bool
+		wantErr        bool
+		wantStatus     destinations.UploadStatus
+		wantExternalID string
+		wantCalls      int
+	}{
+		{
+			name:           "Signed event is delivered",
+			statuses:       []int{200},
+			wantStatus:     destinations.StatusComplete,
+			wantExternalID: "act-1",
+			wantCalls:      1,
+		},
+		{
+			name:           "Endpoint ID is recorded",
+			statuses:       []int{201},
+			respBody:       `{"id": 9001}`,
+			wantStatus:     destinations.StatusComplete,
+			wantExternalID: "9001",
+			wantCalls:      1,
+		},
+		{
+			name:           "FIT file is attached when enabled",
+			includeFit:     true,
+			statuses:       []int{200},
+			wantStatus:     destinations.StatusComplete,
+			wantExternalID: "act-1",
+			wantCalls:      1,
+		},
+		{
+			name:           "Unavailable endpoint is retried",
+			statuses:       []int{503, 429, 200},
+			wantStatus:     destinations.StatusComplete,
+			wantExternalID: "act-1",
+			wantCalls:      3,
+		},
+		{
+			name:       "Persistent server errors fail after the last attempt",
+			statuses:   []int{500},
+			wantStatus: destinations.StatusFailed,
+			wantCalls:  3,
+		},
+		{
+			name:       "Client errors fail without retrying",
+			statuses:   []int{400},
+			wantStatus: destinations.StatusFailed,
+			wantCalls:  1,
+		},
+		{
+			name:       "Disabled integration fails without a request",
+			disabled:   true,
+			wantStatus: destinations.StatusFailed,
+		},
+		{
+			name:       "Plain http endpoint fails without a request",
+			insecure:   true,
+			wantStatus: destinations.StatusFailed,
+		},
+		{
+			name:       "Missing signing secret fails without a request",
+			noSecret:   true,
+			wantStatus: destinations.StatusFailed,
+		},
+		{
+			name:          "Loopback endpoint is refused by the default client",
+			defaultClient: true,
+			statuses:      []int{200},
+			wantStatus:    destinations.StatusFailed,
+		},
+		{
+			name:      "Deletion is delivered",
+			statuses:  []int{204},
+			delete:    true,
+			wantCalls: 1,
+		},
+		{
+			name:      "Rejected deletion is an error",
+			statuses:  []int{404},
+			delete:    true,
+			wantErr:   true,
+			wantCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []delivery
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				d := delivery{header: r.Header}
+				d.body, _ = io.ReadAll(r.Body)
+				got = append(got, d)
+
+				status := tt.statuses[len(tt.statuses)-1]
+				if len(got) <= len(tt.statuses) {
+					status = tt.statuses[len(got)-1]
+				}
+				w.WriteHeader(status)
+				if status < 300 {
+					io.WriteString(w, tt.respBody)
+				}
+			}))
+			defer server.Close()
+
+			endpoint := server.URL + "/hooks/fitglue"
+			if tt.insecure {
+				endpoint = strings.Replace(endpoint, "https://", "http://", 1)
+			}
+			db := database.NewMemoryDatabase()
+			db.SetUser(ctx, &pb.UserRecord{
+				UserId: "u1",
+				Integrations: &pb.UserIntegrations{Webhook: &pb.WebhookIntegration{
+					Enabled:        !tt.disabled,
+					Url:            endpoint,
+					IncludeFitFile: tt.includeFit,
+				}},
+			})
+			secrets := &mocks.MockSecretStore{GetSecretFunc: func(ctx context.Context, projectID, name string) (string, error) {
+				if name != "webhook-signing-secret-u1" {
+					t.Errorf("Unexpected secret %q", name)
+				}
+				if tt.noSecret {
+					return "", status.Error(codes.NotFound, "secret not found")
+				}
+				return "s3cret", nil
+			}}
+			req := &destinations.Request{
+				Event:      event,
+				FitFile:    []byte("FIT"),
+				ExternalID: "act-1",
+				Service:    &bootstrap.Service{DB: db, Secrets: secrets, Config: &bootstrap.Config{}},
+				Logger:     slog.Default(),
+			}
+			dest := &WebhookDestination{HTTPClient: server.Client(), MaxAttempts: 3, Backoff: 1}
+			if tt.defaultClient {
+				dest.HTTPClient = nil
+			}
+
+			if tt.delete {
+				err := dest.Delete(ctx, req)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+				}
+			} else {
+				res, err := dest.Upload(ctx, req)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if res.Status != tt.wantStatus || res.ExternalID != tt.wantExternalID {
+					t.Errorf("Unexpected result: %+v", res)
+				}
+			}
+
+			if len(got) != tt.wantCalls {
+				t.Fatalf("Endpoint called %d times, want %d", len(got), tt.wantCalls)
+			}
+			if len(got) == 0 {
+				return
+			}
+
+			d := got[len(got)-1]
+			if sig := Sign("s3cret", d.header.Get(TimestampHeader), d.body); d.header.Get(SignatureHeader) != sig {
+				t.Errorf("Signature = %q, want %q", d.header.Get(SignatureHeader), sig)
+			}
+			wantEvent := EventActivityCreated
+			if tt.delete {
+				wantEvent = EventActivityDeleted
+			}
+			if d.header.Get(EventHeader) != wantEvent || d.header.Get(DeliveryHeader) != "act-1" {
+				t.Errorf("Unexpected headers: %v", d.header)
+			}
+
+			// Decode the event (and FIT file) from the body
+			eventJSON := d.body
+			if tt.includeFit && !tt.delete {
+				r := &http.Request{Method: "POST", Header: d.header, Body: io.NopCloser(bytes.NewReader(d.body))}
+				if err := r.ParseMultipartForm(1 << 20); err != nil {
+					t.Fatalf("Expected multipart body: %v", err)
+				}
+				eventJSON = []byte(r.MultipartForm.Value["event"][0])
+				f, err := r.MultipartForm.File["file"][0].Open()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if b, _ := io.ReadAll(f); string(b) != "FIT" {
+					t.Errorf("FIT file = %q", b)
+				}
+			} else if ct := d.header.Get("Content-Type"); ct != "application/json" {
+				t.Errorf("Content-Type = %q", ct)
+			}
+			var sent pb.EnrichedActivityEvent
+			if err := protojson.Unmarshal(eventJSON, &sent); err != nil {
+				t.Fatalf("Event is not protojson: %v (%s)", err, eventJSON)
+			}
+			if sent.ActivityId != "act-1" || sent.Name != "Leg Day" || sent.ActivityType != pb.ActivityType_ACTIVITY_TYPE_WEIGHT_TRAINING {
+				t.Errorf("Unexpected event: %+v", &sent)
+			}
+		})
+	}
+}
+
+func TestPublicAddressOnly(t *testing.T) {
+	tests := []struct {
+		address string
+		allowed bool
+	}{
+		{"93.184.216.34:443", true},
+		{"[2606:2800:220:1:248:1893:25c8:1946]:443", true},
+		{"127.0.0.1:443", false},
+		{"[::1]:443", false},
+		{"10.0.0.5:443", false},
+		{"172.16.0.1:443", false},
+		{"192.168.1.1:443", false},
+		{"169.254.169.254:80", false}, // Cloud metadata server
+		{"[fe80::1]:443", false},
+		{"[fd00::1]:443", false},
+		{"0.0.0.0:443", false},
+		{"[::ffff:127.0.0.1]:443", false},
+	}
+	for _, tt := range tests {
+		err := publicAddressOnly("tcp", tt.address, nil)
+		if (err == nil) != tt.allowed {
+			t.Errorf("publicAddressOnly(%s) = %v, want allowed %v", tt.address, err, tt.allowed)
+		}
+	}
+}
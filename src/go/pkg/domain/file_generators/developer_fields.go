@@ -0,0 +1,271 @@
+package file_generators
+
+import (
+	"fmt"
+	"log"
+	"log/slog"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/muktihari/fit/profile/basetype"
+	"github.com/muktihari/fit/profile/mesgdef"
+	"github.com/muktihari/fit/profile/typedef"
+	"github.com/muktihari/fit/proto"
+)
+
+// FitGlueDeveloperDataIndex is the developer_data_index used for all FitGlue developer fields
+const FitGlueDeveloperDataIndex uint8 = 0
+
+// FitGlueApplicationID identifies FitGlue as the application producing the developer data
+var FitGlueApplicationID = []byte{
+	0x66, 0x69, 0x74, 0x67, 0x6c, 0x75, 0x65, 0x2d, // "fitglue-"
+	0x64, 0x65, 0x76, 0x66, 0x69, 0x65, 0x6c, 0x64, // "devfield"
+}
+
+// maxDeveloperStringBytes is the longest string value that fits in a FIT field (255 bytes incl. null terminator)
+const maxDeveloperStringBytes = 254
+
+// Core developer field keys, populated by the orchestrator
+const (
+	DevFieldPipelineID         = "pipeline_id"
+	DevFieldAppliedEnrichments = "applied_enrichments"
+)
+
+// DeveloperFieldDefinition describes a FitGlue developer field written on the Session message.
+// Field numbers are shared across all FitGlue fields and must be unique:
+//
+//	0 pipeline_id, 1 applied_enrichments (core)
+//	2 rpe (user_input), 3 muscle_load (muscle-heatmap), 4 alignment_drift (fitbit-heart-rate)
+type DeveloperFieldDefinition struct {
+	Key   string            // Unique key used by contributors; also written as the FIT field name
+	Num   uint8             // FIT field_definition_number
+	Type  basetype.BaseType // One of String, Uint8, Uint16, Uint32, Sint32, Float32
+	Units string            // Optional display units
+}
+
+var (
+	devFieldsMu     sync.RWMutex
+	devFieldsByKey  = make(map[string]DeveloperFieldDefinition)
+	devFieldsByNum  = make(map[uint8]DeveloperFieldDefinition)
+	supportedDevTys = map[basetype.BaseType]bool{
+		basetype.String:  true,
+		basetype.Uint8:   true,
+		basetype.Uint16:  true,
+		basetype.Uint32:  true,
+		basetype.Sint32:  true,
+		basetype.Float32: true,
+	}
+)
+
+func init() {
+	RegisterDeveloperField(DeveloperFieldDefinition{Key: DevFieldPipelineID, Num: 0, Type: basetype.String})
+	RegisterDeveloperField(DeveloperFieldDefinition{Key: DevFieldAppliedEnrichments, Num: 1, Type: basetype.String})
+}
+
+// RegisterDeveloperField adds a developer field definition to the registry.
+// Ideally called in init() functions of the enrichers contributing the field.
+func RegisterDeveloperField(def DeveloperFieldDefinition) {
+	devFieldsMu.Lock()
+	defer devFieldsMu.Unlock()
+
+	if !supportedDevTys[def.Type] {
+		log.Panicf("Unsupported developer field type for %s: %v", def.Key, def.Type)
+	}
+	if _, exists := devFieldsByKey[def.Key]; exists {
+		log.Panicf("Developer field already registered for key: %s", def.Key)
+	}
+	if existing, exists := devFieldsByNum[def.Num]; exists {
+		log.Panicf("Developer field number %d already registered by: %s", def.Num, existing.Key)
+	}
+	devFieldsByKey[def.Key] = def
+	devFieldsByNum[def.Num] = def
+}
+
+// GetDeveloperField returns the definition registered for key
+func GetDeveloperField(key string) (DeveloperFieldDefinition, bool) {
+	devFieldsMu.RLock()
+	defer devFieldsMu.RUnlock()
+
+	def, ok := devFieldsByKey[key]
+	return def, ok
+}
+
+// buildDeveloperFields converts contributed values into FIT messages.
+// It returns the DeveloperDataId and FieldDescription messages (which must precede their use)
+// and the DeveloperFields to attach to the Session message. Unknown keys and values
+// that cannot be converted to the registered type are logged and skipped.
+func buildDeveloperFields(values map[string]interface{}) ([]proto.Message, []proto.DeveloperField) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var defs []DeveloperFieldDefinition
+	var fields []proto.DeveloperField
+	for _, key := range keys {
+		def, ok := GetDeveloperField(key)
+		if !ok {
+			slog.Warn("Skipping unregistered developer field", "key", key)
+			continue
+		}
+		val, err := toDeveloperValue(def, values[key])
+		if err != nil {
+			slog.Warn("Skipping invalid developer field value", "key", key, "error", err)
+			continue
+		}
+		defs = append(defs, def)
+		fields = append(fields, proto.DeveloperField{
+			Num:                def.Num,
+			DeveloperDataIndex: FitGlueDeveloperDataIndex,
+			Value:              val,
+		})
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	messages := []proto.Message{
+		mesgdef.NewDeveloperDataId(nil).
+			SetApplicationId(FitGlueApplicationID).
+			SetManufacturerId(typedef.ManufacturerDevelopment).
+			SetDeveloperDataIndex(FitGlueDeveloperDataIndex).
+			SetApplicationVersion(1).
+			ToMesg(nil),
+	}
+	for _, def := range defs {
+		desc := mesgdef.NewFieldDescription(nil).
+			SetDeveloperDataIndex(FitGlueDeveloperDataIndex).
+			SetFieldDefinitionNumber(def.Num).
+			SetFitBaseTypeId(def.Type).
+			SetFieldName([]string{def.Key}).
+			SetNativeMesgNum(typedef.MesgNumSession)
+		if def.Units != "" {
+			desc.SetUnits([]string{def.Units})
+		}
+		messages = append(messages, desc.ToMesg(nil))
+	}
+	return messages, fields
+}
+
+// toDeveloperValue converts a contributed Go value to the FIT value of the registered type
+func toDeveloperValue(def DeveloperFieldDefinition, v interface{}) (proto.Value, error) {
+	if def.Type == basetype.String {
+		s := fmt.Sprint(v)
+		if len(s) > maxDeveloperStringBytes {
+			s = s[:maxDeveloperStringBytes]
+		}
+		return proto.String(s), nil
+	}
+
+	var f float64
+	switch t := v.(type) {
+	case int:
+		f = float64(t)
+	case int32:
+		f = float64(t)
+	case int64:
+		f = float64(t)
+	case uint8:
+		f = float64(t)
+	case uint16:
+		f = float64(t)
+	case uint32:
+		f = float64(t)
+	case float32:
+		f = float64(t)
+	case float64:
+		f = t
+	case string:
+		parsed, err := strconv.ParseFloat(t, 64)
+		if err != nil {
+			return proto.Value{}, fmt.Errorf("not numeric: %q", t)
+		}
+		f = parsed
+	default:
+		return proto.Value{}, fmt.Errorf("unsupported value type %T", v)
+	}
+
+	outOfRange := func(min, max float64) error {
+		if f < min || f > max {
+			return fmt.Errorf("value %v out of range for %v", f, def.Type)
+		}
+		return nil
+	}
+
+	switch def.Type {
+	case basetype.Uint8:
+		if err := outOfRange(0, 254); err != nil {
+			return proto.Value{}, err
+		}
+		return proto.Uint8(uint8(f)), nil
+	case basetype.Uint16:
+		if err := outOfRange(0, 65534); err != nil {
+			return proto.Value{}, err
+		}
+		return proto.Uint16(uint16(f)), nil
+	case basetype.Uint32:
+		if err := outOfRange(0, 4294967294); err != nil {
+			return proto.Value{}, err
+		}
+		return proto.Uint32(uint32(f)), nil
+	case basetype.Sint32:
+		if err := outOfRange(-2147483647, 2147483647); err != nil {
+			return proto.Value{}, err
+		}
+		return proto.Int32(int32(f)), nil
+	case basetype.Float32:
+		return proto.Float32(float32(f)), nil
+	}
+	return proto.Value{}, fmt.Errorf("unsupported developer field type %v", def.Type)
+}
+
+// DecodedDeveloperField is a developer field value read back from a FIT file
+type DecodedDeveloperField struct {
+	Message string // Name of the message carrying the field (e.g. "session")
+	Name    string
+	Units   string
+	Value   interface{}
+}
+
+// DecodeDeveloperFields resolves all developer field values in a decoded FIT file
+// against their FieldDescription messages
+func DecodeDeveloperFields(fit *proto.FIT) []DecodedDeveloperField {
+	type descKey struct {
+		Index uint8
+		Num   uint8
+	}
+	descriptions := make(map[descKey]*mesgdef.FieldDescription)
+	var decoded []DecodedDeveloperField
+
+	for i := range fit.Messages {
+		msg := &fit.Messages[i]
+		if msg.Num == typedef.MesgNumFieldDescription {
+			desc := mesgdef.NewFieldDescription(msg)
+			descriptions[descKey{desc.DeveloperDataIndex, desc.FieldDefinitionNumber}] = desc
+			continue
+		}
+		for _, df := range msg.DeveloperFields {
+			field := DecodedDeveloperField{
+				Message: msg.Num.String(),
+				Name:    fmt.Sprintf("developer_%d_%d", df.DeveloperDataIndex, df.Num),
+				Value:   df.Value.Any(),
+			}
+			if desc, ok := descriptions[descKey{df.DeveloperDataIndex, df.Num}]; ok {
+				if len(desc.FieldName) > 0 {
+					field.Name = desc.FieldName[0]
+				}
+				if len(desc.Units) > 0 {
+					field.Units = desc.Units[0]
+				}
+			}
+			decoded = append(decoded, field)
+		}
+	}
+	return decoded
+}
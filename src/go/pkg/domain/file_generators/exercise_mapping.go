@@ -2,6 +2,7 @@ package file_generators
 
 import (
 	"strings"
+	"unicode"
 
 	"github.com/muktihari/fit/profile/typedef"
 )
@@ -104,3 +105,138 @@ func MapExerciseToCategory(exerciseName string) typedef.ExerciseCategory {
 	// Default to generic strength training
 	return typedef.ExerciseCategoryTotalBody
 }
+
+// exerciseNameEntry is a FIT exercise name subtype with its tokenized profile name
+type exerciseNameEntry struct {
+	Value  uint16
+	Tokens []string
+}
+
+// exerciseNameValue is satisfied by all FIT [category]_exercise_name types
+type exerciseNameValue interface {
+	Uint16() uint16
+	String() string
+}
+
+func catalogOf[T exerciseNameValue](names []T) []exerciseNameEntry {
+	entries := make([]exerciseNameEntry, 0, len(names))
+	for _, n := range names {
+		entries = append(entries, exerciseNameEntry{
+			Value:  n.Uint16(),
+			Tokens: tokenizeExerciseName(n.String()),
+		})
+	}
+	return entries
+}
+
+// exerciseNameCatalog holds the FIT exercise name subtypes for each category
+// returned by MapExerciseToCategory
+var exerciseNameCatalog = map[typedef.ExerciseCategory][]exerciseNameEntry{
+	typedef.ExerciseCategoryBenchPress:       catalogOf(typedef.ListBenchPressExerciseName()),
+	typedef.ExerciseCategoryFlye:             catalogOf(typedef.ListFlyeExerciseName()),
+	typedef.ExerciseCategoryDeadlift:         catalogOf(typedef.ListDeadliftExerciseName()),
+	typedef.ExerciseCategoryRow:              catalogOf(typedef.ListRowExerciseName()),
+	typedef.ExerciseCategoryPullUp:           catalogOf(typedef.ListPullUpExerciseName()),
+	typedef.ExerciseCategorySquat:            catalogOf(typedef.ListSquatExerciseName()),
+	typedef.ExerciseCategoryLunge:            catalogOf(typedef.ListLungeExerciseName()),
+	typedef.ExerciseCategoryLegCurl:          catalogOf(typedef.ListLegCurlExerciseName()),
+	typedef.ExerciseCategoryCalfRaise:        catalogOf(typedef.ListCalfRaiseExerciseName()),
+	typedef.ExerciseCategoryShoulderPress:    catalogOf(typedef.ListShoulderPressExerciseName()),
+	typedef.ExerciseCategoryLateralRaise:     catalogOf(typedef.ListLateralRaiseExerciseName()),
+	typedef.ExerciseCategoryShrug:            catalogOf(typedef.ListShrugExerciseName()),
+	typedef.ExerciseCategoryCurl:             catalogOf(typedef.ListCurlExerciseName()),
+	typedef.ExerciseCategoryTricepsExtension: catalogOf(typedef.ListTricepsExtensionExerciseName()),
+	typedef.ExerciseCategoryCrunch:           catalogOf(typedef.ListCrunchExerciseName()),
+	typedef.ExerciseCategoryPlank:            catalogOf(typedef.ListPlankExerciseName()),
+	typedef.ExerciseCategoryOlympicLift:      catalogOf(typedef.ListOlympicLiftExerciseName()),
+	typedef.ExerciseCategoryTotalBody:        catalogOf(typedef.ListTotalBodyExerciseName()),
+}
+
+// minExerciseNameScore is the minimum token similarity (Jaccard index) for a
+// FIT exercise name subtype to be considered a match
+const minExerciseNameScore = 0.5
+
+// MapExerciseToName maps an exercise name to its FIT category and the closest
+// exercise name subtype within that category.
+// ok is false if no subtype is similar enough, in which case only the category should be used.
+func MapExerciseToName(exerciseName string) (category typedef.ExerciseCategory, name uint16, ok bool) {
+	category = MapExerciseToCategory(exerciseName)
+	tokens := tokenizeExerciseName(exerciseName)
+	if len(tokens) == 0 {
+		return category, 0, false
+	}
+
+	bestScore := 0.0
+	for _, entry := range exerciseNameCatalog[category] {
+		score := tokenSimilarity(tokens, entry.Tokens)
+		if score > bestScore {
+			bestScore = score
+			name = entry.Value
+		}
+	}
+
+	if bestScore < minExerciseNameScore {
+		return category, 0, false
+	}
+	return category, name, true
+}
+
+// exerciseTokenSynonyms normalizes common abbreviations and spellings to FIT profile vocabulary
+var exerciseTokenSynonyms = map[string][]string{
+	"db":       {"dumbbell"},
+	"bb":       {"barbell"},
+	"kb":       {"kettlebell"},
+	"pushup":   {"push", "up"},
+	"pullup":   {"pull", "up"},
+	"chinup":   {"chin", "up"},
+	"situp":    {"sit", "up"},
+	"ohp":      {"overhead", "press"},
+	"rdl":      {"romanian", "deadlift"},
+	"smith":    {"smith", "machine"},
+	"pulldown": {"pull", "down"},
+}
+
+// tokenizeExerciseName lower-cases a name, splits it on non-alphanumerics and
+// normalizes synonyms and simple plurals (e.g. "Bench Press (Barbell)" -> [bench press barbell])
+func tokenizeExerciseName(name string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var tokens []string
+	seen := make(map[string]bool)
+	for _, f := range fields {
+		expanded, ok := exerciseTokenSynonyms[f]
+		if !ok {
+			if len(f) > 2 && strings.HasSuffix(f, "s") && !strings.HasSuffix(f, "ss") {
+				f = strings.TrimSuffix(f, "s")
+			}
+			expanded = []string{f}
+		}
+		for _, t := range expanded {
+			if !seen[t] {
+				seen[t] = true
+				tokens = append(tokens, t)
+			}
+		}
+	}
+	return tokens
+}
+
+// tokenSimilarity returns the Jaccard index of two token sets
+func tokenSimilarity(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	set := make(map[string]bool, len(a))
+	for _, t := range a {
+		set[t] = true
+	}
+	shared := 0
+	for _, t := range b {
+		if set[t] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
@@ -0,0 +1,41 @@
+package file_generators
+
+import (
+	"testing"
+
+	"github.com/muktihari/fit/profile/typedef"
+)
+
+func TestMapExerciseToName(t *testing.T) {
+	tests := []struct {
+		exercise     string
+		wantCategory typedef.ExerciseCategory
+		wantName     uint16
+		wantOK       bool
+	}{
+		{"Bench Press (Barbell)", typedef.ExerciseCategoryBenchPress, uint16(typedef.BenchPressExerciseNameBarbellBenchPress), true},
+		{"Incline Bench Press (Dumbbell)", typedef.ExerciseCategoryBenchPress, uint16(typedef.BenchPressExerciseNameInclineDumbbellBenchPress), true},
+		{"Lateral Raise (Dumbbell)", typedef.ExerciseCategoryLateralRaise, uint16(typedef.LateralRaiseExerciseNameDumbbellLateralRaise), true},
+		{"Walking Lunges", typedef.ExerciseCategoryLunge, uint16(typedef.LungeExerciseNameWalkingLunge), true},
+		{"DB Bicep Curl", typedef.ExerciseCategoryCurl, uint16(typedef.CurlExerciseNameDumbbellBicepsCurl), true},
+		{"Overhead Dumbbell Tricep Extension", typedef.ExerciseCategoryTricepsExtension, uint16(typedef.TricepsExtensionExerciseNameOverheadDumbbellTricepsExtension), true},
+		{"Overhead Dumbbell Triceps Extension", typedef.ExerciseCategoryTricepsExtension, uint16(typedef.TricepsExtensionExerciseNameOverheadDumbbellTricepsExtension), true},
+		{"Pull Ups", typedef.ExerciseCategoryPullUp, uint16(typedef.PullUpExerciseNamePullUp), true},
+		{"Mystery Machine", typedef.ExerciseCategoryTotalBody, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.exercise, func(t *testing.T) {
+			category, name, ok := MapExerciseToName(tt.exercise)
+			if category != tt.wantCategory {
+				t.Errorf("category = %v, want %v", category, tt.wantCategory)
+			}
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && name != tt.wantName {
+				t.Errorf("name = %d, want %d", name, tt.wantName)
+			}
+		})
+	}
+}
@@ -1,9 +1,12 @@
 package file_generators
 
 import (
-	"bytes"
 	"fmt"
+	"io"
 	"log/slog"
+	"math"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/muktihari/fit/encoder"
@@ -14,29 +17,149 @@ import (
 	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
 )
 
+// Option configures optional FIT generation behaviour
+type Option func(*generateOptions)
+
+type generateOptions struct {
+	developerFields  map[string]interface{}
+	recordResolution time.Duration
+	noPauseInference bool
+	tee              io.Writer
+}
+
+// MaxSynthesizedRecords caps the number of records produced for a session
+// without source records; longer sessions get a coarser record interval.
+const MaxSynthesizedRecords = 3600
+
+// DefaultRecordResolution returns the record interval for a session of the given
+// length: one second, widened so that at most MaxSynthesizedRecords are produced.
+func DefaultRecordResolution(elapsed time.Duration) time.Duration {
+	seconds := int64(elapsed.Seconds())
+	perRecord := (seconds + MaxSynthesizedRecords - 1) / MaxSynthesizedRecords
+	if perRecord < 1 {
+		perRecord = 1
+	}
+	return time.Duration(perRecord) * time.Second
+}
+
+// WithRecordResolution sets the minimum spacing between Record messages.
+// Source records closer than this to the previously written record are dropped,
+// and synthesized records are spaced at this interval.
+func WithRecordResolution(resolution time.Duration) Option {
+	return func(o *generateOptions) {
+		o.recordResolution = resolution
+	}
+}
+
+// WithoutPauseInference stops gaps in the record stream being treated as timer pauses.
+// Use it when the records were synthesized (e.g. merged from enricher streams) rather than
+// recorded by the source, so gaps only mean missing data.
+func WithoutPauseInference() Option {
+	return func(o *generateOptions) {
+		o.noPauseInference = true
+	}
+}
+
+// WithDeveloperFields writes the given values as FitGlue developer fields on the Session.
+// Keys must be registered via RegisterDeveloperField.
+func WithDeveloperFields(values map[string]interface{}) Option {
+	return func(o *generateOptions) {
+		if o.developerFields == nil {
+			o.developerFields = make(map[string]interface{})
+		}
+		for k, v := range values {
+			o.developerFields[k] = v
+		}
+	}
+}
+
+// WithTee also writes the final file bytes to t, e.g. to validate the file while it is
+// uploaded. t cannot follow the encoder's seek back to the file header, so a tee always
+// takes the two-pass path and only receives the second pass.
+func WithTee(t io.Writer) Option {
+	return func(o *generateOptions) {
+		o.tee = t
+	}
+}
+
 // GenerateFitFile creates a FIT file from StandardizedActivity
 // Supports multiple sport types and rich record data
-func GenerateFitFile(activity *pb.StandardizedActivity) ([]byte, error) {
+func GenerateFitFile(activity *pb.StandardizedActivity, opts ...Option) ([]byte, error) {
+	buf := &writeSeekBuffer{}
+	if err := EncodeFitFile(buf, activity, opts...); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// EncodeFitFile streams the FIT file for activity to w message by message, without
+// holding the whole file in memory. Seekable writers (e.g. files) are written in a
+// single pass. Other writers (e.g. BlobStore uploads), and any writer with WithTee, are
+// written in two passes, the first only sizing the data so that the file header can be
+// written up front.
+func EncodeFitFile(w io.Writer, activity *pb.StandardizedActivity, opts ...Option) error {
+	options := &generateOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	if activity == nil {
-		return nil, fmt.Errorf("activity cannot be nil")
+		return fmt.Errorf("activity cannot be nil")
 	}
 
 	if len(activity.Sessions) == 0 {
-		return nil, fmt.Errorf("activity must have at least one session")
+		return fmt.Errorf("activity must have at least one session")
 	}
 
-	// Parse start time
-	startTime := activity.StartTime.AsTime()
-	if startTime.IsZero() {
-		return nil, fmt.Errorf("invalid start time: zero")
+	if activity.StartTime.AsTime().IsZero() {
+		return fmt.Errorf("invalid start time: zero")
+	}
+
+	devMessages, devFields := buildDeveloperFields(options.developerFields)
+
+	if options.tee == nil {
+		switch w.(type) {
+		case io.WriteSeeker, io.WriterAt:
+			return encodeFitStream(w, activity, options, devMessages, devFields)
+		}
+	} else {
+		w = io.MultiWriter(w, options.tee)
+	}
+
+	sizer := &headerPatchWriter{}
+	if err := encodeFitStream(sizer, activity, options, devMessages, devFields); err != nil {
+		return err
 	}
+	return encodeFitStream(&headerPatchWriter{w: w, header: sizer.header}, activity, options, devMessages, devFields)
+}
+
+// encodeFitStream generates the FIT messages for activity in file order and
+// encodes each as soon as it is complete.
+func encodeFitStream(w io.Writer, activity *pb.StandardizedActivity, options *generateOptions, devMessages []proto.Message, devFields []proto.DeveloperField) error {
+	startTime := activity.StartTime.AsTime()
 
 	// Strict Single Session Enforcement
 	session := activity.Sessions[0]
 
-	// Create proto.FIT struct
-	fit := &proto.FIT{
-		Messages: []proto.Message{},
+	var encOpts []encoder.Option
+	if len(devFields) > 0 {
+		// Developer fields require FIT protocol 2.0
+		encOpts = append(encOpts, encoder.WithProtocolVersion(proto.V2))
+	}
+	enc, err := encoder.NewStream(w, encOpts...)
+	if err != nil {
+		return fmt.Errorf("failed to create FIT encoder: %w", err)
+	}
+
+	// emit encodes a message; the first error stops further encoding
+	var emitErr error
+	emit := func(mesgs ...proto.Message) {
+		for i := range mesgs {
+			if emitErr != nil {
+				return
+			}
+			emitErr = enc.WriteMessage(&mesgs[i])
+		}
 	}
 
 	// 1. FileId message
@@ -45,10 +168,28 @@ func GenerateFitFile(activity *pb.StandardizedActivity) ([]byte, error) {
 		SetManufacturer(typedef.ManufacturerDevelopment).
 		SetProduct(1). // FitGlue product ID
 		SetTimeCreated(startTime)
-	fit.Messages = append(fit.Messages, fileId.ToMesg(nil))
+	emit(fileId.ToMesg(nil))
+
+	// 1b. Developer data definitions (must precede the Session carrying the values)
+	emit(devMessages...)
 
 	// Map Sport
 	sport, subSport := mapSport(activity.Type)
+	if sport == typedef.SportSwimming {
+		subSport = resolveSwimSubSport(session)
+	}
+
+	// Swimming: pool lengths (summarised onto the Lap and Session)
+	var lengthMessages []proto.Message
+	var swim swimSummary
+	poolSwim := sport == typedef.SportSwimming && isPoolSwim(session)
+	if poolSwim {
+		lengthMessages, swim = buildLengthMessages(session, startTime)
+	}
+	totalDistance := session.TotalDistance
+	if totalDistance == 0 && poolSwim {
+		totalDistance = swim.Distance
+	}
 
 	// 2. Activity message (Appended last)
 	activityMsg := mesgdef.NewActivity(nil).
@@ -56,15 +197,8 @@ func GenerateFitFile(activity *pb.StandardizedActivity) ([]byte, error) {
 		SetType(typedef.ActivityManual).
 		SetNumSessions(1)
 
-	// 3a. DeviceInfo: Source App (e.g. Hevy)
-	manuf, product := mapSourceToDevice(activity.Source)
-	sourceDeviceMsg := mesgdef.NewDeviceInfo(nil).
-		SetTimestamp(startTime).
-		SetManufacturer(manuf).
-		SetProduct(0).
-		SetProductName(product).
-		SetDeviceIndex(0) // Primary device
-	fit.Messages = append(fit.Messages, sourceDeviceMsg.ToMesg(nil))
+	// 3a. DeviceInfo: Source App (e.g. Hevy) or recording device
+	emit(buildSourceDeviceInfo(activity, startTime).ToMesg(nil))
 
 	// 3b. DeviceInfo: FitGlue (Enricher/Aggregator)
 	fitGlueDeviceMsg := mesgdef.NewDeviceInfo(nil).
@@ -73,7 +207,7 @@ func GenerateFitFile(activity *pb.StandardizedActivity) ([]byte, error) {
 		SetProduct(1).
 		SetProductName("FitGlue").
 		SetDeviceIndex(1) // Secondary device
-	fit.Messages = append(fit.Messages, fitGlueDeviceMsg.ToMesg(nil))
+	emit(fitGlueDeviceMsg.ToMesg(nil))
 
 	// 4. Session message (Appended last)
 	sessionMsg := mesgdef.NewSession(nil).
@@ -82,14 +216,39 @@ func GenerateFitFile(activity *pb.StandardizedActivity) ([]byte, error) {
 		SetSubSport(subSport).
 		SetStartTime(startTime)
 
+	// Timer: pauses are excluded from timer (moving) time
+	endTime := startTime.Add(time.Duration(session.TotalElapsedTime * float64(time.Second)))
+	resolution := options.recordResolution
+	if resolution <= 0 {
+		resolution = DefaultRecordResolution(endTime.Sub(startTime))
+	}
+	gapThreshold := pauseGapThreshold(resolution)
+	if options.noPauseInference {
+		gapThreshold = 0
+	}
+	pauses := resolvePauses(session, startTime, endTime, gapThreshold)
+	timerTime := timerTimeSeconds(session.TotalElapsedTime, pauses)
+
 	if session.TotalElapsedTime > 0 {
 		sessionMsg.SetTotalElapsedTime(uint32(session.TotalElapsedTime * 1000))
-		sessionMsg.SetTotalTimerTime(uint32(session.TotalElapsedTime * 1000))
+		sessionMsg.SetTotalTimerTime(uint32(timerTime * 1000))
+		activityMsg.SetTotalTimerTime(uint32(timerTime * 1000))
 	}
-	if session.TotalDistance > 0 {
-		sessionMsg.SetTotalDistance(uint32(session.TotalDistance * 100)) // cm? No, FIT uses meters usually but scaled?
+	if totalDistance > 0 {
 		// meters, Type: uint32, Scale: 100, Offset: 0, Units: m
-		sessionMsg.SetTotalDistance(uint32(session.TotalDistance * 100))
+		sessionMsg.SetTotalDistance(uint32(totalDistance * 100))
+	}
+	if poolSwim {
+		if session.PoolLength > 0 {
+			sessionMsg.SetPoolLengthScaled(session.PoolLength)
+			sessionMsg.SetPoolLengthUnit(typedef.DisplayMeasureMetric)
+		}
+		sessionMsg.SetNumLengths(swim.NumLengths)
+		sessionMsg.SetNumActiveLengths(swim.NumActiveLengths)
+		sessionMsg.SetSwimStroke(swim.Stroke)
+		if swim.TotalStrokes > 0 {
+			sessionMsg.SetTotalCycles(swim.TotalStrokes) // total_strokes for swimming
+		}
 	}
 
 	// 5. Lap message (One per session for now)
@@ -102,10 +261,19 @@ func GenerateFitFile(activity *pb.StandardizedActivity) ([]byte, error) {
 
 	if session.TotalElapsedTime > 0 {
 		lapMsg.SetTotalElapsedTime(uint32(session.TotalElapsedTime * 1000))
-		lapMsg.SetTotalTimerTime(uint32(session.TotalElapsedTime * 1000))
+		lapMsg.SetTotalTimerTime(uint32(timerTime * 1000))
+	}
+	if totalDistance > 0 {
+		lapMsg.SetTotalDistance(uint32(totalDistance * 100))
 	}
-	if session.TotalDistance > 0 {
-		lapMsg.SetTotalDistance(uint32(session.TotalDistance * 100))
+	if poolSwim && swim.NumLengths > 0 {
+		lapMsg.SetFirstLengthIndex(0).
+			SetNumLengths(swim.NumLengths).
+			SetNumActiveLengths(swim.NumActiveLengths).
+			SetSwimStroke(swim.Stroke)
+		if swim.TotalStrokes > 0 {
+			lapMsg.SetTotalCycles(swim.TotalStrokes)
+		}
 	}
 
 	// 6. Records
@@ -113,7 +281,18 @@ func GenerateFitFile(activity *pb.StandardizedActivity) ([]byte, error) {
 	// ideally we'd map session.Laps to FIT Laps, but enforcing single Lap for robust uploads first)
 	// We'll flatten all records from all laps into this single FIT Lap/Session for safety.
 
+	// Timer events are interleaved with records in timestamp order
+	timerEvents := buildTimerEvents(startTime, endTime, pauses)
+	nextEvent := 0
+	flushTimerEvents := func(upTo time.Time) {
+		for nextEvent < len(timerEvents) && !timerEvents[nextEvent].Timestamp.After(upTo) {
+			emit(timerEvents[nextEvent].ToMesg(nil))
+			nextEvent++
+		}
+	}
+
 	recordCount := 0
+	var lastRecordTime time.Time
 	for _, lap := range session.Laps {
 		for _, record := range lap.Records {
 			ts := record.Timestamp.AsTime()
@@ -121,6 +300,11 @@ func GenerateFitFile(activity *pb.StandardizedActivity) ([]byte, error) {
 				slog.Warn("Skipping record with invalid timestamp", "timestamp", record.Timestamp)
 				continue // Skip invalid records
 			}
+			if options.recordResolution > 0 && recordCount > 0 && ts.Sub(lastRecordTime) < options.recordResolution {
+				continue // Downsample to the requested resolution
+			}
+			lastRecordTime = ts
+			flushTimerEvents(ts)
 
 			recordMsg := mesgdef.NewRecord(nil).SetTimestamp(ts)
 
@@ -149,9 +333,8 @@ func GenerateFitFile(activity *pb.StandardizedActivity) ([]byte, error) {
 			// Location (Semicircles)
 			// lat * (2^31 / 180)
 			if record.PositionLat != 0 || record.PositionLong != 0 {
-				const semicircleConst = 11930464.7111 // 2^31 / 180
-				lat := int32(record.PositionLat * semicircleConst)
-				long := int32(record.PositionLong * semicircleConst)
+				lat := toSemicircles(record.PositionLat)
+				long := toSemicircles(record.PositionLong)
 				recordMsg.SetPositionLat(lat)
 				recordMsg.SetPositionLong(long)
 			}
@@ -159,9 +342,8 @@ func GenerateFitFile(activity *pb.StandardizedActivity) ([]byte, error) {
 			if recordCount == 0 {
 				// Start Lat/Long for Lap/Session
 				if record.PositionLat != 0 || record.PositionLong != 0 {
-					const semicircleConst = 11930464.7111
-					lat := int32(record.PositionLat * semicircleConst)
-					long := int32(record.PositionLong * semicircleConst)
+					lat := toSemicircles(record.PositionLat)
+					long := toSemicircles(record.PositionLong)
 					lapMsg.SetStartPositionLat(lat)
 					lapMsg.SetStartPositionLong(long)
 					sessionMsg.SetStartPositionLat(lat)
@@ -169,63 +351,176 @@ func GenerateFitFile(activity *pb.StandardizedActivity) ([]byte, error) {
 				}
 			}
 
-			fit.Messages = append(fit.Messages, recordMsg.ToMesg(nil))
+			emit(recordMsg.ToMesg(nil))
 			recordCount++
 		}
 	}
 
-	// Fallback: Synthesize records if none exist
+	// Fallback: Synthesize sparse records if none exist (skipping paused periods)
 	if recordCount == 0 && session.TotalElapsedTime > 0 {
-		duration := int(session.TotalElapsedTime)
-		for i := 0; i < duration; i++ {
-			ts := startTime.Add(time.Duration(i) * time.Second)
+		for ts := startTime; ts.Before(endTime); ts = ts.Add(resolution) {
+			if isPaused(ts, pauses) {
+				continue
+			}
+			flushTimerEvents(ts)
 			recordMsg := mesgdef.NewRecord(nil).SetTimestamp(ts)
-			fit.Messages = append(fit.Messages, recordMsg.ToMesg(nil))
+			emit(recordMsg.ToMesg(nil))
 		}
 	}
 
+	// Remaining timer events (final stop)
+	flushTimerEvents(endTime)
+
 	// 7. Strength Sets (Only for training)
-	if sport == typedef.SportTraining {
-		for i, set := range session.StrengthSets {
-			setStartTime := startTime
-			if set.StartTime != nil {
-				setStartTime = set.StartTime.AsTime()
-			}
+	if sport == typedef.SportTraining && len(session.StrengthSets) > 0 {
+		emit(buildStrengthMessages(session, startTime)...)
+	}
 
-			category := MapExerciseToCategory(set.ExerciseName)
-			setMsg := mesgdef.NewSet(nil).
-				SetTimestamp(setStartTime).
-				SetStartTime(setStartTime).
-				SetCategory([]typedef.ExerciseCategory{category}).
-				SetSetType(typedef.SetTypeActive).
-				SetMessageIndex(typedef.MessageIndex(i))
+	// 8. Swim Lengths (Only for pool swims)
+	emit(lengthMessages...)
 
-			if set.Reps > 0 {
-				setMsg.SetRepetitions(uint16(set.Reps))
+	// Append Summary
+	emit(lapMsg.ToMesg(nil))
+	sessionMesg := sessionMsg.ToMesg(nil)
+	sessionMesg.DeveloperFields = devFields
+	emit(sessionMesg)
+	emit(activityMsg.ToMesg(nil))
+
+	if emitErr != nil {
+		return fmt.Errorf("failed to encode FIT file: %w", emitErr)
+	}
+	if err := enc.SequenceCompleted(); err != nil {
+		return fmt.Errorf("failed to encode FIT file: %w", err)
+	}
+	return nil
+}
+
+// minPauseGap is the minimum gap between consecutive records that is treated as an
+// implicit timer pause when the source provides no explicit pauses.
+const minPauseGap = 60 * time.Second
+
+// pauseGapThreshold returns the record gap treated as a pause at the given record
+// resolution: minPauseGap, widened to two records so that downsampled long activities
+// (e.g. one record per 72s over 72 hours) aren't read as constantly paused.
+func pauseGapThreshold(resolution time.Duration) time.Duration {
+	if gap := 2 * resolution; gap > minPauseGap {
+		return gap
+	}
+	return minPauseGap
+}
+
+// timerPause is a resolved pause window within the session
+type timerPause struct {
+	Start time.Time
+	End   time.Time
+	Auto  bool // Inferred from a record gap rather than provided by the source
+}
+
+// resolvePauses returns the session's timer pauses, sorted, merged and clamped to
+// the session window. Explicit pauses take precedence; otherwise pauses are
+// inferred from record gaps of at least gapThreshold (0 disables inference).
+func resolvePauses(session *pb.Session, startTime, endTime time.Time, gapThreshold time.Duration) []timerPause {
+	var pauses []timerPause
+
+	if len(session.Pauses) > 0 {
+		for _, p := range session.Pauses {
+			if p.StartTime == nil || p.EndTime == nil {
+				continue
 			}
-			if set.WeightKg > 0 {
-				setMsg.SetWeightScaled(set.WeightKg)
+			pauses = append(pauses, timerPause{Start: p.StartTime.AsTime(), End: p.EndTime.AsTime()})
+		}
+	} else if gapThreshold > 0 {
+		var prev time.Time
+		for _, lap := range session.Laps {
+			for _, record := range lap.Records {
+				ts := record.Timestamp.AsTime()
+				if ts.IsZero() {
+					continue
+				}
+				if !prev.IsZero() && ts.Sub(prev) >= gapThreshold {
+					pauses = append(pauses, timerPause{Start: prev, End: ts, Auto: true})
+				}
+				if ts.After(prev) {
+					prev = ts
+				}
 			}
-			if set.DurationSeconds > 0 {
-				setMsg.SetDuration(uint32(set.DurationSeconds * 1000))
+		}
+	}
+
+	sort.Slice(pauses, func(i, j int) bool { return pauses[i].Start.Before(pauses[j].Start) })
+
+	var resolved []timerPause
+	for _, p := range pauses {
+		if p.Start.Before(startTime) {
+			p.Start = startTime
+		}
+		if p.End.After(endTime) {
+			p.End = endTime
+		}
+		if !p.End.After(p.Start) {
+			continue
+		}
+		// Merge overlapping pauses
+		if n := len(resolved); n > 0 && !p.Start.After(resolved[n-1].End) {
+			if p.End.After(resolved[n-1].End) {
+				resolved[n-1].End = p.End
 			}
-			fit.Messages = append(fit.Messages, setMsg.ToMesg(nil))
+			continue
 		}
+		resolved = append(resolved, p)
 	}
+	return resolved
+}
 
-	// Append Summary
-	fit.Messages = append(fit.Messages, lapMsg.ToMesg(nil))
-	fit.Messages = append(fit.Messages, sessionMsg.ToMesg(nil))
-	fit.Messages = append(fit.Messages, activityMsg.ToMesg(nil))
+// timerTimeSeconds returns the elapsed time minus all paused time, in seconds
+func timerTimeSeconds(elapsedSeconds float64, pauses []timerPause) float64 {
+	timer := elapsedSeconds
+	for _, p := range pauses {
+		timer -= p.End.Sub(p.Start).Seconds()
+	}
+	if timer < 0 {
+		return 0
+	}
+	return timer
+}
 
-	// Encode
-	var buf bytes.Buffer
-	enc := encoder.New(&buf)
-	if err := enc.Encode(fit); err != nil {
-		return nil, fmt.Errorf("failed to encode FIT file: %w", err)
+// isPaused reports whether ts falls within a pause window (start inclusive, end exclusive)
+func isPaused(ts time.Time, pauses []timerPause) bool {
+	for _, p := range pauses {
+		if !ts.Before(p.Start) && ts.Before(p.End) {
+			return true
+		}
 	}
+	return false
+}
 
-	return buf.Bytes(), nil
+// buildTimerEvents creates the timer start/stop Event messages for the session:
+// a start at the beginning, a stop/start pair around each pause and a final stop.
+func buildTimerEvents(startTime, endTime time.Time, pauses []timerPause) []*mesgdef.Event {
+	newTimerEvent := func(ts time.Time, eventType typedef.EventType, trigger typedef.TimerTrigger) *mesgdef.Event {
+		return mesgdef.NewEvent(nil).
+			SetTimestamp(ts).
+			SetEvent(typedef.EventTimer).
+			SetEventType(eventType).
+			SetEventGroup(0).
+			SetData(uint32(trigger))
+	}
+
+	events := []*mesgdef.Event{newTimerEvent(startTime, typedef.EventTypeStart, typedef.TimerTriggerManual)}
+	for _, p := range pauses {
+		trigger := typedef.TimerTriggerManual
+		if p.Auto {
+			trigger = typedef.TimerTriggerAuto
+		}
+		events = append(events,
+			newTimerEvent(p.Start, typedef.EventTypeStopAll, trigger),
+			newTimerEvent(p.End, typedef.EventTypeStart, trigger),
+		)
+	}
+	if endTime.After(startTime) {
+		events = append(events, newTimerEvent(endTime, typedef.EventTypeStopAll, typedef.TimerTriggerManual))
+	}
+	return events
 }
 
 func mapSport(activityType pb.ActivityType) (typedef.Sport, typedef.SubSport) {
@@ -294,6 +589,42 @@ func mapSport(activityType pb.ActivityType) (typedef.Sport, typedef.SubSport) {
 	}
 }
 
+// buildSourceDeviceInfo describes the recording device if known, otherwise the source app
+func buildSourceDeviceInfo(activity *pb.StandardizedActivity, startTime time.Time) *mesgdef.DeviceInfo {
+	msg := mesgdef.NewDeviceInfo(nil).
+		SetTimestamp(startTime).
+		SetDeviceIndex(0) // Primary device
+
+	if device := activity.Device; device != nil {
+		manuf := typedef.ManufacturerFromString(device.Manufacturer)
+		if manuf == typedef.ManufacturerInvalid {
+			manuf = typedef.ManufacturerDevelopment
+		}
+		msg.SetManufacturer(manuf).
+			SetProduct(uint16(device.Product)).
+			SetProductName(device.ProductName)
+		if device.SerialNumber != 0 {
+			msg.SetSerialNumber(device.SerialNumber)
+		}
+		if version, err := strconv.ParseFloat(device.SoftwareVersion, 64); err == nil {
+			msg.SetSoftwareVersionScaled(version)
+		}
+		return msg
+	}
+
+	manuf, product := mapSourceToDevice(activity.Source)
+	return msg.SetManufacturer(manuf).
+		SetProduct(0).
+		SetProductName(product)
+}
+
+// toSemicircles converts degrees to FIT semicircles, rounding so that
+// semicircle values survive a round trip through degrees
+func toSemicircles(degrees float64) int32 {
+	const semicircleConst = (1 << 31) / 180.0
+	return int32(math.Round(degrees * semicircleConst))
+}
+
 func mapSourceToDevice(source string) (typedef.Manufacturer, string) {
 	// 255 is ManufacturerDevelopment
 	// We use this because we don't have official Manufacturer IDs for these apps
@@ -0,0 +1,123 @@
+package file_generators
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// writeSeekBuffer is an in-memory io.WriteSeeker, used by GenerateFitFile
+type writeSeekBuffer struct {
+	buf []byte
+	pos int
+}
+
+func (b *writeSeekBuffer) Write(p []byte) (int, error) {
+	if end := b.pos + len(p); end > len(b.buf) {
+		b.buf = append(b.buf[:b.pos], p...)
+	} else {
+		copy(b.buf[b.pos:], p)
+	}
+	b.pos += len(p)
+	return len(p), nil
+}
+
+func (b *writeSeekBuffer) Seek(offset int64, whence int) (int64, error) {
+	var abs int64
+	switch whence {
+	case io.SeekStart:
+		abs = offset
+	case io.SeekCurrent:
+		abs = int64(b.pos) + offset
+	case io.SeekEnd:
+		abs = int64(len(b.buf)) + offset
+	default:
+		return 0, fmt.Errorf("invalid whence: %d", whence)
+	}
+	if abs < 0 || abs > int64(len(b.buf)) {
+		return 0, fmt.Errorf("seek out of range: %d", abs)
+	}
+	b.pos = int(abs)
+	return abs, nil
+}
+
+// Bytes returns the written data
+func (b *writeSeekBuffer) Bytes() []byte {
+	return b.buf
+}
+
+// headerPatchWriter adapts a sequential io.Writer to the io.WriteSeeker required by
+// the FIT stream encoder. The encoder writes a placeholder file header, then seeks
+// back to rewrite it once the data size is known.
+//
+// In the sizing pass (w == nil) nothing is forwarded; the final header is captured.
+// In the writing pass the captured header replaces the placeholder at the start of
+// the first write, and the encoder's later rewrite of already-written bytes is dropped.
+type headerPatchWriter struct {
+	w      io.Writer // nil for the sizing pass
+	header []byte    // Final file header, captured by the sizing pass
+
+	pos int64 // Encoder's logical position
+	end int64 // Bytes written sequentially so far
+}
+
+var errHeaderMismatch = errors.New("FIT header size differs between sizing and writing passes")
+
+func (h *headerPatchWriter) Write(p []byte) (int, error) {
+	start := h.pos
+	h.pos += int64(len(p))
+
+	if start < h.end {
+		// Rewrite of already-written bytes (the file header update). The first
+		// byte of a FIT file header is its size.
+		if h.sizing() && start == 0 && len(p) > 0 && len(p) >= int(p[0]) {
+			h.header = append(h.header[:0], p[:p[0]]...)
+		}
+		return len(p), nil
+	}
+
+	first := start == 0
+	h.end = h.pos
+	if h.sizing() {
+		if first && len(p) > 0 && len(p) >= int(p[0]) {
+			h.header = append(h.header[:0], p[:p[0]]...)
+		}
+		return len(p), nil
+	}
+	if first {
+		// The encoder buffers its output, so the placeholder header arrives at the
+		// start of the first write
+		if len(p) < len(h.header) || len(h.header) == 0 || int(p[0]) != len(h.header) {
+			return 0, errHeaderMismatch
+		}
+		if _, err := h.w.Write(h.header); err != nil {
+			return 0, err
+		}
+		if _, err := h.w.Write(p[len(h.header):]); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+	return h.w.Write(p)
+}
+
+func (h *headerPatchWriter) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+		h.pos = offset
+	case io.SeekCurrent:
+		h.pos += offset
+	case io.SeekEnd:
+		h.pos = h.end + offset
+	default:
+		return 0, fmt.Errorf("invalid whence: %d", whence)
+	}
+	if h.pos < 0 || h.pos > h.end {
+		return 0, fmt.Errorf("seek out of range: %d", h.pos)
+	}
+	return h.pos, nil
+}
+
+func (h *headerPatchWriter) sizing() bool {
+	return h.w == nil
+}
@@ -2,10 +2,13 @@ package file_generators
 
 import (
 	"bytes"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/muktihari/fit/decoder"
+	"github.com/muktihari/fit/profile/basetype"
+	"github.com/muktihari/fit/profile/mesgdef"
 	"github.com/muktihari/fit/profile/typedef"
 	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -139,3 +142,585 @@ func TestGenerateFitFile_NoHR(t *testing.T) {
 		t.Errorf("Expected 10 Record messages (synthesized), got %d", recordCount)
 	}
 }
+
+func TestGenerateFitFile_TimerEvents(t *testing.T) {
+	start := time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)
+
+	decodeTimer := func(t *testing.T, activity *pb.StandardizedActivity, opts ...Option) (timerTime float64, starts, stops, records int) {
+		t.Helper()
+		result, err := GenerateFitFile(activity, opts...)
+		if err != nil {
+			t.Fatalf("GenerateFitFile failed: %v", err)
+		}
+		fitData, err := decoder.New(bytes.NewReader(result)).Decode()
+		if err != nil {
+			t.Fatalf("Failed to decode generated FIT file: %v", err)
+		}
+		for _, msg := range fitData.Messages {
+			switch msg.Num {
+			case typedef.MesgNumEvent:
+				ev := mesgdef.NewEvent(&msg)
+				if ev.Event != typedef.EventTimer {
+					continue
+				}
+				switch ev.EventType {
+				case typedef.EventTypeStart:
+					starts++
+				case typedef.EventTypeStopAll:
+					stops++
+				}
+			case typedef.MesgNumSession:
+				timerTime = mesgdef.NewSession(&msg).TotalTimerTimeScaled()
+			case typedef.MesgNumRecord:
+				records++
+			}
+		}
+		return timerTime, starts, stops, records
+	}
+
+	t.Run("explicit pauses", func(t *testing.T) {
+		activity := &pb.StandardizedActivity{
+			StartTime: timestamppb.New(start),
+			Type:      pb.ActivityType_ACTIVITY_TYPE_RUN,
+			Sessions: []*pb.Session{
+				{
+					StartTime:        timestamppb.New(start),
+					TotalElapsedTime: 600,
+					Pauses: []*pb.Pause{
+						{StartTime: timestamppb.New(start.Add(100 * time.Second)), EndTime: timestamppb.New(start.Add(160 * time.Second))},
+						{StartTime: timestamppb.New(start.Add(300 * time.Second)), EndTime: timestamppb.New(start.Add(330 * time.Second))},
+					},
+				},
+			},
+		}
+
+		timerTime, starts, stops, records := decodeTimer(t, activity)
+		if timerTime != 510 {
+			t.Errorf("Expected timer time 510s, got %v", timerTime)
+		}
+		if starts != 3 || stops != 3 {
+			t.Errorf("Expected 3 timer starts and 3 stops, got %d/%d", starts, stops)
+		}
+		if records != 510 {
+			t.Errorf("Expected 510 synthesized records outside pauses, got %d", records)
+		}
+	})
+
+	t.Run("pause inferred from record gap", func(t *testing.T) {
+		var recs []*pb.Record
+		for i := 0; i < 60; i++ {
+			recs = append(recs, &pb.Record{Timestamp: timestamppb.New(start.Add(time.Duration(i) * time.Second)), HeartRate: 120})
+		}
+		// 5 minute gap, then resume
+		for i := 360; i < 420; i++ {
+			recs = append(recs, &pb.Record{Timestamp: timestamppb.New(start.Add(time.Duration(i) * time.Second)), HeartRate: 130})
+		}
+		activity := &pb.StandardizedActivity{
+			StartTime: timestamppb.New(start),
+			Type:      pb.ActivityType_ACTIVITY_TYPE_RUN,
+			Sessions: []*pb.Session{
+				{
+					StartTime:        timestamppb.New(start),
+					TotalElapsedTime: 420,
+					Laps:             []*pb.Lap{{Records: recs}},
+				},
+			},
+		}
+
+		timerTime, starts, stops, _ := decodeTimer(t, activity)
+		if timerTime != 119 {
+			t.Errorf("Expected timer time 119s, got %v", timerTime)
+		}
+		if starts != 2 || stops != 2 {
+			t.Errorf("Expected 2 timer starts and 2 stops, got %d/%d", starts, stops)
+		}
+	})
+
+	t.Run("72 hour activity at the default resolution is not paused", func(t *testing.T) {
+		elapsed := 72 * time.Hour
+		resolution := DefaultRecordResolution(elapsed)
+		if resolution != 72*time.Second {
+			t.Fatalf("Expected a 72s resolution, got %v", resolution)
+		}
+		var recs []*pb.Record
+		for ts := start; ts.Before(start.Add(elapsed)); ts = ts.Add(resolution) {
+			recs = append(recs, &pb.Record{Timestamp: timestamppb.New(ts), HeartRate: 110})
+		}
+		activity := &pb.StandardizedActivity{
+			StartTime: timestamppb.New(start),
+			Type:      pb.ActivityType_ACTIVITY_TYPE_HIKE,
+			Sessions: []*pb.Session{
+				{
+					StartTime:        timestamppb.New(start),
+					TotalElapsedTime: elapsed.Seconds(),
+					Laps:             []*pb.Lap{{Records: recs}},
+				},
+			},
+		}
+
+		timerTime, starts, stops, records := decodeTimer(t, activity)
+		if timerTime != elapsed.Seconds() {
+			t.Errorf("Expected timer time %vs, got %v", elapsed.Seconds(), timerTime)
+		}
+		if starts != 1 || stops != 1 {
+			t.Errorf("Expected 1 timer start and 1 stop, got %d/%d", starts, stops)
+		}
+		if records != MaxSynthesizedRecords {
+			t.Errorf("Expected %d records, got %d", MaxSynthesizedRecords, records)
+		}
+	})
+
+	t.Run("record gaps are not pauses without pause inference", func(t *testing.T) {
+		recs := []*pb.Record{
+			{Timestamp: timestamppb.New(start), HeartRate: 120},
+			{Timestamp: timestamppb.New(start.Add(5 * time.Minute)), HeartRate: 130},
+		}
+		activity := &pb.StandardizedActivity{
+			StartTime: timestamppb.New(start),
+			Type:      pb.ActivityType_ACTIVITY_TYPE_RUN,
+			Sessions: []*pb.Session{
+				{
+					StartTime:        timestamppb.New(start),
+					TotalElapsedTime: 420,
+					Laps:             []*pb.Lap{{Records: recs}},
+				},
+			},
+		}
+
+		timerTime, starts, stops, _ := decodeTimer(t, activity, WithoutPauseInference())
+		if timerTime != 420 {
+			t.Errorf("Expected timer time 420s, got %v", timerTime)
+		}
+		if starts != 1 || stops != 1 {
+			t.Errorf("Expected 1 timer start and 1 stop, got %d/%d", starts, stops)
+		}
+	})
+
+	t.Run("no pauses", func(t *testing.T) {
+		activity := &pb.StandardizedActivity{
+			StartTime: timestamppb.New(start),
+			Sessions: []*pb.Session{
+				{StartTime: timestamppb.New(start), TotalElapsedTime: 30},
+			},
+		}
+
+		timerTime, starts, stops, _ := decodeTimer(t, activity)
+		if timerTime != 30 {
+			t.Errorf("Expected timer time to equal elapsed time, got %v", timerTime)
+		}
+		if starts != 1 || stops != 1 {
+			t.Errorf("Expected 1 timer start and 1 stop, got %d/%d", starts, stops)
+		}
+	})
+}
+
+func TestGenerateFitFile_StrengthStructure(t *testing.T) {
+	start := time.Date(2026, 1, 10, 18, 0, 0, 0, time.UTC)
+	startTs := timestamppb.New(start)
+	activity := &pb.StandardizedActivity{
+		StartTime: startTs,
+		Type:      pb.ActivityType_ACTIVITY_TYPE_WEIGHT_TRAINING,
+		Sessions: []*pb.Session{
+			{
+				StartTime:        startTs,
+				TotalElapsedTime: 1200,
+				StrengthSets: []*pb.StrengthSet{
+					{ExerciseName: "Bench Press (Barbell)", Reps: 12, WeightKg: 40, StartTime: startTs, SetType: "warmup"},
+					{ExerciseName: "Bench Press (Barbell)", Reps: 8, WeightKg: 80, StartTime: startTs, SetType: "normal"},
+					{ExerciseName: "Bench Press (Barbell)", Reps: 6, WeightKg: 60, StartTime: startTs, SetType: "dropset"},
+					{ExerciseName: "Lateral Raise (Dumbbell)", Reps: 15, WeightKg: 10, StartTime: startTs, SetType: "failure"},
+				},
+			},
+		},
+	}
+
+	result, err := GenerateFitFile(activity)
+	if err != nil {
+		t.Fatalf("GenerateFitFile failed: %v", err)
+	}
+	fitData, err := decoder.New(bytes.NewReader(result)).Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode generated FIT file: %v", err)
+	}
+
+	var titles []*mesgdef.ExerciseTitle
+	var activeSets, restSets []*mesgdef.Set
+	for _, msg := range fitData.Messages {
+		switch msg.Num {
+		case typedef.MesgNumExerciseTitle:
+			titles = append(titles, mesgdef.NewExerciseTitle(&msg))
+		case typedef.MesgNumSet:
+			set := mesgdef.NewSet(&msg)
+			if set.SetType == typedef.SetTypeRest {
+				restSets = append(restSets, set)
+			} else {
+				activeSets = append(activeSets, set)
+			}
+		}
+	}
+
+	// Warmup bench, working bench, lateral raise to failure
+	if len(titles) != 3 {
+		t.Fatalf("Expected 3 ExerciseTitle messages, got %d", len(titles))
+	}
+	if got := titles[0].WktStepName; len(got) != 1 || got[0] != "Bench Press (Barbell) (Warm-up)" {
+		t.Errorf("Unexpected warmup title: %v", got)
+	}
+	if got := titles[1].WktStepName; len(got) != 1 || got[0] != "Bench Press (Barbell)" {
+		t.Errorf("Unexpected working title: %v", got)
+	}
+	if titles[1].ExerciseName != uint16(typedef.BenchPressExerciseNameBarbellBenchPress) {
+		t.Errorf("Expected barbell bench press subtype, got %d", titles[1].ExerciseName)
+	}
+	if got := titles[2].WktStepName; len(got) != 1 || got[0] != "Lateral Raise (Dumbbell) (Failure)" {
+		t.Errorf("Unexpected failure title: %v", got)
+	}
+
+	if len(activeSets) != 4 {
+		t.Fatalf("Expected 4 active sets, got %d", len(activeSets))
+	}
+	// No rest before the drop set: 2 rests for 4 sets
+	if len(restSets) != 2 {
+		t.Fatalf("Expected 2 rest sets, got %d", len(restSets))
+	}
+	if !activeSets[2].StartTime.Equal(activeSets[1].StartTime.Add(time.Duration(activeSets[1].Duration) * time.Millisecond)) {
+		t.Errorf("Expected drop set to start immediately after previous set")
+	}
+	if activeSets[0].WktStepIndex != 0 || activeSets[1].WktStepIndex != 1 || activeSets[2].WktStepIndex != 1 || activeSets[3].WktStepIndex != 2 {
+		t.Errorf("Unexpected wkt_step_index linkage: %d %d %d %d",
+			activeSets[0].WktStepIndex, activeSets[1].WktStepIndex, activeSets[2].WktStepIndex, activeSets[3].WktStepIndex)
+	}
+	if len(activeSets[3].CategorySubtype) != 1 || activeSets[3].CategorySubtype[0] != uint16(typedef.LateralRaiseExerciseNameDumbbellLateralRaise) {
+		t.Errorf("Unexpected lateral raise subtype: %v", activeSets[3].CategorySubtype)
+	}
+
+	// Sets and rests fill the session
+	last := activeSets[3]
+	end := last.StartTime.Add(time.Duration(last.Duration) * time.Millisecond)
+	if end.After(start.Add(1200 * time.Second)) {
+		t.Errorf("Sets extend beyond session end: %v", end)
+	}
+}
+
+func TestGenerateFitFile_DeveloperFields(t *testing.T) {
+	start := time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)
+	activity := &pb.StandardizedActivity{
+		StartTime: timestamppb.New(start),
+		Type:      pb.ActivityType_ACTIVITY_TYPE_RUN,
+		Sessions: []*pb.Session{
+			{StartTime: timestamppb.New(start), TotalElapsedTime: 60},
+		},
+	}
+
+	result, err := GenerateFitFile(activity, WithDeveloperFields(map[string]interface{}{
+		DevFieldPipelineID:         "pipe-123",
+		DevFieldAppliedEnrichments: "fitbit-heart-rate,branding",
+		"not_registered":           "ignored",
+	}))
+	if err != nil {
+		t.Fatalf("GenerateFitFile failed: %v", err)
+	}
+	fitData, err := decoder.New(bytes.NewReader(result)).Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode generated FIT file: %v", err)
+	}
+
+	var devDataIds, descriptions int
+	for _, msg := range fitData.Messages {
+		switch msg.Num {
+		case typedef.MesgNumDeveloperDataId:
+			devDataIds++
+		case typedef.MesgNumFieldDescription:
+			descriptions++
+		}
+	}
+	if devDataIds != 1 {
+		t.Errorf("Expected 1 DeveloperDataId message, got %d", devDataIds)
+	}
+	if descriptions != 2 {
+		t.Errorf("Expected 2 FieldDescription messages, got %d", descriptions)
+	}
+
+	got := make(map[string]interface{})
+	for _, f := range DecodeDeveloperFields(fitData) {
+		if f.Message != "session" {
+			t.Errorf("Expected developer field on session, got %s", f.Message)
+		}
+		got[f.Name] = f.Value
+	}
+	if got[DevFieldPipelineID] != "pipe-123" {
+		t.Errorf("Expected pipeline_id 'pipe-123', got %v", got[DevFieldPipelineID])
+	}
+	if got[DevFieldAppliedEnrichments] != "fitbit-heart-rate,branding" {
+		t.Errorf("Expected applied_enrichments, got %v", got[DevFieldAppliedEnrichments])
+	}
+	if _, ok := got["not_registered"]; ok {
+		t.Errorf("Unregistered developer field should not be written")
+	}
+}
+
+func TestToDeveloperValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		def     DeveloperFieldDefinition
+		value   interface{}
+		want    interface{}
+		wantErr bool
+	}{
+		{"uint8 from int", DeveloperFieldDefinition{Type: basetype.Uint8}, 7, uint8(7), false},
+		{"uint8 from string", DeveloperFieldDefinition{Type: basetype.Uint8}, "8", uint8(8), false},
+		{"uint8 out of range", DeveloperFieldDefinition{Type: basetype.Uint8}, 300, nil, true},
+		{"float32 from float64", DeveloperFieldDefinition{Type: basetype.Float32}, 2.5, float32(2.5), false},
+		{"sint32 negative", DeveloperFieldDefinition{Type: basetype.Sint32}, -4, int32(-4), false},
+		{"string from int", DeveloperFieldDefinition{Type: basetype.String}, 42, "42", false},
+		{"unsupported type", DeveloperFieldDefinition{Type: basetype.Uint16}, true, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := toDeveloperValue(tt.def, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("toDeveloperValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Any() != tt.want {
+				t.Errorf("toDeveloperValue() = %v (%T), want %v (%T)", got.Any(), got.Any(), tt.want, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateFitFile_Swim(t *testing.T) {
+	start := time.Date(2026, 1, 10, 7, 0, 0, 0, time.UTC)
+
+	t.Run("pool swim", func(t *testing.T) {
+		activity := &pb.StandardizedActivity{
+			StartTime: timestamppb.New(start),
+			Type:      pb.ActivityType_ACTIVITY_TYPE_SWIM,
+			Sessions: []*pb.Session{
+				{
+					StartTime:        timestamppb.New(start),
+					TotalElapsedTime: 150,
+					PoolLength:       25,
+					Lengths: []*pb.SwimLength{
+						{TotalElapsedTime: 30, Stroke: pb.SwimStroke_SWIM_STROKE_FREESTYLE, TotalStrokes: 18},
+						{TotalElapsedTime: 32, Stroke: pb.SwimStroke_SWIM_STROKE_FREESTYLE, TotalStrokes: 19},
+						{TotalElapsedTime: 45, Idle: true},
+						{TotalElapsedTime: 40, Stroke: pb.SwimStroke_SWIM_STROKE_BREASTSTROKE, TotalStrokes: 14},
+					},
+				},
+			},
+		}
+
+		result, err := GenerateFitFile(activity)
+		if err != nil {
+			t.Fatalf("GenerateFitFile failed: %v", err)
+		}
+		fitData, err := decoder.New(bytes.NewReader(result)).Decode()
+		if err != nil {
+			t.Fatalf("Failed to decode generated FIT file: %v", err)
+		}
+
+		var lengths []*mesgdef.Length
+		var session *mesgdef.Session
+		for i := range fitData.Messages {
+			msg := &fitData.Messages[i]
+			switch msg.Num {
+			case typedef.MesgNumLength:
+				lengths = append(lengths, mesgdef.NewLength(msg))
+			case typedef.MesgNumSession:
+				session = mesgdef.NewSession(msg)
+			}
+		}
+
+		if len(lengths) != 4 {
+			t.Fatalf("Expected 4 Length messages, got %d", len(lengths))
+		}
+		if lengths[2].LengthType != typedef.LengthTypeIdle {
+			t.Errorf("Expected third length to be idle, got %v", lengths[2].LengthType)
+		}
+		if lengths[3].SwimStroke != typedef.SwimStrokeBreaststroke {
+			t.Errorf("Expected fourth length breaststroke, got %v", lengths[3].SwimStroke)
+		}
+		if !lengths[1].StartTime.Equal(start.Add(30 * time.Second)) {
+			t.Errorf("Expected second length to start at +30s, got %v", lengths[1].StartTime)
+		}
+
+		if session == nil {
+			t.Fatal("Session message missing")
+		}
+		if session.SubSport != typedef.SubSportLapSwimming {
+			t.Errorf("Expected lap swimming sub-sport, got %v", session.SubSport)
+		}
+		if session.PoolLengthScaled() != 25 {
+			t.Errorf("Expected pool length 25, got %v", session.PoolLengthScaled())
+		}
+		if session.NumActiveLengths != 3 {
+			t.Errorf("Expected 3 active lengths, got %d", session.NumActiveLengths)
+		}
+		if session.TotalDistanceScaled() != 75 {
+			t.Errorf("Expected distance derived from lengths (75m), got %v", session.TotalDistanceScaled())
+		}
+		if session.SwimStroke != typedef.SwimStrokeFreestyle {
+			t.Errorf("Expected predominant stroke freestyle, got %v", session.SwimStroke)
+		}
+	})
+
+	t.Run("open water", func(t *testing.T) {
+		session := &pb.Session{
+			Laps: []*pb.Lap{{Records: []*pb.Record{{PositionLat: 51.5, PositionLong: -0.1}}}},
+		}
+		if got := resolveSwimSubSport(session); got != typedef.SubSportOpenWater {
+			t.Errorf("Expected open water sub-sport, got %v", got)
+		}
+	})
+}
+
+func TestDefaultRecordResolution(t *testing.T) {
+	tests := []struct {
+		elapsed time.Duration
+		want    time.Duration
+	}{
+		{10 * time.Second, time.Second},
+		{time.Hour, time.Second},
+		{10 * time.Hour, 10 * time.Second},
+		{48 * time.Hour, 48 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := DefaultRecordResolution(tt.elapsed); got != tt.want {
+			t.Errorf("DefaultRecordResolution(%v) = %v, want %v", tt.elapsed, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateFitFile_RecordResolution(t *testing.T) {
+	start := time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)
+	countRecords := func(t *testing.T, data []byte) int {
+		t.Helper()
+		fitData, err := decoder.New(bytes.NewReader(data)).Decode()
+		if err != nil {
+			t.Fatalf("Failed to decode generated FIT file: %v", err)
+		}
+		count := 0
+		for _, msg := range fitData.Messages {
+			if msg.Num == typedef.MesgNumRecord {
+				count++
+			}
+		}
+		return count
+	}
+
+	t.Run("synthesized records are capped for long sessions", func(t *testing.T) {
+		activity := &pb.StandardizedActivity{
+			StartTime: timestamppb.New(start),
+			Type:      pb.ActivityType_ACTIVITY_TYPE_HIKE,
+			Sessions:  []*pb.Session{{StartTime: timestamppb.New(start), TotalElapsedTime: 48 * 3600}},
+		}
+		data, err := GenerateFitFile(activity)
+		if err != nil {
+			t.Fatalf("GenerateFitFile failed: %v", err)
+		}
+		if got := countRecords(t, data); got != MaxSynthesizedRecords {
+			t.Errorf("Expected %d synthesized records, got %d", MaxSynthesizedRecords, got)
+		}
+	})
+
+	t.Run("source records are downsampled to the requested resolution", func(t *testing.T) {
+		var records []*pb.Record
+		for i := 0; i < 60; i++ {
+			records = append(records, &pb.Record{Timestamp: timestamppb.New(start.Add(time.Duration(i) * time.Second)), HeartRate: 130})
+		}
+		activity := &pb.StandardizedActivity{
+			StartTime: timestamppb.New(start),
+			Type:      pb.ActivityType_ACTIVITY_TYPE_RUN,
+			Sessions: []*pb.Session{{
+				StartTime:        timestamppb.New(start),
+				TotalElapsedTime: 60,
+				Laps:             []*pb.Lap{{Records: records}},
+			}},
+		}
+		data, err := GenerateFitFile(activity, WithRecordResolution(5*time.Second))
+		if err != nil {
+			t.Fatalf("GenerateFitFile failed: %v", err)
+		}
+		if got := countRecords(t, data); got != 12 {
+			t.Errorf("Expected 12 records at 5s resolution, got %d", got)
+		}
+	})
+}
+
+func TestEncodeFitFile_Streaming(t *testing.T) {
+	start := time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)
+	var records []*pb.Record
+	for i := 0; i < 3*3600; i++ {
+		records = append(records, &pb.Record{Timestamp: timestamppb.New(start.Add(time.Duration(i) * time.Second)), HeartRate: 140})
+	}
+	activity := &pb.StandardizedActivity{
+		StartTime: timestamppb.New(start),
+		Type:      pb.ActivityType_ACTIVITY_TYPE_RUN,
+		Sessions: []*pb.Session{{
+			StartTime:        timestamppb.New(start),
+			TotalElapsedTime: 3 * 3600,
+			Laps:             []*pb.Lap{{Records: records}},
+		}},
+	}
+	opts := []Option{WithDeveloperFields(map[string]interface{}{DevFieldPipelineID: "p1"})}
+
+	want, err := GenerateFitFile(activity, opts...)
+	if err != nil {
+		t.Fatalf("GenerateFitFile failed: %v", err)
+	}
+
+	// bytes.Buffer is not seekable, so the two-pass path is used
+	var buf bytes.Buffer
+	if err := EncodeFitFile(&buf, activity, opts...); err != nil {
+		t.Fatalf("EncodeFitFile failed: %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("Streamed output differs from GenerateFitFile (%d vs %d bytes)", buf.Len(), len(want))
+	}
+	if _, err := decoder.New(bytes.NewReader(buf.Bytes())).Decode(); err != nil {
+		t.Errorf("Failed to decode streamed FIT file: %v", err)
+	}
+
+	// A tee receives the final file, even alongside a seekable writer
+	var tee bytes.Buffer
+	seekable := &writeSeekBuffer{}
+	if err := EncodeFitFile(seekable, activity, append(opts, WithTee(&tee))...); err != nil {
+		t.Fatalf("EncodeFitFile with tee failed: %v", err)
+	}
+	if !bytes.Equal(seekable.Bytes(), want) {
+		t.Errorf("Teed output differs from GenerateFitFile (%d vs %d bytes)", len(seekable.Bytes()), len(want))
+	}
+	if !bytes.Equal(tee.Bytes(), want) {
+		t.Errorf("Tee received different bytes from the file (%d vs %d bytes)", tee.Len(), len(want))
+	}
+}
+
+func BenchmarkGenerateFitFile(b *testing.B) {
+	start := time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)
+	for _, hours := range []int{1, 10, 48} {
+		activity := &pb.StandardizedActivity{
+			StartTime: timestamppb.New(start),
+			Type:      pb.ActivityType_ACTIVITY_TYPE_HIKE,
+			Sessions: []*pb.Session{{
+				StartTime:        timestamppb.New(start),
+				TotalElapsedTime: float64(hours * 3600),
+			}},
+		}
+		b.Run(fmt.Sprintf("%dh", hours), func(b *testing.B) {
+			b.ReportAllocs()
+			var size int
+			for i := 0; i < b.N; i++ {
+				data, err := GenerateFitFile(activity)
+				if err != nil {
+					b.Fatalf("GenerateFitFile failed: %v", err)
+				}
+				size = len(data)
+			}
+			b.ReportMetric(float64(size), "fit-bytes")
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package file_generators
+
+import (
+	"strings"
+	"time"
+
+	"github.com/muktihari/fit/profile/mesgdef"
+	"github.com/muktihari/fit/profile/typedef"
+	"github.com/muktihari/fit/proto"
+
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+)
+
+// Set types as provided by sources (e.g. Hevy) on StrengthSet.SetType
+const (
+	SetTypeNormal  = "normal"
+	SetTypeWarmup  = "warmup"
+	SetTypeFailure = "failure"
+	SetTypeDropSet = "dropset"
+)
+
+// Exercise title suffixes for set types that get their own ExerciseTitle.
+// FIT's set_type only distinguishes active from rest, so warm-up and failure
+// sets are carried by the exercise title instead; drop sets are already
+// recognisable from having no rest before them.
+const (
+	WarmupTitleSuffix  = " (Warm-up)"
+	FailureTitleSuffix = " (Failure)"
+)
+
+const (
+	// secondsPerRep is used to estimate set duration when the source provides none
+	secondsPerRep = 3
+	// minEstimatedSetSeconds is the minimum estimated duration of a set without explicit duration
+	minEstimatedSetSeconds = 20
+)
+
+// timedStrengthSet is a StrengthSet placed on the session timeline
+type timedStrengthSet struct {
+	Set      *pb.StrengthSet
+	Start    time.Time
+	Duration time.Duration
+	Rest     time.Duration // Rest following this set (0 for the last set)
+}
+
+// NormalizeSetType maps source set type strings to one of the SetType constants
+func NormalizeSetType(setType string) string {
+	normalized := strings.NewReplacer("_", "", "-", "", " ", "").Replace(strings.ToLower(setType))
+	switch normalized {
+	case "warmup":
+		return SetTypeWarmup
+	case "failure":
+		return SetTypeFailure
+	case "dropset", "drop":
+		return SetTypeDropSet
+	default:
+		return SetTypeNormal
+	}
+}
+
+// estimatedSetDuration returns the set's duration, estimating from reps when not provided
+func estimatedSetDuration(set *pb.StrengthSet) time.Duration {
+	if set.DurationSeconds > 0 {
+		return time.Duration(set.DurationSeconds) * time.Second
+	}
+	seconds := int(set.Reps) * secondsPerRep
+	if seconds < minEstimatedSetSeconds {
+		seconds = minEstimatedSetSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// hasDistinctSetTimes reports whether the source provided real per-set start times
+// (strictly increasing), rather than every set sharing the workout start time.
+func hasDistinctSetTimes(sets []*pb.StrengthSet) bool {
+	if len(sets) < 2 {
+		return false
+	}
+	for i := 1; i < len(sets); i++ {
+		if sets[i].StartTime == nil || sets[i-1].StartTime == nil {
+			return false
+		}
+		if !sets[i].StartTime.AsTime().After(sets[i-1].StartTime.AsTime()) {
+			return false
+		}
+	}
+	return true
+}
+
+// layoutStrengthSets places the session's sets on a timeline.
+// If the source provided real set start times, rests are the gaps between sets.
+// Otherwise sets are laid out sequentially from the session start, with the
+// remaining session time spread evenly as rest between sets. Drop sets follow
+// the previous set immediately, without rest.
+func layoutStrengthSets(sets []*pb.StrengthSet, startTime time.Time, elapsed time.Duration) []timedStrengthSet {
+	timed := make([]timedStrengthSet, len(sets))
+	for i, set := range sets {
+		timed[i] = timedStrengthSet{Set: set, Duration: estimatedSetDuration(set)}
+	}
+	if len(timed) == 0 {
+		return timed
+	}
+
+	if hasDistinctSetTimes(sets) {
+		for i := range timed {
+			timed[i].Start = sets[i].StartTime.AsTime()
+			if i > 0 {
+				prevEnd := timed[i-1].Start.Add(timed[i-1].Duration)
+				if gap := timed[i].Start.Sub(prevEnd); gap > 0 {
+					timed[i-1].Rest = gap
+				}
+			}
+		}
+		return timed
+	}
+
+	var active time.Duration
+	restSlots := 0
+	for i, ts := range timed {
+		active += ts.Duration
+		if i > 0 && NormalizeSetType(ts.Set.SetType) != SetTypeDropSet {
+			restSlots++
+		}
+	}
+
+	// Compress active time if estimates exceed the session
+	if elapsed > 0 && active > elapsed {
+		scale := float64(elapsed) / float64(active)
+		for i := range timed {
+			timed[i].Duration = time.Duration(float64(timed[i].Duration) * scale).Truncate(time.Second)
+		}
+		active = elapsed
+	}
+
+	var restEach time.Duration
+	if restSlots > 0 && elapsed > active {
+		restEach = ((elapsed - active) / time.Duration(restSlots)).Truncate(time.Second)
+	}
+
+	cursor := startTime
+	for i := range timed {
+		if i > 0 && NormalizeSetType(timed[i].Set.SetType) != SetTypeDropSet {
+			timed[i-1].Rest = restEach
+			cursor = cursor.Add(restEach)
+		}
+		timed[i].Start = cursor
+		cursor = cursor.Add(timed[i].Duration)
+	}
+	return timed
+}
+
+// exerciseTitleKey identifies a distinct exercise (and warm-up or failure variant) for ExerciseTitle messages
+type exerciseTitleKey struct {
+	Name   string
+	Suffix string
+}
+
+// exerciseTitleSuffix returns the ExerciseTitle suffix for a normalized set type
+func exerciseTitleSuffix(setType string) string {
+	switch setType {
+	case SetTypeWarmup:
+		return WarmupTitleSuffix
+	case SetTypeFailure:
+		return FailureTitleSuffix
+	default:
+		return ""
+	}
+}
+
+// buildStrengthMessages creates ExerciseTitle messages for each distinct exercise,
+// followed by active and rest Set messages laid out on the session timeline.
+// Sets reference their exercise title via wkt_step_index.
+func buildStrengthMessages(session *pb.Session, startTime time.Time) []proto.Message {
+	elapsed := time.Duration(session.TotalElapsedTime * float64(time.Second))
+	timed := layoutStrengthSets(session.StrengthSets, startTime, elapsed)
+
+	var titles []proto.Message
+	var sets []proto.Message
+	titleIndex := make(map[exerciseTitleKey]typedef.MessageIndex)
+	setIndex := 0
+
+	for _, ts := range timed {
+		set := ts.Set
+		setType := NormalizeSetType(set.SetType)
+		category, name, hasName := MapExerciseToName(set.ExerciseName)
+
+		// One ExerciseTitle per exercise, carrying the user's original name.
+		// Warm-up and failure sets get their own title so they are distinguishable from working sets.
+		key := exerciseTitleKey{Name: strings.TrimSpace(set.ExerciseName), Suffix: exerciseTitleSuffix(setType)}
+		stepIndex, ok := titleIndex[key]
+		if !ok {
+			stepIndex = typedef.MessageIndex(len(titleIndex))
+			titleIndex[key] = stepIndex
+
+			stepName := key.Name
+			if stepName == "" {
+				stepName = "Exercise"
+			}
+			stepName += key.Suffix
+			titleMsg := mesgdef.NewExerciseTitle(nil).
+				SetMessageIndex(stepIndex).
+				SetExerciseCategory(category).
+				SetWktStepName([]string{stepName})
+			if hasName {
+				titleMsg.SetExerciseName(name)
+			}
+			titles = append(titles, titleMsg.ToMesg(nil))
+		}
+
+		setMsg := mesgdef.NewSet(nil).
+			SetTimestamp(ts.Start.Add(ts.Duration)).
+			SetStartTime(ts.Start).
+			SetDuration(uint32(ts.Duration.Milliseconds())).
+			SetCategory([]typedef.ExerciseCategory{category}).
+			SetSetType(typedef.SetTypeActive).
+			SetWktStepIndex(stepIndex).
+			SetMessageIndex(typedef.MessageIndex(setIndex))
+		if hasName {
+			setMsg.SetCategorySubtype([]uint16{name})
+		}
+		if set.Reps > 0 {
+			setMsg.SetRepetitions(uint16(set.Reps))
+		}
+		if set.WeightKg > 0 {
+			setMsg.SetWeightScaled(set.WeightKg)
+			setMsg.SetWeightDisplayUnit(typedef.FitBaseUnitKilogram)
+		}
+		sets = append(sets, setMsg.ToMesg(nil))
+		setIndex++
+
+		// Synthesized rest period following the set
+		if ts.Rest > 0 {
+			restStart := ts.Start.Add(ts.Duration)
+			restMsg := mesgdef.NewSet(nil).
+				SetTimestamp(restStart.Add(ts.Rest)).
+				SetStartTime(restStart).
+				SetDuration(uint32(ts.Rest.Milliseconds())).
+				SetSetType(typedef.SetTypeRest).
+				SetMessageIndex(typedef.MessageIndex(setIndex))
+			sets = append(sets, restMsg.ToMesg(nil))
+			setIndex++
+		}
+	}
+
+	return append(titles, sets...)
+}
@@ -0,0 +1,131 @@
+package file_generators
+
+import (
+	"time"
+
+	"github.com/muktihari/fit/profile/mesgdef"
+	"github.com/muktihari/fit/profile/typedef"
+	"github.com/muktihari/fit/proto"
+
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+)
+
+// swimSummary aggregates Length data for the Lap and Session messages
+type swimSummary struct {
+	NumLengths       uint16
+	NumActiveLengths uint16
+	TotalStrokes     uint32
+	Stroke           typedef.SwimStroke // Predominant stroke of the active lengths
+	Distance         float64            // meters (active lengths x pool length)
+}
+
+// isPoolSwim reports whether the session is a pool swim (pool length or lengths provided)
+func isPoolSwim(session *pb.Session) bool {
+	return session.PoolLength > 0 || len(session.Lengths) > 0
+}
+
+// resolveSwimSubSport selects lap (pool) or open water swimming.
+// Sessions with pool data are pool swims; sessions with GPS but no pool data
+// are open water. Otherwise lap swimming is assumed.
+func resolveSwimSubSport(session *pb.Session) typedef.SubSport {
+	if isPoolSwim(session) {
+		return typedef.SubSportLapSwimming
+	}
+	for _, lap := range session.Laps {
+		for _, rec := range lap.Records {
+			if rec.PositionLat != 0 || rec.PositionLong != 0 {
+				return typedef.SubSportOpenWater
+			}
+		}
+	}
+	return typedef.SubSportLapSwimming
+}
+
+// mapSwimStroke maps the standardized stroke to the FIT swim stroke
+func mapSwimStroke(stroke pb.SwimStroke) typedef.SwimStroke {
+	switch stroke {
+	case pb.SwimStroke_SWIM_STROKE_FREESTYLE:
+		return typedef.SwimStrokeFreestyle
+	case pb.SwimStroke_SWIM_STROKE_BACKSTROKE:
+		return typedef.SwimStrokeBackstroke
+	case pb.SwimStroke_SWIM_STROKE_BREASTSTROKE:
+		return typedef.SwimStrokeBreaststroke
+	case pb.SwimStroke_SWIM_STROKE_BUTTERFLY:
+		return typedef.SwimStrokeButterfly
+	case pb.SwimStroke_SWIM_STROKE_DRILL:
+		return typedef.SwimStrokeDrill
+	case pb.SwimStroke_SWIM_STROKE_MIXED:
+		return typedef.SwimStrokeMixed
+	default:
+		return typedef.SwimStrokeInvalid
+	}
+}
+
+// buildLengthMessages creates one Length message per pool length.
+// Lengths without a start time follow the previous length (starting at the session start).
+func buildLengthMessages(session *pb.Session, startTime time.Time) ([]proto.Message, swimSummary) {
+	var messages []proto.Message
+	summary := swimSummary{Stroke: typedef.SwimStrokeInvalid}
+	strokeCounts := make(map[typedef.SwimStroke]int)
+
+	cursor := startTime
+	for i, length := range session.Lengths {
+		start := cursor
+		if length.StartTime != nil && !length.StartTime.AsTime().IsZero() {
+			start = length.StartTime.AsTime()
+		}
+		elapsed := time.Duration(length.TotalElapsedTime * float64(time.Second))
+		cursor = start.Add(elapsed)
+
+		lengthMsg := mesgdef.NewLength(nil).
+			SetMessageIndex(typedef.MessageIndex(i)).
+			SetTimestamp(start.Add(elapsed)).
+			SetStartTime(start).
+			SetEvent(typedef.EventLength).
+			SetEventType(typedef.EventTypeStop).
+			SetTotalElapsedTime(uint32(elapsed.Milliseconds())).
+			SetTotalTimerTime(uint32(elapsed.Milliseconds()))
+
+		summary.NumLengths++
+		if length.Idle {
+			lengthMsg.SetLengthType(typedef.LengthTypeIdle)
+			messages = append(messages, lengthMsg.ToMesg(nil))
+			continue
+		}
+
+		lengthMsg.SetLengthType(typedef.LengthTypeActive)
+		summary.NumActiveLengths++
+		summary.Distance += session.PoolLength
+
+		stroke := mapSwimStroke(length.Stroke)
+		if stroke != typedef.SwimStrokeInvalid {
+			lengthMsg.SetSwimStroke(stroke)
+			strokeCounts[stroke]++
+		}
+		if length.TotalStrokes > 0 {
+			lengthMsg.SetTotalStrokes(uint16(length.TotalStrokes))
+			summary.TotalStrokes += uint32(length.TotalStrokes)
+			if secs := elapsed.Seconds(); secs > 0 {
+				lengthMsg.SetAvgSwimmingCadence(uint8(float64(length.TotalStrokes) * 60 / secs))
+			}
+		}
+		if session.PoolLength > 0 && elapsed > 0 {
+			lengthMsg.SetAvgSpeedScaled(session.PoolLength / elapsed.Seconds())
+		}
+		messages = append(messages, lengthMsg.ToMesg(nil))
+	}
+
+	// Predominant stroke; ties are reported as mixed
+	best := 0
+	for stroke, count := range strokeCounts {
+		switch {
+		case count > best:
+			best = count
+			summary.Stroke = stroke
+		case count == best:
+			summary.Stroke = typedef.SwimStrokeMixed
+		}
+	}
+
+	return messages, summary
+}
@@ -0,0 +1,354 @@
+package file_generators
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/muktihari/fit/decoder"
+	"github.com/muktihari/fit/profile/basetype"
+	"github.com/muktihari/fit/profile/mesgdef"
+	"github.com/muktihari/fit/profile/typedef"
+	"github.com/muktihari/fit/proto"
+
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+)
+
+// Validation issue codes
+const (
+	IssueFileIDNotFirst        = "file_id_not_first"
+	IssueMissingSummary        = "missing_summary_message"
+	IssueSummaryOrder          = "summary_message_order"
+	IssueActivitySessionCount  = "activity_session_count"
+	IssueLapSessionMismatch    = "lap_session_mismatch"
+	IssueTimestampNotMonotonic = "record_timestamp_not_monotonic"
+	IssueRecordOutsideSession  = "record_outside_session"
+	IssueHeartRateOutOfRange   = "heart_rate_out_of_range"
+	IssueCadenceOutOfRange     = "cadence_out_of_range"
+	IssueAltitudeOutOfRange    = "altitude_out_of_range"
+)
+
+// sessionBoundaryTolerance allows for sub-second rounding of session elapsed time
+const sessionBoundaryTolerance = time.Second
+
+// FIT altitude is stored as uint16 with scale 5 and offset 500
+const (
+	minFitAltitude = -500.0
+	maxFitAltitude = float64(basetype.Uint16Invalid-1)/5 - 500
+)
+
+// IssueSeverity indicates whether an issue is likely to cause an upload rejection
+type IssueSeverity string
+
+const (
+	SeverityError   IssueSeverity = "error"
+	SeverityWarning IssueSeverity = "warning"
+)
+
+// ValidationIssue is a single conformance problem. Per-record problems are
+// aggregated into one issue per code, with Count occurrences.
+type ValidationIssue struct {
+	Code       string
+	Severity   IssueSeverity
+	Message    string
+	Count      int
+	Repairable bool // Can be fixed by RepairActivity and regenerating
+}
+
+func (i ValidationIssue) String() string {
+	if i.Count > 1 {
+		return fmt.Sprintf("%s (%s, x%d): %s", i.Code, i.Severity, i.Count, i.Message)
+	}
+	return fmt.Sprintf("%s (%s): %s", i.Code, i.Severity, i.Message)
+}
+
+// ValidationReport holds all issues found in a FIT file
+type ValidationReport struct {
+	Issues []ValidationIssue
+}
+
+// Valid reports whether no issues were found
+func (r *ValidationReport) Valid() bool {
+	return len(r.Issues) == 0
+}
+
+// HasErrors reports whether any error-severity issues were found
+func (r *ValidationReport) HasErrors() bool {
+	for _, issue := range r.Issues {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Repairable reports whether any issue can be fixed by RepairActivity
+func (r *ValidationReport) Repairable() bool {
+	for _, issue := range r.Issues {
+		if issue.Repairable {
+			return true
+		}
+	}
+	return false
+}
+
+// Summary returns a single-line description of all issues (for metadata/logging)
+func (r *ValidationReport) Summary() string {
+	parts := make([]string, len(r.Issues))
+	for i, issue := range r.Issues {
+		parts[i] = issue.String()
+	}
+	return strings.Join(parts, "; ")
+}
+
+// add records an occurrence of code, aggregating repeated occurrences
+func (r *ValidationReport) add(code string, severity IssueSeverity, repairable bool, format string, args ...interface{}) {
+	for i := range r.Issues {
+		if r.Issues[i].Code == code {
+			r.Issues[i].Count++
+			return
+		}
+	}
+	r.Issues = append(r.Issues, ValidationIssue{
+		Code:       code,
+		Severity:   severity,
+		Message:    fmt.Sprintf(format, args...),
+		Count:      1,
+		Repairable: repairable,
+	})
+}
+
+// ValidateFitFile decodes a generated FIT file and checks message ordering,
+// record timestamps and Session/Lap/Activity consistency.
+// If source is provided, its values are also checked against FIT field ranges,
+// since out-of-range values are silently truncated when encoded.
+// An error is returned only if the file cannot be decoded at all.
+func ValidateFitFile(data []byte, source *pb.StandardizedActivity) (*ValidationReport, error) {
+	return ValidateFitReader(bytes.NewReader(data), source)
+}
+
+// ValidateFitReader is ValidateFitFile for a FIT stream. Messages are checked as
+// they are decoded, so the file is never held in memory.
+func ValidateFitReader(r io.Reader, source *pb.StandardizedActivity) (*ValidationReport, error) {
+	v := &fitValidator{
+		report:          &ValidationReport{},
+		lastLapIdx:      -1,
+		firstSessionIdx: -1,
+		lastSessionIdx:  -1,
+		activityIdx:     -1,
+	}
+	dec := decoder.New(r, decoder.WithMesgListener(v), decoder.WithBroadcastOnly())
+	if _, err := dec.Decode(); err != nil {
+		return nil, fmt.Errorf("failed to decode FIT file: %w", err)
+	}
+	return v.finish(source), nil
+}
+
+// fitValidator collects the state needed for validation as messages are decoded
+type fitValidator struct {
+	report *ValidationReport
+
+	index           int
+	laps            []*mesgdef.Lap
+	sessions        []*mesgdef.Session
+	activity        *mesgdef.Activity
+	lastLapIdx      int
+	firstSessionIdx int
+	lastSessionIdx  int
+	activityIdx     int
+	lastRecordTime  time.Time
+	// Earliest and latest record timestamps, checked against the session once it is decoded
+	firstRecordTime time.Time
+	endRecordTime   time.Time
+}
+
+// OnMesg implements decoder.MesgListener
+func (v *fitValidator) OnMesg(msg proto.Message) {
+	i := v.index
+	v.index++
+
+	if i == 0 && msg.Num != typedef.MesgNumFileId {
+		v.report.add(IssueFileIDNotFirst, SeverityError, false, "first message must be file_id")
+	}
+
+	switch msg.Num {
+	case typedef.MesgNumRecord:
+		ts := mesgdef.NewRecord(&msg).Timestamp
+		if !v.lastRecordTime.IsZero() && ts.Before(v.lastRecordTime) {
+			v.report.add(IssueTimestampNotMonotonic, SeverityError, true,
+				"record at %s precedes previous record at %s", ts.Format(time.RFC3339), v.lastRecordTime.Format(time.RFC3339))
+		}
+		v.lastRecordTime = ts
+		if v.firstRecordTime.IsZero() || ts.Before(v.firstRecordTime) {
+			v.firstRecordTime = ts
+		}
+		if ts.After(v.endRecordTime) {
+			v.endRecordTime = ts
+		}
+	case typedef.MesgNumLap:
+		v.laps = append(v.laps, mesgdef.NewLap(&msg))
+		v.lastLapIdx = i
+	case typedef.MesgNumSession:
+		v.sessions = append(v.sessions, mesgdef.NewSession(&msg))
+		if v.firstSessionIdx < 0 {
+			v.firstSessionIdx = i
+		}
+		v.lastSessionIdx = i
+	case typedef.MesgNumActivity:
+		v.activity = mesgdef.NewActivity(&msg)
+		v.activityIdx = i
+	}
+}
+
+// finish runs the whole-file checks once all messages are decoded
+func (v *fitValidator) finish(source *pb.StandardizedActivity) *ValidationReport {
+	report := v.report
+	laps, sessions, activity := v.laps, v.sessions, v.activity
+	lastLapIdx, firstSessionIdx, lastSessionIdx, activityIdx := v.lastLapIdx, v.firstSessionIdx, v.lastSessionIdx, v.activityIdx
+
+	if v.index == 0 {
+		report.add(IssueFileIDNotFirst, SeverityError, false, "first message must be file_id")
+	}
+
+	// Summary messages: laps, then sessions, then activity
+	switch {
+	case len(laps) == 0:
+		report.add(IssueMissingSummary, SeverityError, false, "no lap message")
+	case len(sessions) == 0:
+		report.add(IssueMissingSummary, SeverityError, false, "no session message")
+	case activity == nil:
+		report.add(IssueMissingSummary, SeverityError, false, "no activity message")
+	default:
+		if lastLapIdx > firstSessionIdx || lastSessionIdx > activityIdx {
+			report.add(IssueSummaryOrder, SeverityError, false, "lap messages must precede sessions, and sessions the activity")
+		}
+		if activity.NumSessions != basetype.Uint16Invalid && int(activity.NumSessions) != len(sessions) {
+			report.add(IssueActivitySessionCount, SeverityError, false,
+				"activity num_sessions is %d but file has %d sessions", activity.NumSessions, len(sessions))
+		}
+	}
+
+	if len(sessions) > 0 {
+		session := sessions[0]
+		sessionElapsed := session.TotalElapsedTimeScaled()
+		for _, lap := range laps {
+			if lap.StartTime.Before(session.StartTime) {
+				report.add(IssueLapSessionMismatch, SeverityWarning, false, "lap starts before its session")
+			}
+			if lap.TotalElapsedTime != basetype.Uint32Invalid && session.TotalElapsedTime != basetype.Uint32Invalid &&
+				lap.TotalElapsedTimeScaled() > sessionElapsed+sessionBoundaryTolerance.Seconds() {
+				report.add(IssueLapSessionMismatch, SeverityWarning, false,
+					"lap elapsed time %.0fs exceeds session elapsed time %.0fs", lap.TotalElapsedTimeScaled(), sessionElapsed)
+			}
+		}
+
+		if session.TotalElapsedTime != basetype.Uint32Invalid && !v.firstRecordTime.IsZero() {
+			// Counted once per session boundary the records cross
+			tolerance := sessionBoundaryTolerance
+			sessionEnd := session.StartTime.Add(time.Duration(sessionElapsed * float64(time.Second)))
+			for _, ts := range []time.Time{v.firstRecordTime, v.endRecordTime} {
+				if ts.Before(session.StartTime.Add(-tolerance)) || ts.After(sessionEnd.Add(tolerance)) {
+					report.add(IssueRecordOutsideSession, SeverityWarning, true,
+						"record at %s is outside the session (%s - %s)",
+						ts.Format(time.RFC3339), session.StartTime.Format(time.RFC3339), sessionEnd.Format(time.RFC3339))
+				}
+			}
+		}
+	}
+
+	if source != nil {
+		validateSourceRanges(source, report)
+	}
+
+	return report
+}
+
+// validateSourceRanges checks source values that cannot be represented in FIT fields
+func validateSourceRanges(activity *pb.StandardizedActivity, report *ValidationReport) {
+	for _, session := range activity.Sessions {
+		for _, lap := range session.Laps {
+			for _, rec := range lap.Records {
+				if rec.HeartRate < 0 || rec.HeartRate > 254 {
+					report.add(IssueHeartRateOutOfRange, SeverityError, true, "heart rate %d is outside 0-254 bpm", rec.HeartRate)
+				}
+				if rec.Cadence < 0 || rec.Cadence > 254 {
+					report.add(IssueCadenceOutOfRange, SeverityError, true, "cadence %d is outside 0-254 rpm", rec.Cadence)
+				}
+				if rec.Altitude != 0 && (rec.Altitude < minFitAltitude || rec.Altitude > maxFitAltitude) {
+					report.add(IssueAltitudeOutOfRange, SeverityError, true,
+						"altitude %.1fm is outside %.0fm to %.0fm", rec.Altitude, minFitAltitude, maxFitAltitude)
+				}
+			}
+		}
+	}
+}
+
+// RepairActivity fixes the repairable issues in the report on the source activity,
+// so that regenerating the FIT file resolves them. It returns the codes repaired.
+func RepairActivity(activity *pb.StandardizedActivity, report *ValidationReport) []string {
+	var repaired []string
+	for _, issue := range report.Issues {
+		if !issue.Repairable {
+			continue
+		}
+		switch issue.Code {
+		case IssueTimestampNotMonotonic:
+			for _, session := range activity.Sessions {
+				for _, lap := range session.Laps {
+					sort.SliceStable(lap.Records, func(i, j int) bool {
+						return lap.Records[i].Timestamp.AsTime().Before(lap.Records[j].Timestamp.AsTime())
+					})
+				}
+			}
+		case IssueRecordOutsideSession:
+			// Drop records before the session start; extend the session to cover later records
+			for _, session := range activity.Sessions {
+				start := session.StartTime.AsTime()
+				for _, lap := range session.Laps {
+					kept := lap.Records[:0]
+					for _, rec := range lap.Records {
+						ts := rec.Timestamp.AsTime()
+						if ts.Before(start) {
+							continue
+						}
+						if covered := ts.Sub(start).Seconds(); covered > session.TotalElapsedTime {
+							session.TotalElapsedTime = covered
+						}
+						kept = append(kept, rec)
+					}
+					lap.Records = kept
+				}
+			}
+		case IssueHeartRateOutOfRange, IssueCadenceOutOfRange, IssueAltitudeOutOfRange:
+			for _, session := range activity.Sessions {
+				for _, lap := range session.Laps {
+					for _, rec := range lap.Records {
+						repairRecordRanges(rec)
+					}
+				}
+			}
+		default:
+			continue
+		}
+		repaired = append(repaired, issue.Code)
+	}
+	return repaired
+}
+
+// repairRecordRanges drops unrepresentable HR/cadence values and clamps altitude
+func repairRecordRanges(rec *pb.Record) {
+	if rec.HeartRate < 0 || rec.HeartRate > 254 {
+		rec.HeartRate = 0
+	}
+	if rec.Cadence < 0 || rec.Cadence > 254 {
+		rec.Cadence = 0
+	}
+	if rec.Altitude < minFitAltitude {
+		rec.Altitude = minFitAltitude
+	} else if rec.Altitude > maxFitAltitude {
+		rec.Altitude = maxFitAltitude
+	}
+}
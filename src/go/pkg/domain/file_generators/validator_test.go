@@ -0,0 +1,136 @@
+package file_generators
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/muktihari/fit/encoder"
+	"github.com/muktihari/fit/profile/mesgdef"
+	"github.com/muktihari/fit/profile/typedef"
+	"github.com/muktihari/fit/proto"
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func validatorTestActivity(start time.Time, records []*pb.Record) *pb.StandardizedActivity {
+	return &pb.StandardizedActivity{
+		StartTime: timestamppb.New(start),
+		Type:      pb.ActivityType_ACTIVITY_TYPE_RUN,
+		Sessions: []*pb.Session{
+			{
+				StartTime:        timestamppb.New(start),
+				TotalElapsedTime: 10,
+				Laps:             []*pb.Lap{{StartTime: timestamppb.New(start), Records: records}},
+			},
+		},
+	}
+}
+
+func hasIssue(report *ValidationReport, code string) bool {
+	for _, issue := range report.Issues {
+		if issue.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+func TestValidateFitFile_Valid(t *testing.T) {
+	start := time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)
+	var records []*pb.Record
+	for i := 0; i < 10; i++ {
+		records = append(records, &pb.Record{Timestamp: timestamppb.New(start.Add(time.Duration(i) * time.Second)), HeartRate: 140, Altitude: 12})
+	}
+	activity := validatorTestActivity(start, records)
+
+	data, err := GenerateFitFile(activity)
+	if err != nil {
+		t.Fatalf("GenerateFitFile failed: %v", err)
+	}
+	report, err := ValidateFitFile(data, activity)
+	if err != nil {
+		t.Fatalf("ValidateFitFile failed: %v", err)
+	}
+	if !report.Valid() {
+		t.Errorf("Expected valid file, got issues: %s", report.Summary())
+	}
+}
+
+func TestValidateFitFile_RepairableIssues(t *testing.T) {
+	start := time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)
+	at := func(s int) *timestamppb.Timestamp { return timestamppb.New(start.Add(time.Duration(s) * time.Second)) }
+	activity := validatorTestActivity(start, []*pb.Record{
+		{Timestamp: at(0), HeartRate: 120},
+		{Timestamp: at(2), HeartRate: 300},
+		{Timestamp: at(1), Altitude: -650},
+		{Timestamp: at(30), HeartRate: 130}, // Beyond the 10s session
+	})
+
+	data, err := GenerateFitFile(activity)
+	if err != nil {
+		t.Fatalf("GenerateFitFile failed: %v", err)
+	}
+	report, err := ValidateFitFile(data, activity)
+	if err != nil {
+		t.Fatalf("ValidateFitFile failed: %v", err)
+	}
+
+	for _, code := range []string{IssueTimestampNotMonotonic, IssueHeartRateOutOfRange, IssueAltitudeOutOfRange, IssueRecordOutsideSession} {
+		if !hasIssue(report, code) {
+			t.Errorf("Expected issue %s, got: %s", code, report.Summary())
+		}
+	}
+	if !report.Repairable() {
+		t.Fatal("Expected issues to be repairable")
+	}
+
+	repaired := RepairActivity(activity, report)
+	if len(repaired) != len(report.Issues) {
+		t.Errorf("Expected all %d issues repaired, got %v", len(report.Issues), repaired)
+	}
+
+	data, err = GenerateFitFile(activity)
+	if err != nil {
+		t.Fatalf("GenerateFitFile after repair failed: %v", err)
+	}
+	report, err = ValidateFitFile(data, activity)
+	if err != nil {
+		t.Fatalf("ValidateFitFile after repair failed: %v", err)
+	}
+	if !report.Valid() {
+		t.Errorf("Expected valid file after repair, got: %s", report.Summary())
+	}
+}
+
+func TestValidateFitFile_StructuralIssues(t *testing.T) {
+	start := time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)
+	fit := &proto.FIT{
+		Messages: []proto.Message{
+			mesgdef.NewFileId(nil).SetType(typedef.FileActivity).SetManufacturer(typedef.ManufacturerDevelopment).SetTimeCreated(start).ToMesg(nil),
+			mesgdef.NewSession(nil).SetTimestamp(start).SetStartTime(start).SetTotalElapsedTime(10000).ToMesg(nil),
+			mesgdef.NewLap(nil).SetTimestamp(start).SetStartTime(start).SetTotalElapsedTime(20000).ToMesg(nil),
+			mesgdef.NewActivity(nil).SetTimestamp(start).SetNumSessions(2).ToMesg(nil),
+		},
+	}
+	var buf bytes.Buffer
+	if err := encoder.New(&buf).Encode(fit); err != nil {
+		t.Fatalf("Failed to encode test FIT: %v", err)
+	}
+
+	report, err := ValidateFitFile(buf.Bytes(), nil)
+	if err != nil {
+		t.Fatalf("ValidateFitFile failed: %v", err)
+	}
+	for _, code := range []string{IssueSummaryOrder, IssueActivitySessionCount, IssueLapSessionMismatch} {
+		if !hasIssue(report, code) {
+			t.Errorf("Expected issue %s, got: %s", code, report.Summary())
+		}
+	}
+	if !report.HasErrors() {
+		t.Error("Expected error-severity issues")
+	}
+	if report.Repairable() {
+		t.Error("Structural issues should not be repairable")
+	}
+}
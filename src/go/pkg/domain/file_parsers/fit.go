@@ -0,0 +1,376 @@
+// Package file_parsers converts activity files into StandardizedActivity
+package file_parsers
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/muktihari/fit/decoder"
+	"github.com/muktihari/fit/kit/semicircles"
+	"github.com/muktihari/fit/profile/basetype"
+	"github.com/muktihari/fit/profile/mesgdef"
+	"github.com/muktihari/fit/profile/typedef"
+	"github.com/muktihari/fit/proto"
+
+	"github.com/ripixel/fitglue-server/src/go/pkg/domain/file_generators"
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ParseFitFile decodes a FIT activity file into a StandardizedActivity
+func ParseFitFile(r io.Reader) (*pb.StandardizedActivity, error) {
+	fit, err := decoder.New(r).Decode()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode FIT file: %w", err)
+	}
+	return ParseFit(fit)
+}
+
+// ParseFit converts decoded FIT messages into a StandardizedActivity.
+// Sessions, laps, records, timer pauses, pool lengths, strength sets and the
+// recording device are mapped. Records and laps are assigned to the session
+// (and lap) whose time range contains them.
+func ParseFit(fit *proto.FIT) (*pb.StandardizedActivity, error) {
+	var (
+		fileID   *mesgdef.FileId
+		devices  []*mesgdef.DeviceInfo
+		sessions []*mesgdef.Session
+		laps     []*mesgdef.Lap
+		records  []*mesgdef.Record
+		lengths  []*mesgdef.Length
+		sets     []*mesgdef.Set
+		events   []*mesgdef.Event
+		titles   = map[typedef.MessageIndex]*mesgdef.ExerciseTitle{}
+	)
+	for i := range fit.Messages {
+		msg := &fit.Messages[i]
+		switch msg.Num {
+		case typedef.MesgNumFileId:
+			if fileID == nil {
+				fileID = mesgdef.NewFileId(msg)
+			}
+		case typedef.MesgNumDeviceInfo:
+			devices = append(devices, mesgdef.NewDeviceInfo(msg))
+		case typedef.MesgNumSession:
+			sessions = append(sessions, mesgdef.NewSession(msg))
+		case typedef.MesgNumLap:
+			laps = append(laps, mesgdef.NewLap(msg))
+		case typedef.MesgNumRecord:
+			records = append(records, mesgdef.NewRecord(msg))
+		case typedef.MesgNumLength:
+			lengths = append(lengths, mesgdef.NewLength(msg))
+		case typedef.MesgNumSet:
+			sets = append(sets, mesgdef.NewSet(msg))
+		case typedef.MesgNumEvent:
+			events = append(events, mesgdef.NewEvent(msg))
+		case typedef.MesgNumExerciseTitle:
+			title := mesgdef.NewExerciseTitle(msg)
+			titles[title.MessageIndex] = title
+		}
+	}
+
+	if fileID != nil && fileID.Type != typedef.FileActivity && fileID.Type != typedef.FileInvalid {
+		return nil, fmt.Errorf("not an activity file: %s", fileID.Type)
+	}
+	if len(sessions) == 0 {
+		return nil, fmt.Errorf("FIT file has no sessions")
+	}
+	sort.SliceStable(sessions, func(i, j int) bool { return sessions[i].StartTime.Before(sessions[j].StartTime) })
+
+	activity := &pb.StandardizedActivity{
+		StartTime: toTimestamp(sessions[0].StartTime),
+		Type:      mapFitSport(sessions[0].Sport, sessions[0].SubSport),
+		Device:    parseDevice(fileID, devices),
+	}
+	if activity.StartTime == nil && fileID != nil {
+		activity.StartTime = toTimestamp(fileID.TimeCreated)
+	}
+
+	pbSessions := make([]*pb.Session, len(sessions))
+	for i, s := range sessions {
+		pbSessions[i] = &pb.Session{
+			StartTime:        toTimestamp(s.StartTime),
+			TotalElapsedTime: scaledOrZero(s.TotalElapsedTimeScaled()),
+			TotalDistance:    scaledOrZero(s.TotalDistanceScaled()),
+			PoolLength:       scaledOrZero(s.PoolLengthScaled()),
+		}
+	}
+	sessionFor := func(ts time.Time) int {
+		return indexAt(len(sessions), func(i int) time.Time { return sessions[i].StartTime }, ts)
+	}
+
+	// Laps, then records into laps
+	sessionLaps := make([][]*mesgdef.Lap, len(sessions))
+	for _, lap := range laps {
+		i := sessionFor(lap.StartTime)
+		sessionLaps[i] = append(sessionLaps[i], lap)
+	}
+	for i, s := range pbSessions {
+		for _, lap := range sessionLaps[i] {
+			s.Laps = append(s.Laps, &pb.Lap{
+				StartTime:        toTimestamp(lap.StartTime),
+				TotalElapsedTime: scaledOrZero(lap.TotalElapsedTimeScaled()),
+				TotalDistance:    scaledOrZero(lap.TotalDistanceScaled()),
+			})
+		}
+	}
+	for _, rec := range records {
+		if rec.Timestamp.IsZero() {
+			continue
+		}
+		s := pbSessions[sessionFor(rec.Timestamp)]
+		if len(s.Laps) == 0 {
+			s.Laps = append(s.Laps, &pb.Lap{StartTime: s.StartTime, TotalElapsedTime: s.TotalElapsedTime, TotalDistance: s.TotalDistance})
+		}
+		lap := s.Laps[indexAt(len(s.Laps), func(i int) time.Time { return s.Laps[i].StartTime.AsTime() }, rec.Timestamp)]
+		lap.Records = append(lap.Records, parseRecord(rec))
+	}
+
+	for _, p := range parsePauses(events) {
+		s := pbSessions[sessionFor(p.StartTime.AsTime())]
+		s.Pauses = append(s.Pauses, p)
+	}
+
+	for _, length := range lengths {
+		s := pbSessions[sessionFor(length.StartTime)]
+		s.Lengths = append(s.Lengths, &pb.SwimLength{
+			StartTime:        toTimestamp(length.StartTime),
+			TotalElapsedTime: scaledOrZero(length.TotalElapsedTimeScaled()),
+			Stroke:           mapFitSwimStroke(length.SwimStroke),
+			TotalStrokes:     int32(validUint16(length.TotalStrokes)),
+			Idle:             length.LengthType == typedef.LengthTypeIdle,
+		})
+	}
+
+	for _, set := range sets {
+		if set.SetType != typedef.SetTypeActive {
+			continue // Rest periods are implied by the gaps between sets
+		}
+		s := pbSessions[sessionFor(set.StartTime)]
+		s.StrengthSets = append(s.StrengthSets, parseStrengthSet(set, titles))
+	}
+
+	activity.Sessions = pbSessions
+	return activity, nil
+}
+
+// indexAt returns the index of the last item starting at or before ts (0 if none)
+func indexAt(n int, start func(i int) time.Time, ts time.Time) int {
+	idx := 0
+	for i := 0; i < n; i++ {
+		if !start(i).After(ts) {
+			idx = i
+		}
+	}
+	return idx
+}
+
+func parseRecord(rec *mesgdef.Record) *pb.Record {
+	r := &pb.Record{Timestamp: toTimestamp(rec.Timestamp)}
+	if rec.HeartRate != basetype.Uint8Invalid {
+		r.HeartRate = int32(rec.HeartRate)
+	}
+	if rec.Power != basetype.Uint16Invalid {
+		r.Power = int32(rec.Power)
+	}
+	if rec.Cadence != basetype.Uint8Invalid {
+		r.Cadence = int32(rec.Cadence)
+	}
+	if speed := rec.EnhancedSpeedScaled(); !math.IsNaN(speed) {
+		r.Speed = speed
+	} else {
+		r.Speed = scaledOrZero(rec.SpeedScaled())
+	}
+	if altitude := rec.EnhancedAltitudeScaled(); !math.IsNaN(altitude) {
+		r.Altitude = altitude
+	} else {
+		r.Altitude = scaledOrZero(rec.AltitudeScaled())
+	}
+	if rec.PositionLat != basetype.Sint32Invalid && rec.PositionLong != basetype.Sint32Invalid {
+		r.PositionLat = semicircles.ToDegrees(rec.PositionLat)
+		r.PositionLong = semicircles.ToDegrees(rec.PositionLong)
+	}
+	return r
+}
+
+// parsePauses pairs timer stop events with the following timer start
+func parsePauses(events []*mesgdef.Event) []*pb.Pause {
+	var pauses []*pb.Pause
+	var stoppedAt time.Time
+	for _, ev := range events {
+		if ev.Event != typedef.EventTimer {
+			continue
+		}
+		switch ev.EventType {
+		case typedef.EventTypeStop, typedef.EventTypeStopAll:
+			if stoppedAt.IsZero() {
+				stoppedAt = ev.Timestamp
+			}
+		case typedef.EventTypeStart:
+			if !stoppedAt.IsZero() && ev.Timestamp.After(stoppedAt) {
+				pauses = append(pauses, &pb.Pause{StartTime: toTimestamp(stoppedAt), EndTime: toTimestamp(ev.Timestamp)})
+			}
+			stoppedAt = time.Time{}
+		}
+	}
+	return pauses
+}
+
+func parseStrengthSet(set *mesgdef.Set, titles map[typedef.MessageIndex]*mesgdef.ExerciseTitle) *pb.StrengthSet {
+	s := &pb.StrengthSet{
+		StartTime:       toTimestamp(set.StartTime),
+		DurationSeconds: int32(math.Round(scaledOrZero(set.DurationScaled()))),
+		Reps:            int32(validUint16(set.Repetitions)),
+		WeightKg:        scaledOrZero(set.WeightScaled()),
+		SetType:         file_generators.SetTypeNormal,
+	}
+
+	if title, ok := titles[set.WktStepIndex]; ok && len(title.WktStepName) > 0 {
+		s.ExerciseName = title.WktStepName[0]
+	} else if len(set.Category) > 0 && set.Category[0] != typedef.ExerciseCategoryInvalid {
+		s.ExerciseName = set.Category[0].String()
+	}
+	if name, ok := strings.CutSuffix(s.ExerciseName, file_generators.WarmupTitleSuffix); ok {
+		s.ExerciseName = name
+		s.SetType = file_generators.SetTypeWarmup
+	} else if name, ok := strings.CutSuffix(s.ExerciseName, file_generators.FailureTitleSuffix); ok {
+		s.ExerciseName = name
+		s.SetType = file_generators.SetTypeFailure
+	}
+	return s
+}
+
+// parseDevice returns the recording device: the creator DeviceInfo, falling back to the FileId
+func parseDevice(fileID *mesgdef.FileId, devices []*mesgdef.DeviceInfo) *pb.DeviceInfo {
+	for _, d := range devices {
+		if d.DeviceIndex != typedef.DeviceIndexCreator || d.Manufacturer == typedef.ManufacturerInvalid {
+			continue
+		}
+		device := &pb.DeviceInfo{
+			Manufacturer: d.Manufacturer.String(),
+			Product:      uint32(validUint16(d.Product)),
+			ProductName:  d.ProductName,
+		}
+		if d.SerialNumber != basetype.Uint32zInvalid {
+			device.SerialNumber = d.SerialNumber
+		}
+		if version := d.SoftwareVersionScaled(); !math.IsNaN(version) {
+			device.SoftwareVersion = fmt.Sprintf("%.2f", version)
+		}
+		return device
+	}
+	if fileID != nil && fileID.Manufacturer != typedef.ManufacturerInvalid {
+		device := &pb.DeviceInfo{
+			Manufacturer: fileID.Manufacturer.String(),
+			Product:      uint32(validUint16(fileID.Product)),
+			ProductName:  fileID.ProductName,
+		}
+		if fileID.SerialNumber != basetype.Uint32zInvalid {
+			device.SerialNumber = fileID.SerialNumber
+		}
+		return device
+	}
+	return nil
+}
+
+func mapFitSport(sport typedef.Sport, subSport typedef.SubSport) pb.ActivityType {
+	switch sport {
+	case typedef.SportRunning:
+		switch subSport {
+		case typedef.SubSportTrail:
+			return pb.ActivityType_ACTIVITY_TYPE_TRAIL_RUN
+		case typedef.SubSportTreadmill, typedef.SubSportVirtualActivity:
+			return pb.ActivityType_ACTIVITY_TYPE_VIRTUAL_RUN
+		}
+		return pb.ActivityType_ACTIVITY_TYPE_RUN
+	case typedef.SportCycling:
+		switch subSport {
+		case typedef.SubSportMountain:
+			return pb.ActivityType_ACTIVITY_TYPE_MOUNTAIN_BIKE_RIDE
+		case typedef.SubSportGravelCycling:
+			return pb.ActivityType_ACTIVITY_TYPE_GRAVEL_RIDE
+		case typedef.SubSportIndoorCycling, typedef.SubSportVirtualActivity:
+			return pb.ActivityType_ACTIVITY_TYPE_VIRTUAL_RIDE
+		}
+		return pb.ActivityType_ACTIVITY_TYPE_RIDE
+	case typedef.SportEBiking:
+		return pb.ActivityType_ACTIVITY_TYPE_EBIKE_RIDE
+	case typedef.SportSwimming:
+		return pb.ActivityType_ACTIVITY_TYPE_SWIM
+	case typedef.SportWalking:
+		return pb.ActivityType_ACTIVITY_TYPE_WALK
+	case typedef.SportHiking:
+		return pb.ActivityType_ACTIVITY_TYPE_HIKE
+	case typedef.SportRowing:
+		if subSport == typedef.SubSportIndoorRowing {
+			return pb.ActivityType_ACTIVITY_TYPE_VIRTUAL_ROW
+		}
+		return pb.ActivityType_ACTIVITY_TYPE_ROWING
+	case typedef.SportTraining, typedef.SportFitnessEquipment:
+		switch subSport {
+		case typedef.SubSportStrengthTraining:
+			return pb.ActivityType_ACTIVITY_TYPE_WEIGHT_TRAINING
+		case typedef.SubSportYoga:
+			return pb.ActivityType_ACTIVITY_TYPE_YOGA
+		case typedef.SubSportHiit:
+			return pb.ActivityType_ACTIVITY_TYPE_HIGH_INTENSITY_INTERVAL_TRAINING
+		}
+		return pb.ActivityType_ACTIVITY_TYPE_WORKOUT
+	case typedef.SportAlpineSkiing:
+		return pb.ActivityType_ACTIVITY_TYPE_ALPINE_SKI
+	case typedef.SportCrossCountrySkiing:
+		return pb.ActivityType_ACTIVITY_TYPE_NORDIC_SKI
+	case typedef.SportSnowboarding:
+		return pb.ActivityType_ACTIVITY_TYPE_SNOWBOARD
+	case typedef.SportRockClimbing:
+		return pb.ActivityType_ACTIVITY_TYPE_ROCK_CLIMBING
+	default:
+		return pb.ActivityType_ACTIVITY_TYPE_WORKOUT
+	}
+}
+
+func mapFitSwimStroke(stroke typedef.SwimStroke) pb.SwimStroke {
+	switch stroke {
+	case typedef.SwimStrokeFreestyle:
+		return pb.SwimStroke_SWIM_STROKE_FREESTYLE
+	case typedef.SwimStrokeBackstroke:
+		return pb.SwimStroke_SWIM_STROKE_BACKSTROKE
+	case typedef.SwimStrokeBreaststroke:
+		return pb.SwimStroke_SWIM_STROKE_BREASTSTROKE
+	case typedef.SwimStrokeButterfly:
+		return pb.SwimStroke_SWIM_STROKE_BUTTERFLY
+	case typedef.SwimStrokeDrill:
+		return pb.SwimStroke_SWIM_STROKE_DRILL
+	case typedef.SwimStrokeMixed, typedef.SwimStrokeIm:
+		return pb.SwimStroke_SWIM_STROKE_MIXED
+	default:
+		return pb.SwimStroke_SWIM_STROKE_UNSPECIFIED
+	}
+}
+
+// toTimestamp converts a FIT time, mapping the zero (invalid) time to nil
+func toTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// scaledOrZero maps the NaN returned by mesgdef for invalid values to 0
+func scaledOrZero(v float64) float64 {
+	if math.IsNaN(v) {
+		return 0
+	}
+	return v
+}
+
+func validUint16(v uint16) uint16 {
+	if v == basetype.Uint16Invalid {
+		return 0
+	}
+	return v
+}
@@ -0,0 +1,174 @@
+package file_parsers
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ripixel/fitglue-server/src/go/pkg/domain/file_generators"
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func roundTrip(t *testing.T, activity *pb.StandardizedActivity) *pb.StandardizedActivity {
+	t.Helper()
+	data, err := file_generators.GenerateFitFile(activity)
+	if err != nil {
+		t.Fatalf("GenerateFitFile failed: %v", err)
+	}
+	parsed, err := ParseFitFile(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParseFitFile failed: %v", err)
+	}
+	return parsed
+}
+
+func TestParseFitFile(t *testing.T) {
+	start := time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)
+	at := func(s int) *timestamppb.Timestamp { return timestamppb.New(start.Add(time.Duration(s) * time.Second)) }
+
+	t.Run("Run with records, pauses and device", func(t *testing.T) {
+		var records []*pb.Record
+		for i := 0; i < 60; i++ {
+			records = append(records, &pb.Record{
+				Timestamp:    at(i),
+				HeartRate:    int32(120 + i),
+				Power:        250,
+				Cadence:      85,
+				Speed:        3.5,
+				Altitude:     42.4,
+				PositionLat:  51.5 + float64(i)*0.0001,
+				PositionLong: -0.12,
+			})
+		}
+		activity := &pb.StandardizedActivity{
+			StartTime: at(0),
+			Type:      pb.ActivityType_ACTIVITY_TYPE_RUN,
+			Device:    &pb.DeviceInfo{Manufacturer: "garmin", Product: 3113, ProductName: "Forerunner", SerialNumber: 12345, SoftwareVersion: "12.34"},
+			Sessions: []*pb.Session{{
+				StartTime:        at(0),
+				TotalElapsedTime: 60,
+				TotalDistance:    210,
+				Pauses:           []*pb.Pause{{StartTime: at(20), EndTime: at(30)}},
+				Laps:             []*pb.Lap{{StartTime: at(0), TotalElapsedTime: 60, TotalDistance: 210, Records: records}},
+			}},
+		}
+
+		parsed := roundTrip(t, activity)
+
+		if parsed.Type != pb.ActivityType_ACTIVITY_TYPE_RUN {
+			t.Errorf("Expected RUN, got %v", parsed.Type)
+		}
+		if !parsed.StartTime.AsTime().Equal(start) {
+			t.Errorf("Expected start %v, got %v", start, parsed.StartTime.AsTime())
+		}
+		d := parsed.Device
+		if d == nil || d.Manufacturer != "garmin" || d.Product != 3113 || d.ProductName != "Forerunner" || d.SerialNumber != 12345 || d.SoftwareVersion != "12.34" {
+			t.Errorf("Unexpected device: %+v", d)
+		}
+
+		if len(parsed.Sessions) != 1 {
+			t.Fatalf("Expected 1 session, got %d", len(parsed.Sessions))
+		}
+		session := parsed.Sessions[0]
+		if session.TotalElapsedTime != 60 || session.TotalDistance != 210 {
+			t.Errorf("Unexpected session summary: elapsed %v, distance %v", session.TotalElapsedTime, session.TotalDistance)
+		}
+		if len(session.Pauses) != 1 || !session.Pauses[0].StartTime.AsTime().Equal(at(20).AsTime()) || !session.Pauses[0].EndTime.AsTime().Equal(at(30).AsTime()) {
+			t.Errorf("Unexpected pauses: %v", session.Pauses)
+		}
+		if len(session.Laps) != 1 || len(session.Laps[0].Records) != 60 {
+			t.Fatalf("Expected 1 lap with 60 records, got %v", session.Laps)
+		}
+
+		got, want := session.Laps[0].Records[10], records[10]
+		if got.HeartRate != want.HeartRate || got.Power != want.Power || got.Cadence != want.Cadence {
+			t.Errorf("Record mismatch: got %+v, want %+v", got, want)
+		}
+		if math.Abs(got.Speed-want.Speed) > 0.001 || math.Abs(got.Altitude-want.Altitude) > 0.2 {
+			t.Errorf("Speed/altitude mismatch: got %v/%v, want %v/%v", got.Speed, got.Altitude, want.Speed, want.Altitude)
+		}
+		if math.Abs(got.PositionLat-want.PositionLat) > 1e-6 || math.Abs(got.PositionLong-want.PositionLong) > 1e-6 {
+			t.Errorf("Position mismatch: got %v,%v, want %v,%v", got.PositionLat, got.PositionLong, want.PositionLat, want.PositionLong)
+		}
+	})
+
+	t.Run("Strength sets", func(t *testing.T) {
+		activity := &pb.StandardizedActivity{
+			StartTime: at(0),
+			Type:      pb.ActivityType_ACTIVITY_TYPE_WEIGHT_TRAINING,
+			Sessions: []*pb.Session{{
+				StartTime:        at(0),
+				TotalElapsedTime: 600,
+				StrengthSets: []*pb.StrengthSet{
+					{ExerciseName: "Bench Press (Barbell)", Reps: 12, WeightKg: 40, SetType: "warmup"},
+					{ExerciseName: "Bench Press (Barbell)", Reps: 8, WeightKg: 80},
+					{ExerciseName: "Squat (Barbell)", Reps: 5, WeightKg: 100, SetType: "failure"},
+				},
+			}},
+		}
+
+		parsed := roundTrip(t, activity)
+
+		if parsed.Type != pb.ActivityType_ACTIVITY_TYPE_WEIGHT_TRAINING {
+			t.Errorf("Expected WEIGHT_TRAINING, got %v", parsed.Type)
+		}
+		sets := parsed.Sessions[0].StrengthSets
+		if len(sets) != 3 {
+			t.Fatalf("Expected 3 sets (rest sets dropped), got %d", len(sets))
+		}
+		for i, want := range activity.Sessions[0].StrengthSets {
+			got := sets[i]
+			if got.ExerciseName != want.ExerciseName || got.Reps != want.Reps || got.WeightKg != want.WeightKg {
+				t.Errorf("Set %d: got %q %dx%.1f, want %q %dx%.1f", i, got.ExerciseName, got.Reps, got.WeightKg, want.ExerciseName, want.Reps, want.WeightKg)
+			}
+			if got.StartTime == nil || got.DurationSeconds == 0 {
+				t.Errorf("Set %d: expected start time and duration, got %+v", i, got)
+			}
+		}
+		if sets[0].SetType != file_generators.SetTypeWarmup || sets[1].SetType != file_generators.SetTypeNormal ||
+			sets[2].SetType != file_generators.SetTypeFailure {
+			t.Errorf("Unexpected set types: %q, %q, %q", sets[0].SetType, sets[1].SetType, sets[2].SetType)
+		}
+	})
+
+	t.Run("Pool swim lengths", func(t *testing.T) {
+		activity := &pb.StandardizedActivity{
+			StartTime: at(0),
+			Type:      pb.ActivityType_ACTIVITY_TYPE_SWIM,
+			Sessions: []*pb.Session{{
+				StartTime:        at(0),
+				TotalElapsedTime: 120,
+				PoolLength:       25,
+				Lengths: []*pb.SwimLength{
+					{StartTime: at(0), TotalElapsedTime: 30, Stroke: pb.SwimStroke_SWIM_STROKE_FREESTYLE, TotalStrokes: 18},
+					{StartTime: at(30), TotalElapsedTime: 30, Idle: true},
+					{StartTime: at(60), TotalElapsedTime: 40, Stroke: pb.SwimStroke_SWIM_STROKE_BREASTSTROKE, TotalStrokes: 14},
+				},
+			}},
+		}
+
+		parsed := roundTrip(t, activity)
+
+		session := parsed.Sessions[0]
+		if session.PoolLength != 25 {
+			t.Errorf("Expected pool length 25, got %v", session.PoolLength)
+		}
+		if len(session.Lengths) != 3 {
+			t.Fatalf("Expected 3 lengths, got %d", len(session.Lengths))
+		}
+		for i, want := range activity.Sessions[0].Lengths {
+			got := session.Lengths[i]
+			if got.Stroke != want.Stroke || got.TotalStrokes != want.TotalStrokes || got.Idle != want.Idle || got.TotalElapsedTime != want.TotalElapsedTime {
+				t.Errorf("Length %d: got %+v, want %+v", i, got, want)
+			}
+		}
+	})
+}
+
+func TestParseFitFile_Invalid(t *testing.T) {
+	if _, err := ParseFitFile(bytes.NewReader([]byte("not a fit file"))); err == nil {
+		t.Error("Expected error for invalid data")
+	}
+}
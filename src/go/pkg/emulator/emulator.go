@@ -0,0 +1,259 @@
+// Package emulator hosts the Go Cloud Functions (enricher, router, strava-uploader,
+// webhook-uploader, intervals-icu-uploader, hevy-uploader, mock-uploader, upload-status-poller
+// and change-propagator) in a single process. Functions are wired together by an in-memory Pub/Sub
+// bus using the same topics as production (pkg/constants.go and the Destination
+// dest_topic options), backed by an in-memory Database, a filesystem BlobStore and a stub
+// Strava API. Used by cmd/fitglue-local and Go integration tests.
+package emulator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	changepropagator "github.com/ripixel/fitglue-server/src/go/functions/change-propagator"
+	"github.com/ripixel/fitglue-server/src/go/functions/enricher"
+	hevyuploader "github.com/ripixel/fitglue-server/src/go/functions/hevy-uploader"
+	intervalsicuuploader "github.com/ripixel/fitglue-server/src/go/functions/intervals-icu-uploader"
+	mockuploader "github.com/ripixel/fitglue-server/src/go/functions/mock-uploader"
+	"github.com/ripixel/fitglue-server/src/go/functions/router"
+	stravauploader "github.com/ripixel/fitglue-server/src/go/functions/strava-uploader"
+	uploadstatuspoller "github.com/ripixel/fitglue-server/src/go/functions/upload-status-poller"
+	webhookuploader "github.com/ripixel/fitglue-server/src/go/functions/webhook-uploader"
+	shared "github.com/ripixel/fitglue-server/src/go/pkg"
+	"github.com/ripixel/fitglue-server/src/go/pkg/bootstrap"
+	"github.com/ripixel/fitglue-server/src/go/pkg/infrastructure/database"
+	infrapubsub "github.com/ripixel/fitglue-server/src/go/pkg/infrastructure/pubsub"
+	infrastorage "github.com/ripixel/fitglue-server/src/go/pkg/infrastructure/storage"
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+)
+
+const DefaultBucket = "fitglue-artifacts"
+
+// maxStatusChecks bounds redeliveries of an upload status check, standing in for the
+// subscription's message retention
+const maxStatusChecks = 20
+
+// Options configures an Emulator
+type Options struct {
+	DataDir          string            // Root of the filesystem BlobStore (required)
+	Bucket           string            // Artifact bucket (default: fitglue-artifacts)
+	LagDelay         time.Duration     // Delay before redelivering messages from the enrichment lag topic
+	StatusCheckDelay time.Duration     // Delay before each delivery (and redelivery) of an upload status check
+	Secrets          map[string]string // Secret values; unknown secrets fall back to environment variables
+
+	// StravaSoftPollTimeout is how long Strava uploads wait for processing before handing over
+	// to the upload-status-poller (0 keeps the production default)
+	StravaSoftPollTimeout time.Duration
+}
+
+// defaultSecrets lets OAuth token refresh against the stubs work without configuration
+var defaultSecrets = map[string]string{
+	"strava-client-id":     "local-client-id",
+	"strava-client-secret": "local-client-secret",
+}
+
+// Emulator is a running single-process FitGlue backend
+type Emulator struct {
+	DB      *database.MemoryDatabase
+	Store   *infrastorage.FileSystemStore
+	Bus     *infrapubsub.MemoryPublisher
+	Strava  *StravaStub
+	Service *bootstrap.Service
+
+	prevTransport http.RoundTripper
+}
+
+// New starts the emulator. Outbound HTTP to Strava (via http.DefaultTransport) is redirected
+// to the stub until Close is called, so only one Emulator should run per process.
+func New(opts Options) (*Emulator, error) {
+	if opts.DataDir == "" {
+		return nil, fmt.Errorf("emulator: DataDir is required")
+	}
+	if opts.Bucket == "" {
+		opts.Bucket = DefaultBucket
+	}
+	secrets := make(map[string]string)
+	for k, v := range defaultSecrets {
+		secrets[k] = v
+	}
+	for k, v := range opts.Secrets {
+		secrets[k] = v
+	}
+
+	em := &Emulator{
+		DB:     database.NewMemoryDatabase(),
+		Store:  &infrastorage.FileSystemStore{Root: opts.DataDir},
+		Bus:    &infrapubsub.MemoryPublisher{},
+		Strava: NewStravaStub(),
+	}
+	em.Service = &bootstrap.Service{
+		DB:      em.DB,
+		Store:   em.Store,
+		Pub:     em.Bus,
+		Secrets: &staticSecrets{values: secrets},
+		Config: &bootstrap.Config{
+			ProjectID:         "fitglue-local",
+			EnablePublish:     true,
+			GCSArtifactBucket: opts.Bucket,
+		},
+	}
+
+	stravaURL, err := url.Parse(em.Strava.URL())
+	if err != nil {
+		em.Strava.Close()
+		return nil, err
+	}
+	em.prevTransport = http.DefaultTransport
+	http.DefaultTransport = &redirectTransport{
+		hosts: map[string]*url.URL{"www.strava.com": stravaURL},
+		base:  em.prevTransport,
+	}
+
+	enricher.SetService(em.Service)
+	router.SetService(em.Service)
+	stravauploader.SetService(em.Service)
+	webhookuploader.SetService(em.Service)
+	intervalsicuuploader.SetService(em.Service)
+	hevyuploader.SetService(em.Service)
+	mockuploader.SetService(em.Service)
+	uploadstatuspoller.SetService(em.Service)
+	changepropagator.SetService(em.Service)
+	stravauploader.SetSoftPollTimeout(opts.StravaSoftPollTimeout)
+
+	em.Bus.Subscribe(shared.TopicRawActivity, enricher.EnrichActivity)
+	em.Bus.Subscribe(shared.TopicEnrichmentLag, delayed(opts.LagDelay, enricher.EnrichActivity))
+	em.Bus.Subscribe(shared.TopicEnrichedActivity, router.RouteActivity)
+	em.Bus.Subscribe(shared.TopicUploadStatusCheck, redelivered(opts.StatusCheckDelay, maxStatusChecks, uploadstatuspoller.CheckUploadStatus))
+	em.Bus.Subscribe(shared.TopicSourceChange, changepropagator.PropagateSourceChange)
+
+	// Destination topics come from the dest_topic enum options, as used by the router
+	destinationHandlers := map[pb.Destination]infrapubsub.Handler{
+		pb.Destination_DESTINATION_STRAVA:        stravauploader.UploadToStrava,
+		pb.Destination_DESTINATION_WEBHOOK:       webhookuploader.UploadToWebhook,
+		pb.Destination_DESTINATION_INTERVALS_ICU: intervalsicuuploader.UploadToIntervalsIcu,
+		pb.Destination_DESTINATION_HEVY:          hevyuploader.UploadToHevy,
+		pb.Destination_DESTINATION_MOCK:          mockuploader.MockUpload,
+	}
+	for dest, h := range destinationHandlers {
+		topic := infrapubsub.GetDestinationTopic(dest)
+		if topic == "" {
+			slog.Warn("Destination has no topic, not subscribed", "destination", dest.String())
+			continue
+		}
+		em.Bus.Subscribe(topic, h)
+	}
+
+	return em, nil
+}
+
+// PublishActivity publishes a raw activity to the enricher, as a source handler would
+func (em *Emulator) PublishActivity(ctx context.Context, payload *pb.ActivityPayload) (string, error) {
+	e, err := infrapubsub.NewCloudEvent(
+		infrapubsub.GetCloudEventSource(sourceForActivity(payload.Source)),
+		infrapubsub.GetCloudEventType(pb.CloudEventType_CLOUD_EVENT_TYPE_ACTIVITY_CREATED),
+		payload,
+	)
+	if err != nil {
+		return "", err
+	}
+	e.SetID(fmt.Sprintf("local-%d", time.Now().UnixNano()))
+	e.SetTime(time.Now())
+	return em.Bus.PublishCloudEvent(ctx, shared.TopicRawActivity, e)
+}
+
+// Wait blocks until every function triggered so far (and everything they trigger) has finished
+func (em *Emulator) Wait() {
+	em.Bus.Wait()
+}
+
+// Close waits for in-flight work, stops the Strava stub and restores http.DefaultTransport
+func (em *Emulator) Close() {
+	em.Bus.Wait()
+	http.DefaultTransport = em.prevTransport
+	em.Strava.Close()
+}
+
+func sourceForActivity(s pb.ActivitySource) pb.CloudEventSource {
+	switch s {
+	case pb.ActivitySource_SOURCE_HEVY:
+		return pb.CloudEventSource_CLOUD_EVENT_SOURCE_HEVY
+	case pb.ActivitySource_SOURCE_FITBIT:
+		return pb.CloudEventSource_CLOUD_EVENT_SOURCE_FITBIT_INGEST
+	case pb.ActivitySource_SOURCE_STRAVA:
+		return pb.CloudEventSource_CLOUD_EVENT_SOURCE_STRAVA
+	default:
+		return pb.CloudEventSource_CLOUD_EVENT_SOURCE_MOCK
+	}
+}
+
+// delayed wraps a handler to simulate a subscription with a minimum delivery delay
+func delayed(d time.Duration, h infrapubsub.Handler) infrapubsub.Handler {
+	if d <= 0 {
+		return h
+	}
+	return func(ctx context.Context, e event.Event) error {
+		select {
+		case <-time.After(d):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		return h(ctx, e)
+	}
+}
+
+// redelivered wraps a handler to simulate a push subscription with a retry policy: each
+// delivery waits d, and failed deliveries are retried up to maxDeliveries in total
+func redelivered(d time.Duration, maxDeliveries int, h infrapubsub.Handler) infrapubsub.Handler {
+	h = delayed(d, h)
+	return func(ctx context.Context, e event.Event) error {
+		var err error
+		for i := 0; i < maxDeliveries; i++ {
+			if err = h(ctx, e.Clone()); err == nil {
+				return nil
+			}
+		}
+		return err
+	}
+}
+
+// redirectTransport sends requests for the given hosts to local servers instead
+type redirectTransport struct {
+	hosts map[string]*url.URL
+	base  http.RoundTripper
+}
+
+func (t *redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	target, ok := t.hosts[req.URL.Host]
+	if !ok {
+		return t.base.RoundTrip(req)
+	}
+	r := req.Clone(req.Context())
+	r.URL.Scheme = target.Scheme
+	r.URL.Host = target.Host
+	r.Host = target.Host
+	return t.base.RoundTrip(r)
+}
+
+// staticSecrets serves secrets from a map, falling back to environment variables
+type staticSecrets struct {
+	values map[string]string
+}
+
+func (s *staticSecrets) GetSecret(ctx context.Context, projectID, name string) (string, error) {
+	if v, ok := s.values[name]; ok {
+		return v, nil
+	}
+	if v := os.Getenv(name); v != "" {
+		return v, nil
+	}
+	return "", status.Errorf(codes.NotFound, "secret %s not configured", name)
+}
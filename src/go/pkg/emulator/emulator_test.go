@@ -0,0 +1,392 @@
+package emulator
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	shared "github.com/ripixel/fitglue-server/src/go/pkg"
+	"github.com/ripixel/fitglue-server/src/go/pkg/destinations"
+	"github.com/ripixel/fitglue-server/src/go/pkg/domain/file_parsers"
+	infrapubsub "github.com/ripixel/fitglue-server/src/go/pkg/infrastructure/pubsub"
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+)
+
+func TestEmulator_RawActivityToUpload(t *testing.T) {
+	em, err := New(Options{DataDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer em.Close()
+	ctx := context.Background()
+
+	if err := em.DB.SetUser(ctx, testUser(pb.Destination_DESTINATION_STRAVA, pb.Destination_DESTINATION_MOCK)); err != nil {
+		t.Fatal(err)
+	}
+
+	payload := testPayload()
+	if _, err := em.PublishActivity(ctx, payload); err != nil {
+		t.Fatalf("PublishActivity failed: %v", err)
+	}
+	em.Wait()
+
+	// Every hop went over the bus on the production topics
+	topics := map[string]int{}
+	for _, m := range em.Bus.Messages() {
+		topics[m.Topic]++
+	}
+	for _, topic := range []string{
+		shared.TopicRawActivity,
+		shared.TopicEnrichedActivity,
+		infrapubsub.GetDestinationTopic(pb.Destination_DESTINATION_STRAVA),
+		infrapubsub.GetDestinationTopic(pb.Destination_DESTINATION_MOCK),
+	} {
+		if topics[topic] != 1 {
+			t.Errorf("Expected 1 message on %s, got %d (all: %v)", topic, topics[topic], topics)
+		}
+	}
+
+	uploads := em.Strava.Uploads()
+	if len(uploads) != 1 {
+		t.Fatalf("Expected 1 Strava upload, got %d", len(uploads))
+	}
+	upload := uploads[0]
+	if upload.Name != "Morning Workout" || upload.SportType != "WeightTraining" || upload.DataType != "fit" {
+		t.Errorf("Unexpected upload fields: %+v", upload)
+	}
+	if upload.AccessToken != "stub-access-1" {
+		t.Errorf("Expected refreshed token, got %q", upload.AccessToken)
+	}
+	parsed, err := file_parsers.ParseFitFile(bytes.NewReader(upload.FitData))
+	if err != nil {
+		t.Fatalf("Uploaded FIT file did not parse: %v", err)
+	}
+	if len(parsed.Sessions) != 1 || len(parsed.Sessions[0].StrengthSets) != 1 {
+		t.Errorf("Unexpected uploaded activity: %v", parsed.Sessions)
+	}
+
+	updated, err := em.DB.GetUser(ctx, "user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Integrations.Strava.AccessToken != "stub-access-1" || updated.Integrations.Strava.RefreshToken != "stub-refresh-1" {
+		t.Errorf("Expected refreshed tokens persisted, got %+v", updated.Integrations.Strava)
+	}
+}
+
+func TestEmulator_SlowStravaUploadResolvedByPoller(t *testing.T) {
+	tests := []struct {
+		name            string
+		processingError string
+		wantPending     pb.PendingUpload_Status
+		wantExec        pb.ExecutionStatus
+	}{
+		{name: "Processed", wantPending: pb.PendingUpload_STATUS_COMPLETED, wantExec: pb.ExecutionStatus_STATUS_SUCCESS},
+		{name: "Rejected", processingError: "duplicate of activity 42", wantPending: pb.PendingUpload_STATUS_FAILED, wantExec: pb.ExecutionStatus_STATUS_FAILED},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			em, err := New(Options{
+				DataDir:               t.TempDir(),
+				StatusCheckDelay:      10 * time.Millisecond,
+				StravaSoftPollTimeout: -1, // Hand every upload straight to the poller
+			})
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+			defer em.Close()
+			ctx := context.Background()
+
+			em.Strava.SetProcessingPolls(2)
+			em.Strava.SetProcessingError(tt.processingError)
+			if err := em.DB.SetUser(ctx, testUser(pb.Destination_DESTINATION_STRAVA)); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := em.PublishActivity(ctx, testPayload()); err != nil {
+				t.Fatalf("PublishActivity failed: %v", err)
+			}
+			em.Wait()
+
+			uploads := em.Strava.Uploads()
+			if len(uploads) != 1 {
+				t.Fatalf("Expected 1 Strava upload, got %d", len(uploads))
+			}
+			var checks int
+			for _, m := range em.Bus.Messages() {
+				if m.Topic == shared.TopicUploadStatusCheck {
+					checks++
+				}
+			}
+			if checks != 1 {
+				t.Errorf("Expected 1 status check message, got %d", checks)
+			}
+
+			pendingID := destinations.PendingUploadID("strava", strconv.FormatInt(uploads[0].ID, 10))
+			pending, err := em.DB.GetPendingUpload(ctx, pendingID)
+			if err != nil {
+				t.Fatalf("Expected pending upload %s: %v", pendingID, err)
+			}
+			if pending.Status != tt.wantPending || pending.Attempts != 3 {
+				t.Errorf("Unexpected pending upload: status=%v attempts=%d", pending.Status, pending.Attempts)
+			}
+
+			exec, err := em.DB.GetExecution(ctx, pending.ExecutionId)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if exec.Status != tt.wantExec {
+				t.Errorf("Uploader execution status = %v, want %v", exec.Status, tt.wantExec)
+			}
+
+			synced, err := em.DB.GetSynchronizedActivity(ctx, "user-1", pending.OriginalEvent.GetActivityId())
+			if tt.wantPending == pb.PendingUpload_STATUS_FAILED {
+				if err == nil {
+					t.Errorf("Expected no synchronized activity, got %+v", synced)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected synchronized activity: %v", err)
+			}
+			if want := strconv.FormatInt(uploads[0].ActivityID, 10); synced.Destinations["strava"] != want || pending.ExternalId != want {
+				t.Errorf("Expected Strava activity %s, got synced=%v pending=%s", want, synced.Destinations, pending.ExternalId)
+			}
+		})
+	}
+}
+
+func TestEmulator_DuplicateStravaUpload(t *testing.T) {
+	const duplicateErr = "activity.fit duplicate of <a href='/activities/555' target='_blank'>Morning Workout</a>"
+
+	tests := []struct {
+		name        string
+		policy      pb.DuplicatePolicy
+		wantSynced  bool
+		wantUpdates int
+	}{
+		{name: "Fail", policy: pb.DuplicatePolicy_DUPLICATE_POLICY_FAIL},
+		{name: "Link", policy: pb.DuplicatePolicy_DUPLICATE_POLICY_LINK, wantSynced: true},
+		{name: "Update", policy: pb.DuplicatePolicy_DUPLICATE_POLICY_UPDATE, wantSynced: true, wantUpdates: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			em, err := New(Options{DataDir: t.TempDir()})
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+			defer em.Close()
+			ctx := context.Background()
+
+			em.Strava.SetProcessingError(duplicateErr)
+			user := testUser(pb.Destination_DESTINATION_STRAVA)
+			user.Pipelines[0].DestinationOptions = map[string]*pb.DestinationOptions{
+				"strava": {DuplicatePolicy: tt.policy, GearId: "b42"},
+			}
+			if err := em.DB.SetUser(ctx, user); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := em.PublishActivity(ctx, testPayload()); err != nil {
+				t.Fatalf("PublishActivity failed: %v", err)
+			}
+			em.Wait()
+
+			if uploads := em.Strava.Uploads(); len(uploads) != 1 {
+				t.Fatalf("Expected 1 Strava upload, got %d", len(uploads))
+			}
+
+			updates := em.Strava.ActivityUpdates()
+			if len(updates) != tt.wantUpdates {
+				t.Fatalf("Expected %d activity updates, got %d", tt.wantUpdates, len(updates))
+			}
+			if tt.wantUpdates > 0 {
+				u := updates[0]
+				if u.ActivityID != 555 || u.GearID != "b42" || u.SportType != "WeightTraining" || u.Name == "" {
+					t.Errorf("Unexpected activity update: %+v", u)
+				}
+			}
+
+			// The enriched event carries the pipeline's destination options to the uploader
+			var enriched pb.EnrichedActivityEvent
+			for _, m := range em.Bus.Messages() {
+				if m.Topic == shared.TopicEnrichedActivity {
+					if err := protojson.Unmarshal(m.Event.Data(), &enriched); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+			if got := enriched.DestinationOptions["strava"].GetDuplicatePolicy(); got != tt.policy {
+				t.Errorf("Enriched event duplicate policy = %v, want %v", got, tt.policy)
+			}
+
+			synced, err := em.DB.GetSynchronizedActivity(ctx, "user-1", enriched.ActivityId)
+			if !tt.wantSynced {
+				if err == nil {
+					t.Errorf("Expected no synchronized activity, got %+v", synced)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected synchronized activity: %v", err)
+			}
+			if synced.Destinations["strava"] != "555" {
+				t.Errorf("Expected existing Strava activity 555 to be linked, got %v", synced.Destinations)
+			}
+		})
+	}
+}
+
+func TestEmulator_StravaUpdateMode(t *testing.T) {
+	em, err := New(Options{DataDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer em.Close()
+	ctx := context.Background()
+
+	// The watch already synced the workout, a minute off and slightly longer
+	payload := testPayload()
+	start := payload.StandardizedActivity.StartTime.AsTime()
+	em.Strava.AddActivity(StravaActivity{ID: 900, Name: "Other Workout", StartDate: start.Add(-2 * time.Hour), ElapsedTime: 600})
+	em.Strava.AddActivity(StravaActivity{ID: 901, Name: "Afternoon Weight Training", StartDate: start.Add(time.Minute), ElapsedTime: 630})
+
+	user := testUser(pb.Destination_DESTINATION_STRAVA)
+	user.Pipelines[0].DestinationOptions = map[string]*pb.DestinationOptions{
+		"strava": {Mode: pb.DestinationMode_DESTINATION_MODE_UPDATE_EXISTING, GearId: "b42"},
+	}
+	if err := em.DB.SetUser(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := em.PublishActivity(ctx, payload); err != nil {
+		t.Fatalf("PublishActivity failed: %v", err)
+	}
+	em.Wait()
+
+	if uploads := em.Strava.Uploads(); len(uploads) != 0 {
+		t.Errorf("Expected no Strava uploads in update mode, got %d", len(uploads))
+	}
+	updates := em.Strava.ActivityUpdates()
+	if len(updates) != 1 {
+		t.Fatalf("Expected 1 activity update, got %d", len(updates))
+	}
+	if u := updates[0]; u.ActivityID != 901 || u.Name != "Morning Workout" || u.SportType != "WeightTraining" || u.GearID != "b42" || u.Description == "" {
+		t.Errorf("Unexpected activity update: %+v", u)
+	}
+
+	var enriched pb.EnrichedActivityEvent
+	for _, m := range em.Bus.Messages() {
+		if m.Topic == shared.TopicEnrichedActivity {
+			if err := protojson.Unmarshal(m.Event.Data(), &enriched); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	synced, err := em.DB.GetSynchronizedActivity(ctx, "user-1", enriched.ActivityId)
+	if err != nil {
+		t.Fatalf("Expected synchronized activity: %v", err)
+	}
+	if synced.Destinations["strava"] != "901" {
+		t.Errorf("Expected Strava activity 901 to be linked, got %v", synced.Destinations)
+	}
+}
+
+func TestEmulator_StravaActivityFlags(t *testing.T) {
+	em, err := New(Options{DataDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer em.Close()
+	ctx := context.Background()
+
+	// A rule marks weight training as trainer + muted; the pipeline overrides mute for Strava
+	user := testUser(pb.Destination_DESTINATION_STRAVA)
+	user.Pipelines[0].Enrichers = append(user.Pipelines[0].Enrichers, &pb.EnricherConfig{
+		ProviderType: pb.EnricherProviderType_ENRICHER_PROVIDER_CONDITION_MATCHER,
+		TypedConfig:  map[string]string{"activity_type": "WeightTraining", "trainer": "true", "hide_from_home": "true"},
+	})
+	unmuted := false
+	user.Pipelines[0].DestinationOptions = map[string]*pb.DestinationOptions{
+		"strava": {HideFromHome: &unmuted, Visibility: pb.ActivityVisibility_ACTIVITY_VISIBILITY_ONLY_ME},
+	}
+	if err := em.DB.SetUser(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := em.PublishActivity(ctx, testPayload()); err != nil {
+		t.Fatalf("PublishActivity failed: %v", err)
+	}
+	em.Wait()
+
+	uploads := em.Strava.Uploads()
+	if len(uploads) != 1 {
+		t.Fatalf("Expected 1 Strava upload, got %d", len(uploads))
+	}
+	if uploads[0].Trainer != "1" || uploads[0].Commute != "" {
+		t.Errorf("Unexpected upload flags: trainer=%q commute=%q", uploads[0].Trainer, uploads[0].Commute)
+	}
+
+	// Mute and visibility can only be set by updating the new activity
+	updates := em.Strava.ActivityUpdates()
+	if len(updates) != 1 {
+		t.Fatalf("Expected 1 activity update, got %d", len(updates))
+	}
+	u := updates[0]
+	if u.ActivityID != uploads[0].ActivityID || u.Visibility != "only_me" {
+		t.Errorf("Unexpected activity update: %+v", u)
+	}
+	if u.HideFromHome == nil || *u.HideFromHome || u.Trainer == nil || !*u.Trainer || u.Commute != nil {
+		t.Errorf("Unexpected activity update flags: %+v", u)
+	}
+}
+
+// testUser is a pro user with a Strava integration (its token expired, forcing a refresh
+// against the stub) and one Hevy pipeline to the given destinations
+func testUser(dests ...pb.Destination) *pb.UserRecord {
+	return &pb.UserRecord{
+		UserId: "user-1",
+		Tier:   "pro",
+		Integrations: &pb.UserIntegrations{
+			Strava: &pb.StravaIntegration{
+				Enabled:      true,
+				AccessToken:  "expired-token",
+				RefreshToken: "refresh-token",
+				ExpiresAt:    timestamppb.New(time.Now().Add(-time.Hour)),
+			},
+		},
+		Pipelines: []*pb.PipelineConfig{{
+			Id:     "pipeline-1",
+			Source: "SOURCE_HEVY",
+			Enrichers: []*pb.EnricherConfig{
+				{ProviderType: pb.EnricherProviderType_ENRICHER_PROVIDER_WORKOUT_SUMMARY},
+			},
+			Destinations: dests,
+		}},
+	}
+}
+
+func testPayload() *pb.ActivityPayload {
+	start := time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)
+	return &pb.ActivityPayload{
+		Source:    pb.ActivitySource_SOURCE_HEVY,
+		UserId:    "user-1",
+		Timestamp: timestamppb.Now(),
+		StandardizedActivity: &pb.StandardizedActivity{
+			ExternalId: "workout-1",
+			Name:       "Morning Workout",
+			Type:       pb.ActivityType_ACTIVITY_TYPE_WEIGHT_TRAINING,
+			StartTime:  timestamppb.New(start),
+			Sessions: []*pb.Session{{
+				StartTime:        timestamppb.New(start),
+				TotalElapsedTime: 600,
+				StrengthSets: []*pb.StrengthSet{
+					{ExerciseName: "Squat (Barbell)", Reps: 5, WeightKg: 100, StartTime: timestamppb.New(start), DurationSeconds: 60},
+				},
+			}},
+		},
+	}
+}
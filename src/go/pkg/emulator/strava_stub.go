@@ -0,0 +1,350 @@
+package emulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StravaUpload is an upload received by the StravaStub
+type StravaUpload struct {
+	ID           int64
+	ActivityID   int64
+	Name         string
+	Description  string
+	SportType    string
+	DataType     string
+	Trainer      string // "1"/"0", empty when not sent
+	Commute      string
+	ExternalID   string
+	Error        string // Processing error reported once the upload is processed (ActivityID is 0)
+	FitData      []byte
+	AccessToken  string
+	ReceivedTime time.Time
+}
+
+// StravaActivity is an activity already on the athlete's account (e.g. synced by their watch)
+type StravaActivity struct {
+	ID          int64
+	Name        string
+	SportType   string
+	StartDate   time.Time
+	ElapsedTime int // seconds
+}
+
+// StravaActivityUpdate is an activity update (PUT /api/v3/activities/{id}) received by the StravaStub
+type StravaActivityUpdate struct {
+	ActivityID   int64
+	Name         string
+	Description  string
+	SportType    string
+	GearID       string
+	Trainer      *bool
+	Commute      *bool
+	HideFromHome *bool
+	Visibility   string
+}
+
+// StravaStub is a local HTTP server implementing the Strava endpoints FitGlue calls:
+// token refresh (POST /oauth/token), uploads (POST /api/v3/uploads), upload status
+// (GET /api/v3/uploads/{id}), the athlete's activities (GET /api/v3/athlete/activities) and
+// activity updates (PUT /api/v3/activities/{id}).
+// Uploads are processed immediately unless SetProcessingPolls is used.
+type StravaStub struct {
+	server *httptest.Server
+
+	mu              sync.Mutex
+	uploads         []StravaUpload
+	activities      []StravaActivity
+	updates         []StravaActivityUpdate
+	tokenCount      int
+	nextID          int64
+	rejectNextN     int // Number of upcoming uploads to fail with 500 (for retry tests)
+	processingPolls int
+	processingError string
+	remainingPolls  map[int64]int // Upload ID -> status checks left before it is processed
+}
+
+// NewStravaStub starts the stub on a random local port
+func NewStravaStub() *StravaStub {
+	s := &StravaStub{nextID: 1000, remainingPolls: make(map[int64]int)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /oauth/token", s.handleToken)
+	mux.HandleFunc("POST /api/v3/uploads", s.handleUpload)
+	mux.HandleFunc("GET /api/v3/uploads/{id}", s.handleUploadStatus)
+	mux.HandleFunc("GET /api/v3/athlete/activities", s.handleListActivities)
+	mux.HandleFunc("PUT /api/v3/activities/{id}", s.handleActivityUpdate)
+	s.server = httptest.NewServer(mux)
+	return s
+}
+
+// URL is the base URL of the stub (e.g. http://127.0.0.1:54321)
+func (s *StravaStub) URL() string {
+	return s.server.URL
+}
+
+func (s *StravaStub) Close() {
+	s.server.Close()
+}
+
+// Uploads returns the uploads received so far, in order
+func (s *StravaStub) Uploads() []StravaUpload {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]StravaUpload(nil), s.uploads...)
+}
+
+// AddActivity adds an activity to the athlete's account, as if synced by another app
+func (s *StravaStub) AddActivity(a StravaActivity) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.activities = append(s.activities, a)
+}
+
+// ActivityUpdates returns the activity updates received so far, in order
+func (s *StravaStub) ActivityUpdates() []StravaActivityUpdate {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]StravaActivityUpdate(nil), s.updates...)
+}
+
+// FailNextUploads makes the next n uploads fail with HTTP 500
+func (s *StravaStub) FailNextUploads(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rejectNextN = n
+}
+
+// SetProcessingPolls keeps subsequent uploads "still being processed" for their first n status
+// checks, as Strava does for large or queued files
+func (s *StravaStub) SetProcessingPolls(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.processingPolls = n
+}
+
+// SetProcessingError makes subsequent uploads fail processing with msg (e.g. a duplicate
+// activity error); an empty msg restores successful processing
+func (s *StravaStub) SetProcessingError(msg string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.processingError = msg
+}
+
+func (s *StravaStub) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("refresh_token") == "" {
+		http.Error(w, `{"message":"Bad Request"}`, http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	s.tokenCount++
+	n := s.tokenCount
+	s.mu.Unlock()
+
+	expiresAt := time.Now().Add(6 * time.Hour)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"token_type":    "Bearer",
+		"access_token":  fmt.Sprintf("stub-access-%d", n),
+		"refresh_token": fmt.Sprintf("stub-refresh-%d", n),
+		"expires_at":    expiresAt.Unix(),
+		"expires_in":    int(time.Until(expiresAt).Seconds()),
+	})
+}
+
+func (s *StravaStub) handleUpload(w http.ResponseWriter, r *http.Request) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		http.Error(w, `{"message":"Authorization Error"}`, http.StatusUnauthorized)
+		return
+	}
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		http.Error(w, `{"message":"Bad Request"}`, http.StatusBadRequest)
+		return
+	}
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, `{"message":"Bad Request","errors":[{"field":"file","code":"missing"}]}`, http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	if s.rejectNextN > 0 {
+		s.rejectNextN--
+		s.mu.Unlock()
+		http.Error(w, `{"message":"Internal Server Error"}`, http.StatusInternalServerError)
+		return
+	}
+	s.nextID++
+	upload := StravaUpload{
+		ID:           s.nextID,
+		ActivityID:   s.nextID * 10,
+		Name:         r.FormValue("name"),
+		Description:  r.FormValue("description"),
+		SportType:    r.FormValue("sport_type"),
+		DataType:     r.FormValue("data_type"),
+		Trainer:      r.FormValue("trainer"),
+		Commute:      r.FormValue("commute"),
+		ExternalID:   r.FormValue("external_id"),
+		Error:        s.processingError,
+		FitData:      data,
+		AccessToken:  token,
+		ReceivedTime: time.Now(),
+	}
+	if upload.Error != "" {
+		upload.ActivityID = 0
+	}
+	s.uploads = append(s.uploads, upload)
+	if s.processingPolls > 0 {
+		s.remainingPolls[upload.ID] = s.processingPolls
+	}
+	resp := s.uploadStatus(upload, false)
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+func (s *StravaStub) handleUploadStatus(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, `{"message":"Record Not Found"}`, http.StatusNotFound)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, u := range s.uploads {
+		if u.ID == id {
+			writeJSON(w, http.StatusOK, s.uploadStatus(u, true))
+			return
+		}
+	}
+	http.Error(w, `{"message":"Record Not Found"}`, http.StatusNotFound)
+}
+
+func (s *StravaStub) handleListActivities(w http.ResponseWriter, r *http.Request) {
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); !ok || token == "" {
+		http.Error(w, `{"message":"Authorization Error"}`, http.StatusUnauthorized)
+		return
+	}
+	after, _ := strconv.ParseInt(r.URL.Query().Get("after"), 10, 64)
+	before, err := strconv.ParseInt(r.URL.Query().Get("before"), 10, 64)
+	if err != nil {
+		before = time.Now().Unix()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := []map[string]interface{}{}
+	for _, a := range s.activities {
+		if start := a.StartDate.Unix(); start <= after || start >= before {
+			continue
+		}
+		list = append(list, map[string]interface{}{
+			"id":           a.ID,
+			"name":         a.Name,
+			"sport_type":   a.SportType,
+			"start_date":   a.StartDate.UTC().Format(time.RFC3339),
+			"elapsed_time": a.ElapsedTime,
+		})
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *StravaStub) handleActivityUpdate(w http.ResponseWriter, r *http.Request) {
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); !ok || token == "" {
+		http.Error(w, `{"message":"Authorization Error"}`, http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, `{"message":"Record Not Found"}`, http.StatusNotFound)
+		return
+	}
+	var body struct {
+		Name         string `json:"name"`
+		Description  string `json:"description"`
+		SportType    string `json:"sport_type"`
+		GearID       string `json:"gear_id"`
+		Trainer      *bool  `json:"trainer"`
+		Commute      *bool  `json:"commute"`
+		HideFromHome *bool  `json:"hide_from_home"`
+		Visibility   string `json:"visibility"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, `{"message":"Bad Request"}`, http.StatusBadRequest)
+		return
+	}
+
+	update := StravaActivityUpdate{
+		ActivityID:   id,
+		Name:         body.Name,
+		Description:  body.Description,
+		SportType:    body.SportType,
+		GearID:       body.GearID,
+		Trainer:      body.Trainer,
+		Commute:      body.Commute,
+		HideFromHome: body.HideFromHome,
+		Visibility:   body.Visibility,
+	}
+	s.mu.Lock()
+	s.updates = append(s.updates, update)
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"id":          id,
+		"name":        update.Name,
+		"description": update.Description,
+		"sport_type":  update.SportType,
+		"gear_id":     update.GearID,
+		"visibility":  update.Visibility,
+	})
+}
+
+// uploadStatus renders an upload as Strava does; checked counts as a status check. Must hold s.mu.
+func (s *StravaStub) uploadStatus(u StravaUpload, checked bool) map[string]interface{} {
+	if n := s.remainingPolls[u.ID]; n > 0 {
+		if checked {
+			s.remainingPolls[u.ID] = n - 1
+		}
+		return map[string]interface{}{
+			"id":          u.ID,
+			"external_id": u.ExternalID,
+			"activity_id": nil,
+			"status":      "Your activity is still being processed.",
+			"error":       nil,
+		}
+	}
+	if u.Error != "" {
+		return map[string]interface{}{
+			"id":          u.ID,
+			"external_id": u.ExternalID,
+			"activity_id": nil,
+			"status":      "There was an error processing your activity.",
+			"error":       u.Error,
+		}
+	}
+	return map[string]interface{}{
+		"id":          u.ID,
+		"external_id": u.ExternalID,
+		"activity_id": u.ActivityID,
+		"status":      "Your activity is ready.",
+		"error":       nil,
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -3,15 +3,15 @@ package auto_increment
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/ripixel/fitglue-server/src/go/pkg/bootstrap"
+	activity_types "github.com/ripixel/fitglue-server/src/go/pkg/domain/activity"
 	"github.com/ripixel/fitglue-server/src/go/pkg/enricher_providers"
 	"github.com/ripixel/fitglue-server/src/go/pkg/plugin"
 	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
-	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type AutoIncrementProvider struct {
@@ -25,7 +25,7 @@ func init() {
 		Id:          "auto-increment",
 		Type:        pb.PluginType_PLUGIN_TYPE_ENRICHER,
 		Name:        "Auto Increment",
-		Description: "Appends an incrementing counter number to activity titles",
+		Description: "Numbers activity titles with a counter, optionally reset each week, month or year",
 		Icon:        "🔢",
 		Enabled:     true,
 		ConfigSchema: []*pb.ConfigFieldSchema{
@@ -51,6 +51,42 @@ func init() {
 				Required:     false,
 				DefaultValue: "1",
 			},
+			{
+				Key:          "period",
+				Label:        "Reset Period",
+				Description:  "Restart the count each week, month or year (in your timezone)",
+				FieldType:    pb.ConfigFieldType_CONFIG_FIELD_TYPE_SELECT,
+				Required:     false,
+				DefaultValue: PeriodNone,
+				Options: []*pb.ConfigFieldOption{
+					{Value: PeriodNone, Label: "Never"},
+					{Value: PeriodWeek, Label: "Weekly (Monday)"},
+					{Value: PeriodMonth, Label: "Monthly"},
+					{Value: PeriodYear, Label: "Yearly"},
+				},
+			},
+			{
+				Key:          "per_activity_type",
+				Label:        "Count Per Activity Type",
+				Description:  "Keep a separate count for each activity type (e.g. Run, Ride)",
+				FieldType:    pb.ConfigFieldType_CONFIG_FIELD_TYPE_BOOLEAN,
+				Required:     false,
+				DefaultValue: "false",
+			},
+			{
+				Key:         "title_template",
+				Label:       "Title Template",
+				Description: "Replaces the title, e.g. \"Run {{n}} of {{year}}\". Placeholders: {{n}}, {{title}}, {{type}}, {{year}}, {{month}}, {{month_name}}, {{week}}, {{week_year}}. Default appends \" (#{{n}})\"",
+				FieldType:   pb.ConfigFieldType_CONFIG_FIELD_TYPE_STRING,
+				Required:    false,
+			},
+			{
+				Key:         "description_template",
+				Label:       "Description Template",
+				Description: "Appended to the description, e.g. \"Week {{week}} session {{n}}\" (same placeholders as the title)",
+				FieldType:   pb.ConfigFieldType_CONFIG_FIELD_TYPE_STRING,
+				Required:    false,
+			},
 		},
 	})
 }
@@ -99,54 +135,100 @@ func (p *AutoIncrementProvider) Enrich(ctx context.Context, activity *pb.Standar
 		}, fmt.Errorf("service not initialized")
 	}
 
-	// 3. Get/Increment Counter
-	counter, err := p.service.DB.GetCounter(ctx, user.UserId, key)
-	if err != nil {
-		if status.Code(err) == codes.NotFound {
-			counter = nil // Treat as missing -> initialize below
-		} else {
-			// Real error from DB
-			return &enricher_providers.EnrichmentResult{
-				Metadata: map[string]string{
-					"auto_increment_applied": "false",
-				},
-			}, fmt.Errorf("failed to get counter: %v", err)
-		}
+	// 3. Resolve counter scope (period in the user's timezone, optional activity type)
+	loc := userLocation(user, inputs)
+	startTime := time.Now()
+	if activity.StartTime != nil {
+		startTime = activity.StartTime.AsTime()
 	}
+	local := startTime.In(loc)
 
-	if counter == nil {
-		// Not found - initialize
-		var currentCount int64 = 0
-		if initialValStr, ok := inputs["initial_value"]; ok && initialValStr != "" {
-			var initialVal int64
-			if _, err := fmt.Sscanf(initialValStr, "%d", &initialVal); err == nil {
-				// We want the *next* increment to result in `initialVal`.
-				// So we start at `initialVal - 1`.
-				currentCount = initialVal - 1
-			}
-		}
-
-		counter = &pb.Counter{
-			Id:    key,
-			Count: currentCount,
+	period, err := periodKey(inputs["period"], local)
+	if err != nil {
+		return &enricher_providers.EnrichmentResult{
+			Metadata: map[string]string{
+				"auto_increment_applied": "false",
+				"reason":                 "Misconfigured",
+			},
+		}, nil
+	}
+	typeName := activity_types.GetStravaActivityType(activity.Type)
+	counterID := counterID(key, typeName, inputs["per_activity_type"] == "true", period)
+
+	// 4. Increment Counter (atomic; idempotent per activity so redeliveries keep their number)
+	var initialVal int64 = 1
+	if initialValStr, ok := inputs["initial_value"]; ok && initialValStr != "" {
+		if _, err := fmt.Sscanf(initialValStr, "%d", &initialVal); err != nil {
+			initialVal = 1
 		}
 	}
 
-	newCount := counter.Count + 1
-	counter.Count = newCount
-	counter.LastUpdated = timestamppb.Now()
-
-	// Persist
-	if err := p.service.DB.SetCounter(ctx, user.UserId, counter); err != nil {
-		return nil, fmt.Errorf("failed to update counter: %v", err)
+	newCount, err := p.service.DB.IncrementCounter(ctx, user.UserId, counterID, period, activityKey(activity), initialVal)
+	if err != nil {
+		return &enricher_providers.EnrichmentResult{
+			Metadata: map[string]string{
+				"auto_increment_applied": "false",
+			},
+		}, fmt.Errorf("failed to increment counter: %v", err)
 	}
 
-	return &enricher_providers.EnrichmentResult{
+	// 5. Render
+	result := &enricher_providers.EnrichmentResult{
 		NameSuffix: fmt.Sprintf(" (#%d)", newCount),
 		Metadata: map[string]string{
 			"auto_increment_applied": "true",
-			"auto_increment_key":     key,
+			"auto_increment_key":     counterID,
 			"auto_increment_val":     fmt.Sprintf("%d", newCount),
 		},
-	}, nil
+	}
+	if period != "" {
+		result.Metadata["auto_increment_period"] = period
+	}
+
+	vars := templateVars(newCount, activity.Name, typeName, inputs["period"], local)
+	if tmpl := inputs["title_template"]; tmpl != "" {
+		result.Name = renderTemplate(tmpl, vars)
+		result.NameSuffix = ""
+	}
+	if tmpl := inputs["description_template"]; tmpl != "" {
+		result.Description = renderTemplate(tmpl, vars)
+	}
+	return result, nil
+}
+
+// counterID is the counter document for this scope. Period-scoped counters use one document
+// per period ("parkrun:2026"), so an activity that arrives late still counts in its own period.
+func counterID(key, typeName string, perType bool, period string) string {
+	id := key
+	if perType {
+		id += ":" + strings.ToLower(typeName)
+	}
+	if period != "" {
+		id += ":" + period
+	}
+	return id
+}
+
+// userLocation resolves the timezone for period boundaries: the "timezone" input, then the
+// user's timezone, then UTC
+func userLocation(user *pb.UserRecord, inputs map[string]string) *time.Location {
+	for _, name := range []string{inputs["timezone"], user.Timezone} {
+		if name == "" {
+			continue
+		}
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc
+		}
+		slog.Warn("auto_increment: unknown timezone, ignoring", "timezone", name)
+	}
+	return time.UTC
+}
+
+// activityKey identifies the activity for counter idempotency ("SOURCE:external_id").
+// Activities without an external ID always take a new number.
+func activityKey(activity *pb.StandardizedActivity) string {
+	if activity.ExternalId == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%s", activity.Source, activity.ExternalId)
 }
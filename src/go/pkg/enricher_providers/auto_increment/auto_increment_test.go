@@ -2,9 +2,17 @@ package auto_increment
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/ripixel/fitglue-server/src/go/pkg/bootstrap"
+	"github.com/ripixel/fitglue-server/src/go/pkg/infrastructure/database"
 	"github.com/ripixel/fitglue-server/src/go/pkg/testing/mocks"
 	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
 )
@@ -39,59 +47,50 @@ func TestAutoIncrement_Enrich(t *testing.T) {
 		}
 	})
 
-	t.Run("Creates new counter if missing", func(t *testing.T) {
-		var setCounter *pb.Counter
+	t.Run("Uses incremented value", func(t *testing.T) {
+		var gotUser, gotKey, gotActivity string
+		var gotInitial int64
 		mockDB := &mocks.MockDatabase{
-			GetCounterFunc: func(ctx context.Context, userId, id string) (*pb.Counter, error) {
-				return nil, nil // Not found
-			},
-			SetCounterFunc: func(ctx context.Context, userId string, counter *pb.Counter) error {
-				setCounter = counter
-				return nil
+			IncrementCounterFunc: func(ctx context.Context, userId, id, period, activityId string, initial int64) (int64, error) {
+				gotUser, gotKey, gotActivity, gotInitial = userId, id, activityId, initial
+				return 6, nil
 			},
 		}
 
 		provider := &AutoIncrementProvider{}
 		provider.SetService(&bootstrap.Service{DB: mockDB})
 
-		activity := &pb.StandardizedActivity{Name: "Parkrun"}
+		activity := &pb.StandardizedActivity{Name: "Parkrun", Source: "SOURCE_HEVY", ExternalId: "w1"}
 		user := &pb.UserRecord{UserId: "u1"}
 		inputs := map[string]string{
-			"counter_key":    "parkrun",
-			"title_contains": "Parkrun",
+			"counter_key": "parkrun",
 		}
 
 		res, err := provider.Enrich(ctx, activity, user, inputs, false)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
-		if res == nil {
-			t.Fatal("Expected result, got nil")
-		}
 
 		// Verify result
-		if res.NameSuffix != " (#1)" {
-			t.Errorf("Expected suffix ' (#1)', got '%s'", res.NameSuffix)
+		if res.NameSuffix != " (#6)" {
+			t.Errorf("Expected suffix ' (#6)', got '%s'", res.NameSuffix)
 		}
-
-		// Verify DB persistence
-		if setCounter == nil {
-			t.Fatal("Expected SetCounter to be called")
+		if res.Metadata["auto_increment_val"] != "6" {
+			t.Errorf("Expected auto_increment_val=6, got %v", res.Metadata["auto_increment_val"])
 		}
-		if setCounter.Count != 1 {
-			t.Errorf("Expected persisted count 1, got %d", setCounter.Count)
+
+		// Verify DB call
+		if gotUser != "u1" || gotKey != "parkrun" || gotActivity != "SOURCE_HEVY:w1" || gotInitial != 1 {
+			t.Errorf("Unexpected IncrementCounter call: user=%q key=%q activity=%q initial=%d", gotUser, gotKey, gotActivity, gotInitial)
 		}
 	})
 
-	t.Run("Increments existing counter", func(t *testing.T) {
-		var setCounter *pb.Counter
+	t.Run("Passes initial value", func(t *testing.T) {
+		var gotInitial int64
 		mockDB := &mocks.MockDatabase{
-			GetCounterFunc: func(ctx context.Context, userId, id string) (*pb.Counter, error) {
-				return &pb.Counter{Id: "parkrun", Count: 5}, nil
-			},
-			SetCounterFunc: func(ctx context.Context, userId string, counter *pb.Counter) error {
-				setCounter = counter
-				return nil
+			IncrementCounterFunc: func(ctx context.Context, userId, id, period, activityId string, initial int64) (int64, error) {
+				gotInitial = initial
+				return initial, nil
 			},
 		}
 
@@ -101,7 +100,8 @@ func TestAutoIncrement_Enrich(t *testing.T) {
 		activity := &pb.StandardizedActivity{Name: "Parkrun"}
 		user := &pb.UserRecord{UserId: "u1"}
 		inputs := map[string]string{
-			"counter_key": "parkrun",
+			"counter_key":   "parkrun",
+			"initial_value": "100",
 		}
 
 		res, err := provider.Enrich(ctx, activity, user, inputs, false)
@@ -109,63 +109,40 @@ func TestAutoIncrement_Enrich(t *testing.T) {
 			t.Fatalf("Unexpected error: %v", err)
 		}
 
-		// Verify result
-		if res.NameSuffix != " (#6)" {
-			t.Errorf("Expected suffix ' (#6)', got '%s'", res.NameSuffix)
+		// Verify result - Should be #100
+		if res.NameSuffix != " (#100)" {
+			t.Errorf("Expected suffix ' (#100)', got '%s'", res.NameSuffix)
 		}
-
-		// Verify DB persistence
-		if setCounter.Count != 6 {
-			t.Errorf("Expected persisted count 6, got %d", setCounter.Count)
+		if gotInitial != 100 {
+			t.Errorf("Expected initial value 100, got %d", gotInitial)
 		}
 	})
 
-	t.Run("Respects initial value", func(t *testing.T) {
-		var setCounter *pb.Counter
+	t.Run("Returns error on DB failure", func(t *testing.T) {
 		mockDB := &mocks.MockDatabase{
-			GetCounterFunc: func(ctx context.Context, userId, id string) (*pb.Counter, error) {
-				return nil, nil // Not found
-			},
-			SetCounterFunc: func(ctx context.Context, userId string, counter *pb.Counter) error {
-				setCounter = counter
-				return nil
+			IncrementCounterFunc: func(ctx context.Context, userId, id, period, activityId string, initial int64) (int64, error) {
+				return 0, status.Error(codes.Aborted, "transaction aborted")
 			},
 		}
 
 		provider := &AutoIncrementProvider{}
 		provider.SetService(&bootstrap.Service{DB: mockDB})
 
-		activity := &pb.StandardizedActivity{Name: "Parkrun"}
-		user := &pb.UserRecord{UserId: "u1"}
-		inputs := map[string]string{
-			"counter_key":   "parkrun",
-			"initial_value": "100",
-		}
-
-		res, err := provider.Enrich(ctx, activity, user, inputs, false)
-		if err != nil {
-			t.Fatalf("Unexpected error: %v", err)
-		}
-
-		// Verify result - Should be #100
-		if res.NameSuffix != " (#100)" {
-			t.Errorf("Expected suffix ' (#100)', got '%s'", res.NameSuffix)
+		res, err := provider.Enrich(ctx, &pb.StandardizedActivity{Name: "Parkrun"}, &pb.UserRecord{UserId: "u1"}, map[string]string{"counter_key": "parkrun"}, false)
+		if err == nil {
+			t.Fatal("Expected error")
 		}
-
-		// Verify DB persistence - Should be stored as 100
-		if setCounter.Count != 100 {
-			t.Errorf("Expected persisted count 100, got %d", setCounter.Count)
+		if res == nil || res.Metadata["auto_increment_applied"] != "false" || res.NameSuffix != "" {
+			t.Errorf("Expected unapplied result, got %+v", res)
 		}
 	})
+
 	t.Run("Matches title case insensitive", func(t *testing.T) {
-		var setCounter *pb.Counter
+		called := false
 		mockDB := &mocks.MockDatabase{
-			GetCounterFunc: func(ctx context.Context, userId, id string) (*pb.Counter, error) {
-				return &pb.Counter{Id: "parkrun", Count: 0}, nil
-			},
-			SetCounterFunc: func(ctx context.Context, userId string, counter *pb.Counter) error {
-				setCounter = counter
-				return nil
+			IncrementCounterFunc: func(ctx context.Context, userId, id, period, activityId string, initial int64) (int64, error) {
+				called = true
+				return 1, nil
 			},
 		}
 
@@ -189,8 +166,307 @@ func TestAutoIncrement_Enrich(t *testing.T) {
 			t.Errorf("Expected suffix ' (#1)', got '%s'", res.NameSuffix)
 		}
 
-		if setCounter == nil {
-			t.Error("Expected SetCounter to be called")
+		if !called {
+			t.Error("Expected IncrementCounter to be called")
 		}
 	})
 }
+
+func TestAutoIncrement_Enrich_Sequence(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		inputs     map[string]string
+		titles     []string
+		ids        []string // External IDs (optional); repeated IDs are redeliveries
+		wantSuffix []string
+		wantCount  int64 // Stored count after all activities (0 = counter never created)
+	}{
+		{
+			name:       "Counts from one",
+			inputs:     map[string]string{"counter_key": "run"},
+			titles:     []string{"Run", "Run", "Run"},
+			wantSuffix: []string{" (#1)", " (#2)", " (#3)"},
+			wantCount:  3,
+		},
+		{
+			name:       "Initial value only applies on creation",
+			inputs:     map[string]string{"counter_key": "parkrun", "initial_value": "50"},
+			titles:     []string{"Parkrun", "Parkrun"},
+			wantSuffix: []string{" (#50)", " (#51)"},
+			wantCount:  51,
+		},
+		{
+			name:       "Filtered activities do not increment",
+			inputs:     map[string]string{"counter_key": "parkrun", "title_contains": "parkrun"},
+			titles:     []string{"Parkrun", "Easy Run", "Bushy Parkrun"},
+			wantSuffix: []string{" (#1)", "", " (#2)"},
+			wantCount:  2,
+		},
+		{
+			name:       "Reprocessed activity keeps its number",
+			inputs:     map[string]string{"counter_key": "run"},
+			titles:     []string{"Run", "Run", "Run", "Run"},
+			ids:        []string{"a", "b", "a", "c"},
+			wantSuffix: []string{" (#1)", " (#2)", " (#1)", " (#3)"},
+			wantCount:  3,
+		},
+		{
+			name:       "Activities without external ID always increment",
+			inputs:     map[string]string{"counter_key": "run"},
+			titles:     []string{"Run", "Run"},
+			ids:        []string{"", ""},
+			wantSuffix: []string{" (#1)", " (#2)"},
+			wantCount:  2,
+		},
+		{
+			name:       "Never matching filter creates no counter",
+			inputs:     map[string]string{"counter_key": "parkrun", "title_contains": "parkrun"},
+			titles:     []string{"Easy Run"},
+			wantSuffix: []string{""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := database.NewMemoryDatabase()
+			provider := &AutoIncrementProvider{}
+			provider.SetService(&bootstrap.Service{DB: db})
+			user := &pb.UserRecord{UserId: "u1"}
+
+			for i, title := range tt.titles {
+				activity := &pb.StandardizedActivity{Name: title, Source: "SOURCE_HEVY"}
+				if tt.ids != nil {
+					activity.ExternalId = tt.ids[i]
+				}
+				res, err := provider.Enrich(ctx, activity, user, tt.inputs, false)
+				if err != nil {
+					t.Fatalf("Activity %d: unexpected error: %v", i, err)
+				}
+				if res.NameSuffix != tt.wantSuffix[i] {
+					t.Errorf("Activity %d: expected suffix %q, got %q", i, tt.wantSuffix[i], res.NameSuffix)
+				}
+			}
+
+			counter, err := db.GetCounter(ctx, "u1", tt.inputs["counter_key"])
+			if tt.wantCount == 0 {
+				if status.Code(err) != codes.NotFound {
+					t.Errorf("Expected no counter, got %v (err %v)", counter, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetCounter failed: %v", err)
+			}
+			if counter.Count != tt.wantCount || counter.LastUpdated == nil {
+				t.Errorf("Expected stored count %d with timestamp, got %+v", tt.wantCount, counter)
+			}
+		})
+	}
+}
+
+func TestAutoIncrement_Enrich_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewMemoryDatabase()
+	provider := &AutoIncrementProvider{}
+	provider.SetService(&bootstrap.Service{DB: db})
+	user := &pb.UserRecord{UserId: "u1"}
+	inputs := map[string]string{"counter_key": "run"}
+
+	const n = 20
+	suffixes := make([]string, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			activity := &pb.StandardizedActivity{Name: "Run", Source: "SOURCE_HEVY", ExternalId: fmt.Sprintf("w%d", i)}
+			res, err := provider.Enrich(ctx, activity, user, inputs, false)
+			if err != nil {
+				t.Errorf("Activity %d: unexpected error: %v", i, err)
+				return
+			}
+			suffixes[i] = res.NameSuffix
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[string]bool)
+	for _, suffix := range suffixes {
+		if seen[suffix] {
+			t.Errorf("Number %q assigned twice", suffix)
+		}
+		seen[suffix] = true
+	}
+	if counter, err := db.GetCounter(ctx, "u1", "run"); err != nil || counter.Count != n {
+		t.Errorf("Expected stored count %d, got %+v (err %v)", n, counter, err)
+	}
+}
+
+func TestAutoIncrement_Enrich_Periods(t *testing.T) {
+	ctx := context.Background()
+
+	type act struct {
+		title string
+		start string // RFC3339
+		typ   pb.ActivityType
+	}
+	run := pb.ActivityType_ACTIVITY_TYPE_RUN
+	ride := pb.ActivityType_ACTIVITY_TYPE_RIDE
+
+	tests := []struct {
+		name       string
+		timezone   string // UserRecord.Timezone
+		inputs     map[string]string
+		activities []act
+		wantName   []string // Final title (activity title + suffix, or rendered template)
+		wantDesc   []string // Optional
+		wantKeys   []string // Optional: auto_increment_key metadata
+	}{
+		{
+			name:   "Yearly counter resets at new year",
+			inputs: map[string]string{"counter_key": "run", "period": "year", "title_template": "Run {{n}} of {{year}}"},
+			activities: []act{
+				{"Morning Run", "2025-12-30T08:00:00Z", run},
+				{"Morning Run", "2025-12-31T08:00:00Z", run},
+				{"Morning Run", "2026-01-01T08:00:00Z", run},
+			},
+			wantName: []string{"Run 1 of 2025", "Run 2 of 2025", "Run 1 of 2026"},
+			wantKeys: []string{"run:2025", "run:2025", "run:2026"},
+		},
+		{
+			name:   "Late activity counts in its own period",
+			inputs: map[string]string{"counter_key": "run", "period": "month"},
+			activities: []act{
+				{"Run", "2026-02-27T08:00:00Z", run},
+				{"Run", "2026-03-02T08:00:00Z", run},
+				{"Run", "2026-02-28T08:00:00Z", run}, // Uploaded late
+				{"Run", "2026-03-03T08:00:00Z", run},
+			},
+			wantName: []string{"Run (#1)", "Run (#1)", "Run (#2)", "Run (#2)"},
+		},
+		{
+			name:   "Weekly counter uses ISO weeks",
+			inputs: map[string]string{"counter_key": "gym", "period": "week", "title_template": "{{title}}", "description_template": "Week {{week}} session {{n}}"},
+			activities: []act{
+				{"Legs", "2026-03-08T10:00:00Z", run}, // Sunday, week 10
+				{"Push", "2026-03-09T10:00:00Z", run}, // Monday, week 11
+				{"Pull", "2026-03-11T10:00:00Z", run},
+			},
+			wantName: []string{"Legs", "Push", "Pull"},
+			wantDesc: []string{"Week 10 session 1", "Week 11 session 1", "Week 11 session 2"},
+			wantKeys: []string{"gym:2026-W10", "gym:2026-W11", "gym:2026-W11"},
+		},
+		{
+			name:   "Weekly counter uses the ISO week's year",
+			inputs: map[string]string{"counter_key": "gym", "period": "week", "title_template": "Week {{week}} of {{year}} #{{n}}", "description_template": "{{week}}/{{week_year}}"},
+			activities: []act{
+				{"Legs", "2026-12-31T10:00:00Z", run}, // Thursday, week 53 of 2026
+				{"Push", "2027-01-01T10:00:00Z", run}, // Friday, still week 53 of 2026
+				{"Pull", "2027-01-04T10:00:00Z", run}, // Monday, week 1 of 2027
+			},
+			wantName: []string{"Week 53 of 2026 #1", "Week 53 of 2026 #2", "Week 1 of 2027 #1"},
+			wantDesc: []string{"53/2026", "53/2026", "1/2027"},
+			wantKeys: []string{"gym:2026-W53", "gym:2026-W53", "gym:2027-W01"},
+		},
+		{
+			name:     "Periods follow the user's timezone",
+			timezone: "America/New_York",
+			inputs:   map[string]string{"counter_key": "run", "period": "year", "title_template": "Run {{n}} of {{year}}"},
+			activities: []act{
+				{"Run", "2026-01-01T03:00:00Z", run}, // Still 31 Dec in New York
+				{"Run", "2026-01-01T15:00:00Z", run},
+			},
+			wantName: []string{"Run 1 of 2025", "Run 1 of 2026"},
+		},
+		{
+			name:   "Timezone input overrides the user's timezone",
+			inputs: map[string]string{"counter_key": "run", "period": "month", "timezone": "Pacific/Auckland", "title_template": "{{month_name}} #{{n}}"},
+			activities: []act{
+				{"Run", "2026-03-31T20:00:00Z", run}, // 1 April in Auckland
+			},
+			wantName: []string{"April #1"},
+		},
+		{
+			name:   "Per activity type counters",
+			inputs: map[string]string{"counter_key": "session", "per_activity_type": "true", "title_template": "{{type}} #{{n}}"},
+			activities: []act{
+				{"A", "2026-03-01T08:00:00Z", run},
+				{"B", "2026-03-02T08:00:00Z", ride},
+				{"C", "2026-03-03T08:00:00Z", run},
+			},
+			wantName: []string{"Run #1", "Ride #1", "Run #2"},
+			wantKeys: []string{"session:run", "session:ride", "session:run"},
+		},
+		{
+			name:   "Unknown period is misconfigured",
+			inputs: map[string]string{"counter_key": "run", "period": "fortnight"},
+			activities: []act{
+				{"Run", "2026-03-01T08:00:00Z", run},
+			},
+			wantName: []string{"Run"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := database.NewMemoryDatabase()
+			provider := &AutoIncrementProvider{}
+			provider.SetService(&bootstrap.Service{DB: db})
+			user := &pb.UserRecord{UserId: "u1", Timezone: tt.timezone}
+
+			for i, a := range tt.activities {
+				start, err := time.Parse(time.RFC3339, a.start)
+				if err != nil {
+					t.Fatal(err)
+				}
+				activity := &pb.StandardizedActivity{
+					Name:       a.title,
+					Type:       a.typ,
+					StartTime:  timestamppb.New(start),
+					Source:     "SOURCE_HEVY",
+					ExternalId: fmt.Sprintf("w%d", i),
+				}
+				res, err := provider.Enrich(ctx, activity, user, tt.inputs, false)
+				if err != nil {
+					t.Fatalf("Activity %d: unexpected error: %v", i, err)
+				}
+
+				name := a.title
+				if res.Name != "" {
+					name = res.Name
+				}
+				name += res.NameSuffix
+				if name != tt.wantName[i] {
+					t.Errorf("Activity %d: expected title %q, got %q", i, tt.wantName[i], name)
+				}
+				if tt.wantDesc != nil && res.Description != tt.wantDesc[i] {
+					t.Errorf("Activity %d: expected description %q, got %q", i, tt.wantDesc[i], res.Description)
+				}
+				if tt.wantKeys != nil && res.Metadata["auto_increment_key"] != tt.wantKeys[i] {
+					t.Errorf("Activity %d: expected counter %q, got %q", i, tt.wantKeys[i], res.Metadata["auto_increment_key"])
+				}
+			}
+		})
+	}
+}
+
+func TestAutoIncrement_Enrich_StoresPeriod(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewMemoryDatabase()
+	provider := &AutoIncrementProvider{}
+	provider.SetService(&bootstrap.Service{DB: db})
+
+	activity := &pb.StandardizedActivity{Name: "Run", StartTime: timestamppb.New(time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC))}
+	if _, err := provider.Enrich(ctx, activity, &pb.UserRecord{UserId: "u1"}, map[string]string{"counter_key": "run", "period": "week"}, false); err != nil {
+		t.Fatal(err)
+	}
+	counter, err := db.GetCounter(ctx, "u1", "run:2026-W11")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counter.Period != "2026-W11" || counter.Count != 1 {
+		t.Errorf("Unexpected counter: %+v", counter)
+	}
+}
@@ -0,0 +1,63 @@
+package auto_increment
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Counter periods (the "period" config input)
+const (
+	PeriodNone  = ""
+	PeriodWeek  = "week"  // ISO week, Monday to Sunday
+	PeriodMonth = "month" // Calendar month
+	PeriodYear  = "year"  // Calendar year
+)
+
+// periodKey returns the period an activity starting at t (in its local timezone) falls in,
+// e.g. "2026", "2026-03" or "2026-W11". Period keys sort chronologically.
+func periodKey(period string, t time.Time) (string, error) {
+	switch period {
+	case PeriodNone:
+		return "", nil
+	case PeriodWeek:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week), nil
+	case PeriodMonth:
+		return t.Format("2006-01"), nil
+	case PeriodYear:
+		return t.Format("2006"), nil
+	}
+	return "", fmt.Errorf("unknown period %q (expected week, month or year)", period)
+}
+
+// renderTemplate substitutes {{var}} placeholders; unknown placeholders are left as-is
+func renderTemplate(tmpl string, vars map[string]string) string {
+	pairs := make([]string, 0, len(vars)*2)
+	for k, v := range vars {
+		pairs = append(pairs, "{{"+k+"}}", v)
+	}
+	return strings.NewReplacer(pairs...).Replace(tmpl)
+}
+
+// templateVars are the placeholders available to title_template and description_template.
+// {{week_year}} is the ISO week-numbering year that {{week}} belongs to; {{year}} follows it
+// for weekly counters so that "Week {{week}} of {{year}}" names the counter's period.
+func templateVars(n int64, title, activityType, period string, local time.Time) map[string]string {
+	weekYear, week := local.ISOWeek()
+	year := local.Year()
+	if period == PeriodWeek {
+		year = weekYear
+	}
+	return map[string]string{
+		"n":          strconv.FormatInt(n, 10),
+		"title":      title,
+		"type":       activityType,
+		"year":       strconv.Itoa(year),
+		"month":      strconv.Itoa(int(local.Month())),
+		"month_name": local.Month().String(),
+		"week":       strconv.Itoa(week),
+		"week_year":  strconv.Itoa(weekYear),
+	}
+}
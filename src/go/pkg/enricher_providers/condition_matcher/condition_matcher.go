@@ -92,6 +92,39 @@ func init() {
 				FieldType:   pb.ConfigFieldType_CONFIG_FIELD_TYPE_STRING,
 				Required:    false,
 			},
+			{
+				Key:         "trainer",
+				Label:       "Trainer",
+				Description: "Mark as recorded on a training machine when conditions match",
+				FieldType:   pb.ConfigFieldType_CONFIG_FIELD_TYPE_BOOLEAN,
+				Required:    false,
+			},
+			{
+				Key:         "commute",
+				Label:       "Commute",
+				Description: "Mark as a commute when conditions match",
+				FieldType:   pb.ConfigFieldType_CONFIG_FIELD_TYPE_BOOLEAN,
+				Required:    false,
+			},
+			{
+				Key:         "hide_from_home",
+				Label:       "Mute",
+				Description: "Hide from followers' feeds when conditions match",
+				FieldType:   pb.ConfigFieldType_CONFIG_FIELD_TYPE_BOOLEAN,
+				Required:    false,
+			},
+			{
+				Key:         "visibility",
+				Label:       "Visibility",
+				Description: "Who can see the activity when conditions match",
+				FieldType:   pb.ConfigFieldType_CONFIG_FIELD_TYPE_SELECT,
+				Required:    false,
+				Options: []*pb.ConfigFieldOption{
+					{Value: "everyone", Label: "Everyone"},
+					{Value: "followers_only", Label: "Followers Only"},
+					{Value: "only_me", Label: "Only Me"},
+				},
+			},
 		},
 	})
 }
@@ -249,9 +282,56 @@ func (p *ConditionMatcherProvider) Enrich(ctx context.Context, act *pb.Standardi
 		result.Description = descTmpl
 	}
 
+	opts, err := destinationOptions(inputs)
+	if err != nil {
+		return nil, err
+	}
+	result.DestinationOptions = opts
+
 	return result, nil
 }
 
+// visibilities maps the visibility config values to ActivityVisibility
+var visibilities = map[string]pb.ActivityVisibility{
+	"everyone":       pb.ActivityVisibility_ACTIVITY_VISIBILITY_EVERYONE,
+	"followers_only": pb.ActivityVisibility_ACTIVITY_VISIBILITY_FOLLOWERS_ONLY,
+	"only_me":        pb.ActivityVisibility_ACTIVITY_VISIBILITY_ONLY_ME,
+}
+
+// destinationOptions builds the activity flags to apply when conditions match (nil if none are set)
+func destinationOptions(inputs map[string]string) (*pb.DestinationOptions, error) {
+	opts := &pb.DestinationOptions{}
+	set := false
+	for key, flag := range map[string]**bool{
+		"trainer":        &opts.Trainer,
+		"commute":        &opts.Commute,
+		"hide_from_home": &opts.HideFromHome,
+	} {
+		val := inputs[key]
+		if val == "" {
+			continue
+		}
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %v", key, err)
+		}
+		*flag = &b
+		set = true
+	}
+	if val := inputs["visibility"]; val != "" {
+		v, ok := visibilities[val]
+		if !ok {
+			return nil, fmt.Errorf("invalid visibility %q", val)
+		}
+		opts.Visibility = v
+		set = true
+	}
+	if !set {
+		return nil, nil
+	}
+	return opts, nil
+}
+
 // Helpers (Duplicated from Parkrun for now, should move to shared/geo?)
 func getStartLocation(activity *pb.StandardizedActivity) (float64, float64, bool) {
 	if len(activity.Sessions) == 0 {
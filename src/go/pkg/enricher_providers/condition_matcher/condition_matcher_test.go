@@ -225,4 +225,47 @@ func TestConditionMatcher_Enrich(t *testing.T) {
 			t.Errorf("Expected name 'Alias Match', got %s", res.Name)
 		}
 	})
+
+	t.Run("Sets Activity Flags", func(t *testing.T) {
+		act := &pb.StandardizedActivity{StartTime: makeTime("Mon", 7), Type: pb.ActivityType_ACTIVITY_TYPE_RIDE}
+		inputs := map[string]string{
+			"activity_type": "ride",
+			"days_of_week":  "Mon",
+			"commute":       "true",
+			"trainer":       "false",
+			"visibility":    "followers_only",
+		}
+		res, err := provider.Enrich(ctx, act, nil, inputs, false)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		opts := res.DestinationOptions
+		if opts == nil {
+			t.Fatal("Expected destination options")
+		}
+		if !opts.GetCommute() || opts.Trainer == nil || opts.GetTrainer() || opts.HideFromHome != nil {
+			t.Errorf("Unexpected flags: %+v", opts)
+		}
+		if opts.Visibility != pb.ActivityVisibility_ACTIVITY_VISIBILITY_FOLLOWERS_ONLY {
+			t.Errorf("Expected followers_only visibility, got %v", opts.Visibility)
+		}
+	})
+
+	t.Run("No Flags On Mismatch", func(t *testing.T) {
+		act := &pb.StandardizedActivity{StartTime: makeTime("Tue", 7), Type: pb.ActivityType_ACTIVITY_TYPE_RIDE}
+		res, err := provider.Enrich(ctx, act, nil, map[string]string{"days_of_week": "Mon", "commute": "true"}, false)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if res.DestinationOptions != nil {
+			t.Errorf("Expected no destination options, got %+v", res.DestinationOptions)
+		}
+	})
+
+	t.Run("Fails Invalid Visibility", func(t *testing.T) {
+		act := &pb.StandardizedActivity{StartTime: makeTime("Mon", 7)}
+		if _, err := provider.Enrich(ctx, act, nil, map[string]string{"visibility": "friends"}, false); err == nil {
+			t.Error("Expected error for invalid visibility")
+		}
+	})
 }
@@ -9,19 +9,28 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/muktihari/fit/profile/basetype"
+
 	"github.com/ripixel/fitglue-server/src/go/pkg/bootstrap"
+	"github.com/ripixel/fitglue-server/src/go/pkg/domain/file_generators"
 	"github.com/ripixel/fitglue-server/src/go/pkg/infrastructure/oauth"
 	fitbit "github.com/ripixel/fitglue-server/src/go/pkg/integrations/fitbit"
 	"github.com/ripixel/fitglue-server/src/go/pkg/plugin"
 	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
 )
 
+// DevFieldAlignmentDrift is the developer field holding the GPS/HR clock drift percentage
+const DevFieldAlignmentDrift = "alignment_drift"
+
 type FitBitHeartRate struct {
 	Service *bootstrap.Service
 }
 
 func init() {
 	Register(NewFitBitHeartRate())
+	file_generators.RegisterDeveloperField(file_generators.DeveloperFieldDefinition{
+		Key: DevFieldAlignmentDrift, Num: 4, Type: basetype.Float32, Units: "%",
+	})
 
 	plugin.RegisterEnricher(pb.EnricherProviderType_ENRICHER_PROVIDER_FITBIT_HEART_RATE, &pb.PluginManifest{
 		Id:                   "fitbit-heart-rate",
@@ -121,7 +130,9 @@ func (p *FitBitHeartRate) EnrichWithClient(ctx context.Context, activity *pb.Sta
 
 	// 7. Build Stream - Check if GPS data exists for alignment
 	var stream []int
+	var streamTimestamps []time.Time
 	alignmentMetadata := make(map[string]string)
+	var developerFields map[string]interface{}
 
 	if hasGPSData(activity) {
 		// Use elastic matching for GPS+HR alignment
@@ -140,6 +151,8 @@ func (p *FitBitHeartRate) EnrichWithClient(ctx context.Context, activity *pb.Sta
 				stream = buildStreamIndexBased(hrResponse.ActivitiesHeartIntraday.Dataset, startTimeStr, durationSec)
 			} else {
 				stream = alignResult.AlignedHR
+				streamTimestamps = gpsTimestamps
+				developerFields = map[string]interface{}{DevFieldAlignmentDrift: alignResult.DriftPercent}
 				for k, v := range alignResult.Metadata {
 					alignmentMetadata[k] = v
 				}
@@ -213,8 +226,9 @@ func (p *FitBitHeartRate) EnrichWithClient(ctx context.Context, activity *pb.Sta
 	}
 
 	return &EnrichmentResult{
-		Name:            "", // Don't wipe name
-		HeartRateStream: stream,
+		Name:             "", // Don't wipe name
+		HeartRateStream:  stream,
+		StreamTimestamps: streamTimestamps,
 		Metadata: mergeMetadata(map[string]string{
 			"hr_source":     "fitbit",
 			"query_date":    date,
@@ -224,6 +238,7 @@ func (p *FitBitHeartRate) EnrichWithClient(ctx context.Context, activity *pb.Sta
 			"status_detail": "Success",
 			"do_not_retry":  fmt.Sprintf("%v", doNotRetry),
 		}, alignmentMetadata),
+		DeveloperFields: developerFields,
 	}, nil
 }
 
@@ -2,6 +2,7 @@ package enricher_providers
 
 import (
 	"context"
+	"time"
 
 	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
 )
@@ -16,7 +17,10 @@ type EnrichmentResult struct {
 	NameSuffix string // Appended to the final name (e.g. " (#5)")
 	Tags       []string
 
-	// Raw Data Streams (for merging)
+	// Raw Data Streams (for merging by timestamp).
+	// Sample i is at StreamTimestamps[i] if set, otherwise i seconds after the session start.
+	// Zero values are treated as "no data".
+	StreamTimestamps   []time.Time
 	HeartRateStream    []int
 	PowerStream        []int
 	PositionLatStream  []float64
@@ -29,6 +33,14 @@ type EnrichmentResult struct {
 	// Extra metadata to append
 	Metadata map[string]string
 
+	// Destination options (e.g. Strava trainer/commute flags) for every destination of the pipeline.
+	// Set fields are merged in provider order; the pipeline's per-destination options override them.
+	DestinationOptions *pb.DestinationOptions
+
+	// Typed values written as FitGlue developer fields in the generated FIT file.
+	// Keys must be registered with file_generators.RegisterDeveloperField.
+	DeveloperFields map[string]interface{}
+
 	// HaltPipeline signals the orchestrator to stop processing this pipeline.
 	// Not a failure - the activity is intentionally skipped (e.g., filtered out).
 	HaltPipeline bool
@@ -6,11 +6,17 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/muktihari/fit/profile/basetype"
+
+	"github.com/ripixel/fitglue-server/src/go/pkg/domain/file_generators"
 	"github.com/ripixel/fitglue-server/src/go/pkg/enricher_providers/muscle_heatmap"
 	"github.com/ripixel/fitglue-server/src/go/pkg/plugin"
 	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
 )
 
+// DevFieldMuscleLoad is the developer field holding relative muscle group load (e.g. "Legs:100,Back:62")
+const DevFieldMuscleLoad = "muscle_load"
+
 // MuscleHeatmapProvider generates an emoji-based "heatmap" of muscle volume.
 type MuscleHeatmapProvider struct {
 	// Coefficient map to skew effort based on muscle size/strength.
@@ -27,6 +33,9 @@ type MuscleHeatmapProvider struct {
 
 func init() {
 	Register(NewMuscleHeatmapProvider())
+	file_generators.RegisterDeveloperField(file_generators.DeveloperFieldDefinition{
+		Key: DevFieldMuscleLoad, Num: 3, Type: basetype.String,
+	})
 
 	minBarLen := float64(3)
 	maxBarLen := float64(10)
@@ -230,7 +239,13 @@ func (p *MuscleHeatmapProvider) Enrich(ctx context.Context, activity *pb.Standar
 	var sb strings.Builder
 	sb.WriteString("Muscle Heatmap:\n")
 
+	// Relative load (percentage of the highest-loaded group) for the FIT developer field
+	loads := make([]string, 0, len(keys))
+
 	for _, k := range keys {
+		if maxScore > 0 {
+			loads = append(loads, fmt.Sprintf("%s:%.0f", k, volumeScores[k]/maxScore*100))
+		}
 		score := volumeScores[k]
 		rating := 0
 		if maxScore > 0 {
@@ -249,6 +264,9 @@ func (p *MuscleHeatmapProvider) Enrich(ctx context.Context, activity *pb.Standar
 			"muscle_groups_displayed": fmt.Sprintf("%d", len(keys)),
 			"max_score":               fmt.Sprintf("%.2f", maxScore),
 		},
+		DeveloperFields: map[string]interface{}{
+			DevFieldMuscleLoad: strings.Join(loads, ","),
+		},
 	}, nil
 }
 
@@ -817,6 +817,12 @@ func resultFromMapping(ex *ExerciseMapping, confidence float64) LookupResult {
 	}
 }
 
+// NormalizeName normalizes an exercise name the way LookupExercise does, so callers can
+// compare names (e.g. against another service's exercise titles) consistently
+func NormalizeName(s string) string {
+	return normalize(s)
+}
+
 // normalize converts a string to lowercase and removes non-alphanumeric characters
 func normalize(s string) string {
 	var result strings.Builder
@@ -3,14 +3,21 @@ package user_input
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 
+	"github.com/muktihari/fit/profile/basetype"
+
 	"github.com/ripixel/fitglue-server/src/go/pkg/bootstrap"
+	"github.com/ripixel/fitglue-server/src/go/pkg/domain/file_generators"
 	"github.com/ripixel/fitglue-server/src/go/pkg/enricher_providers"
 	"github.com/ripixel/fitglue-server/src/go/pkg/plugin"
 	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
 )
 
+// DevFieldRPE is the developer field holding the user's Rate of Perceived Exertion (1-10)
+const DevFieldRPE = "rpe"
+
 type WaitForInputError struct {
 	ActivityID     string
 	RequiredFields []string
@@ -34,6 +41,9 @@ type UserInputProvider struct {
 
 func init() {
 	enricher_providers.Register(&UserInputProvider{})
+	file_generators.RegisterDeveloperField(file_generators.DeveloperFieldDefinition{
+		Key: DevFieldRPE, Num: 2, Type: basetype.Uint8,
+	})
 
 	plugin.RegisterEnricher(pb.EnricherProviderType_ENRICHER_PROVIDER_USER_INPUT, &pb.PluginManifest{
 		Id:          "user-input",
@@ -46,7 +56,7 @@ func init() {
 			{
 				Key:          "fields",
 				Label:        "Required Fields",
-				Description:  "Comma-separated fields to request (title,description)",
+				Description:  "Comma-separated fields to request (title,description,rpe)",
 				FieldType:    pb.ConfigFieldType_CONFIG_FIELD_TYPE_STRING,
 				Required:     false,
 				DefaultValue: "description",
@@ -83,6 +93,10 @@ func (p *UserInputProvider) Enrich(ctx context.Context, activity *pb.Standardize
 					"user_input_applied": "true",
 				},
 			}
+			if rpe, err := strconv.Atoi(strings.TrimSpace(pending.InputData["rpe"])); err == nil && rpe >= 1 && rpe <= 10 {
+				res.DeveloperFields = map[string]interface{}{DevFieldRPE: rpe}
+				res.Metadata["rpe"] = strconv.Itoa(rpe)
+			}
 			return res, nil
 		}
 		if pending.Status == pb.PendingInput_STATUS_WAITING {
@@ -2,9 +2,11 @@ package user_input
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/ripixel/fitglue-server/src/go/pkg/bootstrap"
+	"github.com/ripixel/fitglue-server/src/go/pkg/infrastructure/database"
 	"github.com/ripixel/fitglue-server/src/go/pkg/testing/mocks"
 	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
 )
@@ -94,3 +96,87 @@ func TestUserInput_Enrich(t *testing.T) {
 		}
 	})
 }
+
+func TestUserInput_Enrich_PendingInputStates(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		pending    *pb.PendingInput // Stored before Enrich (nil = none)
+		fields     string
+		wantWait   []string // Expected RequiredFields when waiting (nil = expect a result)
+		wantName   string
+		wantDesc   string
+		wantRPE    interface{}
+		wantRPEKey bool
+	}{
+		{
+			name:     "No pending input requests default field",
+			wantWait: []string{"description"},
+		},
+		{
+			name:     "Waiting input requests configured fields",
+			pending:  &pb.PendingInput{Status: pb.PendingInput_STATUS_WAITING},
+			fields:   "title, rpe",
+			wantWait: []string{"title", "rpe"},
+		},
+		{
+			name:     "Completed input is applied",
+			pending:  &pb.PendingInput{Status: pb.PendingInput_STATUS_COMPLETED, InputData: map[string]string{"title": "Leg Day", "description": "Heavy"}},
+			wantName: "Leg Day",
+			wantDesc: "Heavy",
+		},
+		{
+			name:       "Completed input with RPE sets developer field",
+			pending:    &pb.PendingInput{Status: pb.PendingInput_STATUS_COMPLETED, InputData: map[string]string{"rpe": " 8 "}},
+			wantRPE:    8,
+			wantRPEKey: true,
+		},
+		{
+			name:    "Out of range RPE is ignored",
+			pending: &pb.PendingInput{Status: pb.PendingInput_STATUS_COMPLETED, InputData: map[string]string{"rpe": "11"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := database.NewMemoryDatabase()
+			if tt.pending != nil {
+				tt.pending.ActivityId = "HEVY:123"
+				tt.pending.UserId = "u1"
+				if err := db.CreatePendingInput(ctx, tt.pending); err != nil {
+					t.Fatal(err)
+				}
+			}
+			provider := &UserInputProvider{}
+			provider.SetService(&bootstrap.Service{DB: db})
+
+			activity := &pb.StandardizedActivity{Source: "HEVY", ExternalId: "123"}
+			res, err := provider.Enrich(ctx, activity, &pb.UserRecord{UserId: "u1"}, map[string]string{"fields": tt.fields}, false)
+
+			if tt.wantWait != nil {
+				waitErr, ok := err.(*WaitForInputError)
+				if !ok {
+					t.Fatalf("Expected WaitForInputError, got %v", err)
+				}
+				if waitErr.ActivityID != "HEVY:123" || strings.Join(waitErr.RequiredFields, ",") != strings.Join(tt.wantWait, ",") {
+					t.Errorf("Unexpected wait error: %+v", waitErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if res.Name != tt.wantName || res.Description != tt.wantDesc {
+				t.Errorf("Expected name %q / description %q, got %q / %q", tt.wantName, tt.wantDesc, res.Name, res.Description)
+			}
+			if _, ok := res.Metadata["rpe"]; ok != tt.wantRPEKey {
+				t.Errorf("Unexpected rpe metadata: %v", res.Metadata)
+			}
+			if res.DeveloperFields[DevFieldRPE] != tt.wantRPE {
+				t.Errorf("Expected RPE developer field %v, got %v", tt.wantRPE, res.DeveloperFields[DevFieldRPE])
+			}
+		})
+	}
+}
@@ -49,15 +49,45 @@ func (m *MockDB) UpdatePendingInput(ctx context.Context, id string, data map[str
 func (m *MockDB) ListPendingInputs(ctx context.Context, userID string) ([]*pb.PendingInput, error) {
 	return nil, nil
 }
+func (m *MockDB) GetPendingUpload(ctx context.Context, id string) (*pb.PendingUpload, error) {
+	return nil, nil
+}
+func (m *MockDB) CreatePendingUpload(ctx context.Context, upload *pb.PendingUpload) error {
+	return nil
+}
+func (m *MockDB) UpdatePendingUpload(ctx context.Context, id string, data map[string]interface{}) error {
+	return nil
+}
 func (m *MockDB) GetCounter(ctx context.Context, userId string, id string) (*pb.Counter, error) {
 	return nil, nil
 }
 func (m *MockDB) SetCounter(ctx context.Context, userId string, counter *pb.Counter) error {
 	return nil
 }
+func (m *MockDB) IncrementCounter(ctx context.Context, userId string, id string, period string, activityId string, initial int64) (int64, error) {
+	return initial, nil
+}
 func (m *MockDB) SetSynchronizedActivity(ctx context.Context, userId string, activity *pb.SynchronizedActivity) error {
 	return nil
 }
+func (m *MockDB) UpdateSynchronizedActivity(ctx context.Context, userId string, id string, data map[string]interface{}) error {
+	return nil
+}
+func (m *MockDB) FindSynchronizedActivities(ctx context.Context, userId string, source string, externalId string) ([]*pb.SynchronizedActivity, error) {
+	return nil, nil
+}
+func (m *MockDB) DestinationExists(ctx context.Context, userId string, destination string, externalId string) (bool, error) {
+	return false, nil
+}
+func (m *MockDB) HasProcessedActivity(ctx context.Context, userId string, source string, externalId string) (bool, error) {
+	return false, nil
+}
+func (m *MockDB) MarkActivityProcessed(ctx context.Context, userId string, record *pb.ProcessedActivityRecord) error {
+	return nil
+}
+func (m *MockDB) FindUserByExternalID(ctx context.Context, provider string, externalId string) (string, error) {
+	return "", nil
+}
 func (m *MockDB) IncrementSyncCount(ctx context.Context, userID string) error {
 	return nil
 }
@@ -4,6 +4,10 @@ import (
 	"context"
 
 	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
 	storage "github.com/ripixel/fitglue-server/src/go/pkg/storage/firestore"
 	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
 )
@@ -110,6 +114,20 @@ func (a *FirestoreAdapter) ListPendingInputs(ctx context.Context, userID string)
 	return results, nil
 }
 
+// --- Pending Uploads ---
+
+func (a *FirestoreAdapter) GetPendingUpload(ctx context.Context, id string) (*pb.PendingUpload, error) {
+	return a.storage.PendingUploads().Doc(id).Get(ctx)
+}
+
+func (a *FirestoreAdapter) CreatePendingUpload(ctx context.Context, upload *pb.PendingUpload) error {
+	return a.storage.PendingUploads().Doc(upload.Id).Set(ctx, upload)
+}
+
+func (a *FirestoreAdapter) UpdatePendingUpload(ctx context.Context, id string, data map[string]interface{}) error {
+	return a.storage.PendingUploads().Doc(id).Update(ctx, data)
+}
+
 // --- Counters ---
 
 func (a *FirestoreAdapter) GetCounter(ctx context.Context, userId string, id string) (*pb.Counter, error) {
@@ -126,8 +144,104 @@ func (a *FirestoreAdapter) SetCounter(ctx context.Context, userId string, counte
 	return a.storage.Counters(userId).Doc(counter.Id).Set(ctx, counter)
 }
 
+// IncrementCounter runs in a transaction, so concurrent activities never share a number
+func (a *FirestoreAdapter) IncrementCounter(ctx context.Context, userId string, id string, period string, activityId string, initial int64) (int64, error) {
+	counterRef := a.Client.Collection("users").Doc(userId).Collection("counters").Doc(id)
+
+	var value int64
+	err := a.Client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		var assignmentRef *firestore.DocumentRef
+		if activityId != "" {
+			assignmentRef = counterRef.Collection("assignments").Doc(activityId)
+			snap, err := tx.Get(assignmentRef)
+			if err == nil {
+				value = storage.FirestoreToCounterAssignment(snap.Data())
+				return nil
+			}
+			if status.Code(err) != codes.NotFound {
+				return err
+			}
+		}
+
+		count := initial - 1
+		snap, err := tx.Get(counterRef)
+		if err == nil {
+			count = storage.FirestoreToCounter(snap.Data()).Count
+		} else if status.Code(err) != codes.NotFound {
+			return err
+		}
+
+		value = count + 1
+		counter := &pb.Counter{Id: id, Count: value, LastUpdated: timestamppb.Now(), Period: period}
+		if err := tx.Set(counterRef, storage.CounterToFirestore(counter)); err != nil {
+			return err
+		}
+		if assignmentRef != nil {
+			return tx.Set(assignmentRef, storage.CounterAssignmentToFirestore(value))
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return value, nil
+}
+
 // --- Activities ---
 
 func (a *FirestoreAdapter) SetSynchronizedActivity(ctx context.Context, userId string, activity *pb.SynchronizedActivity) error {
 	return a.storage.Activities(userId).Doc(activity.ActivityId).Set(ctx, activity)
 }
+
+func (a *FirestoreAdapter) UpdateSynchronizedActivity(ctx context.Context, userId string, id string, data map[string]interface{}) error {
+	return a.storage.Activities(userId).Doc(id).Update(ctx, data)
+}
+
+// FindSynchronizedActivities queries activities by source and external_id (requires a composite index)
+func (a *FirestoreAdapter) FindSynchronizedActivities(ctx context.Context, userId string, source string, externalId string) ([]*pb.SynchronizedActivity, error) {
+	docs, err := a.storage.Activities(userId).Ref.Where("source", "==", source).Where("external_id", "==", externalId).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+	activities := make([]*pb.SynchronizedActivity, len(docs))
+	for i, doc := range docs {
+		activities[i] = storage.FirestoreToSynchronizedActivity(doc.Data())
+	}
+	return activities, nil
+}
+
+// DestinationExists queries activities by destinations.{destination} (requires a single-field index)
+func (a *FirestoreAdapter) DestinationExists(ctx context.Context, userId string, destination string, externalId string) (bool, error) {
+	docs, err := a.storage.Activities(userId).Ref.Where("destinations."+destination, "==", externalId).Limit(1).Documents(ctx).GetAll()
+	if err != nil {
+		return false, err
+	}
+	return len(docs) > 0, nil
+}
+
+// --- Processed Activities ---
+
+func (a *FirestoreAdapter) HasProcessedActivity(ctx context.Context, userId string, source string, externalId string) (bool, error) {
+	_, err := a.storage.ProcessedActivities(userId).Doc(storage.ProcessedActivityID(source, externalId)).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (a *FirestoreAdapter) MarkActivityProcessed(ctx context.Context, userId string, record *pb.ProcessedActivityRecord) error {
+	return a.storage.ProcessedActivities(userId).Doc(storage.ProcessedActivityID(record.Source, record.ExternalId)).Set(ctx, record)
+}
+
+// --- Integration Identities ---
+
+func (a *FirestoreAdapter) FindUserByExternalID(ctx context.Context, provider string, externalId string) (string, error) {
+	snap, err := a.Client.Collection("integrations").Doc(provider).Collection("ids").Doc(externalId).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return storage.FirestoreToIntegrationIdentity(snap.Data()), nil
+}
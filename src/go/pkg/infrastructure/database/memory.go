@@ -0,0 +1,411 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	storage "github.com/ripixel/fitglue-server/src/go/pkg/storage/firestore"
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+)
+
+// MemoryDatabase is an in-memory implementation of shared.Database for local runs and tests.
+// Documents are stored in their Firestore form (via the storage converters) with the same
+// write semantics as the FirestoreAdapter, so reads, partial updates and NotFound errors
+// behave as they do against Firestore. Safe for concurrent use.
+type MemoryDatabase struct {
+	mu   sync.RWMutex
+	docs map[string]map[string]interface{} // Keyed by document path, e.g. "users/{id}"
+}
+
+func NewMemoryDatabase() *MemoryDatabase {
+	return &MemoryDatabase{docs: make(map[string]map[string]interface{})}
+}
+
+func userPath(id string) string             { return "users/" + id }
+func executionPath(id string) string        { return "executions/" + id }
+func pendingInputPath(id string) string     { return "pending_inputs/" + id }
+func pendingUploadPath(id string) string    { return "pending_uploads/" + id }
+func counterPath(userId, id string) string  { return "users/" + userId + "/counters/" + id }
+func activityPath(userId, id string) string { return "users/" + userId + "/activities/" + id }
+func processedActivityPath(userId, id string) string {
+	return "users/" + userId + "/raw_activities/" + id
+}
+func integrationIdentityPath(provider, externalId string) string {
+	return "integrations/" + provider + "/ids/" + externalId
+}
+func counterAssignmentPath(userId, id, activityId string) string {
+	return counterPath(userId, id) + "/assignments/" + activityId
+}
+
+func (m *MemoryDatabase) get(path string) (map[string]interface{}, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	doc, ok := m.docs[path]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "document not found: %s", path)
+	}
+	return normalizeValue(doc).(map[string]interface{}), nil
+}
+
+// set mirrors DocumentRef.Set(data, firestore.MergeAll), which backs both Set and Update in
+// the storage wrapper: the document is created if missing, nested maps are merged and any
+// other value (including slices and nil) replaces the field. firestore.Delete removes a
+// field and firestore.ServerTimestamp stores the current time.
+func (m *MemoryDatabase) set(path string, data map[string]interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	doc, ok := m.docs[path]
+	if !ok {
+		doc = make(map[string]interface{})
+		m.docs[path] = doc
+	}
+	mergeMaps(doc, data)
+}
+
+func mergeMaps(dst, src map[string]interface{}) {
+	for k, v := range src {
+		switch v {
+		case firestore.Delete:
+			delete(dst, k)
+			continue
+		case firestore.ServerTimestamp:
+			dst[k] = time.Now().UTC()
+			continue
+		}
+		// Non-empty maps (of any key/value type) are merged field by field; an empty map is a leaf value
+		nv := normalizeValue(v)
+		if srcMap, ok := nv.(map[string]interface{}); ok && len(srcMap) > 0 {
+			dstMap, ok := dst[k].(map[string]interface{})
+			if !ok {
+				dstMap = make(map[string]interface{})
+				dst[k] = dstMap
+			}
+			mergeMaps(dstMap, srcMap)
+			continue
+		}
+		dst[k] = nv
+	}
+}
+
+// normalizeValue deep-copies a value into the types Firestore returns on read:
+// int64, float64, string, bool, time.Time, []interface{} and map[string]interface{}
+func normalizeValue(v interface{}) interface{} {
+	if v == firestore.Delete || v == firestore.ServerTimestamp {
+		return v
+	}
+	switch t := v.(type) {
+	case nil, string, bool, int64, float64, time.Time:
+		return t
+	case []byte:
+		return append([]byte(nil), t...)
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for k, val := range t {
+			out[k] = normalizeValue(val)
+		}
+		return out
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.String:
+		return rv.String()
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.Slice, reflect.Array:
+		out := make([]interface{}, rv.Len())
+		for i := range out {
+			out[i] = normalizeValue(rv.Index(i).Interface())
+		}
+		return out
+	case reflect.Map:
+		out := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			out[fmt.Sprint(iter.Key().Interface())] = normalizeValue(iter.Value().Interface())
+		}
+		return out
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return nil
+		}
+		return normalizeValue(rv.Elem().Interface())
+	default:
+		return v
+	}
+}
+
+func (m *MemoryDatabase) SetExecution(ctx context.Context, record *pb.ExecutionRecord) error {
+	m.set(executionPath(record.ExecutionId), storage.ExecutionToFirestore(record))
+	return nil
+}
+
+func (m *MemoryDatabase) UpdateExecution(ctx context.Context, id string, data map[string]interface{}) error {
+	m.set(executionPath(id), data)
+	return nil
+}
+
+// GetExecution returns a stored execution record (not part of shared.Database; used for inspection)
+func (m *MemoryDatabase) GetExecution(ctx context.Context, id string) (*pb.ExecutionRecord, error) {
+	doc, err := m.get(executionPath(id))
+	if err != nil {
+		return nil, err
+	}
+	return storage.FirestoreToExecution(doc), nil
+}
+
+// SetUser stores a user record (not part of shared.Database; used to seed local runs)
+func (m *MemoryDatabase) SetUser(ctx context.Context, user *pb.UserRecord) error {
+	m.set(userPath(user.UserId), storage.UserToFirestore(user))
+	return nil
+}
+
+func (m *MemoryDatabase) GetUser(ctx context.Context, id string) (*pb.UserRecord, error) {
+	doc, err := m.get(userPath(id))
+	if err != nil {
+		return nil, err
+	}
+	user := storage.FirestoreToUser(doc)
+	user.UserId = id
+	return user, nil
+}
+
+func (m *MemoryDatabase) UpdateUser(ctx context.Context, id string, data map[string]interface{}) error {
+	m.set(userPath(id), data)
+	return nil
+}
+
+// --- Sync Count (for tier limits) ---
+
+func (m *MemoryDatabase) IncrementSyncCount(ctx context.Context, userID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	doc, ok := m.docs[userPath(userID)]
+	if !ok {
+		return status.Errorf(codes.NotFound, "user not found: %s", userID)
+	}
+	count, _ := doc["sync_count_this_month"].(int64)
+	doc["sync_count_this_month"] = count + 1
+	return nil
+}
+
+func (m *MemoryDatabase) ResetSyncCount(ctx context.Context, userID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	doc, ok := m.docs[userPath(userID)]
+	if !ok {
+		return status.Errorf(codes.NotFound, "user not found: %s", userID)
+	}
+	doc["sync_count_this_month"] = int64(0)
+	doc["sync_count_reset_at"] = time.Now()
+	return nil
+}
+
+// --- Pending Inputs ---
+
+func (m *MemoryDatabase) GetPendingInput(ctx context.Context, id string) (*pb.PendingInput, error) {
+	doc, err := m.get(pendingInputPath(id))
+	if err != nil {
+		return nil, err
+	}
+	return storage.FirestoreToPendingInput(doc), nil
+}
+
+func (m *MemoryDatabase) CreatePendingInput(ctx context.Context, input *pb.PendingInput) error {
+	m.set(pendingInputPath(input.ActivityId), storage.PendingInputToFirestore(input))
+	return nil
+}
+
+func (m *MemoryDatabase) UpdatePendingInput(ctx context.Context, id string, data map[string]interface{}) error {
+	m.set(pendingInputPath(id), data)
+	return nil
+}
+
+// ListPendingInputs returns the user's pending inputs ordered by document ID, as a Firestore query would
+func (m *MemoryDatabase) ListPendingInputs(ctx context.Context, userID string) ([]*pb.PendingInput, error) {
+	m.mu.RLock()
+	docs := make(map[string]map[string]interface{})
+	var ids []string
+	for path, doc := range m.docs {
+		if id, ok := strings.CutPrefix(path, "pending_inputs/"); ok && !strings.Contains(id, "/") && doc["user_id"] == userID {
+			docs[id] = normalizeValue(doc).(map[string]interface{})
+			ids = append(ids, id)
+		}
+	}
+	m.mu.RUnlock()
+	sort.Strings(ids)
+
+	var results []*pb.PendingInput
+	for _, id := range ids {
+		p := storage.FirestoreToPendingInput(docs[id])
+		if p.ActivityId == "" {
+			p.ActivityId = id
+		}
+		results = append(results, p)
+	}
+	return results, nil
+}
+
+// --- Pending Uploads ---
+
+func (m *MemoryDatabase) GetPendingUpload(ctx context.Context, id string) (*pb.PendingUpload, error) {
+	doc, err := m.get(pendingUploadPath(id))
+	if err != nil {
+		return nil, err
+	}
+	return storage.FirestoreToPendingUpload(doc), nil
+}
+
+func (m *MemoryDatabase) CreatePendingUpload(ctx context.Context, upload *pb.PendingUpload) error {
+	m.set(pendingUploadPath(upload.Id), storage.PendingUploadToFirestore(upload))
+	return nil
+}
+
+func (m *MemoryDatabase) UpdatePendingUpload(ctx context.Context, id string, data map[string]interface{}) error {
+	m.set(pendingUploadPath(id), data)
+	return nil
+}
+
+// --- Counters ---
+
+func (m *MemoryDatabase) GetCounter(ctx context.Context, userId string, id string) (*pb.Counter, error) {
+	doc, err := m.get(counterPath(userId, id))
+	if err != nil {
+		return nil, err
+	}
+	counter := storage.FirestoreToCounter(doc)
+	counter.Id = id
+	return counter, nil
+}
+
+func (m *MemoryDatabase) SetCounter(ctx context.Context, userId string, counter *pb.Counter) error {
+	m.set(counterPath(userId, counter.Id), storage.CounterToFirestore(counter))
+	return nil
+}
+
+func (m *MemoryDatabase) IncrementCounter(ctx context.Context, userId string, id string, period string, activityId string, initial int64) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	assignment := counterAssignmentPath(userId, id, activityId)
+	if activityId != "" {
+		if doc, ok := m.docs[assignment]; ok {
+			return storage.FirestoreToCounterAssignment(doc), nil
+		}
+	}
+
+	count := initial - 1
+	if doc, ok := m.docs[counterPath(userId, id)]; ok {
+		count = storage.FirestoreToCounter(doc).Count
+	}
+	value := count + 1
+	counter := &pb.Counter{Id: id, Count: value, LastUpdated: timestamppb.Now(), Period: period}
+	m.docs[counterPath(userId, id)] = normalizeValue(storage.CounterToFirestore(counter)).(map[string]interface{})
+	if activityId != "" {
+		m.docs[assignment] = normalizeValue(storage.CounterAssignmentToFirestore(value)).(map[string]interface{})
+	}
+	return value, nil
+}
+
+// --- Activities ---
+
+func (m *MemoryDatabase) SetSynchronizedActivity(ctx context.Context, userId string, activity *pb.SynchronizedActivity) error {
+	m.set(activityPath(userId, activity.ActivityId), storage.SynchronizedActivityToFirestore(activity))
+	return nil
+}
+
+func (m *MemoryDatabase) UpdateSynchronizedActivity(ctx context.Context, userId string, id string, data map[string]interface{}) error {
+	m.set(activityPath(userId, id), data)
+	return nil
+}
+
+// GetSynchronizedActivity returns a stored synchronized activity (not part of shared.Database; used for inspection)
+func (m *MemoryDatabase) GetSynchronizedActivity(ctx context.Context, userId string, id string) (*pb.SynchronizedActivity, error) {
+	doc, err := m.get(activityPath(userId, id))
+	if err != nil {
+		return nil, err
+	}
+	return storage.FirestoreToSynchronizedActivity(doc), nil
+}
+
+func (m *MemoryDatabase) DestinationExists(ctx context.Context, userId string, destination string, externalId string) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	prefix := activityPath(userId, "")
+	for path, doc := range m.docs {
+		id, ok := strings.CutPrefix(path, prefix)
+		if !ok || strings.Contains(id, "/") {
+			continue
+		}
+		if dests, ok := doc["destinations"].(map[string]interface{}); ok && dests[destination] == externalId {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MemoryDatabase) FindSynchronizedActivities(ctx context.Context, userId string, source string, externalId string) ([]*pb.SynchronizedActivity, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	prefix := activityPath(userId, "")
+	var activities []*pb.SynchronizedActivity
+	for path, doc := range m.docs {
+		id, ok := strings.CutPrefix(path, prefix)
+		if !ok || strings.Contains(id, "/") {
+			continue
+		}
+		if doc["source"] == source && doc["external_id"] == externalId {
+			activities = append(activities, storage.FirestoreToSynchronizedActivity(normalizeValue(doc).(map[string]interface{})))
+		}
+	}
+	// Map iteration order is random; keep results stable
+	sort.Slice(activities, func(i, j int) bool { return activities[i].ActivityId < activities[j].ActivityId })
+	return activities, nil
+}
+
+// --- Processed Activities ---
+
+func (m *MemoryDatabase) HasProcessedActivity(ctx context.Context, userId string, source string, externalId string) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, ok := m.docs[processedActivityPath(userId, storage.ProcessedActivityID(source, externalId))]
+	return ok, nil
+}
+
+func (m *MemoryDatabase) MarkActivityProcessed(ctx context.Context, userId string, record *pb.ProcessedActivityRecord) error {
+	m.set(processedActivityPath(userId, storage.ProcessedActivityID(record.Source, record.ExternalId)), storage.ProcessedActivityToFirestore(record))
+	return nil
+}
+
+// --- Integration Identities ---
+
+// SetIntegrationIdentity maps a provider's user ID to a FitGlue user (not part of shared.Database;
+// the OAuth handlers do this in production. Used to seed local runs)
+func (m *MemoryDatabase) SetIntegrationIdentity(ctx context.Context, provider string, externalId string, userId string) error {
+	m.set(integrationIdentityPath(provider, externalId), storage.IntegrationIdentityToFirestore(userId))
+	return nil
+}
+
+func (m *MemoryDatabase) FindUserByExternalID(ctx context.Context, provider string, externalId string) (string, error) {
+	doc, err := m.get(integrationIdentityPath(provider, externalId))
+	if err != nil {
+		return "", nil
+	}
+	return storage.FirestoreToIntegrationIdentity(doc), nil
+}
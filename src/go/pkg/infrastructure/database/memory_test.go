@@ -0,0 +1,374 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+)
+
+func TestMemoryDatabase_MergeSemantics(t *testing.T) {
+	tests := []struct {
+		name   string
+		doc    map[string]interface{}
+		update map[string]interface{}
+		want   map[string]interface{}
+	}{
+		{
+			name:   "Creates missing document",
+			update: map[string]interface{}{"status": "STATUS_STARTED"},
+			want:   map[string]interface{}{"status": "STATUS_STARTED"},
+		},
+		{
+			name:   "Keeps untouched fields",
+			doc:    map[string]interface{}{"status": "STATUS_STARTED", "service": "enricher"},
+			update: map[string]interface{}{"status": "STATUS_SUCCESS"},
+			want:   map[string]interface{}{"status": "STATUS_SUCCESS", "service": "enricher"},
+		},
+		{
+			name:   "Merges nested maps",
+			doc:    map[string]interface{}{"integrations": map[string]interface{}{"strava": map[string]interface{}{"access_token": "a", "enabled": true}}},
+			update: map[string]interface{}{"integrations": map[string]interface{}{"strava": map[string]interface{}{"access_token": "b"}}},
+			want:   map[string]interface{}{"integrations": map[string]interface{}{"strava": map[string]interface{}{"access_token": "b", "enabled": true}}},
+		},
+		{
+			name:   "Merges typed maps",
+			doc:    map[string]interface{}{"input_data": map[string]interface{}{"title": "A"}},
+			update: map[string]interface{}{"input_data": map[string]string{"description": "B"}},
+			want:   map[string]interface{}{"input_data": map[string]interface{}{"title": "A", "description": "B"}},
+		},
+		{
+			name:   "Empty map replaces",
+			doc:    map[string]interface{}{"input_data": map[string]interface{}{"title": "A"}},
+			update: map[string]interface{}{"input_data": map[string]interface{}{}},
+			want:   map[string]interface{}{"input_data": map[string]interface{}{}},
+		},
+		{
+			name:   "Slices replace",
+			doc:    map[string]interface{}{"fcm_tokens": []interface{}{"a", "b"}},
+			update: map[string]interface{}{"fcm_tokens": []string{"c"}},
+			want:   map[string]interface{}{"fcm_tokens": []interface{}{"c"}},
+		},
+		{
+			name:   "Nil sets null",
+			doc:    map[string]interface{}{"error": "boom"},
+			update: map[string]interface{}{"error": nil},
+			want:   map[string]interface{}{"error": nil},
+		},
+		{
+			name:   "Delete removes field",
+			doc:    map[string]interface{}{"error": "boom", "status": "STATUS_FAILED"},
+			update: map[string]interface{}{"error": firestore.Delete},
+			want:   map[string]interface{}{"status": "STATUS_FAILED"},
+		},
+		{
+			name:   "Numbers normalize to Firestore types",
+			update: map[string]interface{}{"count": int32(3), "ratio": float32(0.5)},
+			want:   map[string]interface{}{"count": int64(3), "ratio": float64(0.5)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := NewMemoryDatabase()
+			if tt.doc != nil {
+				db.set("executions/e1", tt.doc)
+			}
+			if err := db.UpdateExecution(context.Background(), "e1", tt.update); err != nil {
+				t.Fatalf("UpdateExecution failed: %v", err)
+			}
+			got, err := db.get("executions/e1")
+			if err != nil {
+				t.Fatalf("get failed: %v", err)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryDatabase_Users(t *testing.T) {
+	ctx := context.Background()
+	db := NewMemoryDatabase()
+
+	if _, err := db.GetUser(ctx, "missing"); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound, got %v", err)
+	}
+	if err := db.IncrementSyncCount(ctx, "missing"); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound for increment on missing user, got %v", err)
+	}
+
+	user := &pb.UserRecord{
+		UserId: "u1",
+		Tier:   "pro",
+		Integrations: &pb.UserIntegrations{
+			Strava: &pb.StravaIntegration{Enabled: true, AccessToken: "old", RefreshToken: "r"},
+		},
+	}
+	if err := db.SetUser(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	// Mutating the caller's message must not affect the stored document
+	user.Tier = "free"
+
+	// Token refresh style partial update
+	if err := db.UpdateUser(ctx, "u1", map[string]interface{}{
+		"integrations": map[string]interface{}{"strava": map[string]interface{}{"access_token": "new"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := db.IncrementSyncCount(ctx, "u1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := db.GetUser(ctx, "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.UserId != "u1" || got.Tier != "pro" {
+		t.Errorf("Unexpected user: %+v", got)
+	}
+	if s := got.Integrations.Strava; s.AccessToken != "new" || s.RefreshToken != "r" || !s.Enabled {
+		t.Errorf("Expected merged strava integration, got %+v", s)
+	}
+	if got.SyncCountThisMonth != 3 {
+		t.Errorf("Expected sync count 3, got %d", got.SyncCountThisMonth)
+	}
+
+	if err := db.ResetSyncCount(ctx, "u1"); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = db.GetUser(ctx, "u1")
+	if got.SyncCountThisMonth != 0 || got.SyncCountResetAt == nil {
+		t.Errorf("Expected reset sync count, got %d / %v", got.SyncCountThisMonth, got.SyncCountResetAt)
+	}
+}
+
+func TestMemoryDatabase_PendingInputs(t *testing.T) {
+	ctx := context.Background()
+	db := NewMemoryDatabase()
+
+	for _, id := range []string{"SOURCE_HEVY:2", "SOURCE_HEVY:1"} {
+		if err := db.CreatePendingInput(ctx, &pb.PendingInput{ActivityId: id, UserId: "u1", Status: pb.PendingInput_STATUS_WAITING}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.CreatePendingInput(ctx, &pb.PendingInput{ActivityId: "SOURCE_HEVY:3", UserId: "u2", Status: pb.PendingInput_STATUS_WAITING}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.UpdatePendingInput(ctx, "SOURCE_HEVY:1", map[string]interface{}{
+		"status":     int32(pb.PendingInput_STATUS_COMPLETED),
+		"input_data": map[string]string{"title": "Leg Day"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := db.ListPendingInputs(ctx, "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].ActivityId != "SOURCE_HEVY:1" || list[1].ActivityId != "SOURCE_HEVY:2" {
+		t.Fatalf("Expected u1's inputs ordered by ID, got %v", list)
+	}
+
+	got, err := db.GetPendingInput(ctx, "SOURCE_HEVY:1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != pb.PendingInput_STATUS_COMPLETED || got.InputData["title"] != "Leg Day" || got.UserId != "u1" {
+		t.Errorf("Unexpected pending input: %+v", got)
+	}
+}
+
+func TestMemoryDatabase_PendingUploads(t *testing.T) {
+	ctx := context.Background()
+	db := NewMemoryDatabase()
+
+	if _, err := db.GetPendingUpload(ctx, "strava-1"); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound, got %v", err)
+	}
+
+	if err := db.CreatePendingUpload(ctx, &pb.PendingUpload{
+		Id:            "strava-1",
+		UserId:        "u1",
+		Destination:   "strava",
+		UploadId:      "1",
+		Status:        pb.PendingUpload_STATUS_PROCESSING,
+		ExecutionId:   "exec-1",
+		OriginalEvent: &pb.EnrichedActivityEvent{ActivityId: "a1", Name: "Leg Day", ActivityType: pb.ActivityType_ACTIVITY_TYPE_WEIGHT_TRAINING},
+		CreatedAt:     timestamppb.Now(),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.UpdatePendingUpload(ctx, "strava-1", map[string]interface{}{
+		"status":      int32(pb.PendingUpload_STATUS_COMPLETED),
+		"external_id": "42",
+		"attempts":    int32(2),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := db.GetPendingUpload(ctx, "strava-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != pb.PendingUpload_STATUS_COMPLETED || got.ExternalId != "42" || got.Attempts != 2 || got.ExecutionId != "exec-1" {
+		t.Errorf("Unexpected pending upload: %+v", got)
+	}
+	if got.OriginalEvent.GetName() != "Leg Day" || got.OriginalEvent.GetActivityType() != pb.ActivityType_ACTIVITY_TYPE_WEIGHT_TRAINING {
+		t.Errorf("Original event not preserved: %+v", got.OriginalEvent)
+	}
+}
+
+func TestMemoryDatabase_CountersAndActivities(t *testing.T) {
+	ctx := context.Background()
+	db := NewMemoryDatabase()
+
+	if _, err := db.GetCounter(ctx, "u1", "parkrun"); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound, got %v", err)
+	}
+	now := time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)
+	if err := db.SetCounter(ctx, "u1", &pb.Counter{Id: "parkrun", Count: 5, LastUpdated: timestamppb.New(now)}); err != nil {
+		t.Fatal(err)
+	}
+	counter, err := db.GetCounter(ctx, "u1", "parkrun")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counter.Id != "parkrun" || counter.Count != 5 || !counter.LastUpdated.AsTime().Equal(now) {
+		t.Errorf("Unexpected counter: %+v", counter)
+	}
+	if _, err := db.GetCounter(ctx, "u2", "parkrun"); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected counters to be scoped per user, got %v", err)
+	}
+
+	if err := db.SetSynchronizedActivity(ctx, "u1", &pb.SynchronizedActivity{ActivityId: "a1", Title: "Run", Destinations: map[string]string{"strava": "1"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SetSynchronizedActivity(ctx, "u1", &pb.SynchronizedActivity{ActivityId: "a1", Title: "Run", Destinations: map[string]string{"mock": "m1"}}); err != nil {
+		t.Fatal(err)
+	}
+	activity, err := db.GetSynchronizedActivity(ctx, "u1", "a1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if activity.Destinations["strava"] != "1" || activity.Destinations["mock"] != "m1" {
+		t.Errorf("Expected destinations from both uploads to merge, got %v", activity.Destinations)
+	}
+}
+
+func TestMemoryDatabase_Concurrency(t *testing.T) {
+	ctx := context.Background()
+	db := NewMemoryDatabase()
+	if err := db.SetUser(ctx, &pb.UserRecord{UserId: "u1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			db.IncrementSyncCount(ctx, "u1")
+			db.UpdateExecution(ctx, fmt.Sprintf("e%d", i), map[string]interface{}{"status": "STATUS_STARTED"})
+			db.GetUser(ctx, "u1")
+		}(i)
+	}
+	wg.Wait()
+
+	user, _ := db.GetUser(ctx, "u1")
+	if user.SyncCountThisMonth != 50 {
+		t.Errorf("Expected 50 increments, got %d", user.SyncCountThisMonth)
+	}
+}
+
+func TestMemoryDatabase_IncrementCounter(t *testing.T) {
+	ctx := context.Background()
+	db := NewMemoryDatabase()
+
+	steps := []struct {
+		activityId string
+		want       int64
+	}{
+		{"SOURCE_HEVY:1", 10}, // Starts at initial
+		{"SOURCE_HEVY:2", 11},
+		{"SOURCE_HEVY:1", 10}, // Idempotent per activity
+		{"", 12},              // No activity ID: always increments
+		{"", 13},
+	}
+	for i, step := range steps {
+		got, err := db.IncrementCounter(ctx, "u1", "parkrun", "", step.activityId, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != step.want {
+			t.Errorf("Step %d: expected %d, got %d", i, step.want, got)
+		}
+	}
+
+	counter, err := db.GetCounter(ctx, "u1", "parkrun")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counter.Count != 13 || counter.LastUpdated == nil {
+		t.Errorf("Unexpected counter: %+v", counter)
+	}
+
+	// Assignments are scoped per counter
+	if got, _ := db.IncrementCounter(ctx, "u1", "other", "2026", "SOURCE_HEVY:1", 1); got != 1 {
+		t.Errorf("Expected a fresh counter to assign 1, got %d", got)
+	}
+	if other, _ := db.GetCounter(ctx, "u1", "other"); other.Period != "2026" {
+		t.Errorf("Expected period to be stored, got %q", other.Period)
+	}
+}
+
+func TestMemoryDatabase_SourceLookups(t *testing.T) {
+	ctx := context.Background()
+	db := NewMemoryDatabase()
+
+	// Loop prevention: destinations of synchronized activities
+	db.SetSynchronizedActivity(ctx, "u1", &pb.SynchronizedActivity{
+		ActivityId:   "a1",
+		Destinations: map[string]string{"strava": "111"},
+	})
+	if ok, _ := db.DestinationExists(ctx, "u1", "strava", "111"); !ok {
+		t.Error("Expected strava 111 to exist")
+	}
+	if ok, _ := db.DestinationExists(ctx, "u1", "strava", "222"); ok {
+		t.Error("Expected strava 222 not to exist")
+	}
+	if ok, _ := db.DestinationExists(ctx, "u2", "strava", "111"); ok {
+		t.Error("Expected destinations to be scoped per user")
+	}
+
+	// Dedup: processed activities are scoped by source
+	db.MarkActivityProcessed(ctx, "u1", &pb.ProcessedActivityRecord{Source: "strava", ExternalId: "333", ProcessedAt: timestamppb.Now()})
+	if ok, _ := db.HasProcessedActivity(ctx, "u1", "strava", "333"); !ok {
+		t.Error("Expected strava 333 to be processed")
+	}
+	if ok, _ := db.HasProcessedActivity(ctx, "u1", "hevy", "333"); ok {
+		t.Error("Expected hevy 333 not to be processed")
+	}
+
+	// Identities
+	db.SetIntegrationIdentity(ctx, "strava", "98765", "u1")
+	if got, err := db.FindUserByExternalID(ctx, "strava", "98765"); err != nil || got != "u1" {
+		t.Errorf("Expected u1, got %q (err %v)", got, err)
+	}
+	if got, err := db.FindUserByExternalID(ctx, "strava", "unknown"); err != nil || got != "" {
+		t.Errorf("Expected no user, got %q (err %v)", got, err)
+	}
+}
@@ -0,0 +1,76 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+// PublishedMessage is a CloudEvent captured by MemoryPublisher
+type PublishedMessage struct {
+	ID    string
+	Topic string
+	Event event.Event
+}
+
+// Handler consumes a CloudEvent delivered from a topic (same signature as the function entry points)
+type Handler func(ctx context.Context, e event.Event) error
+
+// MemoryPublisher records published CloudEvents in memory, for local runs and tests.
+// Handlers subscribed to a topic receive each message asynchronously, like a push subscription;
+// failed deliveries are logged and not redelivered. Use Wait to block until the bus is idle.
+type MemoryPublisher struct {
+	mu       sync.Mutex
+	messages []PublishedMessage
+	subs     map[string][]Handler
+	inflight sync.WaitGroup
+}
+
+// Subscribe registers a handler for messages published to topicID after this call
+func (p *MemoryPublisher) Subscribe(topicID string, h Handler) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.subs == nil {
+		p.subs = make(map[string][]Handler)
+	}
+	p.subs[topicID] = append(p.subs[topicID], h)
+}
+
+func (p *MemoryPublisher) PublishCloudEvent(ctx context.Context, topicID string, e event.Event) (string, error) {
+	p.mu.Lock()
+	id := fmt.Sprintf("local-msg-%d", len(p.messages)+1)
+	msg := PublishedMessage{ID: id, Topic: topicID, Event: e.Clone()}
+	p.messages = append(p.messages, msg)
+	handlers := append([]Handler(nil), p.subs[topicID]...)
+	p.inflight.Add(len(handlers))
+	p.mu.Unlock()
+
+	if len(handlers) == 0 && p.subs != nil {
+		slog.Warn("No subscribers for topic, message dropped", "topic", topicID, "message_id", id)
+	}
+	for _, h := range handlers {
+		// Detach from the publisher's context: delivery outlives the publishing request
+		go func(h Handler) {
+			defer p.inflight.Done()
+			if err := h(context.WithoutCancel(ctx), msg.Event.Clone()); err != nil {
+				slog.Error("Message handler failed", "topic", topicID, "message_id", id, "error", err)
+			}
+		}(h)
+	}
+	return id, nil
+}
+
+// Wait blocks until all deliveries, including those triggered by handlers publishing further messages, finish
+func (p *MemoryPublisher) Wait() {
+	p.inflight.Wait()
+}
+
+// Messages returns the published messages in order
+func (p *MemoryPublisher) Messages() []PublishedMessage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]PublishedMessage(nil), p.messages...)
+}
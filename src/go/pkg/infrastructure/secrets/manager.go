@@ -36,7 +36,7 @@ func (a *SecretsAdapter) GetSecret(ctx context.Context, projectID, secretName st
 	// Call the API.
 	result, err := client.AccessSecretVersion(ctx, req)
 	if err != nil {
-		return "", fmt.Errorf("failed to access secret version: %v", err)
+		return "", fmt.Errorf("failed to access secret version: %w", err)
 	}
 
 	// Verify the data checksum.
@@ -0,0 +1,192 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	shared "github.com/ripixel/fitglue-server/src/go/pkg"
+)
+
+// FileSystemStore provides blob storage on the local filesystem.
+// Buckets are directories under Root; object names may contain "/" separators.
+// Used for tests and local runs.
+type FileSystemStore struct {
+	Root string
+}
+
+// bucketDir resolves a bucket directory under Root, rejecting names that escape it
+func (s *FileSystemStore) bucketDir(bucketName string) (string, error) {
+	if bucketName == "" || bucketName == "." || bucketName == ".." || strings.ContainsAny(bucketName, `/\`) {
+		return "", fmt.Errorf("invalid bucket name: %q", bucketName)
+	}
+	return filepath.Join(s.Root, bucketName), nil
+}
+
+// path resolves bucket/object under Root, rejecting names that escape it
+func (s *FileSystemStore) path(bucketName, objectName string) (string, error) {
+	bucketDir, err := s.bucketDir(bucketName)
+	if err != nil {
+		return "", err
+	}
+	p := filepath.Join(bucketDir, filepath.FromSlash(objectName))
+	if rel, err := filepath.Rel(bucketDir, p); err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("invalid object name: %q", objectName)
+	}
+	return p, nil
+}
+
+func (s *FileSystemStore) Write(ctx context.Context, bucketName, objectName string, data []byte) error {
+	w, err := s.NewWriter(ctx, bucketName, objectName)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		w.(*fileWriter).abort()
+		return err
+	}
+	return w.Close()
+}
+
+func (s *FileSystemStore) Read(ctx context.Context, bucketName, objectName string) ([]byte, error) {
+	p, err := s.path(bucketName, objectName)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", shared.ErrObjectNotFound, URI(bucketName, objectName))
+	}
+	return data, err
+}
+
+// LocalPath maps a gs://bucket/object URI to the object's file under Root
+func (s *FileSystemStore) LocalPath(uri string) (string, error) {
+	bucketName, objectName, err := ParseURI(uri)
+	if err != nil {
+		return "", err
+	}
+	return s.path(bucketName, objectName)
+}
+
+func (s *FileSystemStore) List(ctx context.Context, bucketName, prefix string) ([]string, error) {
+	bucketDir, err := s.bucketDir(bucketName)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	err = filepath.WalkDir(bucketDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && p == bucketDir {
+				return fs.SkipAll // Missing bucket lists as empty
+			}
+			return err
+		}
+		if d.IsDir() || isTempFile(d.Name()) {
+			return nil
+		}
+		rel, err := filepath.Rel(bucketDir, p)
+		if err != nil {
+			return err
+		}
+		if name := filepath.ToSlash(rel); strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Delete removes the object's file and any directories left empty by it
+func (s *FileSystemStore) Delete(ctx context.Context, bucketName, objectName string) error {
+	p, err := s.path(bucketName, objectName)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("%w: %s", shared.ErrObjectNotFound, URI(bucketName, objectName))
+		}
+		return err
+	}
+	bucketDir, _ := s.bucketDir(bucketName)
+	for dir := filepath.Dir(p); dir != bucketDir; dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			break // Not empty
+		}
+	}
+	return nil
+}
+
+func (s *FileSystemStore) Exists(ctx context.Context, bucketName, objectName string) (bool, error) {
+	p, err := s.path(bucketName, objectName)
+	if err != nil {
+		return false, err
+	}
+	info, err := os.Stat(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return !info.IsDir(), nil
+}
+
+// isTempFile reports whether name is an uncommitted NewWriter file
+func isTempFile(name string) bool {
+	return strings.HasPrefix(name, ".") && strings.Contains(name, ".tmp-")
+}
+
+// NewWriter writes to a temporary file that replaces the object on Close.
+// The returned writer is seekable, so FIT files are streamed in a single pass unless
+// the encoder also tees them elsewhere (see file_generators.WithTee).
+func (s *FileSystemStore) NewWriter(ctx context.Context, bucketName, objectName string) (io.WriteCloser, error) {
+	p, err := s.path(bucketName, objectName)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.CreateTemp(filepath.Dir(p), "."+filepath.Base(p)+".tmp-*")
+	if err != nil {
+		return nil, err
+	}
+	return &fileWriter{File: f, target: p, ctx: ctx}, nil
+}
+
+// fileWriter commits a temporary file to its target path on Close
+type fileWriter struct {
+	*os.File
+	target string
+	ctx    context.Context
+}
+
+// Close commits the file, unless the writer's context was cancelled
+func (w *fileWriter) Close() error {
+	if err := w.ctx.Err(); err != nil {
+		w.abort()
+		return err
+	}
+	if err := w.File.Close(); err != nil {
+		os.Remove(w.Name())
+		return err
+	}
+	return os.Rename(w.Name(), w.target)
+}
+
+func (w *fileWriter) abort() {
+	w.File.Close()
+	os.Remove(w.Name())
+}
@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileSystemStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Write and Read", func(t *testing.T) {
+		store := &FileSystemStore{Root: t.TempDir()}
+		if err := store.Write(ctx, "bucket", "a/b/c.fit", []byte("data")); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+		got, err := store.Read(ctx, "bucket", "a/b/c.fit")
+		if err != nil {
+			t.Fatalf("Read failed: %v", err)
+		}
+		if string(got) != "data" {
+			t.Errorf("Expected %q, got %q", "data", got)
+		}
+	})
+
+	t.Run("Rejects escaping object names", func(t *testing.T) {
+		store := &FileSystemStore{Root: t.TempDir()}
+		for _, name := range []string{"../other/x", "", "."} {
+			if err := store.Write(ctx, "bucket", name, []byte("x")); err == nil {
+				t.Errorf("Expected error for object name %q", name)
+			}
+		}
+	})
+
+	t.Run("Cancelled writer abandons the object", func(t *testing.T) {
+		root := t.TempDir()
+		store := &FileSystemStore{Root: root}
+		cctx, cancel := context.WithCancel(ctx)
+		w, err := store.NewWriter(cctx, "bucket", "obj")
+		if err != nil {
+			t.Fatalf("NewWriter failed: %v", err)
+		}
+		if _, err := w.Write([]byte("partial")); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+		cancel()
+		if err := w.Close(); err == nil {
+			t.Error("Expected Close to fail after cancellation")
+		}
+		entries, _ := os.ReadDir(filepath.Join(root, "bucket"))
+		if len(entries) != 0 {
+			t.Errorf("Expected no files left behind, got %d", len(entries))
+		}
+	})
+}
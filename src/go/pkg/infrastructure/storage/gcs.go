@@ -2,9 +2,14 @@ package storage
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
 
 	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+
+	shared "github.com/ripixel/fitglue-server/src/go/pkg"
 )
 
 // StorageAdapter provides blob storage operations using Google Cloud Storage
@@ -20,11 +25,51 @@ func (a *StorageAdapter) Write(ctx context.Context, bucketName, objectName strin
 	return wc.Close()
 }
 
+func (a *StorageAdapter) NewWriter(ctx context.Context, bucketName, objectName string) (io.WriteCloser, error) {
+	return a.Client.Bucket(bucketName).Object(objectName).NewWriter(ctx), nil
+}
+
 func (a *StorageAdapter) Read(ctx context.Context, bucketName, objectName string) ([]byte, error) {
 	rc, err := a.Client.Bucket(bucketName).Object(objectName).NewReader(ctx)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, bucketName, objectName)
 	}
 	defer rc.Close()
 	return io.ReadAll(rc)
 }
+
+func (a *StorageAdapter) List(ctx context.Context, bucketName, prefix string) ([]string, error) {
+	it := a.Client.Bucket(bucketName).Objects(ctx, &storage.Query{Prefix: prefix})
+	var names []string
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, attrs.Name)
+	}
+	return names, nil
+}
+
+func (a *StorageAdapter) Delete(ctx context.Context, bucketName, objectName string) error {
+	return translateError(a.Client.Bucket(bucketName).Object(objectName).Delete(ctx), bucketName, objectName)
+}
+
+func (a *StorageAdapter) Exists(ctx context.Context, bucketName, objectName string) (bool, error) {
+	_, err := a.Client.Bucket(bucketName).Object(objectName).Attrs(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// translateError maps GCS not-found errors to shared.ErrObjectNotFound
+func translateError(err error, bucketName, objectName string) error {
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return fmt.Errorf("%w: %s", shared.ErrObjectNotFound, URI(bucketName, objectName))
+	}
+	return err
+}
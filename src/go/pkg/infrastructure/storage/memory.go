@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	shared "github.com/ripixel/fitglue-server/src/go/pkg"
+)
+
+// MemoryStore provides blob storage in memory, for tests and local runs. Safe for concurrent use.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	objects map[string][]byte // Keyed by gs:// URI
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{objects: make(map[string][]byte)}
+}
+
+func (s *MemoryStore) Write(ctx context.Context, bucketName, objectName string, data []byte) error {
+	if objectName == "" {
+		return fmt.Errorf("invalid object name: %q", objectName)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[URI(bucketName, objectName)] = bytes.Clone(data)
+	return nil
+}
+
+func (s *MemoryStore) Read(ctx context.Context, bucketName, objectName string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	data, ok := s.objects[URI(bucketName, objectName)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", shared.ErrObjectNotFound, URI(bucketName, objectName))
+	}
+	return bytes.Clone(data), nil
+}
+
+// NewWriter buffers the object and stores it on Close, unless ctx was cancelled
+func (s *MemoryStore) NewWriter(ctx context.Context, bucketName, objectName string) (io.WriteCloser, error) {
+	if objectName == "" {
+		return nil, fmt.Errorf("invalid object name: %q", objectName)
+	}
+	return &memoryWriter{store: s, ctx: ctx, bucket: bucketName, object: objectName}, nil
+}
+
+func (s *MemoryStore) List(ctx context.Context, bucketName, prefix string) ([]string, error) {
+	bucketPrefix := URI(bucketName, "")
+	s.mu.RLock()
+	var names []string
+	for uri := range s.objects {
+		if name, ok := strings.CutPrefix(uri, bucketPrefix); ok && strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	s.mu.RUnlock()
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s *MemoryStore) Delete(ctx context.Context, bucketName, objectName string) error {
+	uri := URI(bucketName, objectName)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.objects[uri]; !ok {
+		return fmt.Errorf("%w: %s", shared.ErrObjectNotFound, uri)
+	}
+	delete(s.objects, uri)
+	return nil
+}
+
+func (s *MemoryStore) Exists(ctx context.Context, bucketName, objectName string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.objects[URI(bucketName, objectName)]
+	return ok, nil
+}
+
+// memoryWriter buffers written data and commits it to the store on Close
+type memoryWriter struct {
+	store          *MemoryStore
+	ctx            context.Context
+	bucket, object string
+	buf            bytes.Buffer
+}
+
+func (w *memoryWriter) Write(p []byte) (int, error) { return w.buf.Write(p) }
+
+func (w *memoryWriter) Close() error {
+	if err := w.ctx.Err(); err != nil {
+		return err
+	}
+	return w.store.Write(w.ctx, w.bucket, w.object, w.buf.Bytes())
+}
@@ -0,0 +1,139 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	shared "github.com/ripixel/fitglue-server/src/go/pkg"
+)
+
+// TestBlobStores runs the same behaviour checks against every local BlobStore
+func TestBlobStores(t *testing.T) {
+	stores := map[string]func(t *testing.T) shared.BlobStore{
+		"FileSystemStore": func(t *testing.T) shared.BlobStore { return &FileSystemStore{Root: t.TempDir()} },
+		"MemoryStore":     func(t *testing.T) shared.BlobStore { return NewMemoryStore() },
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			t.Run("Missing objects", func(t *testing.T) {
+				store := newStore(t)
+				if _, err := store.Read(ctx, "bucket", "missing.fit"); !errors.Is(err, shared.ErrObjectNotFound) {
+					t.Errorf("Read: expected ErrObjectNotFound, got %v", err)
+				}
+				if err := store.Delete(ctx, "bucket", "missing.fit"); !errors.Is(err, shared.ErrObjectNotFound) {
+					t.Errorf("Delete: expected ErrObjectNotFound, got %v", err)
+				}
+				if ok, err := store.Exists(ctx, "bucket", "missing.fit"); ok || err != nil {
+					t.Errorf("Exists: expected false, nil; got %v, %v", ok, err)
+				}
+				if names, err := store.List(ctx, "bucket", ""); len(names) != 0 || err != nil {
+					t.Errorf("List: expected empty, got %v, %v", names, err)
+				}
+			})
+
+			t.Run("List, Exists and Delete", func(t *testing.T) {
+				store := newStore(t)
+				for _, obj := range []string{"activities/u1/b.fit", "activities/u1/a.fit", "activities/u2/c.fit", "other.txt"} {
+					if err := store.Write(ctx, "bucket", obj, []byte(obj)); err != nil {
+						t.Fatalf("Write %s failed: %v", obj, err)
+					}
+				}
+				if err := store.Write(ctx, "elsewhere", "activities/u1/z.fit", []byte("z")); err != nil {
+					t.Fatal(err)
+				}
+
+				names, err := store.List(ctx, "bucket", "activities/u1/")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if want := []string{"activities/u1/a.fit", "activities/u1/b.fit"}; !reflect.DeepEqual(names, want) {
+					t.Errorf("List: expected %v, got %v", want, names)
+				}
+				if names, _ := store.List(ctx, "bucket", ""); len(names) != 4 {
+					t.Errorf("List: expected 4 objects in bucket, got %v", names)
+				}
+
+				if ok, err := store.Exists(ctx, "bucket", "activities/u1/a.fit"); !ok || err != nil {
+					t.Errorf("Exists: expected true, nil; got %v, %v", ok, err)
+				}
+				if err := store.Delete(ctx, "bucket", "activities/u1/a.fit"); err != nil {
+					t.Fatalf("Delete failed: %v", err)
+				}
+				if ok, _ := store.Exists(ctx, "bucket", "activities/u1/a.fit"); ok {
+					t.Error("Expected object to be gone after Delete")
+				}
+				if names, _ := store.List(ctx, "bucket", "activities/"); !reflect.DeepEqual(names, []string{"activities/u1/b.fit", "activities/u2/c.fit"}) {
+					t.Errorf("List after Delete: got %v", names)
+				}
+			})
+
+			t.Run("NewWriter commits on Close", func(t *testing.T) {
+				store := newStore(t)
+				w, err := store.NewWriter(ctx, "bucket", "stream.fit")
+				if err != nil {
+					t.Fatal(err)
+				}
+				w.Write([]byte("part1-"))
+				if ok, _ := store.Exists(ctx, "bucket", "stream.fit"); ok {
+					t.Error("Expected object to be invisible before Close")
+				}
+				if names, _ := store.List(ctx, "bucket", ""); len(names) != 0 {
+					t.Errorf("Expected uncommitted writes to be unlisted, got %v", names)
+				}
+				w.Write([]byte("part2"))
+				if err := w.Close(); err != nil {
+					t.Fatal(err)
+				}
+				if got, _ := store.Read(ctx, "bucket", "stream.fit"); string(got) != "part1-part2" {
+					t.Errorf("Expected %q, got %q", "part1-part2", got)
+				}
+			})
+		})
+	}
+}
+
+func TestParseURI(t *testing.T) {
+	tests := []struct {
+		uri            string
+		bucket, object string
+		wantErr        bool
+	}{
+		{uri: "gs://fitglue-artifacts/activities/u1/123.fit", bucket: "fitglue-artifacts", object: "activities/u1/123.fit"},
+		{uri: URI("b", "o"), bucket: "b", object: "o"},
+		{uri: "/tmp/activities/123.fit", wantErr: true},
+		{uri: "gs://bucket-only", wantErr: true},
+		{uri: "gs://bucket/", wantErr: true},
+		{uri: "gs:///object", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			bucket, object, err := ParseURI(tt.uri)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseURI(%q) error = %v, wantErr %v", tt.uri, err, tt.wantErr)
+			}
+			if bucket != tt.bucket || object != tt.object {
+				t.Errorf("ParseURI(%q) = %q, %q; want %q, %q", tt.uri, bucket, object, tt.bucket, tt.object)
+			}
+		})
+	}
+}
+
+func TestFileSystemStore_LocalPath(t *testing.T) {
+	store := &FileSystemStore{Root: "/data"}
+	got, err := store.LocalPath("gs://fitglue-artifacts/activities/u1/123.fit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "/data/fitglue-artifacts/activities/u1/123.fit" {
+		t.Errorf("Unexpected local path %q", got)
+	}
+	if _, err := store.LocalPath("gs://fitglue-artifacts/../../etc/passwd"); err == nil {
+		t.Error("Expected error for escaping URI")
+	}
+}
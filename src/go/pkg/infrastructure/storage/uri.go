@@ -0,0 +1,28 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+)
+
+// URIScheme is the scheme of artifact URIs (e.g. EnrichedActivityEvent.fit_file_uri).
+// All BlobStore implementations use gs:// URIs, so events are portable between GCS and local stores.
+const URIScheme = "gs://"
+
+// URI formats a bucket and object name as gs://bucket/object
+func URI(bucketName, objectName string) string {
+	return URIScheme + bucketName + "/" + objectName
+}
+
+// ParseURI splits a gs://bucket/object URI into its bucket and object name
+func ParseURI(uri string) (bucketName, objectName string, err error) {
+	rest, ok := strings.CutPrefix(uri, URIScheme)
+	if !ok {
+		return "", "", fmt.Errorf("invalid storage URI %q: expected %sbucket/object", uri, URIScheme)
+	}
+	bucketName, objectName, ok = strings.Cut(rest, "/")
+	if !ok || bucketName == "" || objectName == "" {
+		return "", "", fmt.Errorf("invalid storage URI %q: expected %sbucket/object", uri, URIScheme)
+	}
+	return bucketName, objectName, nil
+}
@@ -2,6 +2,8 @@ package shared
 
 import (
 	"context"
+	"errors"
+	"io"
 
 	"github.com/cloudevents/sdk-go/v2/event"
 	"github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
@@ -25,12 +27,36 @@ type Database interface {
 	UpdatePendingInput(ctx context.Context, id string, data map[string]interface{}) error
 	ListPendingInputs(ctx context.Context, userID string) ([]*pb.PendingInput, error) // Optional: for web list
 
+	// Pending Uploads (destination uploads still being processed)
+	GetPendingUpload(ctx context.Context, id string) (*pb.PendingUpload, error)
+	CreatePendingUpload(ctx context.Context, upload *pb.PendingUpload) error
+	UpdatePendingUpload(ctx context.Context, id string, data map[string]interface{}) error
+
 	// Counters
 	GetCounter(ctx context.Context, userId string, id string) (*pb.Counter, error)
 	SetCounter(ctx context.Context, userId string, counter *pb.Counter) error
+	// IncrementCounter atomically increments a counter and returns its new value; a missing
+	// counter starts at initial and records period. When activityId is set the assigned value
+	// is remembered, so reprocessing the same activity returns its original number.
+	IncrementCounter(ctx context.Context, userId string, id string, period string, activityId string, initial int64) (int64, error)
 
 	// Activities
 	SetSynchronizedActivity(ctx context.Context, userId string, activity *pb.SynchronizedActivity) error
+	UpdateSynchronizedActivity(ctx context.Context, userId string, id string, data map[string]interface{}) error
+	// FindSynchronizedActivities returns the synchronized activities (one per pipeline) created
+	// from a source activity. Used to propagate source updates and deletes to destinations.
+	FindSynchronizedActivities(ctx context.Context, userId string, source string, externalId string) ([]*pb.SynchronizedActivity, error)
+	// DestinationExists reports whether any synchronized activity was delivered to destination
+	// as externalId. Sources use it for loop prevention (skipping activities FitGlue created).
+	DestinationExists(ctx context.Context, userId string, destination string, externalId string) (bool, error)
+
+	// Processed source activities (ingestion dedup), keyed by "{source}_{external_id}"
+	HasProcessedActivity(ctx context.Context, userId string, source string, externalId string) (bool, error)
+	MarkActivityProcessed(ctx context.Context, userId string, record *pb.ProcessedActivityRecord) error
+
+	// Integration identities: maps a provider's user ID (e.g. Strava athlete ID) to the
+	// FitGlue user ID. Returns "" when the identity is not mapped.
+	FindUserByExternalID(ctx context.Context, provider string, externalId string) (string, error)
 }
 
 // --- Messaging Interfaces ---
@@ -44,8 +70,19 @@ type Publisher interface {
 type BlobStore interface {
 	Write(ctx context.Context, bucket, object string, data []byte) error
 	Read(ctx context.Context, bucket, object string) ([]byte, error)
+	// NewWriter returns a writer that uploads the object incrementally.
+	// The object is committed on Close; cancelling ctx before Close abandons the upload.
+	NewWriter(ctx context.Context, bucket, object string) (io.WriteCloser, error)
+	// List returns the names of objects starting with prefix, in lexical order.
+	List(ctx context.Context, bucket, prefix string) ([]string, error)
+	// Delete removes an object; a missing object returns ErrObjectNotFound.
+	Delete(ctx context.Context, bucket, object string) error
+	Exists(ctx context.Context, bucket, object string) (bool, error)
 }
 
+// ErrObjectNotFound is returned (wrapped) by BlobStore implementations for missing objects
+var ErrObjectNotFound = errors.New("object not found")
+
 // --- Secrets Interface ---
 
 type SecretStore interface {
@@ -0,0 +1,226 @@
+package strava
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	shared "github.com/ripixel/fitglue-server/src/go/pkg"
+	"github.com/ripixel/fitglue-server/src/go/pkg/domain/activity"
+	stravaapi "github.com/ripixel/fitglue-server/src/go/pkg/integrations/strava"
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+)
+
+const apiBase = "https://www.strava.com/api/v3"
+
+// Name is the connector name, used for identities, processed-activity dedup and loop prevention
+const Name = "strava"
+
+// pauseGap is the shortest gap between stream samples treated as a timer pause.
+// Smart recording leaves gaps of several seconds while moving, so shorter gaps are ignored.
+const pauseGap = time.Minute
+
+// streamKeys are the activity streams fetched for the record stream
+var streamKeys = []stravaapi.GetActivityStreamsParamsKeys{
+	stravaapi.GetActivityStreamsParamsKeysTime,
+	stravaapi.GetActivityStreamsParamsKeysLatlng,
+	stravaapi.GetActivityStreamsParamsKeysAltitude,
+	stravaapi.GetActivityStreamsParamsKeysHeartrate,
+	stravaapi.GetActivityStreamsParamsKeysCadence,
+	stravaapi.GetActivityStreamsParamsKeysWatts,
+	stravaapi.GetActivityStreamsParamsKeysVelocitySmooth,
+}
+
+// WebhookEvent is a Strava webhook event (https://developers.strava.com/docs/webhooks/)
+type WebhookEvent struct {
+	ObjectType     string                 `json:"object_type"` // "activity" or "athlete"
+	ObjectID       int64                  `json:"object_id"`
+	AspectType     string                 `json:"aspect_type"` // "create", "update" or "delete"
+	OwnerID        int64                  `json:"owner_id"`    // Athlete ID
+	SubscriptionID int64                  `json:"subscription_id"`
+	EventTime      int64                  `json:"event_time"`
+	Updates        map[string]interface{} `json:"updates,omitempty"` // Changed fields for updates, e.g. {"title": "..."}
+}
+
+// Fetch loads an activity and its streams with the generated Strava client.
+// Activities without streams (e.g. manual entries) return nil streams.
+func Fetch(ctx context.Context, client *http.Client, activityID int64) (*stravaapi.DetailedActivity, *stravaapi.StreamSet, error) {
+	api, err := stravaapi.NewClientWithResponses(apiBase, stravaapi.WithHTTPClient(client))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	actResp, err := api.GetActivityByIdWithResponse(ctx, activityID, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Strava API Error: %w", err)
+	}
+	if actResp.JSON200 == nil {
+		return nil, nil, fmt.Errorf("strava request failed: GET /activities/%d status %d", activityID, actResp.StatusCode())
+	}
+
+	streamResp, err := api.GetActivityStreamsWithResponse(ctx, activityID, &stravaapi.GetActivityStreamsParams{
+		Keys:      streamKeys,
+		KeyByType: true,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("Strava API Error: %w", err)
+	}
+	switch {
+	case streamResp.JSON200 != nil:
+		return actResp.JSON200, streamResp.JSON200, nil
+	case streamResp.StatusCode() == http.StatusNotFound:
+		return actResp.JSON200, nil, nil
+	default:
+		return nil, nil, fmt.Errorf("strava request failed: GET /activities/%d/streams status %d", activityID, streamResp.StatusCode())
+	}
+}
+
+// Exists reports whether the activity can still be fetched, distinguishing a deleted activity
+// (404) from a failed request
+func Exists(ctx context.Context, client *http.Client, activityID int64) (bool, error) {
+	api, err := stravaapi.NewClientWithResponses(apiBase, stravaapi.WithHTTPClient(client))
+	if err != nil {
+		return false, err
+	}
+	resp, err := api.GetActivityByIdWithResponse(ctx, activityID, nil)
+	if err != nil {
+		return false, fmt.Errorf("Strava API Error: %w", err)
+	}
+	switch resp.StatusCode() {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("strava request failed: GET /activities/%d status %d", activityID, resp.StatusCode())
+	}
+}
+
+// IsFitGlueUpload reports whether the activity was uploaded by FitGlue (see shared.UploadExternalIDPrefix)
+func IsFitGlueUpload(act *stravaapi.DetailedActivity) bool {
+	return act.ExternalId != nil && strings.HasPrefix(*act.ExternalId, shared.UploadExternalIDPrefix)
+}
+
+// MapActivity maps a Strava activity and its streams to a StandardizedActivity with a single
+// session and lap holding the full record stream. Gaps in the time stream of a minute or more
+// become pauses, so the generated FIT file keeps Strava's moving time.
+func MapActivity(act *stravaapi.DetailedActivity, streams *stravaapi.StreamSet, userID string) *pb.StandardizedActivity {
+	start := deref(act.StartDate)
+	std := &pb.StandardizedActivity{
+		Source:      "STRAVA",
+		ExternalId:  strconv.FormatInt(deref(act.Id), 10),
+		UserId:      userID,
+		StartTime:   timestamppb.New(start),
+		Name:        deref(act.Name),
+		Type:        activityType(act),
+		Description: deref(act.Description),
+	}
+
+	records, pauses := mapStreams(start, streams)
+	elapsed := float64(deref(act.ElapsedTime))
+	distance := float64(deref(act.Distance))
+	std.Sessions = []*pb.Session{{
+		StartTime:        std.StartTime,
+		TotalElapsedTime: elapsed,
+		TotalDistance:    distance,
+		Pauses:           pauses,
+		Laps: []*pb.Lap{{
+			StartTime:        std.StartTime,
+			TotalElapsedTime: elapsed,
+			TotalDistance:    distance,
+			Records:          records,
+		}},
+	}}
+	return std
+}
+
+// activityType maps sport_type (falling back to the legacy type) to an ActivityType
+func activityType(act *stravaapi.DetailedActivity) pb.ActivityType {
+	for _, t := range []*stravaapi.ActivityType{act.SportType, act.Type} {
+		if t == nil {
+			continue
+		}
+		if at := activity.ParseActivityTypeFromString(string(*t)); at != pb.ActivityType_ACTIVITY_TYPE_UNSPECIFIED {
+			return at
+		}
+	}
+	return pb.ActivityType_ACTIVITY_TYPE_WORKOUT
+}
+
+// mapStreams builds one record per sample of the time stream (seconds from start)
+func mapStreams(start time.Time, streams *stravaapi.StreamSet) ([]*pb.Record, []*pb.Pause) {
+	if streams == nil || streams.Time == nil || streams.Time.Data == nil {
+		return nil, nil
+	}
+	offsets := *streams.Time.Data
+
+	var latlng []stravaapi.LatLng
+	if streams.Latlng != nil && streams.Latlng.Data != nil {
+		latlng = *streams.Latlng.Data
+	}
+	var altitude, speed []float32
+	if streams.Altitude != nil && streams.Altitude.Data != nil {
+		altitude = *streams.Altitude.Data
+	}
+	if streams.VelocitySmooth != nil && streams.VelocitySmooth.Data != nil {
+		speed = *streams.VelocitySmooth.Data
+	}
+	var heartRate, cadence, watts []int
+	if streams.Heartrate != nil && streams.Heartrate.Data != nil {
+		heartRate = *streams.Heartrate.Data
+	}
+	if streams.Cadence != nil && streams.Cadence.Data != nil {
+		cadence = *streams.Cadence.Data
+	}
+	if streams.Watts != nil && streams.Watts.Data != nil {
+		watts = *streams.Watts.Data
+	}
+
+	records := make([]*pb.Record, len(offsets))
+	var pauses []*pb.Pause
+	for i, offset := range offsets {
+		ts := start.Add(time.Duration(offset) * time.Second)
+		r := &pb.Record{Timestamp: timestamppb.New(ts)}
+		if i < len(latlng) && len(latlng[i]) == 2 {
+			r.PositionLat = float64(latlng[i][0])
+			r.PositionLong = float64(latlng[i][1])
+		}
+		if i < len(altitude) {
+			r.Altitude = float64(altitude[i])
+		}
+		if i < len(speed) {
+			r.Speed = float64(speed[i])
+		}
+		if i < len(heartRate) {
+			r.HeartRate = int32(heartRate[i])
+		}
+		if i < len(cadence) {
+			r.Cadence = int32(cadence[i])
+		}
+		if i < len(watts) {
+			r.Power = int32(watts[i])
+		}
+		records[i] = r
+
+		if i > 0 && time.Duration(offset-offsets[i-1])*time.Second >= pauseGap {
+			pauses = append(pauses, &pb.Pause{
+				StartTime: records[i-1].Timestamp,
+				EndTime:   r.Timestamp,
+			})
+		}
+	}
+	return records, pauses
+}
+
+func deref[T any](p *T) T {
+	var zero T
+	if p == nil {
+		return zero
+	}
+	return *p
+}
@@ -0,0 +1,134 @@
+package strava
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
+)
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func jsonResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+	}
+}
+
+const activityJSON = `{
+	"id": 42,
+	"name": "Morning Run",
+	"description": "Easy loop",
+	"sport_type": "TrailRun",
+	"type": "Run",
+	"start_date": "2026-01-10T08:00:00Z",
+	"elapsed_time": 200,
+	"distance": 500.5,
+	"external_id": "garmin_push_123"
+}`
+
+const streamsJSON = `{
+	"time": {"data": [0, 1, 2, 120]},
+	"latlng": {"data": [[51.5, -0.1], [51.5001, -0.1001], [51.5002, -0.1002], [51.5003, -0.1003]]},
+	"altitude": {"data": [10, 11, 12, 13]},
+	"heartrate": {"data": [120, 125, 130, 110]},
+	"velocity_smooth": {"data": [2.5, 2.6, 2.7, 0]}
+}`
+
+func TestFetchAndMapActivity(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		streams     *http.Response
+		wantErr     bool
+		wantRecords int
+		wantPauses  int
+	}{
+		{name: "Activity with streams", streams: jsonResponse(200, streamsJSON), wantRecords: 4, wantPauses: 1},
+		{name: "Manual activity without streams", streams: jsonResponse(404, `{"message": "Record Not Found"}`)},
+		{name: "Streams request failing", streams: jsonResponse(500, `{}`), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				if strings.HasSuffix(req.URL.Path, "/streams") {
+					if req.URL.Query().Get("key_by_type") != "true" {
+						t.Errorf("Streams should be keyed by type: %s", req.URL.RawQuery)
+					}
+					return tt.streams, nil
+				}
+				return jsonResponse(200, activityJSON), nil
+			})}
+
+			act, streams, err := Fetch(ctx, client, 42)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if IsFitGlueUpload(act) {
+				t.Error("Activity from another uploader flagged as a FitGlue upload")
+			}
+
+			std := MapActivity(act, streams, "user-1")
+			if std.Source != "STRAVA" || std.ExternalId != "42" || std.UserId != "user-1" || std.Name != "Morning Run" || std.Description != "Easy loop" {
+				t.Errorf("Unexpected activity: %+v", std)
+			}
+			if std.Type != pb.ActivityType_ACTIVITY_TYPE_TRAIL_RUN {
+				t.Errorf("Type = %v, want TRAIL_RUN", std.Type)
+			}
+			if !std.StartTime.AsTime().Equal(time.Date(2026, 1, 10, 8, 0, 0, 0, time.UTC)) {
+				t.Errorf("Unexpected start time: %v", std.StartTime.AsTime())
+			}
+
+			session := std.Sessions[0]
+			if session.TotalElapsedTime != 200 || session.TotalDistance != 500.5 {
+				t.Errorf("Unexpected session totals: %+v", session)
+			}
+			records := session.Laps[0].Records
+			if len(records) != tt.wantRecords || len(session.Pauses) != tt.wantPauses {
+				t.Fatalf("Got %d records and %d pauses, want %d and %d", len(records), len(session.Pauses), tt.wantRecords, tt.wantPauses)
+			}
+			if tt.wantRecords > 0 {
+				r := records[1]
+				if r.HeartRate != 125 || r.Altitude != 11 || r.PositionLat < 51.5 || r.Speed < 2.5 {
+					t.Errorf("Unexpected record: %+v", r)
+				}
+				if got := records[3].Timestamp.AsTime().Sub(std.StartTime.AsTime()); got != 2*time.Minute {
+					t.Errorf("Last record offset = %v, want 2m", got)
+				}
+			}
+		})
+	}
+}
+
+func TestIsFitGlueUpload(t *testing.T) {
+	client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "/streams") {
+			return jsonResponse(404, `{}`), nil
+		}
+		return jsonResponse(200, `{"id": 7, "external_id": "fitglue-act-1.fit"}`), nil
+	})}
+	act, _, err := Fetch(context.Background(), client, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsFitGlueUpload(act) {
+		t.Error("Expected FitGlue upload to be recognised by its external_id")
+	}
+}
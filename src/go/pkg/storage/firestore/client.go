@@ -41,6 +41,14 @@ func (c *Client) PendingInputs() *Collection[pb.PendingInput] {
 	}
 }
 
+func (c *Client) PendingUploads() *Collection[pb.PendingUpload] {
+	return &Collection[pb.PendingUpload]{
+		Ref:           c.fs.Collection("pending_uploads"),
+		ToFirestore:   PendingUploadToFirestore,
+		FromFirestore: FirestoreToPendingUpload,
+	}
+}
+
 // Counters are sub-collections of Users: users/{uid}/counters/{id}
 func (c *Client) Counters(userId string) *Collection[pb.Counter] {
 	return &Collection[pb.Counter]{
@@ -58,3 +66,17 @@ func (c *Client) Activities(userId string) *Collection[pb.SynchronizedActivity]
 		FromFirestore: FirestoreToSynchronizedActivity,
 	}
 }
+
+// ProcessedActivities are sub-collections of Users: users/{uid}/raw_activities/{source}_{externalId}
+func (c *Client) ProcessedActivities(userId string) *Collection[pb.ProcessedActivityRecord] {
+	return &Collection[pb.ProcessedActivityRecord]{
+		Ref:           c.fs.Collection("users").Doc(userId).Collection("raw_activities"),
+		ToFirestore:   ProcessedActivityToFirestore,
+		FromFirestore: FirestoreToProcessedActivity,
+	}
+}
+
+// ProcessedActivityID scopes a source's external ID, matching the TypeScript connectors
+func ProcessedActivityID(source, externalId string) string {
+	return source + "_" + externalId
+}
@@ -46,6 +46,19 @@ func getTime(m map[string]interface{}, key string) *timestamppb.Timestamp {
 	return nil
 }
 
+// Helper to safely get an integer from map (Firestore returns int64; float64 for legacy docs)
+func getInt64(m map[string]interface{}, key string) int64 {
+	switch n := m[key].(type) {
+	case int64:
+		return n
+	case int:
+		return int64(n)
+	case float64:
+		return int64(n)
+	}
+	return 0
+}
+
 // --- UserRecord Converters ---
 
 func UserToFirestore(u *pb.UserRecord) map[string]interface{} {
@@ -81,6 +94,20 @@ func UserToFirestore(u *pb.UserRecord) map[string]interface{} {
 				"athlete_id":    u.Integrations.Strava.AthleteId,
 			}
 		}
+		if u.Integrations.Webhook != nil {
+			integrations["webhook"] = map[string]interface{}{
+				"enabled":          u.Integrations.Webhook.Enabled,
+				"url":              u.Integrations.Webhook.Url,
+				"include_fit_file": u.Integrations.Webhook.IncludeFitFile,
+			}
+		}
+		if u.Integrations.IntervalsIcu != nil {
+			integrations["intervals_icu"] = map[string]interface{}{
+				"enabled":    u.Integrations.IntervalsIcu.Enabled,
+				"athlete_id": u.Integrations.IntervalsIcu.AthleteId,
+				"api_key":    u.Integrations.IntervalsIcu.ApiKey,
+			}
+		}
 		m["integrations"] = integrations
 	}
 
@@ -88,6 +115,32 @@ func UserToFirestore(u *pb.UserRecord) map[string]interface{} {
 		m["fcm_tokens"] = u.FcmTokens
 	}
 
+	// Billing/tier fields are owned by the TypeScript services; only write them when set
+	if u.Tier != "" {
+		m["tier"] = u.Tier
+	}
+	if u.TrialEndsAt != nil {
+		m["trial_ends_at"] = u.TrialEndsAt.AsTime()
+	}
+	if u.IsAdmin {
+		m["is_admin"] = true
+	}
+	if u.SyncCountThisMonth != 0 {
+		m["sync_count_this_month"] = u.SyncCountThisMonth
+	}
+	if u.SyncCountResetAt != nil {
+		m["sync_count_reset_at"] = u.SyncCountResetAt.AsTime()
+	}
+	if u.StripeCustomerId != "" {
+		m["stripe_customer_id"] = u.StripeCustomerId
+	}
+	if u.Timezone != "" {
+		m["timezone"] = u.Timezone
+	}
+	if u.SourceChangePolicy != pb.SourceChangePolicy_SOURCE_CHANGE_POLICY_UNSPECIFIED {
+		m["source_change_policy"] = int32(u.SourceChangePolicy)
+	}
+
 	if len(u.Pipelines) > 0 {
 		pipelines := make([]map[string]interface{}, len(u.Pipelines))
 		for i, p := range u.Pipelines {
@@ -104,6 +157,29 @@ func UserToFirestore(u *pb.UserRecord) map[string]interface{} {
 				"destinations": p.Destinations,
 				"enrichers":    enrichers,
 			}
+			if len(p.DestinationOptions) > 0 {
+				destOptions := make(map[string]interface{}, len(p.DestinationOptions))
+				for name, o := range p.DestinationOptions {
+					oMap := map[string]interface{}{
+						"duplicate_policy": int32(o.GetDuplicatePolicy()),
+						"gear_id":          o.GetGearId(),
+						"mode":             int32(o.GetMode()),
+						"visibility":       int32(o.GetVisibility()),
+					}
+					// Optional flags are only stored when set
+					if o.Trainer != nil {
+						oMap["trainer"] = *o.Trainer
+					}
+					if o.Commute != nil {
+						oMap["commute"] = *o.Commute
+					}
+					if o.HideFromHome != nil {
+						oMap["hide_from_home"] = *o.HideFromHome
+					}
+					destOptions[name] = oMap
+				}
+				pipelines[i]["destination_options"] = destOptions
+			}
 		}
 		m["pipelines"] = pipelines
 	}
@@ -113,8 +189,16 @@ func UserToFirestore(u *pb.UserRecord) map[string]interface{} {
 
 func FirestoreToUser(m map[string]interface{}) *pb.UserRecord {
 	u := &pb.UserRecord{
-		UserId:    getString(m, "user_id"),
-		CreatedAt: getTime(m, "created_at"),
+		UserId:             getString(m, "user_id"),
+		CreatedAt:          getTime(m, "created_at"),
+		Tier:               getString(m, "tier"),
+		TrialEndsAt:        getTime(m, "trial_ends_at"),
+		IsAdmin:            getBool(m, "is_admin"),
+		SyncCountThisMonth: int32(getInt64(m, "sync_count_this_month")),
+		SyncCountResetAt:   getTime(m, "sync_count_reset_at"),
+		StripeCustomerId:   getString(m, "stripe_customer_id"),
+		Timezone:           getString(m, "timezone"),
+		SourceChangePolicy: pb.SourceChangePolicy(getInt64(m, "source_change_policy")),
 	}
 
 	if iMap, ok := m["integrations"].(map[string]interface{}); ok {
@@ -155,6 +239,20 @@ func FirestoreToUser(m map[string]interface{}) *pb.UserRecord {
 				}
 			}
 		}
+		if wMap, ok := iMap["webhook"].(map[string]interface{}); ok {
+			u.Integrations.Webhook = &pb.WebhookIntegration{
+				Enabled:        getBool(wMap, "enabled"),
+				Url:            getString(wMap, "url"),
+				IncludeFitFile: getBool(wMap, "include_fit_file"),
+			}
+		}
+		if iiMap, ok := iMap["intervals_icu"].(map[string]interface{}); ok {
+			u.Integrations.IntervalsIcu = &pb.IntervalsIcuIntegration{
+				Enabled:   getBool(iiMap, "enabled"),
+				AthleteId: getString(iiMap, "athlete_id"),
+				ApiKey:    getString(iiMap, "api_key"),
+			}
+		}
 	}
 
 	if tokens, ok := m["fcm_tokens"].([]interface{}); ok {
@@ -225,6 +323,12 @@ func FirestoreToUser(m map[string]interface{}) *pb.UserRecord {
 							switch val {
 							case "strava", "DESTINATION_STRAVA":
 								dests = append(dests, pb.Destination_DESTINATION_STRAVA)
+							case "webhook", "DESTINATION_WEBHOOK":
+								dests = append(dests, pb.Destination_DESTINATION_WEBHOOK)
+							case "intervals_icu", "DESTINATION_INTERVALS_ICU":
+								dests = append(dests, pb.Destination_DESTINATION_INTERVALS_ICU)
+							case "hevy", "DESTINATION_HEVY":
+								dests = append(dests, pb.Destination_DESTINATION_HEVY)
 							case "mock", "DESTINATION_MOCK":
 								dests = append(dests, pb.Destination_DESTINATION_MOCK)
 							}
@@ -232,11 +336,38 @@ func FirestoreToUser(m map[string]interface{}) *pb.UserRecord {
 					}
 				}
 
+				// Destination options, keyed by destination name
+				var destOptions map[string]*pb.DestinationOptions
+				if oMap, ok := pMap["destination_options"].(map[string]interface{}); ok {
+					destOptions = make(map[string]*pb.DestinationOptions, len(oMap))
+					for name, oRaw := range oMap {
+						if o, ok := oRaw.(map[string]interface{}); ok {
+							opts := &pb.DestinationOptions{
+								DuplicatePolicy: pb.DuplicatePolicy(getInt64(o, "duplicate_policy")),
+								GearId:          getString(o, "gear_id"),
+								Mode:            pb.DestinationMode(getInt64(o, "mode")),
+								Visibility:      pb.ActivityVisibility(getInt64(o, "visibility")),
+							}
+							if v, ok := o["trainer"].(bool); ok {
+								opts.Trainer = &v
+							}
+							if v, ok := o["commute"].(bool); ok {
+								opts.Commute = &v
+							}
+							if v, ok := o["hide_from_home"].(bool); ok {
+								opts.HideFromHome = &v
+							}
+							destOptions[name] = opts
+						}
+					}
+				}
+
 				u.Pipelines[i] = &pb.PipelineConfig{
-					Id:           getString(pMap, "id"),
-					Source:       getString(pMap, "source"),
-					Enrichers:    enrichers,
-					Destinations: dests,
+					Id:                 getString(pMap, "id"),
+					Source:             getString(pMap, "source"),
+					Enrichers:          enrichers,
+					Destinations:       dests,
+					DestinationOptions: destOptions,
 				}
 			}
 		}
@@ -309,13 +440,29 @@ func CounterToFirestore(c *pb.Counter) map[string]interface{} {
 		"id":           c.Id,
 		"count":        c.Count,
 		"last_updated": c.LastUpdated.AsTime(),
+		"period":       c.Period,
+	}
+}
+
+// Counter assignments record the value a counter gave an activity:
+// users/{uid}/counters/{id}/assignments/{activityId}
+
+func CounterAssignmentToFirestore(value int64) map[string]interface{} {
+	return map[string]interface{}{
+		"value":       value,
+		"assigned_at": time.Now(),
 	}
 }
 
+func FirestoreToCounterAssignment(m map[string]interface{}) int64 {
+	return getInt64(m, "value")
+}
+
 func FirestoreToCounter(m map[string]interface{}) *pb.Counter {
 	c := &pb.Counter{
 		Id:          getString(m, "id"),
 		LastUpdated: getTime(m, "last_updated"),
+		Period:      getString(m, "period"),
 	}
 	// Handle number types
 	if v, ok := m["count"]; ok {
@@ -420,6 +567,63 @@ func FirestoreToPendingInput(m map[string]interface{}) *pb.PendingInput {
 	return p
 }
 
+// --- PendingUpload Converters ---
+
+func PendingUploadToFirestore(p *pb.PendingUpload) map[string]interface{} {
+	m := map[string]interface{}{
+		"id":                    p.Id,
+		"user_id":               p.UserId,
+		"destination":           p.Destination,
+		"upload_id":             p.UploadId,
+		"status":                int32(p.Status),
+		"external_id":           p.ExternalId,
+		"error":                 p.Error,
+		"attempts":              p.Attempts,
+		"execution_id":          p.ExecutionId,
+		"pipeline_execution_id": p.PipelineExecutionId,
+		"created_at":            p.CreatedAt.AsTime(),
+		"updated_at":            p.UpdatedAt.AsTime(),
+	}
+	if p.CompletedAt != nil {
+		m["completed_at"] = p.CompletedAt.AsTime()
+	}
+
+	// Serialize original_event to JSON string, as for PendingInput.original_payload
+	if p.OriginalEvent != nil {
+		jsonBytes, err := protojson.Marshal(p.OriginalEvent)
+		if err == nil {
+			m["original_event"] = string(jsonBytes)
+		}
+	}
+	return m
+}
+
+func FirestoreToPendingUpload(m map[string]interface{}) *pb.PendingUpload {
+	p := &pb.PendingUpload{
+		Id:                  getString(m, "id"),
+		UserId:              getString(m, "user_id"),
+		Destination:         getString(m, "destination"),
+		UploadId:            getString(m, "upload_id"),
+		Status:              pb.PendingUpload_Status(getInt64(m, "status")),
+		ExternalId:          getString(m, "external_id"),
+		Error:               getString(m, "error"),
+		Attempts:            int32(getInt64(m, "attempts")),
+		ExecutionId:         getString(m, "execution_id"),
+		PipelineExecutionId: getString(m, "pipeline_execution_id"),
+		CreatedAt:           getTime(m, "created_at"),
+		UpdatedAt:           getTime(m, "updated_at"),
+		CompletedAt:         getTime(m, "completed_at"),
+	}
+
+	if jsonStr := getString(m, "original_event"); jsonStr != "" {
+		var ev pb.EnrichedActivityEvent
+		if err := protojson.Unmarshal([]byte(jsonStr), &ev); err == nil {
+			p.OriginalEvent = &ev
+		}
+	}
+	return p
+}
+
 // --- SynchronizedActivity Converters ---
 
 func SynchronizedActivityToFirestore(s *pb.SynchronizedActivity) map[string]interface{} {
@@ -433,11 +637,19 @@ func SynchronizedActivityToFirestore(s *pb.SynchronizedActivity) map[string]inte
 		"synced_at":             s.SyncedAt.AsTime(),
 		"pipeline_id":           s.PipelineId,
 		"pipeline_execution_id": s.PipelineExecutionId,
+		"external_id":           s.ExternalId,
+		"source_type":           int32(s.SourceType),
 	}
 
 	if s.Destinations != nil {
 		m["destinations"] = s.Destinations
 	}
+	if s.SourceTitle != nil {
+		m["source_title"] = *s.SourceTitle
+	}
+	if s.SourceDescription != nil {
+		m["source_description"] = *s.SourceDescription
+	}
 
 	return m
 }
@@ -452,6 +664,14 @@ func FirestoreToSynchronizedActivity(m map[string]interface{}) *pb.SynchronizedA
 		SyncedAt:            getTime(m, "synced_at"),
 		PipelineId:          getString(m, "pipeline_id"),
 		PipelineExecutionId: getString(m, "pipeline_execution_id"),
+		ExternalId:          getString(m, "external_id"),
+		SourceType:          pb.ActivityType(getInt64(m, "source_type")),
+	}
+	if v, ok := m["source_title"].(string); ok {
+		s.SourceTitle = &v
+	}
+	if v, ok := m["source_description"].(string); ok {
+		s.SourceDescription = &v
 	}
 
 	if v, ok := m["type"]; ok {
@@ -480,3 +700,35 @@ func FirestoreToSynchronizedActivity(m map[string]interface{}) *pb.SynchronizedA
 
 	return s
 }
+
+// --- ProcessedActivityRecord Converters ---
+
+func ProcessedActivityToFirestore(p *pb.ProcessedActivityRecord) map[string]interface{} {
+	return map[string]interface{}{
+		"source":       p.Source,
+		"external_id":  p.ExternalId,
+		"processed_at": p.ProcessedAt.AsTime(),
+	}
+}
+
+func FirestoreToProcessedActivity(m map[string]interface{}) *pb.ProcessedActivityRecord {
+	return &pb.ProcessedActivityRecord{
+		Source:      getString(m, "source"),
+		ExternalId:  getString(m, "external_id"),
+		ProcessedAt: getTime(m, "processed_at"),
+	}
+}
+
+// Integration identities map a provider's user ID to a FitGlue user:
+// integrations/{provider}/ids/{externalId}
+
+func IntegrationIdentityToFirestore(userId string) map[string]interface{} {
+	return map[string]interface{}{
+		"user_id":    userId,
+		"created_at": time.Now(),
+	}
+}
+
+func FirestoreToIntegrationIdentity(m map[string]interface{}) string {
+	return getString(m, "user_id")
+}
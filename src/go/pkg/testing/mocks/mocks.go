@@ -1,8 +1,10 @@
 package mocks
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 
 	"github.com/cloudevents/sdk-go/v2/event"
 	pb "github.com/ripixel/fitglue-server/src/go/pkg/types/pb"
@@ -20,9 +22,21 @@ type MockDatabase struct {
 	UpdatePendingInputFunc func(ctx context.Context, id string, data map[string]interface{}) error
 	ListPendingInputsFunc  func(ctx context.Context, userID string) ([]*pb.PendingInput, error)
 
-	GetCounterFunc              func(ctx context.Context, userId string, id string) (*pb.Counter, error)
-	SetCounterFunc              func(ctx context.Context, userId string, counter *pb.Counter) error
-	SetSynchronizedActivityFunc func(ctx context.Context, userId string, activity *pb.SynchronizedActivity) error
+	GetPendingUploadFunc    func(ctx context.Context, id string) (*pb.PendingUpload, error)
+	CreatePendingUploadFunc func(ctx context.Context, upload *pb.PendingUpload) error
+	UpdatePendingUploadFunc func(ctx context.Context, id string, data map[string]interface{}) error
+
+	GetCounterFunc                 func(ctx context.Context, userId string, id string) (*pb.Counter, error)
+	SetCounterFunc                 func(ctx context.Context, userId string, counter *pb.Counter) error
+	IncrementCounterFunc           func(ctx context.Context, userId string, id string, period string, activityId string, initial int64) (int64, error)
+	SetSynchronizedActivityFunc    func(ctx context.Context, userId string, activity *pb.SynchronizedActivity) error
+	UpdateSynchronizedActivityFunc func(ctx context.Context, userId string, id string, data map[string]interface{}) error
+	FindSynchronizedActivitiesFunc func(ctx context.Context, userId string, source string, externalId string) ([]*pb.SynchronizedActivity, error)
+	DestinationExistsFunc          func(ctx context.Context, userId string, destination string, externalId string) (bool, error)
+
+	HasProcessedActivityFunc  func(ctx context.Context, userId string, source string, externalId string) (bool, error)
+	MarkActivityProcessedFunc func(ctx context.Context, userId string, record *pb.ProcessedActivityRecord) error
+	FindUserByExternalIDFunc  func(ctx context.Context, provider string, externalId string) (string, error)
 }
 
 func (m *MockDatabase) SetExecution(ctx context.Context, record *pb.ExecutionRecord) error {
@@ -78,6 +92,27 @@ func (m *MockDatabase) ListPendingInputs(ctx context.Context, userID string) ([]
 	return nil, nil
 }
 
+func (m *MockDatabase) GetPendingUpload(ctx context.Context, id string) (*pb.PendingUpload, error) {
+	if m.GetPendingUploadFunc != nil {
+		return m.GetPendingUploadFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockDatabase) CreatePendingUpload(ctx context.Context, upload *pb.PendingUpload) error {
+	if m.CreatePendingUploadFunc != nil {
+		return m.CreatePendingUploadFunc(ctx, upload)
+	}
+	return nil
+}
+
+func (m *MockDatabase) UpdatePendingUpload(ctx context.Context, id string, data map[string]interface{}) error {
+	if m.UpdatePendingUploadFunc != nil {
+		return m.UpdatePendingUploadFunc(ctx, id, data)
+	}
+	return nil
+}
+
 func (m *MockDatabase) GetCounter(ctx context.Context, userId string, id string) (*pb.Counter, error) {
 	if m.GetCounterFunc != nil {
 		return m.GetCounterFunc(ctx, userId, id)
@@ -92,6 +127,13 @@ func (m *MockDatabase) SetCounter(ctx context.Context, userId string, counter *p
 	return nil
 }
 
+func (m *MockDatabase) IncrementCounter(ctx context.Context, userId string, id string, period string, activityId string, initial int64) (int64, error) {
+	if m.IncrementCounterFunc != nil {
+		return m.IncrementCounterFunc(ctx, userId, id, period, activityId, initial)
+	}
+	return initial, nil
+}
+
 func (m *MockDatabase) SetSynchronizedActivity(ctx context.Context, userId string, activity *pb.SynchronizedActivity) error {
 	if m.SetSynchronizedActivityFunc != nil {
 		return m.SetSynchronizedActivityFunc(ctx, userId, activity)
@@ -99,6 +141,48 @@ func (m *MockDatabase) SetSynchronizedActivity(ctx context.Context, userId strin
 	return nil
 }
 
+func (m *MockDatabase) UpdateSynchronizedActivity(ctx context.Context, userId string, id string, data map[string]interface{}) error {
+	if m.UpdateSynchronizedActivityFunc != nil {
+		return m.UpdateSynchronizedActivityFunc(ctx, userId, id, data)
+	}
+	return nil
+}
+
+func (m *MockDatabase) FindSynchronizedActivities(ctx context.Context, userId string, source string, externalId string) ([]*pb.SynchronizedActivity, error) {
+	if m.FindSynchronizedActivitiesFunc != nil {
+		return m.FindSynchronizedActivitiesFunc(ctx, userId, source, externalId)
+	}
+	return nil, nil
+}
+
+func (m *MockDatabase) DestinationExists(ctx context.Context, userId string, destination string, externalId string) (bool, error) {
+	if m.DestinationExistsFunc != nil {
+		return m.DestinationExistsFunc(ctx, userId, destination, externalId)
+	}
+	return false, nil
+}
+
+func (m *MockDatabase) HasProcessedActivity(ctx context.Context, userId string, source string, externalId string) (bool, error) {
+	if m.HasProcessedActivityFunc != nil {
+		return m.HasProcessedActivityFunc(ctx, userId, source, externalId)
+	}
+	return false, nil
+}
+
+func (m *MockDatabase) MarkActivityProcessed(ctx context.Context, userId string, record *pb.ProcessedActivityRecord) error {
+	if m.MarkActivityProcessedFunc != nil {
+		return m.MarkActivityProcessedFunc(ctx, userId, record)
+	}
+	return nil
+}
+
+func (m *MockDatabase) FindUserByExternalID(ctx context.Context, provider string, externalId string) (string, error) {
+	if m.FindUserByExternalIDFunc != nil {
+		return m.FindUserByExternalIDFunc(ctx, provider, externalId)
+	}
+	return "", nil
+}
+
 // --- Sync Count (for tier limits) ---
 
 func (m *MockDatabase) IncrementSyncCount(ctx context.Context, userID string) error {
@@ -125,8 +209,12 @@ func (m *MockPublisher) PublishCloudEvent(ctx context.Context, topic string, e e
 
 // --- Mock Storage ---
 type MockBlobStore struct {
-	WriteFunc func(ctx context.Context, bucket, object string, data []byte) error
-	ReadFunc  func(ctx context.Context, bucket, object string) ([]byte, error)
+	WriteFunc     func(ctx context.Context, bucket, object string, data []byte) error
+	ReadFunc      func(ctx context.Context, bucket, object string) ([]byte, error)
+	NewWriterFunc func(ctx context.Context, bucket, object string) (io.WriteCloser, error)
+	ListFunc      func(ctx context.Context, bucket, prefix string) ([]string, error)
+	DeleteFunc    func(ctx context.Context, bucket, object string) error
+	ExistsFunc    func(ctx context.Context, bucket, object string) (bool, error)
 }
 
 func (m *MockBlobStore) Write(ctx context.Context, bucket, object string, data []byte) error {
@@ -135,6 +223,26 @@ func (m *MockBlobStore) Write(ctx context.Context, bucket, object string, data [
 	}
 	return nil
 }
+func (m *MockBlobStore) NewWriter(ctx context.Context, bucket, object string) (io.WriteCloser, error) {
+	if m.NewWriterFunc != nil {
+		return m.NewWriterFunc(ctx, bucket, object)
+	}
+	return &mockBlobWriter{store: m, ctx: ctx, bucket: bucket, object: object}, nil
+}
+
+// mockBlobWriter buffers written data and hands it to Write on Close
+type mockBlobWriter struct {
+	store          *MockBlobStore
+	ctx            context.Context
+	bucket, object string
+	buf            bytes.Buffer
+}
+
+func (w *mockBlobWriter) Write(p []byte) (int, error) { return w.buf.Write(p) }
+func (w *mockBlobWriter) Close() error {
+	return w.store.Write(w.ctx, w.bucket, w.object, w.buf.Bytes())
+}
+
 func (m *MockBlobStore) Read(ctx context.Context, bucket, object string) ([]byte, error) {
 	if m.ReadFunc != nil {
 		return m.ReadFunc(ctx, bucket, object)
@@ -142,6 +250,27 @@ func (m *MockBlobStore) Read(ctx context.Context, bucket, object string) ([]byte
 	return []byte("mock-data"), nil
 }
 
+func (m *MockBlobStore) List(ctx context.Context, bucket, prefix string) ([]string, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx, bucket, prefix)
+	}
+	return nil, nil
+}
+
+func (m *MockBlobStore) Delete(ctx context.Context, bucket, object string) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, bucket, object)
+	}
+	return nil
+}
+
+func (m *MockBlobStore) Exists(ctx context.Context, bucket, object string) (bool, error) {
+	if m.ExistsFunc != nil {
+		return m.ExistsFunc(ctx, bucket, object)
+	}
+	return true, nil
+}
+
 // --- Mock Secrets ---
 type MockSecretStore struct {
 	GetSecretFunc func(ctx context.Context, projectID, name string) (string, error)
@@ -29,6 +29,7 @@ const (
 	ActivitySource_SOURCE_UNKNOWN ActivitySource = 0
 	ActivitySource_SOURCE_HEVY    ActivitySource = 1
 	ActivitySource_SOURCE_FITBIT  ActivitySource = 3
+	ActivitySource_SOURCE_STRAVA  ActivitySource = 4
 	ActivitySource_SOURCE_TEST    ActivitySource = 99
 )
 
@@ -38,12 +39,14 @@ var (
 		0:  "SOURCE_UNKNOWN",
 		1:  "SOURCE_HEVY",
 		3:  "SOURCE_FITBIT",
+		4:  "SOURCE_STRAVA",
 		99: "SOURCE_TEST",
 	}
 	ActivitySource_value = map[string]int32{
 		"SOURCE_UNKNOWN": 0,
 		"SOURCE_HEVY":    1,
 		"SOURCE_FITBIT":  3,
+		"SOURCE_STRAVA":  4,
 		"SOURCE_TEST":    99,
 	}
 )
@@ -75,6 +78,56 @@ func (ActivitySource) EnumDescriptor() ([]byte, []int) {
 	return file_activity_proto_rawDescGZIP(), []int{0}
 }
 
+// SourceChangeType is what happened to an activity at its source
+type SourceChangeType int32
+
+const (
+	SourceChangeType_SOURCE_CHANGE_TYPE_UNSPECIFIED SourceChangeType = 0
+	SourceChangeType_SOURCE_CHANGE_TYPE_UPDATED     SourceChangeType = 1
+	SourceChangeType_SOURCE_CHANGE_TYPE_DELETED     SourceChangeType = 2
+)
+
+// Enum value maps for SourceChangeType.
+var (
+	SourceChangeType_name = map[int32]string{
+		0: "SOURCE_CHANGE_TYPE_UNSPECIFIED",
+		1: "SOURCE_CHANGE_TYPE_UPDATED",
+		2: "SOURCE_CHANGE_TYPE_DELETED",
+	}
+	SourceChangeType_value = map[string]int32{
+		"SOURCE_CHANGE_TYPE_UNSPECIFIED": 0,
+		"SOURCE_CHANGE_TYPE_UPDATED":     1,
+		"SOURCE_CHANGE_TYPE_DELETED":     2,
+	}
+)
+
+func (x SourceChangeType) Enum() *SourceChangeType {
+	p := new(SourceChangeType)
+	*p = x
+	return p
+}
+
+func (x SourceChangeType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SourceChangeType) Descriptor() protoreflect.EnumDescriptor {
+	return file_activity_proto_enumTypes[1].Descriptor()
+}
+
+func (SourceChangeType) Type() protoreflect.EnumType {
+	return &file_activity_proto_enumTypes[1]
+}
+
+func (x SourceChangeType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SourceChangeType.Descriptor instead.
+func (SourceChangeType) EnumDescriptor() ([]byte, []int) {
+	return file_activity_proto_rawDescGZIP(), []int{1}
+}
+
 // ActivityPayload is the unified message format published to Pub/Sub.
 type ActivityPayload struct {
 	state                protoimpl.MessageState `protogen:"open.v1"`
@@ -86,8 +139,12 @@ type ActivityPayload struct {
 	StandardizedActivity *StandardizedActivity  `protobuf:"bytes,6,opt,name=standardized_activity,json=standardizedActivity,proto3" json:"standardized_activity,omitempty"`
 	// Execution tracing
 	PipelineExecutionId *string `protobuf:"bytes,7,opt,name=pipeline_execution_id,json=pipelineExecutionId,proto3,oneof" json:"pipeline_execution_id,omitempty"`
-	unknownFields       protoimpl.UnknownFields
-	sizeCache           protoimpl.SizeCache
+	// Set when the pipeline is re-run for an activity that was already synchronized (a source
+	// update under SOURCE_CHANGE_POLICY_FULL_RESYNC): uploaders update the existing destination
+	// activities instead of creating new ones
+	Resync        bool `protobuf:"varint,8,opt,name=resync,proto3" json:"resync,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ActivityPayload) Reset() {
@@ -169,11 +226,107 @@ func (x *ActivityPayload) GetPipelineExecutionId() string {
 	return ""
 }
 
+func (x *ActivityPayload) GetResync() bool {
+	if x != nil {
+		return x.Resync
+	}
+	return false
+}
+
+// SourceActivityChange reports an update or deletion of an already-ingested activity at its
+// source, so it can be propagated to the destinations it was synchronized to.
+// Event payload for CLOUD_EVENT_TYPE_SOURCE_ACTIVITY_CHANGED
+type SourceActivityChange struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	UserId     string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Source     ActivitySource         `protobuf:"varint,2,opt,name=source,proto3,enum=fitglue.ActivitySource" json:"source,omitempty"`
+	ExternalId string                 `protobuf:"bytes,3,opt,name=external_id,json=externalId,proto3" json:"external_id,omitempty"` // Source activity ID (StandardizedActivity.external_id)
+	Change     SourceChangeType       `protobuf:"varint,4,opt,name=change,proto3,enum=fitglue.SourceChangeType" json:"change,omitempty"`
+	// The updated activity (SOURCE_CHANGE_TYPE_UPDATED only)
+	StandardizedActivity *StandardizedActivity `protobuf:"bytes,5,opt,name=standardized_activity,json=standardizedActivity,proto3" json:"standardized_activity,omitempty"`
+	// Execution tracing
+	PipelineExecutionId *string `protobuf:"bytes,6,opt,name=pipeline_execution_id,json=pipelineExecutionId,proto3,oneof" json:"pipeline_execution_id,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *SourceActivityChange) Reset() {
+	*x = SourceActivityChange{}
+	mi := &file_activity_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SourceActivityChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SourceActivityChange) ProtoMessage() {}
+
+func (x *SourceActivityChange) ProtoReflect() protoreflect.Message {
+	mi := &file_activity_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SourceActivityChange.ProtoReflect.Descriptor instead.
+func (*SourceActivityChange) Descriptor() ([]byte, []int) {
+	return file_activity_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SourceActivityChange) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SourceActivityChange) GetSource() ActivitySource {
+	if x != nil {
+		return x.Source
+	}
+	return ActivitySource_SOURCE_UNKNOWN
+}
+
+func (x *SourceActivityChange) GetExternalId() string {
+	if x != nil {
+		return x.ExternalId
+	}
+	return ""
+}
+
+func (x *SourceActivityChange) GetChange() SourceChangeType {
+	if x != nil {
+		return x.Change
+	}
+	return SourceChangeType_SOURCE_CHANGE_TYPE_UNSPECIFIED
+}
+
+func (x *SourceActivityChange) GetStandardizedActivity() *StandardizedActivity {
+	if x != nil {
+		return x.StandardizedActivity
+	}
+	return nil
+}
+
+func (x *SourceActivityChange) GetPipelineExecutionId() string {
+	if x != nil && x.PipelineExecutionId != nil {
+		return *x.PipelineExecutionId
+	}
+	return ""
+}
+
 var File_activity_proto protoreflect.FileDescriptor
 
 const file_activity_proto_rawDesc = "" +
 	"\n" +
-	"\x0eactivity.proto\x12\afitglue\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1bstandardized_activity.proto\"\xf1\x03\n" +
+	"\x0eactivity.proto\x12\afitglue\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1bstandardized_activity.proto\"\x89\x04\n" +
 	"\x0fActivityPayload\x12/\n" +
 	"\x06source\x18\x01 \x01(\x0e2\x17.fitglue.ActivitySourceR\x06source\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x128\n" +
@@ -181,16 +334,31 @@ const file_activity_proto_rawDesc = "" +
 	"\x15original_payload_json\x18\x04 \x01(\tR\x13originalPayloadJson\x12B\n" +
 	"\bmetadata\x18\x05 \x03(\v2&.fitglue.ActivityPayload.MetadataEntryR\bmetadata\x12R\n" +
 	"\x15standardized_activity\x18\x06 \x01(\v2\x1d.fitglue.StandardizedActivityR\x14standardizedActivity\x127\n" +
-	"\x15pipeline_execution_id\x18\a \x01(\tH\x00R\x13pipelineExecutionId\x88\x01\x01\x1a;\n" +
+	"\x15pipeline_execution_id\x18\a \x01(\tH\x00R\x13pipelineExecutionId\x88\x01\x01\x12\x16\n" +
+	"\x06resync\x18\b \x01(\bR\x06resync\x1a;\n" +
 	"\rMetadataEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\x18\n" +
-	"\x16_pipeline_execution_id*Y\n" +
+	"\x16_pipeline_execution_id\"\xdb\x02\n" +
+	"\x14SourceActivityChange\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12/\n" +
+	"\x06source\x18\x02 \x01(\x0e2\x17.fitglue.ActivitySourceR\x06source\x12\x1f\n" +
+	"\vexternal_id\x18\x03 \x01(\tR\n" +
+	"externalId\x121\n" +
+	"\x06change\x18\x04 \x01(\x0e2\x19.fitglue.SourceChangeTypeR\x06change\x12R\n" +
+	"\x15standardized_activity\x18\x05 \x01(\v2\x1d.fitglue.StandardizedActivityR\x14standardizedActivity\x127\n" +
+	"\x15pipeline_execution_id\x18\x06 \x01(\tH\x00R\x13pipelineExecutionId\x88\x01\x01B\x18\n" +
+	"\x16_pipeline_execution_id*l\n" +
 	"\x0eActivitySource\x12\x12\n" +
 	"\x0eSOURCE_UNKNOWN\x10\x00\x12\x0f\n" +
 	"\vSOURCE_HEVY\x10\x01\x12\x11\n" +
-	"\rSOURCE_FITBIT\x10\x03\x12\x0f\n" +
-	"\vSOURCE_TEST\x10cB7Z5github.com/ripixel/fitglue-server/src/go/pkg/types/pbb\x06proto3"
+	"\rSOURCE_FITBIT\x10\x03\x12\x11\n" +
+	"\rSOURCE_STRAVA\x10\x04\x12\x0f\n" +
+	"\vSOURCE_TEST\x10c*v\n" +
+	"\x10SourceChangeType\x12\"\n" +
+	"\x1eSOURCE_CHANGE_TYPE_UNSPECIFIED\x10\x00\x12\x1e\n" +
+	"\x1aSOURCE_CHANGE_TYPE_UPDATED\x10\x01\x12\x1e\n" +
+	"\x1aSOURCE_CHANGE_TYPE_DELETED\x10\x02B7Z5github.com/ripixel/fitglue-server/src/go/pkg/types/pbb\x06proto3"
 
 var (
 	file_activity_proto_rawDescOnce sync.Once
@@ -204,25 +372,30 @@ func file_activity_proto_rawDescGZIP() []byte {
 	return file_activity_proto_rawDescData
 }
 
-var file_activity_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_activity_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_activity_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_activity_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
 var file_activity_proto_goTypes = []any{
 	(ActivitySource)(0),          // 0: fitglue.ActivitySource
-	(*ActivityPayload)(nil),      // 1: fitglue.ActivityPayload
-	nil,                          // 2: fitglue.ActivityPayload.MetadataEntry
-	(*timestamp.Timestamp)(nil),  // 3: google.protobuf.Timestamp
-	(*StandardizedActivity)(nil), // 4: fitglue.StandardizedActivity
+	(SourceChangeType)(0),        // 1: fitglue.SourceChangeType
+	(*ActivityPayload)(nil),      // 2: fitglue.ActivityPayload
+	(*SourceActivityChange)(nil), // 3: fitglue.SourceActivityChange
+	nil,                          // 4: fitglue.ActivityPayload.MetadataEntry
+	(*timestamp.Timestamp)(nil),  // 5: google.protobuf.Timestamp
+	(*StandardizedActivity)(nil), // 6: fitglue.StandardizedActivity
 }
 var file_activity_proto_depIdxs = []int32{
 	0, // 0: fitglue.ActivityPayload.source:type_name -> fitglue.ActivitySource
-	3, // 1: fitglue.ActivityPayload.timestamp:type_name -> google.protobuf.Timestamp
-	2, // 2: fitglue.ActivityPayload.metadata:type_name -> fitglue.ActivityPayload.MetadataEntry
-	4, // 3: fitglue.ActivityPayload.standardized_activity:type_name -> fitglue.StandardizedActivity
-	4, // [4:4] is the sub-list for method output_type
-	4, // [4:4] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	5, // 1: fitglue.ActivityPayload.timestamp:type_name -> google.protobuf.Timestamp
+	4, // 2: fitglue.ActivityPayload.metadata:type_name -> fitglue.ActivityPayload.MetadataEntry
+	6, // 3: fitglue.ActivityPayload.standardized_activity:type_name -> fitglue.StandardizedActivity
+	0, // 4: fitglue.SourceActivityChange.source:type_name -> fitglue.ActivitySource
+	1, // 5: fitglue.SourceActivityChange.change:type_name -> fitglue.SourceChangeType
+	6, // 6: fitglue.SourceActivityChange.standardized_activity:type_name -> fitglue.StandardizedActivity
+	7, // [7:7] is the sub-list for method output_type
+	7, // [7:7] is the sub-list for method input_type
+	7, // [7:7] is the sub-list for extension type_name
+	7, // [7:7] is the sub-list for extension extendee
+	0, // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_activity_proto_init() }
@@ -232,13 +405,14 @@ func file_activity_proto_init() {
 	}
 	file_standardized_activity_proto_init()
 	file_activity_proto_msgTypes[0].OneofWrappers = []any{}
+	file_activity_proto_msgTypes[1].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_activity_proto_rawDesc), len(file_activity_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   2,
+			NumEnums:      2,
+			NumMessages:   3,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
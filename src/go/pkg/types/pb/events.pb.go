@@ -40,6 +40,12 @@ const (
 	CloudEventType_CLOUD_EVENT_TYPE_ENRICHMENT_LAG CloudEventType = 5
 	// Input Resolved: Payload is ActivityPayload (resumed)
 	CloudEventType_CLOUD_EVENT_TYPE_INPUT_RESOLVED CloudEventType = 6
+	// Upload Status Check: Payload is UploadStatusCheck
+	CloudEventType_CLOUD_EVENT_TYPE_UPLOAD_STATUS_CHECK CloudEventType = 7
+	// Strava Webhook: Payload is a Strava webhook event (raw JSON)
+	CloudEventType_CLOUD_EVENT_TYPE_STRAVA_WEBHOOK CloudEventType = 8
+	// Source Activity Changed: Payload is SourceActivityChange
+	CloudEventType_CLOUD_EVENT_TYPE_SOURCE_ACTIVITY_CHANGED CloudEventType = 9
 )
 
 // Enum value maps for CloudEventType.
@@ -52,15 +58,21 @@ var (
 		4: "CLOUD_EVENT_TYPE_FITBIT_NOTIFICATION",
 		5: "CLOUD_EVENT_TYPE_ENRICHMENT_LAG",
 		6: "CLOUD_EVENT_TYPE_INPUT_RESOLVED",
+		7: "CLOUD_EVENT_TYPE_UPLOAD_STATUS_CHECK",
+		8: "CLOUD_EVENT_TYPE_STRAVA_WEBHOOK",
+		9: "CLOUD_EVENT_TYPE_SOURCE_ACTIVITY_CHANGED",
 	}
 	CloudEventType_value = map[string]int32{
-		"CLOUD_EVENT_TYPE_UNSPECIFIED":         0,
-		"CLOUD_EVENT_TYPE_ACTIVITY_CREATED":    1,
-		"CLOUD_EVENT_TYPE_ACTIVITY_ENRICHED":   2,
-		"CLOUD_EVENT_TYPE_JOB_ROUTED":          3,
-		"CLOUD_EVENT_TYPE_FITBIT_NOTIFICATION": 4,
-		"CLOUD_EVENT_TYPE_ENRICHMENT_LAG":      5,
-		"CLOUD_EVENT_TYPE_INPUT_RESOLVED":      6,
+		"CLOUD_EVENT_TYPE_UNSPECIFIED":             0,
+		"CLOUD_EVENT_TYPE_ACTIVITY_CREATED":        1,
+		"CLOUD_EVENT_TYPE_ACTIVITY_ENRICHED":       2,
+		"CLOUD_EVENT_TYPE_JOB_ROUTED":              3,
+		"CLOUD_EVENT_TYPE_FITBIT_NOTIFICATION":     4,
+		"CLOUD_EVENT_TYPE_ENRICHMENT_LAG":          5,
+		"CLOUD_EVENT_TYPE_INPUT_RESOLVED":          6,
+		"CLOUD_EVENT_TYPE_UPLOAD_STATUS_CHECK":     7,
+		"CLOUD_EVENT_TYPE_STRAVA_WEBHOOK":          8,
+		"CLOUD_EVENT_TYPE_SOURCE_ACTIVITY_CHANGED": 9,
 	}
 )
 
@@ -95,14 +107,17 @@ func (CloudEventType) EnumDescriptor() ([]byte, []int) {
 type CloudEventSource int32
 
 const (
-	CloudEventSource_CLOUD_EVENT_SOURCE_UNSPECIFIED    CloudEventSource = 0
-	CloudEventSource_CLOUD_EVENT_SOURCE_HEVY           CloudEventSource = 1
-	CloudEventSource_CLOUD_EVENT_SOURCE_FITBIT_WEBHOOK CloudEventSource = 2
-	CloudEventSource_CLOUD_EVENT_SOURCE_FITBIT_INGEST  CloudEventSource = 3
-	CloudEventSource_CLOUD_EVENT_SOURCE_ENRICHER       CloudEventSource = 4
-	CloudEventSource_CLOUD_EVENT_SOURCE_ROUTER         CloudEventSource = 5
-	CloudEventSource_CLOUD_EVENT_SOURCE_INPUTS_HANDLER CloudEventSource = 6
-	CloudEventSource_CLOUD_EVENT_SOURCE_MOCK           CloudEventSource = 99
+	CloudEventSource_CLOUD_EVENT_SOURCE_UNSPECIFIED       CloudEventSource = 0
+	CloudEventSource_CLOUD_EVENT_SOURCE_HEVY              CloudEventSource = 1
+	CloudEventSource_CLOUD_EVENT_SOURCE_FITBIT_WEBHOOK    CloudEventSource = 2
+	CloudEventSource_CLOUD_EVENT_SOURCE_FITBIT_INGEST     CloudEventSource = 3
+	CloudEventSource_CLOUD_EVENT_SOURCE_ENRICHER          CloudEventSource = 4
+	CloudEventSource_CLOUD_EVENT_SOURCE_ROUTER            CloudEventSource = 5
+	CloudEventSource_CLOUD_EVENT_SOURCE_INPUTS_HANDLER    CloudEventSource = 6
+	CloudEventSource_CLOUD_EVENT_SOURCE_UPLOADER          CloudEventSource = 7
+	CloudEventSource_CLOUD_EVENT_SOURCE_STRAVA            CloudEventSource = 8
+	CloudEventSource_CLOUD_EVENT_SOURCE_CHANGE_PROPAGATOR CloudEventSource = 9
+	CloudEventSource_CLOUD_EVENT_SOURCE_MOCK              CloudEventSource = 99
 )
 
 // Enum value maps for CloudEventSource.
@@ -115,17 +130,23 @@ var (
 		4:  "CLOUD_EVENT_SOURCE_ENRICHER",
 		5:  "CLOUD_EVENT_SOURCE_ROUTER",
 		6:  "CLOUD_EVENT_SOURCE_INPUTS_HANDLER",
+		7:  "CLOUD_EVENT_SOURCE_UPLOADER",
+		8:  "CLOUD_EVENT_SOURCE_STRAVA",
+		9:  "CLOUD_EVENT_SOURCE_CHANGE_PROPAGATOR",
 		99: "CLOUD_EVENT_SOURCE_MOCK",
 	}
 	CloudEventSource_value = map[string]int32{
-		"CLOUD_EVENT_SOURCE_UNSPECIFIED":    0,
-		"CLOUD_EVENT_SOURCE_HEVY":           1,
-		"CLOUD_EVENT_SOURCE_FITBIT_WEBHOOK": 2,
-		"CLOUD_EVENT_SOURCE_FITBIT_INGEST":  3,
-		"CLOUD_EVENT_SOURCE_ENRICHER":       4,
-		"CLOUD_EVENT_SOURCE_ROUTER":         5,
-		"CLOUD_EVENT_SOURCE_INPUTS_HANDLER": 6,
-		"CLOUD_EVENT_SOURCE_MOCK":           99,
+		"CLOUD_EVENT_SOURCE_UNSPECIFIED":       0,
+		"CLOUD_EVENT_SOURCE_HEVY":              1,
+		"CLOUD_EVENT_SOURCE_FITBIT_WEBHOOK":    2,
+		"CLOUD_EVENT_SOURCE_FITBIT_INGEST":     3,
+		"CLOUD_EVENT_SOURCE_ENRICHER":          4,
+		"CLOUD_EVENT_SOURCE_ROUTER":            5,
+		"CLOUD_EVENT_SOURCE_INPUTS_HANDLER":    6,
+		"CLOUD_EVENT_SOURCE_UPLOADER":          7,
+		"CLOUD_EVENT_SOURCE_STRAVA":            8,
+		"CLOUD_EVENT_SOURCE_CHANGE_PROPAGATOR": 9,
+		"CLOUD_EVENT_SOURCE_MOCK":              99,
 	}
 )
 
@@ -160,9 +181,12 @@ func (CloudEventSource) EnumDescriptor() ([]byte, []int) {
 type Destination int32
 
 const (
-	Destination_DESTINATION_UNSPECIFIED Destination = 0
-	Destination_DESTINATION_STRAVA      Destination = 1
-	Destination_DESTINATION_MOCK        Destination = 99
+	Destination_DESTINATION_UNSPECIFIED   Destination = 0
+	Destination_DESTINATION_STRAVA        Destination = 1
+	Destination_DESTINATION_WEBHOOK       Destination = 2
+	Destination_DESTINATION_INTERVALS_ICU Destination = 3
+	Destination_DESTINATION_HEVY          Destination = 4
+	Destination_DESTINATION_MOCK          Destination = 99
 )
 
 // Enum value maps for Destination.
@@ -170,12 +194,18 @@ var (
 	Destination_name = map[int32]string{
 		0:  "DESTINATION_UNSPECIFIED",
 		1:  "DESTINATION_STRAVA",
+		2:  "DESTINATION_WEBHOOK",
+		3:  "DESTINATION_INTERVALS_ICU",
+		4:  "DESTINATION_HEVY",
 		99: "DESTINATION_MOCK",
 	}
 	Destination_value = map[string]int32{
-		"DESTINATION_UNSPECIFIED": 0,
-		"DESTINATION_STRAVA":      1,
-		"DESTINATION_MOCK":        99,
+		"DESTINATION_UNSPECIFIED":   0,
+		"DESTINATION_STRAVA":        1,
+		"DESTINATION_WEBHOOK":       2,
+		"DESTINATION_INTERVALS_ICU": 3,
+		"DESTINATION_HEVY":          4,
+		"DESTINATION_MOCK":          99,
 	}
 )
 
@@ -206,6 +236,269 @@ func (Destination) EnumDescriptor() ([]byte, []int) {
 	return file_events_proto_rawDescGZIP(), []int{2}
 }
 
+// DuplicatePolicy controls what an uploader does when the destination rejects an upload as a
+// duplicate of an activity it already has (e.g. the watch synced to Strava first).
+type DuplicatePolicy int32
+
+const (
+	DuplicatePolicy_DUPLICATE_POLICY_UNSPECIFIED DuplicatePolicy = 0 // Same as FAIL
+	DuplicatePolicy_DUPLICATE_POLICY_FAIL        DuplicatePolicy = 1 // Fail the upload
+	DuplicatePolicy_DUPLICATE_POLICY_LINK        DuplicatePolicy = 2 // Link the existing activity without changing it
+	DuplicatePolicy_DUPLICATE_POLICY_UPDATE      DuplicatePolicy = 3 // Update the existing activity's name, description, type and gear, then link it
+)
+
+// Enum value maps for DuplicatePolicy.
+var (
+	DuplicatePolicy_name = map[int32]string{
+		0: "DUPLICATE_POLICY_UNSPECIFIED",
+		1: "DUPLICATE_POLICY_FAIL",
+		2: "DUPLICATE_POLICY_LINK",
+		3: "DUPLICATE_POLICY_UPDATE",
+	}
+	DuplicatePolicy_value = map[string]int32{
+		"DUPLICATE_POLICY_UNSPECIFIED": 0,
+		"DUPLICATE_POLICY_FAIL":        1,
+		"DUPLICATE_POLICY_LINK":        2,
+		"DUPLICATE_POLICY_UPDATE":      3,
+	}
+)
+
+func (x DuplicatePolicy) Enum() *DuplicatePolicy {
+	p := new(DuplicatePolicy)
+	*p = x
+	return p
+}
+
+func (x DuplicatePolicy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DuplicatePolicy) Descriptor() protoreflect.EnumDescriptor {
+	return file_events_proto_enumTypes[3].Descriptor()
+}
+
+func (DuplicatePolicy) Type() protoreflect.EnumType {
+	return &file_events_proto_enumTypes[3]
+}
+
+func (x DuplicatePolicy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DuplicatePolicy.Descriptor instead.
+func (DuplicatePolicy) EnumDescriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{3}
+}
+
+// DestinationMode selects how a destination receives a pipeline's activities
+type DestinationMode int32
+
+const (
+	DestinationMode_DESTINATION_MODE_UNSPECIFIED     DestinationMode = 0 // Same as UPLOAD
+	DestinationMode_DESTINATION_MODE_UPLOAD          DestinationMode = 1 // Upload the FIT file as a new activity
+	DestinationMode_DESTINATION_MODE_UPDATE_EXISTING DestinationMode = 2 // Update the matching activity already on the destination (e.g. synced by the watch)
+)
+
+// Enum value maps for DestinationMode.
+var (
+	DestinationMode_name = map[int32]string{
+		0: "DESTINATION_MODE_UNSPECIFIED",
+		1: "DESTINATION_MODE_UPLOAD",
+		2: "DESTINATION_MODE_UPDATE_EXISTING",
+	}
+	DestinationMode_value = map[string]int32{
+		"DESTINATION_MODE_UNSPECIFIED":     0,
+		"DESTINATION_MODE_UPLOAD":          1,
+		"DESTINATION_MODE_UPDATE_EXISTING": 2,
+	}
+)
+
+func (x DestinationMode) Enum() *DestinationMode {
+	p := new(DestinationMode)
+	*p = x
+	return p
+}
+
+func (x DestinationMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DestinationMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_events_proto_enumTypes[4].Descriptor()
+}
+
+func (DestinationMode) Type() protoreflect.EnumType {
+	return &file_events_proto_enumTypes[4]
+}
+
+func (x DestinationMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DestinationMode.Descriptor instead.
+func (DestinationMode) EnumDescriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{4}
+}
+
+// ActivityVisibility is who can see the activity on the destination
+type ActivityVisibility int32
+
+const (
+	ActivityVisibility_ACTIVITY_VISIBILITY_UNSPECIFIED    ActivityVisibility = 0 // Destination's default for the athlete
+	ActivityVisibility_ACTIVITY_VISIBILITY_EVERYONE       ActivityVisibility = 1
+	ActivityVisibility_ACTIVITY_VISIBILITY_FOLLOWERS_ONLY ActivityVisibility = 2
+	ActivityVisibility_ACTIVITY_VISIBILITY_ONLY_ME        ActivityVisibility = 3
+)
+
+// Enum value maps for ActivityVisibility.
+var (
+	ActivityVisibility_name = map[int32]string{
+		0: "ACTIVITY_VISIBILITY_UNSPECIFIED",
+		1: "ACTIVITY_VISIBILITY_EVERYONE",
+		2: "ACTIVITY_VISIBILITY_FOLLOWERS_ONLY",
+		3: "ACTIVITY_VISIBILITY_ONLY_ME",
+	}
+	ActivityVisibility_value = map[string]int32{
+		"ACTIVITY_VISIBILITY_UNSPECIFIED":    0,
+		"ACTIVITY_VISIBILITY_EVERYONE":       1,
+		"ACTIVITY_VISIBILITY_FOLLOWERS_ONLY": 2,
+		"ACTIVITY_VISIBILITY_ONLY_ME":        3,
+	}
+)
+
+func (x ActivityVisibility) Enum() *ActivityVisibility {
+	p := new(ActivityVisibility)
+	*p = x
+	return p
+}
+
+func (x ActivityVisibility) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ActivityVisibility) Descriptor() protoreflect.EnumDescriptor {
+	return file_events_proto_enumTypes[5].Descriptor()
+}
+
+func (ActivityVisibility) Type() protoreflect.EnumType {
+	return &file_events_proto_enumTypes[5]
+}
+
+func (x ActivityVisibility) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ActivityVisibility.Descriptor instead.
+func (ActivityVisibility) EnumDescriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{5}
+}
+
+// DestinationOptions configures how a pipeline delivers to a single destination.
+// Unset fields leave the destination's default.
+type DestinationOptions struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	DuplicatePolicy DuplicatePolicy        `protobuf:"varint,1,opt,name=duplicate_policy,json=duplicatePolicy,proto3,enum=fitglue.events.DuplicatePolicy" json:"duplicate_policy,omitempty"`
+	// Destination-specific gear ID (e.g. Strava "b1234567")
+	GearId string          `protobuf:"bytes,2,opt,name=gear_id,json=gearId,proto3" json:"gear_id,omitempty"`
+	Mode   DestinationMode `protobuf:"varint,3,opt,name=mode,proto3,enum=fitglue.events.DestinationMode" json:"mode,omitempty"`
+	// Activity flags
+	Trainer      *bool              `protobuf:"varint,4,opt,name=trainer,proto3,oneof" json:"trainer,omitempty"` // Recorded on a training machine
+	Commute      *bool              `protobuf:"varint,5,opt,name=commute,proto3,oneof" json:"commute,omitempty"`
+	HideFromHome *bool              `protobuf:"varint,6,opt,name=hide_from_home,json=hideFromHome,proto3,oneof" json:"hide_from_home,omitempty"` // Muted from followers' feeds
+	Visibility   ActivityVisibility `protobuf:"varint,7,opt,name=visibility,proto3,enum=fitglue.events.ActivityVisibility" json:"visibility,omitempty"`
+	// Hevy: create custom exercises for exercise names with no matching template.
+	// Unmatched exercises are left out of the workout otherwise.
+	CreateCustomExercises *bool `protobuf:"varint,8,opt,name=create_custom_exercises,json=createCustomExercises,proto3,oneof" json:"create_custom_exercises,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *DestinationOptions) Reset() {
+	*x = DestinationOptions{}
+	mi := &file_events_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DestinationOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DestinationOptions) ProtoMessage() {}
+
+func (x *DestinationOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DestinationOptions.ProtoReflect.Descriptor instead.
+func (*DestinationOptions) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *DestinationOptions) GetDuplicatePolicy() DuplicatePolicy {
+	if x != nil {
+		return x.DuplicatePolicy
+	}
+	return DuplicatePolicy_DUPLICATE_POLICY_UNSPECIFIED
+}
+
+func (x *DestinationOptions) GetGearId() string {
+	if x != nil {
+		return x.GearId
+	}
+	return ""
+}
+
+func (x *DestinationOptions) GetMode() DestinationMode {
+	if x != nil {
+		return x.Mode
+	}
+	return DestinationMode_DESTINATION_MODE_UNSPECIFIED
+}
+
+func (x *DestinationOptions) GetTrainer() bool {
+	if x != nil && x.Trainer != nil {
+		return *x.Trainer
+	}
+	return false
+}
+
+func (x *DestinationOptions) GetCommute() bool {
+	if x != nil && x.Commute != nil {
+		return *x.Commute
+	}
+	return false
+}
+
+func (x *DestinationOptions) GetHideFromHome() bool {
+	if x != nil && x.HideFromHome != nil {
+		return *x.HideFromHome
+	}
+	return false
+}
+
+func (x *DestinationOptions) GetVisibility() ActivityVisibility {
+	if x != nil {
+		return x.Visibility
+	}
+	return ActivityVisibility_ACTIVITY_VISIBILITY_UNSPECIFIED
+}
+
+func (x *DestinationOptions) GetCreateCustomExercises() bool {
+	if x != nil && x.CreateCustomExercises != nil {
+		return *x.CreateCustomExercises
+	}
+	return false
+}
+
 // Event payload for CLOUD_EVENT_TYPE_ACTIVITY_ENRICHED
 // Also used contextually for Upload Trigger
 type EnrichedActivityEvent struct {
@@ -227,13 +520,21 @@ type EnrichedActivityEvent struct {
 	Tags               []string              `protobuf:"bytes,14,rep,name=tags,proto3" json:"tags,omitempty"`
 	// Execution tracing
 	PipelineExecutionId *string `protobuf:"bytes,15,opt,name=pipeline_execution_id,json=pipelineExecutionId,proto3,oneof" json:"pipeline_execution_id,omitempty"`
-	unknownFields       protoimpl.UnknownFields
-	sizeCache           protoimpl.SizeCache
+	// Per-destination options from the pipeline, keyed by destination name (e.g. "strava")
+	DestinationOptions map[string]*DestinationOptions `protobuf:"bytes,16,rep,name=destination_options,json=destinationOptions,proto3" json:"destination_options,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Copied from ActivityPayload.resync: update the activity's existing destination copies
+	Resync bool `protobuf:"varint,17,opt,name=resync,proto3" json:"resync,omitempty"`
+	// The source activity's own name, description and type, before enrichment
+	SourceName        string       `protobuf:"bytes,18,opt,name=source_name,json=sourceName,proto3" json:"source_name,omitempty"`
+	SourceDescription string       `protobuf:"bytes,19,opt,name=source_description,json=sourceDescription,proto3" json:"source_description,omitempty"`
+	SourceType        ActivityType `protobuf:"varint,20,opt,name=source_type,json=sourceType,proto3,enum=fitglue.ActivityType" json:"source_type,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
 func (x *EnrichedActivityEvent) Reset() {
 	*x = EnrichedActivityEvent{}
-	mi := &file_events_proto_msgTypes[0]
+	mi := &file_events_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -245,7 +546,7 @@ func (x *EnrichedActivityEvent) String() string {
 func (*EnrichedActivityEvent) ProtoMessage() {}
 
 func (x *EnrichedActivityEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_events_proto_msgTypes[0]
+	mi := &file_events_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -258,7 +559,7 @@ func (x *EnrichedActivityEvent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EnrichedActivityEvent.ProtoReflect.Descriptor instead.
 func (*EnrichedActivityEvent) Descriptor() ([]byte, []int) {
-	return file_events_proto_rawDescGZIP(), []int{0}
+	return file_events_proto_rawDescGZIP(), []int{1}
 }
 
 func (x *EnrichedActivityEvent) GetActivityId() string {
@@ -366,6 +667,41 @@ func (x *EnrichedActivityEvent) GetPipelineExecutionId() string {
 	return ""
 }
 
+func (x *EnrichedActivityEvent) GetDestinationOptions() map[string]*DestinationOptions {
+	if x != nil {
+		return x.DestinationOptions
+	}
+	return nil
+}
+
+func (x *EnrichedActivityEvent) GetResync() bool {
+	if x != nil {
+		return x.Resync
+	}
+	return false
+}
+
+func (x *EnrichedActivityEvent) GetSourceName() string {
+	if x != nil {
+		return x.SourceName
+	}
+	return ""
+}
+
+func (x *EnrichedActivityEvent) GetSourceDescription() string {
+	if x != nil {
+		return x.SourceDescription
+	}
+	return ""
+}
+
+func (x *EnrichedActivityEvent) GetSourceType() ActivityType {
+	if x != nil {
+		return x.SourceType
+	}
+	return ActivityType_ACTIVITY_TYPE_UNSPECIFIED
+}
+
 type MessagePublishedData struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
@@ -378,7 +714,7 @@ type MessagePublishedData struct {
 
 func (x *MessagePublishedData) Reset() {
 	*x = MessagePublishedData{}
-	mi := &file_events_proto_msgTypes[1]
+	mi := &file_events_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -390,7 +726,7 @@ func (x *MessagePublishedData) String() string {
 func (*MessagePublishedData) ProtoMessage() {}
 
 func (x *MessagePublishedData) ProtoReflect() protoreflect.Message {
-	mi := &file_events_proto_msgTypes[1]
+	mi := &file_events_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -403,7 +739,7 @@ func (x *MessagePublishedData) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MessagePublishedData.ProtoReflect.Descriptor instead.
 func (*MessagePublishedData) Descriptor() ([]byte, []int) {
-	return file_events_proto_rawDescGZIP(), []int{1}
+	return file_events_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *MessagePublishedData) GetData() []byte {
@@ -475,7 +811,24 @@ var File_events_proto protoreflect.FileDescriptor
 
 const file_events_proto_rawDesc = "" +
 	"\n" +
-	"\fevents.proto\x12\x0efitglue.events\x1a google/protobuf/descriptor.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1bstandardized_activity.proto\x1a\x0eactivity.proto\"\xc6\x06\n" +
+	"\fevents.proto\x12\x0efitglue.events\x1a google/protobuf/descriptor.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1bstandardized_activity.proto\x1a\x0eactivity.proto\"\xdf\x03\n" +
+	"\x12DestinationOptions\x12J\n" +
+	"\x10duplicate_policy\x18\x01 \x01(\x0e2\x1f.fitglue.events.DuplicatePolicyR\x0fduplicatePolicy\x12\x17\n" +
+	"\agear_id\x18\x02 \x01(\tR\x06gearId\x123\n" +
+	"\x04mode\x18\x03 \x01(\x0e2\x1f.fitglue.events.DestinationModeR\x04mode\x12\x1d\n" +
+	"\atrainer\x18\x04 \x01(\bH\x00R\atrainer\x88\x01\x01\x12\x1d\n" +
+	"\acommute\x18\x05 \x01(\bH\x01R\acommute\x88\x01\x01\x12)\n" +
+	"\x0ehide_from_home\x18\x06 \x01(\bH\x02R\fhideFromHome\x88\x01\x01\x12B\n" +
+	"\n" +
+	"visibility\x18\a \x01(\x0e2\".fitglue.events.ActivityVisibilityR\n" +
+	"visibility\x12;\n" +
+	"\x17create_custom_exercises\x18\b \x01(\bH\x03R\x15createCustomExercises\x88\x01\x01B\n" +
+	"\n" +
+	"\b_trainerB\n" +
+	"\n" +
+	"\b_commuteB\x11\n" +
+	"\x0f_hide_from_homeB\x1a\n" +
+	"\x18_create_custom_exercises\"\xc1\t\n" +
 	"\x15EnrichedActivityEvent\x12\x1f\n" +
 	"\vactivity_id\x18\x01 \x01(\tR\n" +
 	"activityId\x12\x17\n" +
@@ -496,10 +849,20 @@ const file_events_proto_rawDesc = "" +
 	"\x13enrichment_metadata\x18\f \x03(\v2=.fitglue.events.EnrichedActivityEvent.EnrichmentMetadataEntryR\x12enrichmentMetadata\x12?\n" +
 	"\fdestinations\x18\r \x03(\x0e2\x1b.fitglue.events.DestinationR\fdestinations\x12\x12\n" +
 	"\x04tags\x18\x0e \x03(\tR\x04tags\x127\n" +
-	"\x15pipeline_execution_id\x18\x0f \x01(\tH\x00R\x13pipelineExecutionId\x88\x01\x01\x1aE\n" +
+	"\x15pipeline_execution_id\x18\x0f \x01(\tH\x00R\x13pipelineExecutionId\x88\x01\x01\x12n\n" +
+	"\x13destination_options\x18\x10 \x03(\v2=.fitglue.events.EnrichedActivityEvent.DestinationOptionsEntryR\x12destinationOptions\x12\x16\n" +
+	"\x06resync\x18\x11 \x01(\bR\x06resync\x12\x1f\n" +
+	"\vsource_name\x18\x12 \x01(\tR\n" +
+	"sourceName\x12-\n" +
+	"\x12source_description\x18\x13 \x01(\tR\x11sourceDescription\x126\n" +
+	"\vsource_type\x18\x14 \x01(\x0e2\x15.fitglue.ActivityTypeR\n" +
+	"sourceType\x1aE\n" +
 	"\x17EnrichmentMetadataEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\x18\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1ai\n" +
+	"\x17DestinationOptionsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x128\n" +
+	"\x05value\x18\x02 \x01(\v2\".fitglue.events.DestinationOptionsR\x05value:\x028\x01B\x18\n" +
 	"\x16_pipeline_execution_id\"\x81\x02\n" +
 	"\x14MessagePublishedData\x12\x12\n" +
 	"\x04data\x18\x01 \x01(\fR\x04data\x12T\n" +
@@ -511,7 +874,7 @@ const file_events_proto_rawDesc = "" +
 	"\fpublish_time\x18\x04 \x01(\tR\vpublishTime\x1a=\n" +
 	"\x0fAttributesEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01*\xdc\x03\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01*\xc7\x05\n" +
 	"\x0eCloudEventType\x12 \n" +
 	"\x1cCLOUD_EVENT_TYPE_UNSPECIFIED\x10\x00\x12G\n" +
 	"!CLOUD_EVENT_TYPE_ACTIVITY_CREATED\x10\x01\x1a \x82\xb5\x18\x1ccom.fitglue.activity.created\x12I\n" +
@@ -519,7 +882,10 @@ const file_events_proto_rawDesc = "" +
 	"\x1bCLOUD_EVENT_TYPE_JOB_ROUTED\x10\x03\x1a\x1a\x82\xb5\x18\x16com.fitglue.job.routed\x12M\n" +
 	"$CLOUD_EVENT_TYPE_FITBIT_NOTIFICATION\x10\x04\x1a#\x82\xb5\x18\x1fcom.fitglue.fitbit.notification\x12C\n" +
 	"\x1fCLOUD_EVENT_TYPE_ENRICHMENT_LAG\x10\x05\x1a\x1e\x82\xb5\x18\x1acom.fitglue.enrichment.lag\x12C\n" +
-	"\x1fCLOUD_EVENT_TYPE_INPUT_RESOLVED\x10\x06\x1a\x1e\x82\xb5\x18\x1acom.fitglue.input.resolved*\xd7\x03\n" +
+	"\x1fCLOUD_EVENT_TYPE_INPUT_RESOLVED\x10\x06\x1a\x1e\x82\xb5\x18\x1acom.fitglue.input.resolved\x12M\n" +
+	"$CLOUD_EVENT_TYPE_UPLOAD_STATUS_CHECK\x10\a\x1a#\x82\xb5\x18\x1fcom.fitglue.upload.status_check\x12C\n" +
+	"\x1fCLOUD_EVENT_TYPE_STRAVA_WEBHOOK\x10\b\x1a\x1e\x82\xb5\x18\x1acom.fitglue.strava.webhook\x12U\n" +
+	"(CLOUD_EVENT_TYPE_SOURCE_ACTIVITY_CHANGED\x10\t\x1a'\x82\xb5\x18#com.fitglue.activity.source_changed*\x8c\x05\n" +
 	"\x10CloudEventSource\x12\"\n" +
 	"\x1eCLOUD_EVENT_SOURCE_UNSPECIFIED\x10\x00\x123\n" +
 	"\x17CLOUD_EVENT_SOURCE_HEVY\x10\x01\x1a\x16\x8a\xb5\x18\x12/integrations/hevy\x12G\n" +
@@ -528,11 +894,31 @@ const file_events_proto_rawDesc = "" +
 	"\x1bCLOUD_EVENT_SOURCE_ENRICHER\x10\x04\x1a\x12\x8a\xb5\x18\x0e/core/enricher\x12/\n" +
 	"\x19CLOUD_EVENT_SOURCE_ROUTER\x10\x05\x1a\x10\x8a\xb5\x18\f/core/router\x12?\n" +
 	"!CLOUD_EVENT_SOURCE_INPUTS_HANDLER\x10\x06\x1a\x18\x8a\xb5\x18\x14/core/inputs-handler\x123\n" +
-	"\x17CLOUD_EVENT_SOURCE_MOCK\x10c\x1a\x16\x8a\xb5\x18\x12/integrations/mock*\x90\x01\n" +
+	"\x1bCLOUD_EVENT_SOURCE_UPLOADER\x10\a\x1a\x12\x8a\xb5\x18\x0e/core/uploader\x127\n" +
+	"\x19CLOUD_EVENT_SOURCE_STRAVA\x10\b\x1a\x18\x8a\xb5\x18\x14/integrations/strava\x12E\n" +
+	"$CLOUD_EVENT_SOURCE_CHANGE_PROPAGATOR\x10\t\x1a\x1b\x8a\xb5\x18\x17/core/change-propagator\x123\n" +
+	"\x17CLOUD_EVENT_SOURCE_MOCK\x10c\x1a\x16\x8a\xb5\x18\x12/integrations/mock*\xbb\x02\n" +
 	"\vDestination\x12\x1b\n" +
 	"\x17DESTINATION_UNSPECIFIED\x10\x00\x123\n" +
-	"\x12DESTINATION_STRAVA\x10\x01\x1a\x1b\x92\xb5\x18\x17topic-job-upload-strava\x12/\n" +
-	"\x10DESTINATION_MOCK\x10c\x1a\x19\x92\xb5\x18\x15topic-job-upload-mock:<\n" +
+	"\x12DESTINATION_STRAVA\x10\x01\x1a\x1b\x92\xb5\x18\x17topic-job-upload-strava\x125\n" +
+	"\x13DESTINATION_WEBHOOK\x10\x02\x1a\x1c\x92\xb5\x18\x18topic-job-upload-webhook\x12A\n" +
+	"\x19DESTINATION_INTERVALS_ICU\x10\x03\x1a\"\x92\xb5\x18\x1etopic-job-upload-intervals-icu\x12/\n" +
+	"\x10DESTINATION_HEVY\x10\x04\x1a\x19\x92\xb5\x18\x15topic-job-upload-hevy\x12/\n" +
+	"\x10DESTINATION_MOCK\x10c\x1a\x19\x92\xb5\x18\x15topic-job-upload-mock*\x86\x01\n" +
+	"\x0fDuplicatePolicy\x12 \n" +
+	"\x1cDUPLICATE_POLICY_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15DUPLICATE_POLICY_FAIL\x10\x01\x12\x19\n" +
+	"\x15DUPLICATE_POLICY_LINK\x10\x02\x12\x1b\n" +
+	"\x17DUPLICATE_POLICY_UPDATE\x10\x03*v\n" +
+	"\x0fDestinationMode\x12 \n" +
+	"\x1cDESTINATION_MODE_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17DESTINATION_MODE_UPLOAD\x10\x01\x12$\n" +
+	" DESTINATION_MODE_UPDATE_EXISTING\x10\x02*\xa4\x01\n" +
+	"\x12ActivityVisibility\x12#\n" +
+	"\x1fACTIVITY_VISIBILITY_UNSPECIFIED\x10\x00\x12 \n" +
+	"\x1cACTIVITY_VISIBILITY_EVERYONE\x10\x01\x12&\n" +
+	"\"ACTIVITY_VISIBILITY_FOLLOWERS_ONLY\x10\x02\x12\x1f\n" +
+	"\x1bACTIVITY_VISIBILITY_ONLY_ME\x10\x03:<\n" +
 	"\ace_type\x12!.google.protobuf.EnumValueOptions\x18І\x03 \x01(\tR\x06ceType:@\n" +
 	"\tce_source\x12!.google.protobuf.EnumValueOptions\x18ц\x03 \x01(\tR\bceSource:B\n" +
 	"\n" +
@@ -550,38 +936,49 @@ func file_events_proto_rawDescGZIP() []byte {
 	return file_events_proto_rawDescData
 }
 
-var file_events_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
-var file_events_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_events_proto_enumTypes = make([]protoimpl.EnumInfo, 6)
+var file_events_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
 var file_events_proto_goTypes = []any{
 	(CloudEventType)(0),                 // 0: fitglue.events.CloudEventType
 	(CloudEventSource)(0),               // 1: fitglue.events.CloudEventSource
 	(Destination)(0),                    // 2: fitglue.events.Destination
-	(*EnrichedActivityEvent)(nil),       // 3: fitglue.events.EnrichedActivityEvent
-	(*MessagePublishedData)(nil),        // 4: fitglue.events.MessagePublishedData
-	nil,                                 // 5: fitglue.events.EnrichedActivityEvent.EnrichmentMetadataEntry
-	nil,                                 // 6: fitglue.events.MessagePublishedData.AttributesEntry
-	(ActivityType)(0),                   // 7: fitglue.ActivityType
-	(*timestamp.Timestamp)(nil),         // 8: google.protobuf.Timestamp
-	(ActivitySource)(0),                 // 9: fitglue.ActivitySource
-	(*StandardizedActivity)(nil),        // 10: fitglue.StandardizedActivity
-	(*descriptor.EnumValueOptions)(nil), // 11: google.protobuf.EnumValueOptions
+	(DuplicatePolicy)(0),                // 3: fitglue.events.DuplicatePolicy
+	(DestinationMode)(0),                // 4: fitglue.events.DestinationMode
+	(ActivityVisibility)(0),             // 5: fitglue.events.ActivityVisibility
+	(*DestinationOptions)(nil),          // 6: fitglue.events.DestinationOptions
+	(*EnrichedActivityEvent)(nil),       // 7: fitglue.events.EnrichedActivityEvent
+	(*MessagePublishedData)(nil),        // 8: fitglue.events.MessagePublishedData
+	nil,                                 // 9: fitglue.events.EnrichedActivityEvent.EnrichmentMetadataEntry
+	nil,                                 // 10: fitglue.events.EnrichedActivityEvent.DestinationOptionsEntry
+	nil,                                 // 11: fitglue.events.MessagePublishedData.AttributesEntry
+	(ActivityType)(0),                   // 12: fitglue.ActivityType
+	(*timestamp.Timestamp)(nil),         // 13: google.protobuf.Timestamp
+	(ActivitySource)(0),                 // 14: fitglue.ActivitySource
+	(*StandardizedActivity)(nil),        // 15: fitglue.StandardizedActivity
+	(*descriptor.EnumValueOptions)(nil), // 16: google.protobuf.EnumValueOptions
 }
 var file_events_proto_depIdxs = []int32{
-	7,  // 0: fitglue.events.EnrichedActivityEvent.activity_type:type_name -> fitglue.ActivityType
-	8,  // 1: fitglue.events.EnrichedActivityEvent.start_time:type_name -> google.protobuf.Timestamp
-	9,  // 2: fitglue.events.EnrichedActivityEvent.source:type_name -> fitglue.ActivitySource
-	10, // 3: fitglue.events.EnrichedActivityEvent.activity_data:type_name -> fitglue.StandardizedActivity
-	5,  // 4: fitglue.events.EnrichedActivityEvent.enrichment_metadata:type_name -> fitglue.events.EnrichedActivityEvent.EnrichmentMetadataEntry
-	2,  // 5: fitglue.events.EnrichedActivityEvent.destinations:type_name -> fitglue.events.Destination
-	6,  // 6: fitglue.events.MessagePublishedData.attributes:type_name -> fitglue.events.MessagePublishedData.AttributesEntry
-	11, // 7: fitglue.events.ce_type:extendee -> google.protobuf.EnumValueOptions
-	11, // 8: fitglue.events.ce_source:extendee -> google.protobuf.EnumValueOptions
-	11, // 9: fitglue.events.dest_topic:extendee -> google.protobuf.EnumValueOptions
-	10, // [10:10] is the sub-list for method output_type
-	10, // [10:10] is the sub-list for method input_type
-	10, // [10:10] is the sub-list for extension type_name
-	7,  // [7:10] is the sub-list for extension extendee
-	0,  // [0:7] is the sub-list for field type_name
+	3,  // 0: fitglue.events.DestinationOptions.duplicate_policy:type_name -> fitglue.events.DuplicatePolicy
+	4,  // 1: fitglue.events.DestinationOptions.mode:type_name -> fitglue.events.DestinationMode
+	5,  // 2: fitglue.events.DestinationOptions.visibility:type_name -> fitglue.events.ActivityVisibility
+	12, // 3: fitglue.events.EnrichedActivityEvent.activity_type:type_name -> fitglue.ActivityType
+	13, // 4: fitglue.events.EnrichedActivityEvent.start_time:type_name -> google.protobuf.Timestamp
+	14, // 5: fitglue.events.EnrichedActivityEvent.source:type_name -> fitglue.ActivitySource
+	15, // 6: fitglue.events.EnrichedActivityEvent.activity_data:type_name -> fitglue.StandardizedActivity
+	9,  // 7: fitglue.events.EnrichedActivityEvent.enrichment_metadata:type_name -> fitglue.events.EnrichedActivityEvent.EnrichmentMetadataEntry
+	2,  // 8: fitglue.events.EnrichedActivityEvent.destinations:type_name -> fitglue.events.Destination
+	10, // 9: fitglue.events.EnrichedActivityEvent.destination_options:type_name -> fitglue.events.EnrichedActivityEvent.DestinationOptionsEntry
+	12, // 10: fitglue.events.EnrichedActivityEvent.source_type:type_name -> fitglue.ActivityType
+	11, // 11: fitglue.events.MessagePublishedData.attributes:type_name -> fitglue.events.MessagePublishedData.AttributesEntry
+	6,  // 12: fitglue.events.EnrichedActivityEvent.DestinationOptionsEntry.value:type_name -> fitglue.events.DestinationOptions
+	16, // 13: fitglue.events.ce_type:extendee -> google.protobuf.EnumValueOptions
+	16, // 14: fitglue.events.ce_source:extendee -> google.protobuf.EnumValueOptions
+	16, // 15: fitglue.events.dest_topic:extendee -> google.protobuf.EnumValueOptions
+	16, // [16:16] is the sub-list for method output_type
+	16, // [16:16] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	13, // [13:16] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
 }
 
 func init() { file_events_proto_init() }
@@ -592,13 +989,14 @@ func file_events_proto_init() {
 	file_standardized_activity_proto_init()
 	file_activity_proto_init()
 	file_events_proto_msgTypes[0].OneofWrappers = []any{}
+	file_events_proto_msgTypes[1].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_events_proto_rawDesc), len(file_events_proto_rawDesc)),
-			NumEnums:      3,
-			NumMessages:   4,
+			NumEnums:      6,
+			NumMessages:   6,
 			NumExtensions: 3,
 			NumServices:   0,
 		},
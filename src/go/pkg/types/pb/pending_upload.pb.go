@@ -0,0 +1,387 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v3.12.4
+// source: pending_upload.proto
+
+package pb
+
+import (
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type PendingUpload_Status int32
+
+const (
+	PendingUpload_STATUS_UNSPECIFIED PendingUpload_Status = 0
+	PendingUpload_STATUS_PROCESSING  PendingUpload_Status = 1
+	PendingUpload_STATUS_COMPLETED   PendingUpload_Status = 2
+	PendingUpload_STATUS_FAILED      PendingUpload_Status = 3
+)
+
+// Enum value maps for PendingUpload_Status.
+var (
+	PendingUpload_Status_name = map[int32]string{
+		0: "STATUS_UNSPECIFIED",
+		1: "STATUS_PROCESSING",
+		2: "STATUS_COMPLETED",
+		3: "STATUS_FAILED",
+	}
+	PendingUpload_Status_value = map[string]int32{
+		"STATUS_UNSPECIFIED": 0,
+		"STATUS_PROCESSING":  1,
+		"STATUS_COMPLETED":   2,
+		"STATUS_FAILED":      3,
+	}
+)
+
+func (x PendingUpload_Status) Enum() *PendingUpload_Status {
+	p := new(PendingUpload_Status)
+	*p = x
+	return p
+}
+
+func (x PendingUpload_Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PendingUpload_Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_pending_upload_proto_enumTypes[0].Descriptor()
+}
+
+func (PendingUpload_Status) Type() protoreflect.EnumType {
+	return &file_pending_upload_proto_enumTypes[0]
+}
+
+func (x PendingUpload_Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PendingUpload_Status.Descriptor instead.
+func (PendingUpload_Status) EnumDescriptor() ([]byte, []int) {
+	return file_pending_upload_proto_rawDescGZIP(), []int{0, 0}
+}
+
+// PendingUpload tracks an upload a destination accepted but has not finished processing
+// (e.g. a Strava upload without an activity ID yet). The upload-status-poller resolves it.
+type PendingUpload struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// "{destination}-{upload_id}"
+	Id     string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId string `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Destination name (e.g. "strava")
+	Destination string               `protobuf:"bytes,3,opt,name=destination,proto3" json:"destination,omitempty"`
+	UploadId    string               `protobuf:"bytes,4,opt,name=upload_id,json=uploadId,proto3" json:"upload_id,omitempty"`
+	Status      PendingUpload_Status `protobuf:"varint,5,opt,name=status,proto3,enum=fitglue.PendingUpload_Status" json:"status,omitempty"`
+	// Set once COMPLETED (e.g. the Strava activity ID)
+	ExternalId string `protobuf:"bytes,6,opt,name=external_id,json=externalId,proto3" json:"external_id,omitempty"`
+	// Set once FAILED
+	Error string `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+	// Number of status checks made so far
+	Attempts int32 `protobuf:"varint,8,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	// The uploader execution to update with the final result
+	ExecutionId         string `protobuf:"bytes,9,opt,name=execution_id,json=executionId,proto3" json:"execution_id,omitempty"`
+	PipelineExecutionId string `protobuf:"bytes,10,opt,name=pipeline_execution_id,json=pipelineExecutionId,proto3" json:"pipeline_execution_id,omitempty"`
+	// The event that was uploaded, used to record the SynchronizedActivity
+	OriginalEvent *EnrichedActivityEvent `protobuf:"bytes,11,opt,name=original_event,json=originalEvent,proto3" json:"original_event,omitempty"`
+	CreatedAt     *timestamp.Timestamp   `protobuf:"bytes,12,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamp.Timestamp   `protobuf:"bytes,13,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	CompletedAt   *timestamp.Timestamp   `protobuf:"bytes,14,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PendingUpload) Reset() {
+	*x = PendingUpload{}
+	mi := &file_pending_upload_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PendingUpload) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PendingUpload) ProtoMessage() {}
+
+func (x *PendingUpload) ProtoReflect() protoreflect.Message {
+	mi := &file_pending_upload_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PendingUpload.ProtoReflect.Descriptor instead.
+func (*PendingUpload) Descriptor() ([]byte, []int) {
+	return file_pending_upload_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PendingUpload) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PendingUpload) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *PendingUpload) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *PendingUpload) GetUploadId() string {
+	if x != nil {
+		return x.UploadId
+	}
+	return ""
+}
+
+func (x *PendingUpload) GetStatus() PendingUpload_Status {
+	if x != nil {
+		return x.Status
+	}
+	return PendingUpload_STATUS_UNSPECIFIED
+}
+
+func (x *PendingUpload) GetExternalId() string {
+	if x != nil {
+		return x.ExternalId
+	}
+	return ""
+}
+
+func (x *PendingUpload) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *PendingUpload) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *PendingUpload) GetExecutionId() string {
+	if x != nil {
+		return x.ExecutionId
+	}
+	return ""
+}
+
+func (x *PendingUpload) GetPipelineExecutionId() string {
+	if x != nil {
+		return x.PipelineExecutionId
+	}
+	return ""
+}
+
+func (x *PendingUpload) GetOriginalEvent() *EnrichedActivityEvent {
+	if x != nil {
+		return x.OriginalEvent
+	}
+	return nil
+}
+
+func (x *PendingUpload) GetCreatedAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *PendingUpload) GetUpdatedAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *PendingUpload) GetCompletedAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+// Event payload for CLOUD_EVENT_TYPE_UPLOAD_STATUS_CHECK
+type UploadStatusCheck struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	PendingUploadId     string                 `protobuf:"bytes,1,opt,name=pending_upload_id,json=pendingUploadId,proto3" json:"pending_upload_id,omitempty"`
+	UserId              string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	PipelineExecutionId string                 `protobuf:"bytes,3,opt,name=pipeline_execution_id,json=pipelineExecutionId,proto3" json:"pipeline_execution_id,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *UploadStatusCheck) Reset() {
+	*x = UploadStatusCheck{}
+	mi := &file_pending_upload_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadStatusCheck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadStatusCheck) ProtoMessage() {}
+
+func (x *UploadStatusCheck) ProtoReflect() protoreflect.Message {
+	mi := &file_pending_upload_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadStatusCheck.ProtoReflect.Descriptor instead.
+func (*UploadStatusCheck) Descriptor() ([]byte, []int) {
+	return file_pending_upload_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *UploadStatusCheck) GetPendingUploadId() string {
+	if x != nil {
+		return x.PendingUploadId
+	}
+	return ""
+}
+
+func (x *UploadStatusCheck) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UploadStatusCheck) GetPipelineExecutionId() string {
+	if x != nil {
+		return x.PipelineExecutionId
+	}
+	return ""
+}
+
+var File_pending_upload_proto protoreflect.FileDescriptor
+
+const file_pending_upload_proto_rawDesc = "" +
+	"\n" +
+	"\x14pending_upload.proto\x12\afitglue\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\fevents.proto\"\xbd\x05\n" +
+	"\rPendingUpload\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12 \n" +
+	"\vdestination\x18\x03 \x01(\tR\vdestination\x12\x1b\n" +
+	"\tupload_id\x18\x04 \x01(\tR\buploadId\x125\n" +
+	"\x06status\x18\x05 \x01(\x0e2\x1d.fitglue.PendingUpload.StatusR\x06status\x12\x1f\n" +
+	"\vexternal_id\x18\x06 \x01(\tR\n" +
+	"externalId\x12\x14\n" +
+	"\x05error\x18\a \x01(\tR\x05error\x12\x1a\n" +
+	"\battempts\x18\b \x01(\x05R\battempts\x12!\n" +
+	"\fexecution_id\x18\t \x01(\tR\vexecutionId\x122\n" +
+	"\x15pipeline_execution_id\x18\n" +
+	" \x01(\tR\x13pipelineExecutionId\x12L\n" +
+	"\x0eoriginal_event\x18\v \x01(\v2%.fitglue.events.EnrichedActivityEventR\roriginalEvent\x129\n" +
+	"\n" +
+	"created_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12=\n" +
+	"\fcompleted_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\"`\n" +
+	"\x06Status\x12\x16\n" +
+	"\x12STATUS_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11STATUS_PROCESSING\x10\x01\x12\x14\n" +
+	"\x10STATUS_COMPLETED\x10\x02\x12\x11\n" +
+	"\rSTATUS_FAILED\x10\x03\"\x8c\x01\n" +
+	"\x11UploadStatusCheck\x12*\n" +
+	"\x11pending_upload_id\x18\x01 \x01(\tR\x0fpendingUploadId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x122\n" +
+	"\x15pipeline_execution_id\x18\x03 \x01(\tR\x13pipelineExecutionIdB7Z5github.com/ripixel/fitglue-server/src/go/pkg/types/pbb\x06proto3"
+
+var (
+	file_pending_upload_proto_rawDescOnce sync.Once
+	file_pending_upload_proto_rawDescData []byte
+)
+
+func file_pending_upload_proto_rawDescGZIP() []byte {
+	file_pending_upload_proto_rawDescOnce.Do(func() {
+		file_pending_upload_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_pending_upload_proto_rawDesc), len(file_pending_upload_proto_rawDesc)))
+	})
+	return file_pending_upload_proto_rawDescData
+}
+
+var file_pending_upload_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_pending_upload_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_pending_upload_proto_goTypes = []any{
+	(PendingUpload_Status)(0),     // 0: fitglue.PendingUpload.Status
+	(*PendingUpload)(nil),         // 1: fitglue.PendingUpload
+	(*UploadStatusCheck)(nil),     // 2: fitglue.UploadStatusCheck
+	(*EnrichedActivityEvent)(nil), // 3: fitglue.events.EnrichedActivityEvent
+	(*timestamp.Timestamp)(nil),   // 4: google.protobuf.Timestamp
+}
+var file_pending_upload_proto_depIdxs = []int32{
+	0, // 0: fitglue.PendingUpload.status:type_name -> fitglue.PendingUpload.Status
+	3, // 1: fitglue.PendingUpload.original_event:type_name -> fitglue.events.EnrichedActivityEvent
+	4, // 2: fitglue.PendingUpload.created_at:type_name -> google.protobuf.Timestamp
+	4, // 3: fitglue.PendingUpload.updated_at:type_name -> google.protobuf.Timestamp
+	4, // 4: fitglue.PendingUpload.completed_at:type_name -> google.protobuf.Timestamp
+	5, // [5:5] is the sub-list for method output_type
+	5, // [5:5] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_pending_upload_proto_init() }
+func file_pending_upload_proto_init() {
+	if File_pending_upload_proto != nil {
+		return
+	}
+	file_events_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pending_upload_proto_rawDesc), len(file_pending_upload_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_pending_upload_proto_goTypes,
+		DependencyIndexes: file_pending_upload_proto_depIdxs,
+		EnumInfos:         file_pending_upload_proto_enumTypes,
+		MessageInfos:      file_pending_upload_proto_msgTypes,
+	}.Build()
+	File_pending_upload_proto = out.File
+	file_pending_upload_proto_goTypes = nil
+	file_pending_upload_proto_depIdxs = nil
+}
@@ -219,6 +219,67 @@ func (ActivityType) EnumDescriptor() ([]byte, []int) {
 	return file_standardized_activity_proto_rawDescGZIP(), []int{0}
 }
 
+type SwimStroke int32
+
+const (
+	SwimStroke_SWIM_STROKE_UNSPECIFIED  SwimStroke = 0
+	SwimStroke_SWIM_STROKE_FREESTYLE    SwimStroke = 1
+	SwimStroke_SWIM_STROKE_BACKSTROKE   SwimStroke = 2
+	SwimStroke_SWIM_STROKE_BREASTSTROKE SwimStroke = 3
+	SwimStroke_SWIM_STROKE_BUTTERFLY    SwimStroke = 4
+	SwimStroke_SWIM_STROKE_DRILL        SwimStroke = 5
+	SwimStroke_SWIM_STROKE_MIXED        SwimStroke = 6
+)
+
+// Enum value maps for SwimStroke.
+var (
+	SwimStroke_name = map[int32]string{
+		0: "SWIM_STROKE_UNSPECIFIED",
+		1: "SWIM_STROKE_FREESTYLE",
+		2: "SWIM_STROKE_BACKSTROKE",
+		3: "SWIM_STROKE_BREASTSTROKE",
+		4: "SWIM_STROKE_BUTTERFLY",
+		5: "SWIM_STROKE_DRILL",
+		6: "SWIM_STROKE_MIXED",
+	}
+	SwimStroke_value = map[string]int32{
+		"SWIM_STROKE_UNSPECIFIED":  0,
+		"SWIM_STROKE_FREESTYLE":    1,
+		"SWIM_STROKE_BACKSTROKE":   2,
+		"SWIM_STROKE_BREASTSTROKE": 3,
+		"SWIM_STROKE_BUTTERFLY":    4,
+		"SWIM_STROKE_DRILL":        5,
+		"SWIM_STROKE_MIXED":        6,
+	}
+)
+
+func (x SwimStroke) Enum() *SwimStroke {
+	p := new(SwimStroke)
+	*p = x
+	return p
+}
+
+func (x SwimStroke) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SwimStroke) Descriptor() protoreflect.EnumDescriptor {
+	return file_standardized_activity_proto_enumTypes[1].Descriptor()
+}
+
+func (SwimStroke) Type() protoreflect.EnumType {
+	return &file_standardized_activity_proto_enumTypes[1]
+}
+
+func (x SwimStroke) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SwimStroke.Descriptor instead.
+func (SwimStroke) EnumDescriptor() ([]byte, []int) {
+	return file_standardized_activity_proto_rawDescGZIP(), []int{1}
+}
+
 type MuscleGroup int32
 
 const (
@@ -306,11 +367,11 @@ func (x MuscleGroup) String() string {
 }
 
 func (MuscleGroup) Descriptor() protoreflect.EnumDescriptor {
-	return file_standardized_activity_proto_enumTypes[1].Descriptor()
+	return file_standardized_activity_proto_enumTypes[2].Descriptor()
 }
 
 func (MuscleGroup) Type() protoreflect.EnumType {
-	return &file_standardized_activity_proto_enumTypes[1]
+	return &file_standardized_activity_proto_enumTypes[2]
 }
 
 func (x MuscleGroup) Number() protoreflect.EnumNumber {
@@ -319,7 +380,7 @@ func (x MuscleGroup) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use MuscleGroup.Descriptor instead.
 func (MuscleGroup) EnumDescriptor() ([]byte, []int) {
-	return file_standardized_activity_proto_rawDescGZIP(), []int{1}
+	return file_standardized_activity_proto_rawDescGZIP(), []int{2}
 }
 
 // StandardizedActivity represents a normalized fitness activity.
@@ -340,9 +401,11 @@ type StandardizedActivity struct {
 	// Hierarchy
 	Sessions []*Session `protobuf:"bytes,7,rep,name=sessions,proto3" json:"sessions,omitempty"`
 	// Rich metadata (preserved from source)
-	Description   string   `protobuf:"bytes,8,opt,name=description,proto3" json:"description,omitempty"` // User notes
-	Tags          []string `protobuf:"bytes,9,rep,name=tags,proto3" json:"tags,omitempty"`
-	Notes         string   `protobuf:"bytes,10,opt,name=notes,proto3" json:"notes,omitempty"`
+	Description string   `protobuf:"bytes,8,opt,name=description,proto3" json:"description,omitempty"` // User notes
+	Tags        []string `protobuf:"bytes,9,rep,name=tags,proto3" json:"tags,omitempty"`
+	Notes       string   `protobuf:"bytes,10,opt,name=notes,proto3" json:"notes,omitempty"`
+	// Recording device (e.g. from an imported FIT file). Unset for app sources.
+	Device        *DeviceInfo `protobuf:"bytes,11,opt,name=device,proto3" json:"device,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -447,6 +510,90 @@ func (x *StandardizedActivity) GetNotes() string {
 	return ""
 }
 
+func (x *StandardizedActivity) GetDevice() *DeviceInfo {
+	if x != nil {
+		return x.Device
+	}
+	return nil
+}
+
+// DeviceInfo identifies the device or app that recorded an activity.
+type DeviceInfo struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Manufacturer    string                 `protobuf:"bytes,1,opt,name=manufacturer,proto3" json:"manufacturer,omitempty"` // FIT manufacturer name, e.g. "garmin"
+	Product         uint32                 `protobuf:"varint,2,opt,name=product,proto3" json:"product,omitempty"`          // Manufacturer-specific product ID
+	ProductName     string                 `protobuf:"bytes,3,opt,name=product_name,json=productName,proto3" json:"product_name,omitempty"`
+	SerialNumber    uint32                 `protobuf:"varint,4,opt,name=serial_number,json=serialNumber,proto3" json:"serial_number,omitempty"`
+	SoftwareVersion string                 `protobuf:"bytes,5,opt,name=software_version,json=softwareVersion,proto3" json:"software_version,omitempty"` // e.g. "12.34"
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *DeviceInfo) Reset() {
+	*x = DeviceInfo{}
+	mi := &file_standardized_activity_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeviceInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeviceInfo) ProtoMessage() {}
+
+func (x *DeviceInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_standardized_activity_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeviceInfo.ProtoReflect.Descriptor instead.
+func (*DeviceInfo) Descriptor() ([]byte, []int) {
+	return file_standardized_activity_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DeviceInfo) GetManufacturer() string {
+	if x != nil {
+		return x.Manufacturer
+	}
+	return ""
+}
+
+func (x *DeviceInfo) GetProduct() uint32 {
+	if x != nil {
+		return x.Product
+	}
+	return 0
+}
+
+func (x *DeviceInfo) GetProductName() string {
+	if x != nil {
+		return x.ProductName
+	}
+	return ""
+}
+
+func (x *DeviceInfo) GetSerialNumber() uint32 {
+	if x != nil {
+		return x.SerialNumber
+	}
+	return 0
+}
+
+func (x *DeviceInfo) GetSoftwareVersion() string {
+	if x != nil {
+		return x.SoftwareVersion
+	}
+	return ""
+}
+
 type Session struct {
 	state            protoimpl.MessageState `protogen:"open.v1"`
 	StartTime        *timestamp.Timestamp   `protobuf:"bytes,1,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
@@ -454,14 +601,21 @@ type Session struct {
 	TotalDistance    float64                `protobuf:"fixed64,3,opt,name=total_distance,json=totalDistance,proto3" json:"total_distance,omitempty"`            // meters
 	Laps             []*Lap                 `protobuf:"bytes,4,rep,name=laps,proto3" json:"laps,omitempty"`
 	// High-fidelity strength data (not just 1Hz streams)
-	StrengthSets  []*StrengthSet `protobuf:"bytes,5,rep,name=strength_sets,json=strengthSets,proto3" json:"strength_sets,omitempty"`
+	StrengthSets []*StrengthSet `protobuf:"bytes,5,rep,name=strength_sets,json=strengthSets,proto3" json:"strength_sets,omitempty"`
+	// Explicit timer pauses (auto-pause, manual stop/resume).
+	// Time spent paused is excluded from the total timer (moving) time.
+	Pauses []*Pause `protobuf:"bytes,6,rep,name=pauses,proto3" json:"pauses,omitempty"`
+	// Swimming: pool length in meters (0 = open water / unknown) and
+	// per-length data for pool swims.
+	PoolLength    float64       `protobuf:"fixed64,7,opt,name=pool_length,json=poolLength,proto3" json:"pool_length,omitempty"`
+	Lengths       []*SwimLength `protobuf:"bytes,8,rep,name=lengths,proto3" json:"lengths,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Session) Reset() {
 	*x = Session{}
-	mi := &file_standardized_activity_proto_msgTypes[1]
+	mi := &file_standardized_activity_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -473,7 +627,7 @@ func (x *Session) String() string {
 func (*Session) ProtoMessage() {}
 
 func (x *Session) ProtoReflect() protoreflect.Message {
-	mi := &file_standardized_activity_proto_msgTypes[1]
+	mi := &file_standardized_activity_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -486,7 +640,7 @@ func (x *Session) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Session.ProtoReflect.Descriptor instead.
 func (*Session) Descriptor() ([]byte, []int) {
-	return file_standardized_activity_proto_rawDescGZIP(), []int{1}
+	return file_standardized_activity_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *Session) GetStartTime() *timestamp.Timestamp {
@@ -524,6 +678,157 @@ func (x *Session) GetStrengthSets() []*StrengthSet {
 	return nil
 }
 
+func (x *Session) GetPauses() []*Pause {
+	if x != nil {
+		return x.Pauses
+	}
+	return nil
+}
+
+func (x *Session) GetPoolLength() float64 {
+	if x != nil {
+		return x.PoolLength
+	}
+	return 0
+}
+
+func (x *Session) GetLengths() []*SwimLength {
+	if x != nil {
+		return x.Lengths
+	}
+	return nil
+}
+
+// Pause represents a period during which the activity timer was stopped.
+type Pause struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StartTime     *timestamp.Timestamp   `protobuf:"bytes,1,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime       *timestamp.Timestamp   `protobuf:"bytes,2,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Pause) Reset() {
+	*x = Pause{}
+	mi := &file_standardized_activity_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Pause) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Pause) ProtoMessage() {}
+
+func (x *Pause) ProtoReflect() protoreflect.Message {
+	mi := &file_standardized_activity_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Pause.ProtoReflect.Descriptor instead.
+func (*Pause) Descriptor() ([]byte, []int) {
+	return file_standardized_activity_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Pause) GetStartTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *Pause) GetEndTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+// SwimLength represents a single pool length (or a rest period at the wall).
+type SwimLength struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	StartTime        *timestamp.Timestamp   `protobuf:"bytes,1,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	TotalElapsedTime float64                `protobuf:"fixed64,2,opt,name=total_elapsed_time,json=totalElapsedTime,proto3" json:"total_elapsed_time,omitempty"` // seconds
+	Stroke           SwimStroke             `protobuf:"varint,3,opt,name=stroke,proto3,enum=fitglue.SwimStroke" json:"stroke,omitempty"`
+	TotalStrokes     int32                  `protobuf:"varint,4,opt,name=total_strokes,json=totalStrokes,proto3" json:"total_strokes,omitempty"`
+	Idle             bool                   `protobuf:"varint,5,opt,name=idle,proto3" json:"idle,omitempty"` // Rest period with no strokes
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *SwimLength) Reset() {
+	*x = SwimLength{}
+	mi := &file_standardized_activity_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SwimLength) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SwimLength) ProtoMessage() {}
+
+func (x *SwimLength) ProtoReflect() protoreflect.Message {
+	mi := &file_standardized_activity_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SwimLength.ProtoReflect.Descriptor instead.
+func (*SwimLength) Descriptor() ([]byte, []int) {
+	return file_standardized_activity_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SwimLength) GetStartTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *SwimLength) GetTotalElapsedTime() float64 {
+	if x != nil {
+		return x.TotalElapsedTime
+	}
+	return 0
+}
+
+func (x *SwimLength) GetStroke() SwimStroke {
+	if x != nil {
+		return x.Stroke
+	}
+	return SwimStroke_SWIM_STROKE_UNSPECIFIED
+}
+
+func (x *SwimLength) GetTotalStrokes() int32 {
+	if x != nil {
+		return x.TotalStrokes
+	}
+	return 0
+}
+
+func (x *SwimLength) GetIdle() bool {
+	if x != nil {
+		return x.Idle
+	}
+	return false
+}
+
 type Lap struct {
 	state            protoimpl.MessageState `protogen:"open.v1"`
 	StartTime        *timestamp.Timestamp   `protobuf:"bytes,1,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
@@ -536,7 +841,7 @@ type Lap struct {
 
 func (x *Lap) Reset() {
 	*x = Lap{}
-	mi := &file_standardized_activity_proto_msgTypes[2]
+	mi := &file_standardized_activity_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -548,7 +853,7 @@ func (x *Lap) String() string {
 func (*Lap) ProtoMessage() {}
 
 func (x *Lap) ProtoReflect() protoreflect.Message {
-	mi := &file_standardized_activity_proto_msgTypes[2]
+	mi := &file_standardized_activity_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -561,7 +866,7 @@ func (x *Lap) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Lap.ProtoReflect.Descriptor instead.
 func (*Lap) Descriptor() ([]byte, []int) {
-	return file_standardized_activity_proto_rawDescGZIP(), []int{2}
+	return file_standardized_activity_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *Lap) GetStartTime() *timestamp.Timestamp {
@@ -611,7 +916,7 @@ type Record struct {
 
 func (x *Record) Reset() {
 	*x = Record{}
-	mi := &file_standardized_activity_proto_msgTypes[3]
+	mi := &file_standardized_activity_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -623,7 +928,7 @@ func (x *Record) String() string {
 func (*Record) ProtoMessage() {}
 
 func (x *Record) ProtoReflect() protoreflect.Message {
-	mi := &file_standardized_activity_proto_msgTypes[3]
+	mi := &file_standardized_activity_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -636,7 +941,7 @@ func (x *Record) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Record.ProtoReflect.Descriptor instead.
 func (*Record) Descriptor() ([]byte, []int) {
-	return file_standardized_activity_proto_rawDescGZIP(), []int{3}
+	return file_standardized_activity_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *Record) GetTimestamp() *timestamp.Timestamp {
@@ -720,7 +1025,7 @@ type StrengthSet struct {
 
 func (x *StrengthSet) Reset() {
 	*x = StrengthSet{}
-	mi := &file_standardized_activity_proto_msgTypes[4]
+	mi := &file_standardized_activity_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -732,7 +1037,7 @@ func (x *StrengthSet) String() string {
 func (*StrengthSet) ProtoMessage() {}
 
 func (x *StrengthSet) ProtoReflect() protoreflect.Message {
-	mi := &file_standardized_activity_proto_msgTypes[4]
+	mi := &file_standardized_activity_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -745,7 +1050,7 @@ func (x *StrengthSet) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StrengthSet.ProtoReflect.Descriptor instead.
 func (*StrengthSet) Descriptor() ([]byte, []int) {
-	return file_standardized_activity_proto_rawDescGZIP(), []int{4}
+	return file_standardized_activity_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *StrengthSet) GetExerciseName() string {
@@ -846,7 +1151,7 @@ var File_standardized_activity_proto protoreflect.FileDescriptor
 
 const file_standardized_activity_proto_rawDesc = "" +
 	"\n" +
-	"\x1bstandardized_activity.proto\x12\afitglue\x1a\x1fgoogle/protobuf/timestamp.proto\x1a google/protobuf/descriptor.proto\"\xdc\x02\n" +
+	"\x1bstandardized_activity.proto\x12\afitglue\x1a\x1fgoogle/protobuf/timestamp.proto\x1a google/protobuf/descriptor.proto\"\x89\x03\n" +
 	"\x14StandardizedActivity\x12\x16\n" +
 	"\x06source\x18\x01 \x01(\tR\x06source\x12\x1f\n" +
 	"\vexternal_id\x18\x02 \x01(\tR\n" +
@@ -860,14 +1165,38 @@ const file_standardized_activity_proto_rawDesc = "" +
 	"\vdescription\x18\b \x01(\tR\vdescription\x12\x12\n" +
 	"\x04tags\x18\t \x03(\tR\x04tags\x12\x14\n" +
 	"\x05notes\x18\n" +
-	" \x01(\tR\x05notes\"\xf6\x01\n" +
+	" \x01(\tR\x05notes\x12+\n" +
+	"\x06device\x18\v \x01(\v2\x13.fitglue.DeviceInfoR\x06device\"\xbd\x01\n" +
+	"\n" +
+	"DeviceInfo\x12\"\n" +
+	"\fmanufacturer\x18\x01 \x01(\tR\fmanufacturer\x12\x18\n" +
+	"\aproduct\x18\x02 \x01(\rR\aproduct\x12!\n" +
+	"\fproduct_name\x18\x03 \x01(\tR\vproductName\x12#\n" +
+	"\rserial_number\x18\x04 \x01(\rR\fserialNumber\x12)\n" +
+	"\x10software_version\x18\x05 \x01(\tR\x0fsoftwareVersion\"\xee\x02\n" +
 	"\aSession\x129\n" +
 	"\n" +
 	"start_time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x12,\n" +
 	"\x12total_elapsed_time\x18\x02 \x01(\x01R\x10totalElapsedTime\x12%\n" +
 	"\x0etotal_distance\x18\x03 \x01(\x01R\rtotalDistance\x12 \n" +
 	"\x04laps\x18\x04 \x03(\v2\f.fitglue.LapR\x04laps\x129\n" +
-	"\rstrength_sets\x18\x05 \x03(\v2\x14.fitglue.StrengthSetR\fstrengthSets\"\xc0\x01\n" +
+	"\rstrength_sets\x18\x05 \x03(\v2\x14.fitglue.StrengthSetR\fstrengthSets\x12&\n" +
+	"\x06pauses\x18\x06 \x03(\v2\x0e.fitglue.PauseR\x06pauses\x12\x1f\n" +
+	"\vpool_length\x18\a \x01(\x01R\n" +
+	"poolLength\x12-\n" +
+	"\alengths\x18\b \x03(\v2\x13.fitglue.SwimLengthR\alengths\"y\n" +
+	"\x05Pause\x129\n" +
+	"\n" +
+	"start_time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bend_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\"\xdb\x01\n" +
+	"\n" +
+	"SwimLength\x129\n" +
+	"\n" +
+	"start_time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x12,\n" +
+	"\x12total_elapsed_time\x18\x02 \x01(\x01R\x10totalElapsedTime\x12+\n" +
+	"\x06stroke\x18\x03 \x01(\x0e2\x13.fitglue.SwimStrokeR\x06stroke\x12#\n" +
+	"\rtotal_strokes\x18\x04 \x01(\x05R\ftotalStrokes\x12\x12\n" +
+	"\x04idle\x18\x05 \x01(\bR\x04idle\"\xc0\x01\n" +
 	"\x03Lap\x129\n" +
 	"\n" +
 	"start_time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x12,\n" +
@@ -963,7 +1292,16 @@ const file_standardized_activity_proto_rawDesc = "" +
 	"Wheelchair\x12(\n" +
 	"\x16ACTIVITY_TYPE_WINDSURF\x100\x1a\fҵ\x18\bWindsurf\x12&\n" +
 	"\x15ACTIVITY_TYPE_WORKOUT\x101\x1a\vҵ\x18\aWorkout\x12 \n" +
-	"\x12ACTIVITY_TYPE_YOGA\x102\x1a\bҵ\x18\x04Yoga*\xbb\x04\n" +
+	"\x12ACTIVITY_TYPE_YOGA\x102\x1a\bҵ\x18\x04Yoga*\xc7\x01\n" +
+	"\n" +
+	"SwimStroke\x12\x1b\n" +
+	"\x17SWIM_STROKE_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15SWIM_STROKE_FREESTYLE\x10\x01\x12\x1a\n" +
+	"\x16SWIM_STROKE_BACKSTROKE\x10\x02\x12\x1c\n" +
+	"\x18SWIM_STROKE_BREASTSTROKE\x10\x03\x12\x19\n" +
+	"\x15SWIM_STROKE_BUTTERFLY\x10\x04\x12\x15\n" +
+	"\x11SWIM_STROKE_DRILL\x10\x05\x12\x15\n" +
+	"\x11SWIM_STROKE_MIXED\x10\x06*\xbb\x04\n" +
 	"\vMuscleGroup\x12\x1c\n" +
 	"\x18MUSCLE_GROUP_UNSPECIFIED\x10\x00\x12\x1b\n" +
 	"\x17MUSCLE_GROUP_ABDOMINALS\x10\x01\x12\x1a\n" +
@@ -1002,38 +1340,49 @@ func file_standardized_activity_proto_rawDescGZIP() []byte {
 	return file_standardized_activity_proto_rawDescData
 }
 
-var file_standardized_activity_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_standardized_activity_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_standardized_activity_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_standardized_activity_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
 var file_standardized_activity_proto_goTypes = []any{
 	(ActivityType)(0),                   // 0: fitglue.ActivityType
-	(MuscleGroup)(0),                    // 1: fitglue.MuscleGroup
-	(*StandardizedActivity)(nil),        // 2: fitglue.StandardizedActivity
-	(*Session)(nil),                     // 3: fitglue.Session
-	(*Lap)(nil),                         // 4: fitglue.Lap
-	(*Record)(nil),                      // 5: fitglue.Record
-	(*StrengthSet)(nil),                 // 6: fitglue.StrengthSet
-	(*timestamp.Timestamp)(nil),         // 7: google.protobuf.Timestamp
-	(*descriptor.EnumValueOptions)(nil), // 8: google.protobuf.EnumValueOptions
+	(SwimStroke)(0),                     // 1: fitglue.SwimStroke
+	(MuscleGroup)(0),                    // 2: fitglue.MuscleGroup
+	(*StandardizedActivity)(nil),        // 3: fitglue.StandardizedActivity
+	(*DeviceInfo)(nil),                  // 4: fitglue.DeviceInfo
+	(*Session)(nil),                     // 5: fitglue.Session
+	(*Pause)(nil),                       // 6: fitglue.Pause
+	(*SwimLength)(nil),                  // 7: fitglue.SwimLength
+	(*Lap)(nil),                         // 8: fitglue.Lap
+	(*Record)(nil),                      // 9: fitglue.Record
+	(*StrengthSet)(nil),                 // 10: fitglue.StrengthSet
+	(*timestamp.Timestamp)(nil),         // 11: google.protobuf.Timestamp
+	(*descriptor.EnumValueOptions)(nil), // 12: google.protobuf.EnumValueOptions
 }
 var file_standardized_activity_proto_depIdxs = []int32{
-	7,  // 0: fitglue.StandardizedActivity.start_time:type_name -> google.protobuf.Timestamp
+	11, // 0: fitglue.StandardizedActivity.start_time:type_name -> google.protobuf.Timestamp
 	0,  // 1: fitglue.StandardizedActivity.type:type_name -> fitglue.ActivityType
-	3,  // 2: fitglue.StandardizedActivity.sessions:type_name -> fitglue.Session
-	7,  // 3: fitglue.Session.start_time:type_name -> google.protobuf.Timestamp
-	4,  // 4: fitglue.Session.laps:type_name -> fitglue.Lap
-	6,  // 5: fitglue.Session.strength_sets:type_name -> fitglue.StrengthSet
-	7,  // 6: fitglue.Lap.start_time:type_name -> google.protobuf.Timestamp
-	5,  // 7: fitglue.Lap.records:type_name -> fitglue.Record
-	7,  // 8: fitglue.Record.timestamp:type_name -> google.protobuf.Timestamp
-	7,  // 9: fitglue.StrengthSet.start_time:type_name -> google.protobuf.Timestamp
-	1,  // 10: fitglue.StrengthSet.primary_muscle_group:type_name -> fitglue.MuscleGroup
-	1,  // 11: fitglue.StrengthSet.secondary_muscle_groups:type_name -> fitglue.MuscleGroup
-	8,  // 12: fitglue.strava_name:extendee -> google.protobuf.EnumValueOptions
-	13, // [13:13] is the sub-list for method output_type
-	13, // [13:13] is the sub-list for method input_type
-	13, // [13:13] is the sub-list for extension type_name
-	12, // [12:13] is the sub-list for extension extendee
-	0,  // [0:12] is the sub-list for field type_name
+	5,  // 2: fitglue.StandardizedActivity.sessions:type_name -> fitglue.Session
+	4,  // 3: fitglue.StandardizedActivity.device:type_name -> fitglue.DeviceInfo
+	11, // 4: fitglue.Session.start_time:type_name -> google.protobuf.Timestamp
+	8,  // 5: fitglue.Session.laps:type_name -> fitglue.Lap
+	10, // 6: fitglue.Session.strength_sets:type_name -> fitglue.StrengthSet
+	6,  // 7: fitglue.Session.pauses:type_name -> fitglue.Pause
+	7,  // 8: fitglue.Session.lengths:type_name -> fitglue.SwimLength
+	11, // 9: fitglue.Pause.start_time:type_name -> google.protobuf.Timestamp
+	11, // 10: fitglue.Pause.end_time:type_name -> google.protobuf.Timestamp
+	11, // 11: fitglue.SwimLength.start_time:type_name -> google.protobuf.Timestamp
+	1,  // 12: fitglue.SwimLength.stroke:type_name -> fitglue.SwimStroke
+	11, // 13: fitglue.Lap.start_time:type_name -> google.protobuf.Timestamp
+	9,  // 14: fitglue.Lap.records:type_name -> fitglue.Record
+	11, // 15: fitglue.Record.timestamp:type_name -> google.protobuf.Timestamp
+	11, // 16: fitglue.StrengthSet.start_time:type_name -> google.protobuf.Timestamp
+	2,  // 17: fitglue.StrengthSet.primary_muscle_group:type_name -> fitglue.MuscleGroup
+	2,  // 18: fitglue.StrengthSet.secondary_muscle_groups:type_name -> fitglue.MuscleGroup
+	12, // 19: fitglue.strava_name:extendee -> google.protobuf.EnumValueOptions
+	20, // [20:20] is the sub-list for method output_type
+	20, // [20:20] is the sub-list for method input_type
+	20, // [20:20] is the sub-list for extension type_name
+	19, // [19:20] is the sub-list for extension extendee
+	0,  // [0:19] is the sub-list for field type_name
 }
 
 func init() { file_standardized_activity_proto_init() }
@@ -1046,8 +1395,8 @@ func file_standardized_activity_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_standardized_activity_proto_rawDesc), len(file_standardized_activity_proto_rawDesc)),
-			NumEnums:      2,
-			NumMessages:   5,
+			NumEnums:      3,
+			NumMessages:   8,
 			NumExtensions: 1,
 			NumServices:   0,
 		},
@@ -22,6 +22,59 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// SourceChangePolicy controls how updates and deletions at a source reach the destinations
+type SourceChangePolicy int32
+
+const (
+	SourceChangePolicy_SOURCE_CHANGE_POLICY_UNSPECIFIED     SourceChangePolicy = 0 // Same as IGNORE
+	SourceChangePolicy_SOURCE_CHANGE_POLICY_IGNORE          SourceChangePolicy = 1 // Leave destination activities unchanged
+	SourceChangePolicy_SOURCE_CHANGE_POLICY_UPDATE_METADATA SourceChangePolicy = 2 // Copy name, description and type; delete on source deletion
+	SourceChangePolicy_SOURCE_CHANGE_POLICY_FULL_RESYNC     SourceChangePolicy = 3 // Re-run the pipeline and update the destinations; delete on source deletion
+)
+
+// Enum value maps for SourceChangePolicy.
+var (
+	SourceChangePolicy_name = map[int32]string{
+		0: "SOURCE_CHANGE_POLICY_UNSPECIFIED",
+		1: "SOURCE_CHANGE_POLICY_IGNORE",
+		2: "SOURCE_CHANGE_POLICY_UPDATE_METADATA",
+		3: "SOURCE_CHANGE_POLICY_FULL_RESYNC",
+	}
+	SourceChangePolicy_value = map[string]int32{
+		"SOURCE_CHANGE_POLICY_UNSPECIFIED":     0,
+		"SOURCE_CHANGE_POLICY_IGNORE":          1,
+		"SOURCE_CHANGE_POLICY_UPDATE_METADATA": 2,
+		"SOURCE_CHANGE_POLICY_FULL_RESYNC":     3,
+	}
+)
+
+func (x SourceChangePolicy) Enum() *SourceChangePolicy {
+	p := new(SourceChangePolicy)
+	*p = x
+	return p
+}
+
+func (x SourceChangePolicy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SourceChangePolicy) Descriptor() protoreflect.EnumDescriptor {
+	return file_user_proto_enumTypes[0].Descriptor()
+}
+
+func (SourceChangePolicy) Type() protoreflect.EnumType {
+	return &file_user_proto_enumTypes[0]
+}
+
+func (x SourceChangePolicy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SourceChangePolicy.Descriptor instead.
+func (SourceChangePolicy) EnumDescriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{0}
+}
+
 type EnricherProviderType int32
 
 const (
@@ -88,11 +141,11 @@ func (x EnricherProviderType) String() string {
 }
 
 func (EnricherProviderType) Descriptor() protoreflect.EnumDescriptor {
-	return file_user_proto_enumTypes[0].Descriptor()
+	return file_user_proto_enumTypes[1].Descriptor()
 }
 
 func (EnricherProviderType) Type() protoreflect.EnumType {
-	return &file_user_proto_enumTypes[0]
+	return &file_user_proto_enumTypes[1]
 }
 
 func (x EnricherProviderType) Number() protoreflect.EnumNumber {
@@ -101,7 +154,7 @@ func (x EnricherProviderType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use EnricherProviderType.Descriptor instead.
 func (EnricherProviderType) EnumDescriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{0}
+	return file_user_proto_rawDescGZIP(), []int{1}
 }
 
 // Workout Summary format styles
@@ -141,11 +194,11 @@ func (x WorkoutSummaryFormat) String() string {
 }
 
 func (WorkoutSummaryFormat) Descriptor() protoreflect.EnumDescriptor {
-	return file_user_proto_enumTypes[1].Descriptor()
+	return file_user_proto_enumTypes[2].Descriptor()
 }
 
 func (WorkoutSummaryFormat) Type() protoreflect.EnumType {
-	return &file_user_proto_enumTypes[1]
+	return &file_user_proto_enumTypes[2]
 }
 
 func (x WorkoutSummaryFormat) Number() protoreflect.EnumNumber {
@@ -154,7 +207,7 @@ func (x WorkoutSummaryFormat) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use WorkoutSummaryFormat.Descriptor instead.
 func (WorkoutSummaryFormat) EnumDescriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{1}
+	return file_user_proto_rawDescGZIP(), []int{2}
 }
 
 // Muscle Heatmap visualization styles
@@ -194,11 +247,11 @@ func (x MuscleHeatmapStyle) String() string {
 }
 
 func (MuscleHeatmapStyle) Descriptor() protoreflect.EnumDescriptor {
-	return file_user_proto_enumTypes[2].Descriptor()
+	return file_user_proto_enumTypes[3].Descriptor()
 }
 
 func (MuscleHeatmapStyle) Type() protoreflect.EnumType {
-	return &file_user_proto_enumTypes[2]
+	return &file_user_proto_enumTypes[3]
 }
 
 func (x MuscleHeatmapStyle) Number() protoreflect.EnumNumber {
@@ -207,7 +260,7 @@ func (x MuscleHeatmapStyle) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use MuscleHeatmapStyle.Descriptor instead.
 func (MuscleHeatmapStyle) EnumDescriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{2}
+	return file_user_proto_rawDescGZIP(), []int{3}
 }
 
 // Muscle Heatmap coefficient presets
@@ -247,11 +300,11 @@ func (x MuscleHeatmapPreset) String() string {
 }
 
 func (MuscleHeatmapPreset) Descriptor() protoreflect.EnumDescriptor {
-	return file_user_proto_enumTypes[3].Descriptor()
+	return file_user_proto_enumTypes[4].Descriptor()
 }
 
 func (MuscleHeatmapPreset) Type() protoreflect.EnumType {
-	return &file_user_proto_enumTypes[3]
+	return &file_user_proto_enumTypes[4]
 }
 
 func (x MuscleHeatmapPreset) Number() protoreflect.EnumNumber {
@@ -260,7 +313,7 @@ func (x MuscleHeatmapPreset) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use MuscleHeatmapPreset.Descriptor instead.
 func (MuscleHeatmapPreset) EnumDescriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{3}
+	return file_user_proto_rawDescGZIP(), []int{4}
 }
 
 // Virtual GPS route options
@@ -297,11 +350,11 @@ func (x VirtualGPSRoute) String() string {
 }
 
 func (VirtualGPSRoute) Descriptor() protoreflect.EnumDescriptor {
-	return file_user_proto_enumTypes[4].Descriptor()
+	return file_user_proto_enumTypes[5].Descriptor()
 }
 
 func (VirtualGPSRoute) Type() protoreflect.EnumType {
-	return &file_user_proto_enumTypes[4]
+	return &file_user_proto_enumTypes[5]
 }
 
 func (x VirtualGPSRoute) Number() protoreflect.EnumNumber {
@@ -310,7 +363,7 @@ func (x VirtualGPSRoute) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use VirtualGPSRoute.Descriptor instead.
 func (VirtualGPSRoute) EnumDescriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{4}
+	return file_user_proto_rawDescGZIP(), []int{5}
 }
 
 type UserRecord struct {
@@ -332,8 +385,12 @@ type UserRecord struct {
 	SyncCountResetAt   *timestamp.Timestamp `protobuf:"bytes,10,opt,name=sync_count_reset_at,json=syncCountResetAt,proto3" json:"sync_count_reset_at,omitempty"`
 	// Stripe customer ID for billing
 	StripeCustomerId string `protobuf:"bytes,11,opt,name=stripe_customer_id,json=stripeCustomerId,proto3" json:"stripe_customer_id,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+	// IANA timezone (e.g. "Europe/London") for calendar-based features; empty = UTC
+	Timezone string `protobuf:"bytes,12,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	// What happens to synchronized destination activities when the source activity changes
+	SourceChangePolicy SourceChangePolicy `protobuf:"varint,13,opt,name=source_change_policy,json=sourceChangePolicy,proto3,enum=fitglue.SourceChangePolicy" json:"source_change_policy,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
 func (x *UserRecord) Reset() {
@@ -443,14 +500,30 @@ func (x *UserRecord) GetStripeCustomerId() string {
 	return ""
 }
 
+func (x *UserRecord) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *UserRecord) GetSourceChangePolicy() SourceChangePolicy {
+	if x != nil {
+		return x.SourceChangePolicy
+	}
+	return SourceChangePolicy_SOURCE_CHANGE_POLICY_UNSPECIFIED
+}
+
 type PipelineConfig struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`         // Unique ID (uuid) for tracing
-	Source        string                 `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"` // e.g. "SOURCE_HEVY"
-	Enrichers     []*EnricherConfig      `protobuf:"bytes,3,rep,name=enrichers,proto3" json:"enrichers,omitempty"`
-	Destinations  []Destination          `protobuf:"varint,4,rep,packed,name=destinations,proto3,enum=fitglue.events.Destination" json:"destinations,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Id           string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`         // Unique ID (uuid) for tracing
+	Source       string                 `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"` // e.g. "SOURCE_HEVY"
+	Enrichers    []*EnricherConfig      `protobuf:"bytes,3,rep,name=enrichers,proto3" json:"enrichers,omitempty"`
+	Destinations []Destination          `protobuf:"varint,4,rep,packed,name=destinations,proto3,enum=fitglue.events.Destination" json:"destinations,omitempty"`
+	// Per-destination options, keyed by destination name (e.g. "strava")
+	DestinationOptions map[string]*DestinationOptions `protobuf:"bytes,5,rep,name=destination_options,json=destinationOptions,proto3" json:"destination_options,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
 func (x *PipelineConfig) Reset() {
@@ -511,12 +584,21 @@ func (x *PipelineConfig) GetDestinations() []Destination {
 	return nil
 }
 
+func (x *PipelineConfig) GetDestinationOptions() map[string]*DestinationOptions {
+	if x != nil {
+		return x.DestinationOptions
+	}
+	return nil
+}
+
 type UserIntegrations struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Hevy          *HevyIntegration       `protobuf:"bytes,1,opt,name=hevy,proto3" json:"hevy,omitempty"`
-	Fitbit        *FitbitIntegration     `protobuf:"bytes,2,opt,name=fitbit,proto3" json:"fitbit,omitempty"`
-	Strava        *StravaIntegration     `protobuf:"bytes,3,opt,name=strava,proto3" json:"strava,omitempty"`
-	Mock          *MockIntegration       `protobuf:"bytes,4,opt,name=mock,proto3" json:"mock,omitempty"`
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	Hevy          *HevyIntegration         `protobuf:"bytes,1,opt,name=hevy,proto3" json:"hevy,omitempty"`
+	Fitbit        *FitbitIntegration       `protobuf:"bytes,2,opt,name=fitbit,proto3" json:"fitbit,omitempty"`
+	Strava        *StravaIntegration       `protobuf:"bytes,3,opt,name=strava,proto3" json:"strava,omitempty"`
+	Mock          *MockIntegration         `protobuf:"bytes,4,opt,name=mock,proto3" json:"mock,omitempty"`
+	Webhook       *WebhookIntegration      `protobuf:"bytes,5,opt,name=webhook,proto3" json:"webhook,omitempty"`
+	IntervalsIcu  *IntervalsIcuIntegration `protobuf:"bytes,6,opt,name=intervals_icu,json=intervalsIcu,proto3" json:"intervals_icu,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -579,6 +661,20 @@ func (x *UserIntegrations) GetMock() *MockIntegration {
 	return nil
 }
 
+func (x *UserIntegrations) GetWebhook() *WebhookIntegration {
+	if x != nil {
+		return x.Webhook
+	}
+	return nil
+}
+
+func (x *UserIntegrations) GetIntervalsIcu() *IntervalsIcuIntegration {
+	if x != nil {
+		return x.IntervalsIcu
+	}
+	return nil
+}
+
 type MockIntegration struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Enabled       bool                   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
@@ -639,20 +735,177 @@ func (x *MockIntegration) GetLastUsedAt() *timestamp.Timestamp {
 	return nil
 }
 
-type HevyIntegration struct {
+// WebhookIntegration delivers activities to a user-run HTTP endpoint.
+// Requests are signed with the user's secret, "webhook-signing-secret-{user_id}" in the SecretStore.
+type WebhookIntegration struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Enabled        bool                   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	Url            string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	IncludeFitFile bool                   `protobuf:"varint,3,opt,name=include_fit_file,json=includeFitFile,proto3" json:"include_fit_file,omitempty"` // Send the FIT file alongside the event (multipart/form-data)
+	CreatedAt      *timestamp.Timestamp   `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	LastUsedAt     *timestamp.Timestamp   `protobuf:"bytes,5,opt,name=last_used_at,json=lastUsedAt,proto3" json:"last_used_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *WebhookIntegration) Reset() {
+	*x = WebhookIntegration{}
+	mi := &file_user_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookIntegration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookIntegration) ProtoMessage() {}
+
+func (x *WebhookIntegration) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookIntegration.ProtoReflect.Descriptor instead.
+func (*WebhookIntegration) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *WebhookIntegration) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *WebhookIntegration) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *WebhookIntegration) GetIncludeFitFile() bool {
+	if x != nil {
+		return x.IncludeFitFile
+	}
+	return false
+}
+
+func (x *WebhookIntegration) GetCreatedAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *WebhookIntegration) GetLastUsedAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.LastUsedAt
+	}
+	return nil
+}
+
+// IntervalsIcuIntegration authenticates with an intervals.icu API key (Settings > Developer Settings)
+type IntervalsIcuIntegration struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Enabled       bool                   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
-	ApiKey        string                 `protobuf:"bytes,2,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
-	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	AthleteId     string                 `protobuf:"bytes,2,opt,name=athlete_id,json=athleteId,proto3" json:"athlete_id,omitempty"` // e.g. "i12345"
+	ApiKey        string                 `protobuf:"bytes,3,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
 	CreatedAt     *timestamp.Timestamp   `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	LastUsedAt    *timestamp.Timestamp   `protobuf:"bytes,5,opt,name=last_used_at,json=lastUsedAt,proto3" json:"last_used_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
+func (x *IntervalsIcuIntegration) Reset() {
+	*x = IntervalsIcuIntegration{}
+	mi := &file_user_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IntervalsIcuIntegration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IntervalsIcuIntegration) ProtoMessage() {}
+
+func (x *IntervalsIcuIntegration) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IntervalsIcuIntegration.ProtoReflect.Descriptor instead.
+func (*IntervalsIcuIntegration) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *IntervalsIcuIntegration) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *IntervalsIcuIntegration) GetAthleteId() string {
+	if x != nil {
+		return x.AthleteId
+	}
+	return ""
+}
+
+func (x *IntervalsIcuIntegration) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *IntervalsIcuIntegration) GetCreatedAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *IntervalsIcuIntegration) GetLastUsedAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.LastUsedAt
+	}
+	return nil
+}
+
+type HevyIntegration struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Enabled    bool                   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	ApiKey     string                 `protobuf:"bytes,2,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	UserId     string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	CreatedAt  *timestamp.Timestamp   `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	LastUsedAt *timestamp.Timestamp   `protobuf:"bytes,5,opt,name=last_used_at,json=lastUsedAt,proto3" json:"last_used_at,omitempty"`
+	// Time up to which the Hevy change poller has read workout events
+	ChangesSyncedAt *timestamp.Timestamp `protobuf:"bytes,6,opt,name=changes_synced_at,json=changesSyncedAt,proto3" json:"changes_synced_at,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
 func (x *HevyIntegration) Reset() {
 	*x = HevyIntegration{}
-	mi := &file_user_proto_msgTypes[4]
+	mi := &file_user_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -664,7 +917,7 @@ func (x *HevyIntegration) String() string {
 func (*HevyIntegration) ProtoMessage() {}
 
 func (x *HevyIntegration) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[4]
+	mi := &file_user_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -677,7 +930,7 @@ func (x *HevyIntegration) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HevyIntegration.ProtoReflect.Descriptor instead.
 func (*HevyIntegration) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{4}
+	return file_user_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *HevyIntegration) GetEnabled() bool {
@@ -715,6 +968,13 @@ func (x *HevyIntegration) GetLastUsedAt() *timestamp.Timestamp {
 	return nil
 }
 
+func (x *HevyIntegration) GetChangesSyncedAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.ChangesSyncedAt
+	}
+	return nil
+}
+
 type FitbitIntegration struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Enabled       bool                   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
@@ -730,7 +990,7 @@ type FitbitIntegration struct {
 
 func (x *FitbitIntegration) Reset() {
 	*x = FitbitIntegration{}
-	mi := &file_user_proto_msgTypes[5]
+	mi := &file_user_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -742,7 +1002,7 @@ func (x *FitbitIntegration) String() string {
 func (*FitbitIntegration) ProtoMessage() {}
 
 func (x *FitbitIntegration) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[5]
+	mi := &file_user_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -755,7 +1015,7 @@ func (x *FitbitIntegration) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FitbitIntegration.ProtoReflect.Descriptor instead.
 func (*FitbitIntegration) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{5}
+	return file_user_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *FitbitIntegration) GetEnabled() bool {
@@ -816,7 +1076,7 @@ type SourceEnrichmentConfig struct {
 
 func (x *SourceEnrichmentConfig) Reset() {
 	*x = SourceEnrichmentConfig{}
-	mi := &file_user_proto_msgTypes[6]
+	mi := &file_user_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -828,7 +1088,7 @@ func (x *SourceEnrichmentConfig) String() string {
 func (*SourceEnrichmentConfig) ProtoMessage() {}
 
 func (x *SourceEnrichmentConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[6]
+	mi := &file_user_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -841,7 +1101,7 @@ func (x *SourceEnrichmentConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SourceEnrichmentConfig.ProtoReflect.Descriptor instead.
 func (*SourceEnrichmentConfig) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{6}
+	return file_user_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *SourceEnrichmentConfig) GetEnrichers() []*EnricherConfig {
@@ -864,7 +1124,7 @@ type EnricherConfig struct {
 
 func (x *EnricherConfig) Reset() {
 	*x = EnricherConfig{}
-	mi := &file_user_proto_msgTypes[7]
+	mi := &file_user_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -876,7 +1136,7 @@ func (x *EnricherConfig) String() string {
 func (*EnricherConfig) ProtoMessage() {}
 
 func (x *EnricherConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[7]
+	mi := &file_user_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -889,7 +1149,7 @@ func (x *EnricherConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EnricherConfig.ProtoReflect.Descriptor instead.
 func (*EnricherConfig) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{7}
+	return file_user_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *EnricherConfig) GetProviderType() EnricherProviderType {
@@ -921,7 +1181,7 @@ type StravaIntegration struct {
 
 func (x *StravaIntegration) Reset() {
 	*x = StravaIntegration{}
-	mi := &file_user_proto_msgTypes[8]
+	mi := &file_user_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -933,7 +1193,7 @@ func (x *StravaIntegration) String() string {
 func (*StravaIntegration) ProtoMessage() {}
 
 func (x *StravaIntegration) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[8]
+	mi := &file_user_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -946,7 +1206,7 @@ func (x *StravaIntegration) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StravaIntegration.ProtoReflect.Descriptor instead.
 func (*StravaIntegration) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{8}
+	return file_user_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *StravaIntegration) GetEnabled() bool {
@@ -1009,7 +1269,7 @@ type ProcessedActivityRecord struct {
 
 func (x *ProcessedActivityRecord) Reset() {
 	*x = ProcessedActivityRecord{}
-	mi := &file_user_proto_msgTypes[9]
+	mi := &file_user_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1021,7 +1281,7 @@ func (x *ProcessedActivityRecord) String() string {
 func (*ProcessedActivityRecord) ProtoMessage() {}
 
 func (x *ProcessedActivityRecord) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[9]
+	mi := &file_user_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1034,7 +1294,7 @@ func (x *ProcessedActivityRecord) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProcessedActivityRecord.ProtoReflect.Descriptor instead.
 func (*ProcessedActivityRecord) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{9}
+	return file_user_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *ProcessedActivityRecord) GetSource() string {
@@ -1059,17 +1319,19 @@ func (x *ProcessedActivityRecord) GetProcessedAt() *timestamp.Timestamp {
 }
 
 type Counter struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // Key, e.g. "parkrun_bushy"
-	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
-	LastUpdated   *timestamp.Timestamp   `protobuf:"bytes,3,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // Key, e.g. "parkrun_bushy"
+	Count       int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	LastUpdated *timestamp.Timestamp   `protobuf:"bytes,3,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"`
+	// Period the count belongs to, e.g. "2026", "2026-03", "2026-W11"; empty = all-time
+	Period        string `protobuf:"bytes,4,opt,name=period,proto3" json:"period,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Counter) Reset() {
 	*x = Counter{}
-	mi := &file_user_proto_msgTypes[10]
+	mi := &file_user_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1081,7 +1343,7 @@ func (x *Counter) String() string {
 func (*Counter) ProtoMessage() {}
 
 func (x *Counter) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[10]
+	mi := &file_user_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1094,7 +1356,7 @@ func (x *Counter) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Counter.ProtoReflect.Descriptor instead.
 func (*Counter) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{10}
+	return file_user_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *Counter) GetId() string {
@@ -1118,6 +1380,13 @@ func (x *Counter) GetLastUpdated() *timestamp.Timestamp {
 	return nil
 }
 
+func (x *Counter) GetPeriod() string {
+	if x != nil {
+		return x.Period
+	}
+	return ""
+}
+
 type SynchronizedActivity struct {
 	state               protoimpl.MessageState `protogen:"open.v1"`
 	ActivityId          string                 `protobuf:"bytes,1,opt,name=activity_id,json=activityId,proto3" json:"activity_id,omitempty"`
@@ -1130,13 +1399,19 @@ type SynchronizedActivity struct {
 	SyncedAt            *timestamp.Timestamp   `protobuf:"bytes,8,opt,name=synced_at,json=syncedAt,proto3" json:"synced_at,omitempty"`
 	PipelineId          string                 `protobuf:"bytes,9,opt,name=pipeline_id,json=pipelineId,proto3" json:"pipeline_id,omitempty"`
 	PipelineExecutionId string                 `protobuf:"bytes,10,opt,name=pipeline_execution_id,json=pipelineExecutionId,proto3" json:"pipeline_execution_id,omitempty"`
-	unknownFields       protoimpl.UnknownFields
-	sizeCache           protoimpl.SizeCache
+	ExternalId          string                 `protobuf:"bytes,11,opt,name=external_id,json=externalId,proto3" json:"external_id,omitempty"` // Source activity ID, for propagating source updates and deletes
+	// The source activity's own metadata that title, description and type were enriched from.
+	// Unset on activities synced before it was recorded.
+	SourceTitle       *string      `protobuf:"bytes,12,opt,name=source_title,json=sourceTitle,proto3,oneof" json:"source_title,omitempty"`
+	SourceDescription *string      `protobuf:"bytes,13,opt,name=source_description,json=sourceDescription,proto3,oneof" json:"source_description,omitempty"`
+	SourceType        ActivityType `protobuf:"varint,14,opt,name=source_type,json=sourceType,proto3,enum=fitglue.ActivityType" json:"source_type,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
 func (x *SynchronizedActivity) Reset() {
 	*x = SynchronizedActivity{}
-	mi := &file_user_proto_msgTypes[11]
+	mi := &file_user_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1148,7 +1423,7 @@ func (x *SynchronizedActivity) String() string {
 func (*SynchronizedActivity) ProtoMessage() {}
 
 func (x *SynchronizedActivity) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[11]
+	mi := &file_user_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1161,7 +1436,7 @@ func (x *SynchronizedActivity) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SynchronizedActivity.ProtoReflect.Descriptor instead.
 func (*SynchronizedActivity) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{11}
+	return file_user_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *SynchronizedActivity) GetActivityId() string {
@@ -1234,12 +1509,40 @@ func (x *SynchronizedActivity) GetPipelineExecutionId() string {
 	return ""
 }
 
+func (x *SynchronizedActivity) GetExternalId() string {
+	if x != nil {
+		return x.ExternalId
+	}
+	return ""
+}
+
+func (x *SynchronizedActivity) GetSourceTitle() string {
+	if x != nil && x.SourceTitle != nil {
+		return *x.SourceTitle
+	}
+	return ""
+}
+
+func (x *SynchronizedActivity) GetSourceDescription() string {
+	if x != nil && x.SourceDescription != nil {
+		return *x.SourceDescription
+	}
+	return ""
+}
+
+func (x *SynchronizedActivity) GetSourceType() ActivityType {
+	if x != nil {
+		return x.SourceType
+	}
+	return ActivityType_ACTIVITY_TYPE_UNSPECIFIED
+}
+
 var File_user_proto protoreflect.FileDescriptor
 
 const file_user_proto_rawDesc = "" +
 	"\n" +
 	"\n" +
-	"user.proto\x12\afitglue\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1bstandardized_activity.proto\x1a\fevents.proto\"\x90\x04\n" +
+	"user.proto\x12\afitglue\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1bstandardized_activity.proto\x1a\fevents.proto\"\xfb\x04\n" +
 	"\n" +
 	"UserRecord\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x129\n" +
@@ -1255,23 +1558,48 @@ const file_user_proto_rawDesc = "" +
 	"\x15sync_count_this_month\x18\t \x01(\x05R\x12syncCountThisMonth\x12I\n" +
 	"\x13sync_count_reset_at\x18\n" +
 	" \x01(\v2\x1a.google.protobuf.TimestampR\x10syncCountResetAt\x12,\n" +
-	"\x12stripe_customer_id\x18\v \x01(\tR\x10stripeCustomerId\"\xb0\x01\n" +
+	"\x12stripe_customer_id\x18\v \x01(\tR\x10stripeCustomerId\x12\x1a\n" +
+	"\btimezone\x18\f \x01(\tR\btimezone\x12M\n" +
+	"\x14source_change_policy\x18\r \x01(\x0e2\x1b.fitglue.SourceChangePolicyR\x12sourceChangePolicy\"\xfd\x02\n" +
 	"\x0ePipelineConfig\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
 	"\x06source\x18\x02 \x01(\tR\x06source\x125\n" +
 	"\tenrichers\x18\x03 \x03(\v2\x17.fitglue.EnricherConfigR\tenrichers\x12?\n" +
-	"\fdestinations\x18\x04 \x03(\x0e2\x1b.fitglue.events.DestinationR\fdestinations\"\xd6\x01\n" +
+	"\fdestinations\x18\x04 \x03(\x0e2\x1b.fitglue.events.DestinationR\fdestinations\x12`\n" +
+	"\x13destination_options\x18\x05 \x03(\v2/.fitglue.PipelineConfig.DestinationOptionsEntryR\x12destinationOptions\x1ai\n" +
+	"\x17DestinationOptionsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x128\n" +
+	"\x05value\x18\x02 \x01(\v2\".fitglue.events.DestinationOptionsR\x05value:\x028\x01\"\xd4\x02\n" +
 	"\x10UserIntegrations\x12,\n" +
 	"\x04hevy\x18\x01 \x01(\v2\x18.fitglue.HevyIntegrationR\x04hevy\x122\n" +
 	"\x06fitbit\x18\x02 \x01(\v2\x1a.fitglue.FitbitIntegrationR\x06fitbit\x122\n" +
 	"\x06strava\x18\x03 \x01(\v2\x1a.fitglue.StravaIntegrationR\x06strava\x12,\n" +
-	"\x04mock\x18\x04 \x01(\v2\x18.fitglue.MockIntegrationR\x04mock\"\xa4\x01\n" +
+	"\x04mock\x18\x04 \x01(\v2\x18.fitglue.MockIntegrationR\x04mock\x125\n" +
+	"\awebhook\x18\x05 \x01(\v2\x1b.fitglue.WebhookIntegrationR\awebhook\x12E\n" +
+	"\rintervals_icu\x18\x06 \x01(\v2 .fitglue.IntervalsIcuIntegrationR\fintervalsIcu\"\xa4\x01\n" +
 	"\x0fMockIntegration\x12\x18\n" +
 	"\aenabled\x18\x01 \x01(\bR\aenabled\x129\n" +
 	"\n" +
 	"created_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12<\n" +
 	"\flast_used_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
-	"lastUsedAt\"\xd6\x01\n" +
+	"lastUsedAt\"\xe3\x01\n" +
+	"\x12WebhookIntegration\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12(\n" +
+	"\x10include_fit_file\x18\x03 \x01(\bR\x0eincludeFitFile\x129\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12<\n" +
+	"\flast_used_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"lastUsedAt\"\xe4\x01\n" +
+	"\x17IntervalsIcuIntegration\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x1d\n" +
+	"\n" +
+	"athlete_id\x18\x02 \x01(\tR\tathleteId\x12\x17\n" +
+	"\aapi_key\x18\x03 \x01(\tR\x06apiKey\x129\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12<\n" +
+	"\flast_used_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"lastUsedAt\"\x9e\x02\n" +
 	"\x0fHevyIntegration\x12\x18\n" +
 	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x17\n" +
 	"\aapi_key\x18\x02 \x01(\tR\x06apiKey\x12\x17\n" +
@@ -1279,7 +1607,8 @@ const file_user_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12<\n" +
 	"\flast_used_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
-	"lastUsedAt\"\xcf\x02\n" +
+	"lastUsedAt\x12F\n" +
+	"\x11changes_synced_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x0fchangesSyncedAt\"\xcf\x02\n" +
 	"\x11FitbitIntegration\x12\x18\n" +
 	"\aenabled\x18\x01 \x01(\bR\aenabled\x12!\n" +
 	"\faccess_token\x18\x02 \x01(\tR\vaccessToken\x12#\n" +
@@ -1315,11 +1644,12 @@ const file_user_proto_rawDesc = "" +
 	"\x06source\x18\x01 \x01(\tR\x06source\x12\x1f\n" +
 	"\vexternal_id\x18\x02 \x01(\tR\n" +
 	"externalId\x12=\n" +
-	"\fprocessed_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\vprocessedAt\"n\n" +
+	"\fprocessed_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\vprocessedAt\"\x86\x01\n" +
 	"\aCounter\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05count\x18\x02 \x01(\x03R\x05count\x12=\n" +
-	"\flast_updated\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\vlastUpdated\"\x91\x04\n" +
+	"\flast_updated\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\vlastUpdated\x12\x16\n" +
+	"\x06period\x18\x04 \x01(\tR\x06period\"\xee\x05\n" +
 	"\x14SynchronizedActivity\x12\x1f\n" +
 	"\vactivity_id\x18\x01 \x01(\tR\n" +
 	"activityId\x12\x14\n" +
@@ -1334,10 +1664,23 @@ const file_user_proto_rawDesc = "" +
 	"\vpipeline_id\x18\t \x01(\tR\n" +
 	"pipelineId\x122\n" +
 	"\x15pipeline_execution_id\x18\n" +
-	" \x01(\tR\x13pipelineExecutionId\x1a?\n" +
+	" \x01(\tR\x13pipelineExecutionId\x12\x1f\n" +
+	"\vexternal_id\x18\v \x01(\tR\n" +
+	"externalId\x12&\n" +
+	"\fsource_title\x18\f \x01(\tH\x00R\vsourceTitle\x88\x01\x01\x122\n" +
+	"\x12source_description\x18\r \x01(\tH\x01R\x11sourceDescription\x88\x01\x01\x126\n" +
+	"\vsource_type\x18\x0e \x01(\x0e2\x15.fitglue.ActivityTypeR\n" +
+	"sourceType\x1a?\n" +
 	"\x11DestinationsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01*\xeb\x03\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\x0f\n" +
+	"\r_source_titleB\x15\n" +
+	"\x13_source_description*\xab\x01\n" +
+	"\x12SourceChangePolicy\x12$\n" +
+	" SOURCE_CHANGE_POLICY_UNSPECIFIED\x10\x00\x12\x1f\n" +
+	"\x1bSOURCE_CHANGE_POLICY_IGNORE\x10\x01\x12(\n" +
+	"$SOURCE_CHANGE_POLICY_UPDATE_METADATA\x10\x02\x12$\n" +
+	" SOURCE_CHANGE_POLICY_FULL_RESYNC\x10\x03*\xeb\x03\n" +
 	"\x14EnricherProviderType\x12!\n" +
 	"\x1dENRICHER_PROVIDER_UNSPECIFIED\x10\x00\x12'\n" +
 	"#ENRICHER_PROVIDER_FITBIT_HEART_RATE\x10\x01\x12%\n" +
@@ -1385,68 +1728,84 @@ func file_user_proto_rawDescGZIP() []byte {
 	return file_user_proto_rawDescData
 }
 
-var file_user_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
-var file_user_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_user_proto_enumTypes = make([]protoimpl.EnumInfo, 6)
+var file_user_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
 var file_user_proto_goTypes = []any{
-	(EnricherProviderType)(0),       // 0: fitglue.EnricherProviderType
-	(WorkoutSummaryFormat)(0),       // 1: fitglue.WorkoutSummaryFormat
-	(MuscleHeatmapStyle)(0),         // 2: fitglue.MuscleHeatmapStyle
-	(MuscleHeatmapPreset)(0),        // 3: fitglue.MuscleHeatmapPreset
-	(VirtualGPSRoute)(0),            // 4: fitglue.VirtualGPSRoute
-	(*UserRecord)(nil),              // 5: fitglue.UserRecord
-	(*PipelineConfig)(nil),          // 6: fitglue.PipelineConfig
-	(*UserIntegrations)(nil),        // 7: fitglue.UserIntegrations
-	(*MockIntegration)(nil),         // 8: fitglue.MockIntegration
-	(*HevyIntegration)(nil),         // 9: fitglue.HevyIntegration
-	(*FitbitIntegration)(nil),       // 10: fitglue.FitbitIntegration
-	(*SourceEnrichmentConfig)(nil),  // 11: fitglue.SourceEnrichmentConfig
-	(*EnricherConfig)(nil),          // 12: fitglue.EnricherConfig
-	(*StravaIntegration)(nil),       // 13: fitglue.StravaIntegration
-	(*ProcessedActivityRecord)(nil), // 14: fitglue.ProcessedActivityRecord
-	(*Counter)(nil),                 // 15: fitglue.Counter
-	(*SynchronizedActivity)(nil),    // 16: fitglue.SynchronizedActivity
-	nil,                             // 17: fitglue.EnricherConfig.TypedConfigEntry
-	nil,                             // 18: fitglue.SynchronizedActivity.DestinationsEntry
-	(*timestamp.Timestamp)(nil),     // 19: google.protobuf.Timestamp
-	(Destination)(0),                // 20: fitglue.events.Destination
-	(ActivityType)(0),               // 21: fitglue.ActivityType
+	(SourceChangePolicy)(0),         // 0: fitglue.SourceChangePolicy
+	(EnricherProviderType)(0),       // 1: fitglue.EnricherProviderType
+	(WorkoutSummaryFormat)(0),       // 2: fitglue.WorkoutSummaryFormat
+	(MuscleHeatmapStyle)(0),         // 3: fitglue.MuscleHeatmapStyle
+	(MuscleHeatmapPreset)(0),        // 4: fitglue.MuscleHeatmapPreset
+	(VirtualGPSRoute)(0),            // 5: fitglue.VirtualGPSRoute
+	(*UserRecord)(nil),              // 6: fitglue.UserRecord
+	(*PipelineConfig)(nil),          // 7: fitglue.PipelineConfig
+	(*UserIntegrations)(nil),        // 8: fitglue.UserIntegrations
+	(*MockIntegration)(nil),         // 9: fitglue.MockIntegration
+	(*WebhookIntegration)(nil),      // 10: fitglue.WebhookIntegration
+	(*IntervalsIcuIntegration)(nil), // 11: fitglue.IntervalsIcuIntegration
+	(*HevyIntegration)(nil),         // 12: fitglue.HevyIntegration
+	(*FitbitIntegration)(nil),       // 13: fitglue.FitbitIntegration
+	(*SourceEnrichmentConfig)(nil),  // 14: fitglue.SourceEnrichmentConfig
+	(*EnricherConfig)(nil),          // 15: fitglue.EnricherConfig
+	(*StravaIntegration)(nil),       // 16: fitglue.StravaIntegration
+	(*ProcessedActivityRecord)(nil), // 17: fitglue.ProcessedActivityRecord
+	(*Counter)(nil),                 // 18: fitglue.Counter
+	(*SynchronizedActivity)(nil),    // 19: fitglue.SynchronizedActivity
+	nil,                             // 20: fitglue.PipelineConfig.DestinationOptionsEntry
+	nil,                             // 21: fitglue.EnricherConfig.TypedConfigEntry
+	nil,                             // 22: fitglue.SynchronizedActivity.DestinationsEntry
+	(*timestamp.Timestamp)(nil),     // 23: google.protobuf.Timestamp
+	(Destination)(0),                // 24: fitglue.events.Destination
+	(ActivityType)(0),               // 25: fitglue.ActivityType
+	(*DestinationOptions)(nil),      // 26: fitglue.events.DestinationOptions
 }
 var file_user_proto_depIdxs = []int32{
-	19, // 0: fitglue.UserRecord.created_at:type_name -> google.protobuf.Timestamp
-	7,  // 1: fitglue.UserRecord.integrations:type_name -> fitglue.UserIntegrations
-	6,  // 2: fitglue.UserRecord.pipelines:type_name -> fitglue.PipelineConfig
-	19, // 3: fitglue.UserRecord.trial_ends_at:type_name -> google.protobuf.Timestamp
-	19, // 4: fitglue.UserRecord.sync_count_reset_at:type_name -> google.protobuf.Timestamp
-	12, // 5: fitglue.PipelineConfig.enrichers:type_name -> fitglue.EnricherConfig
-	20, // 6: fitglue.PipelineConfig.destinations:type_name -> fitglue.events.Destination
-	9,  // 7: fitglue.UserIntegrations.hevy:type_name -> fitglue.HevyIntegration
-	10, // 8: fitglue.UserIntegrations.fitbit:type_name -> fitglue.FitbitIntegration
-	13, // 9: fitglue.UserIntegrations.strava:type_name -> fitglue.StravaIntegration
-	8,  // 10: fitglue.UserIntegrations.mock:type_name -> fitglue.MockIntegration
-	19, // 11: fitglue.MockIntegration.created_at:type_name -> google.protobuf.Timestamp
-	19, // 12: fitglue.MockIntegration.last_used_at:type_name -> google.protobuf.Timestamp
-	19, // 13: fitglue.HevyIntegration.created_at:type_name -> google.protobuf.Timestamp
-	19, // 14: fitglue.HevyIntegration.last_used_at:type_name -> google.protobuf.Timestamp
-	19, // 15: fitglue.FitbitIntegration.expires_at:type_name -> google.protobuf.Timestamp
-	19, // 16: fitglue.FitbitIntegration.created_at:type_name -> google.protobuf.Timestamp
-	19, // 17: fitglue.FitbitIntegration.last_used_at:type_name -> google.protobuf.Timestamp
-	12, // 18: fitglue.SourceEnrichmentConfig.enrichers:type_name -> fitglue.EnricherConfig
-	0,  // 19: fitglue.EnricherConfig.provider_type:type_name -> fitglue.EnricherProviderType
-	17, // 20: fitglue.EnricherConfig.typed_config:type_name -> fitglue.EnricherConfig.TypedConfigEntry
-	19, // 21: fitglue.StravaIntegration.expires_at:type_name -> google.protobuf.Timestamp
-	19, // 22: fitglue.StravaIntegration.created_at:type_name -> google.protobuf.Timestamp
-	19, // 23: fitglue.StravaIntegration.last_used_at:type_name -> google.protobuf.Timestamp
-	19, // 24: fitglue.ProcessedActivityRecord.processed_at:type_name -> google.protobuf.Timestamp
-	19, // 25: fitglue.Counter.last_updated:type_name -> google.protobuf.Timestamp
-	21, // 26: fitglue.SynchronizedActivity.type:type_name -> fitglue.ActivityType
-	19, // 27: fitglue.SynchronizedActivity.start_time:type_name -> google.protobuf.Timestamp
-	18, // 28: fitglue.SynchronizedActivity.destinations:type_name -> fitglue.SynchronizedActivity.DestinationsEntry
-	19, // 29: fitglue.SynchronizedActivity.synced_at:type_name -> google.protobuf.Timestamp
-	30, // [30:30] is the sub-list for method output_type
-	30, // [30:30] is the sub-list for method input_type
-	30, // [30:30] is the sub-list for extension type_name
-	30, // [30:30] is the sub-list for extension extendee
-	0,  // [0:30] is the sub-list for field type_name
+	23, // 0: fitglue.UserRecord.created_at:type_name -> google.protobuf.Timestamp
+	8,  // 1: fitglue.UserRecord.integrations:type_name -> fitglue.UserIntegrations
+	7,  // 2: fitglue.UserRecord.pipelines:type_name -> fitglue.PipelineConfig
+	23, // 3: fitglue.UserRecord.trial_ends_at:type_name -> google.protobuf.Timestamp
+	23, // 4: fitglue.UserRecord.sync_count_reset_at:type_name -> google.protobuf.Timestamp
+	0,  // 5: fitglue.UserRecord.source_change_policy:type_name -> fitglue.SourceChangePolicy
+	15, // 6: fitglue.PipelineConfig.enrichers:type_name -> fitglue.EnricherConfig
+	24, // 7: fitglue.PipelineConfig.destinations:type_name -> fitglue.events.Destination
+	20, // 8: fitglue.PipelineConfig.destination_options:type_name -> fitglue.PipelineConfig.DestinationOptionsEntry
+	12, // 9: fitglue.UserIntegrations.hevy:type_name -> fitglue.HevyIntegration
+	13, // 10: fitglue.UserIntegrations.fitbit:type_name -> fitglue.FitbitIntegration
+	16, // 11: fitglue.UserIntegrations.strava:type_name -> fitglue.StravaIntegration
+	9,  // 12: fitglue.UserIntegrations.mock:type_name -> fitglue.MockIntegration
+	10, // 13: fitglue.UserIntegrations.webhook:type_name -> fitglue.WebhookIntegration
+	11, // 14: fitglue.UserIntegrations.intervals_icu:type_name -> fitglue.IntervalsIcuIntegration
+	23, // 15: fitglue.MockIntegration.created_at:type_name -> google.protobuf.Timestamp
+	23, // 16: fitglue.MockIntegration.last_used_at:type_name -> google.protobuf.Timestamp
+	23, // 17: fitglue.WebhookIntegration.created_at:type_name -> google.protobuf.Timestamp
+	23, // 18: fitglue.WebhookIntegration.last_used_at:type_name -> google.protobuf.Timestamp
+	23, // 19: fitglue.IntervalsIcuIntegration.created_at:type_name -> google.protobuf.Timestamp
+	23, // 20: fitglue.IntervalsIcuIntegration.last_used_at:type_name -> google.protobuf.Timestamp
+	23, // 21: fitglue.HevyIntegration.created_at:type_name -> google.protobuf.Timestamp
+	23, // 22: fitglue.HevyIntegration.last_used_at:type_name -> google.protobuf.Timestamp
+	23, // 23: fitglue.HevyIntegration.changes_synced_at:type_name -> google.protobuf.Timestamp
+	23, // 24: fitglue.FitbitIntegration.expires_at:type_name -> google.protobuf.Timestamp
+	23, // 25: fitglue.FitbitIntegration.created_at:type_name -> google.protobuf.Timestamp
+	23, // 26: fitglue.FitbitIntegration.last_used_at:type_name -> google.protobuf.Timestamp
+	15, // 27: fitglue.SourceEnrichmentConfig.enrichers:type_name -> fitglue.EnricherConfig
+	1,  // 28: fitglue.EnricherConfig.provider_type:type_name -> fitglue.EnricherProviderType
+	21, // 29: fitglue.EnricherConfig.typed_config:type_name -> fitglue.EnricherConfig.TypedConfigEntry
+	23, // 30: fitglue.StravaIntegration.expires_at:type_name -> google.protobuf.Timestamp
+	23, // 31: fitglue.StravaIntegration.created_at:type_name -> google.protobuf.Timestamp
+	23, // 32: fitglue.StravaIntegration.last_used_at:type_name -> google.protobuf.Timestamp
+	23, // 33: fitglue.ProcessedActivityRecord.processed_at:type_name -> google.protobuf.Timestamp
+	23, // 34: fitglue.Counter.last_updated:type_name -> google.protobuf.Timestamp
+	25, // 35: fitglue.SynchronizedActivity.type:type_name -> fitglue.ActivityType
+	23, // 36: fitglue.SynchronizedActivity.start_time:type_name -> google.protobuf.Timestamp
+	22, // 37: fitglue.SynchronizedActivity.destinations:type_name -> fitglue.SynchronizedActivity.DestinationsEntry
+	23, // 38: fitglue.SynchronizedActivity.synced_at:type_name -> google.protobuf.Timestamp
+	25, // 39: fitglue.SynchronizedActivity.source_type:type_name -> fitglue.ActivityType
+	26, // 40: fitglue.PipelineConfig.DestinationOptionsEntry.value:type_name -> fitglue.events.DestinationOptions
+	41, // [41:41] is the sub-list for method output_type
+	41, // [41:41] is the sub-list for method input_type
+	41, // [41:41] is the sub-list for extension type_name
+	41, // [41:41] is the sub-list for extension extendee
+	0,  // [0:41] is the sub-list for field type_name
 }
 
 func init() { file_user_proto_init() }
@@ -1456,13 +1815,14 @@ func file_user_proto_init() {
 	}
 	file_standardized_activity_proto_init()
 	file_events_proto_init()
+	file_user_proto_msgTypes[13].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_user_proto_rawDesc), len(file_user_proto_rawDesc)),
-			NumEnums:      5,
-			NumMessages:   14,
+			NumEnums:      6,
+			NumMessages:   17,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
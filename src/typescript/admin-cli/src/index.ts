@@ -20,6 +20,8 @@ import {
     Destination,
     INTEGRATIONS,
     UserIntegrations,
+    setSecret,
+    WEBHOOK_SIGNING_SECRET_PREFIX,
 } from '@fitglue/shared';
 
 
@@ -220,6 +222,8 @@ program.command('users:configure-integration')
                     message: `${field.name}:`,
                     validate: (input: unknown) => {
                         if (field.required && (input === '' || input === undefined)) return `${field.name} is required`;
+                        const error = field.validate?.(input);
+                        if (error) return `${field.name} ${error}`;
                         return true;
                     }
                 }));
@@ -242,9 +246,20 @@ program.command('users:configure-integration')
                 (payload as any).enabled = true;
             }
 
+            // Webhook deliveries are signed; provision a new secret before enabling the integration
+            let signingSecret: string | undefined;
+            if (provider === 'webhook') {
+                signingSecret = crypto.randomBytes(32).toString('hex');
+                const projectId = process.env.GOOGLE_CLOUD_PROJECT || 'fitglue-server-dev';
+                await setSecret(projectId, `${WEBHOOK_SIGNING_SECRET_PREFIX}${userId}`, signingSecret);
+            }
+
             // eslint-disable-next-line @typescript-eslint/no-explicit-any
             await userService.setIntegration(userId, provider as keyof UserIntegrations, payload as any);
             console.log(`✅ ${definition.displayName} integration configured.`);
+            if (signingSecret) {
+                console.log(`Signing secret (shown once): ${signingSecret}`);
+            }
 
         } catch (error: unknown) {
             console.error('Error configuring integration:', error);
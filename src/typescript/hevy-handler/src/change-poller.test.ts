@@ -0,0 +1,148 @@
+import { handler } from './change-poller';
+import { FrameworkContext } from '@fitglue/shared';
+import { HevyConnector } from './connector';
+
+const mockGet = jest.fn();
+const mockPublish = jest.fn();
+
+jest.mock('@fitglue/shared', () => ({
+  ...jest.requireActual('@fitglue/shared'),
+  createHevyClient: jest.fn(() => ({ GET: mockGet })),
+  CloudEventPublisher: jest.fn().mockImplementation(() => ({ publish: mockPublish }))
+}));
+
+jest.mock('./connector', () => ({
+  HevyConnector: jest.fn()
+}));
+
+const syncedAt = new Date('2026-01-01T00:00:00Z');
+
+const hevyUser = (changesSyncedAt?: Date) => ({
+  userId: 'user-1',
+  integrations: { hevy: { enabled: true, apiKey: 'key', userId: 'hevy-user', changesSyncedAt } },
+  pipelines: [{ id: 'p1', source: 'SOURCE_HEVY', enrichers: [], destinations: [], destinationOptions: {} }]
+});
+
+describe('hevyChangePoller', () => {
+  let mockRes: { status: jest.Mock; json: jest.Mock };
+  let mockCtx: FrameworkContext;
+  let users: unknown[];
+  let processed: Set<string>;
+  let postedByFitGlue: Set<string>;
+  const mockFetchAndMap = jest.fn();
+
+  beforeEach(() => {
+    jest.clearAllMocks();
+    users = [hevyUser(syncedAt)];
+    processed = new Set(['w-updated', 'w-deleted', 'w-new', 'w-posted']);
+    postedByFitGlue = new Set(['w-posted']);
+    mockRes = {
+      status: jest.fn().mockReturnThis(),
+      json: jest.fn()
+    };
+    mockCtx = {
+      logger: { info: jest.fn(), warn: jest.fn(), error: jest.fn(), debug: jest.fn() },
+      executionId: 'exec-1',
+      pubsub: {},
+      stores: {
+        users: {
+          list: jest.fn(async () => users),
+          updateChangesSyncedAt: jest.fn()
+        }
+      },
+      services: {
+        user: {
+          hasProcessedActivity: jest.fn(async (_u: string, _c: string, id: string) => processed.has(id)),
+          checkDestinationExists: jest.fn(async (_u: string, _c: string, id: string) => postedByFitGlue.has(id))
+        }
+      }
+    } as unknown as FrameworkContext;
+
+    mockPublish.mockResolvedValue('msg-1');
+    mockFetchAndMap.mockImplementation(async (id: string) => [{ externalId: id, name: 'Edited' }]);
+    (HevyConnector as unknown as jest.Mock).mockImplementation(() => ({ fetchAndMap: mockFetchAndMap }));
+  });
+
+  it('publishes updates and deletions of ingested workouts and advances the cursor', async () => {
+    mockGet.mockResolvedValue({
+      data: {
+        page: 1,
+        page_count: 1,
+        events: [
+          { type: 'deleted', id: 'w-deleted', deleted_at: '2026-01-01T02:00:00Z' },
+          { type: 'updated', workout: { id: 'w-updated', created_at: '2026-01-01T00:30:00Z', updated_at: '2026-01-01T01:00:00Z' } },
+          { type: 'updated', workout: { id: 'w-new', created_at: '2026-01-01T00:45:00Z', updated_at: '2026-01-01T00:45:00Z' } },
+          { type: 'updated', workout: { id: 'w-posted', created_at: '2026-01-01T00:30:00Z', updated_at: '2026-01-01T00:50:00Z' } },
+          { type: 'deleted', id: 'w-unknown', deleted_at: '2026-01-01T00:40:00Z' }
+        ]
+      },
+      response: { status: 200 }
+    });
+
+    await handler({}, mockRes, mockCtx);
+
+    expect(mockGet).toHaveBeenCalledWith('/v1/workouts/events', {
+      params: { query: { page: 1, pageSize: 10, since: syncedAt.toISOString() } }
+    });
+    expect(mockPublish).toHaveBeenCalledTimes(2);
+    expect(mockPublish.mock.calls[0][0]).toEqual({
+      userId: 'user-1',
+      source: 1,
+      externalId: 'w-updated',
+      change: 1,
+      standardizedActivity: { externalId: 'w-updated', name: 'Edited' },
+      pipelineExecutionId: 'exec-1'
+    });
+    expect(mockPublish.mock.calls[1][0]).toEqual({
+      userId: 'user-1',
+      source: 1,
+      externalId: 'w-deleted',
+      change: 2,
+      pipelineExecutionId: 'exec-1'
+    });
+    expect(mockFetchAndMap).toHaveBeenCalledWith('w-updated', expect.objectContaining({ apiKey: 'key', userId: 'user-1' }));
+    expect(mockCtx.stores.users.updateChangesSyncedAt).toHaveBeenCalledWith('user-1', 'hevy', expect.any(Date));
+    expect(mockRes.status).toHaveBeenCalledWith(200);
+  });
+
+  it('reads every page of events', async () => {
+    mockGet
+      .mockResolvedValueOnce({ data: { page: 1, page_count: 2, events: [] }, response: { status: 200 } })
+      .mockResolvedValueOnce({ data: { page: 2, page_count: 2, events: [{ type: 'deleted', id: 'w-deleted' }] }, response: { status: 200 } });
+
+    await handler({}, mockRes, mockCtx);
+
+    expect(mockGet).toHaveBeenCalledTimes(2);
+    expect(mockGet.mock.calls[1][1].params.query.page).toBe(2);
+    expect(mockPublish).toHaveBeenCalledTimes(1);
+  });
+
+  it('only starts the cursor on the first poll', async () => {
+    users = [hevyUser()];
+
+    await handler({}, mockRes, mockCtx);
+
+    expect(mockGet).not.toHaveBeenCalled();
+    expect(mockCtx.stores.users.updateChangesSyncedAt).toHaveBeenCalledWith('user-1', 'hevy', expect.any(Date));
+  });
+
+  it('skips users without a Hevy-sourced pipeline', async () => {
+    users = [{ ...hevyUser(syncedAt), pipelines: [{ id: 'p1', source: 'SOURCE_FITBIT', enrichers: [], destinations: [], destinationOptions: {} }] }];
+
+    await handler({}, mockRes, mockCtx);
+
+    expect(mockGet).not.toHaveBeenCalled();
+    expect(mockCtx.stores.users.updateChangesSyncedAt).not.toHaveBeenCalled();
+  });
+
+  it('keeps the cursor when the Hevy API fails', async () => {
+    mockGet.mockResolvedValue({ error: { message: 'boom' }, response: { status: 500, statusText: 'Internal Server Error' } });
+
+    await handler({}, mockRes, mockCtx);
+
+    expect(mockPublish).not.toHaveBeenCalled();
+    expect(mockCtx.stores.users.updateChangesSyncedAt).not.toHaveBeenCalled();
+    expect(mockRes.status).toHaveBeenCalledWith(500);
+    expect(mockRes.json).toHaveBeenCalledWith({ users: 1, published: 0, failed: ['user-1'] });
+  });
+});
@@ -0,0 +1,141 @@
+import { FrameworkContext, UserRecord, HevyIntegration, ActivitySource, CloudEventSource, CloudEventType, CloudEventPublisher, SourceActivityChange, SourceChangeType, TOPICS, createHevyClient, getCloudEventSource, getCloudEventType } from '@fitglue/shared';
+import type { components } from "@fitglue/shared/dist/integrations/hevy/schema";
+import { HevyConnector } from './connector';
+
+type HevyWorkoutEvent = components["schemas"]["PaginatedWorkoutEvents"]["events"][number];
+type HevyUpdatedWorkout = components["schemas"]["UpdatedWorkout"];
+type HevyDeletedWorkout = components["schemas"]["DeletedWorkout"];
+
+// Hevy's maximum page size for /v1/workouts/events
+const EVENTS_PAGE_SIZE = 10;
+
+/**
+ * Hevy Change Poller
+ *
+ * Hevy's webhook only reports new workouts, so edits and deletions are read from
+ * GET /v1/workouts/events on a schedule (Cloud Scheduler, see terraform/functions.tf).
+ * For every user with a Hevy-sourced pipeline, events since the user's
+ * integrations.hevy.changes_synced_at are forwarded to topic-source-activity-change
+ * for workouts FitGlue ingested, and the cursor is advanced.
+ */
+// eslint-disable-next-line @typescript-eslint/no-explicit-any
+export const handler = async (req: any, res: any, ctx: FrameworkContext) => {
+  const { logger } = ctx;
+
+  const users = (await ctx.stores.users.list()).flatMap(user => {
+    const hevy = hevySource(user);
+    return hevy ? [{ user, hevy }] : [];
+  });
+  logger.info(`Polling Hevy workout events for ${users.length} users`);
+
+  let published = 0;
+  const failed: string[] = [];
+  for (const { user, hevy } of users) {
+    try {
+      published += await pollUser(user, hevy, ctx);
+    } catch (err) {
+      // Leave the cursor where it is, so the next run retries this user's events
+      logger.error(`Failed to poll Hevy workout events for user ${user.userId}`, { error: err instanceof Error ? err.message : err });
+      failed.push(user.userId);
+    }
+  }
+
+  logger.info(`Hevy change poll published ${published} changes`, { users: users.length, failed: failed.length });
+  res.status(failed.length > 0 ? 500 : 200).json({ users: users.length, published, failed });
+  return { users: users.length, published, failed };
+};
+
+/**
+ * hevySource returns the user's Hevy integration if it is enabled and feeds a pipeline.
+ */
+const hevySource = (user: UserRecord): HevyIntegration | undefined => {
+  const hevy = user.integrations?.hevy;
+  if (!hevy?.enabled || !hevy.apiKey) return undefined;
+  return (user.pipelines || []).some(p => p.source === 'SOURCE_HEVY') ? hevy : undefined;
+};
+
+/**
+ * pollUser publishes the user's workout changes since their cursor and returns how many were published.
+ * The first poll only starts the cursor, so changes from before the poller ran are not replayed.
+ */
+const pollUser = async (user: UserRecord, hevy: HevyIntegration, ctx: FrameworkContext): Promise<number> => {
+  const { logger } = ctx;
+  const userId = user.userId;
+  const polledAt = new Date();
+
+  if (!hevy.changesSyncedAt) {
+    await ctx.stores.users.updateChangesSyncedAt(userId, 'hevy', polledAt);
+    logger.info(`Started Hevy change cursor for user ${userId}`);
+    return 0;
+  }
+
+  const client = createHevyClient({
+    apiKey: hevy.apiKey,
+    usageTracking: { userStore: ctx.stores.users, userId }
+  });
+
+  const events: HevyWorkoutEvent[] = [];
+  for (let page = 1, pageCount = 1; page <= pageCount; page++) {
+    const { data, error, response } = await client.GET("/v1/workouts/events", {
+      params: { query: { page, pageSize: EVENTS_PAGE_SIZE, since: hevy.changesSyncedAt.toISOString() } }
+    });
+    if (error || !data) {
+      throw new Error(`Hevy API error: ${response.status} ${response.statusText}`);
+    }
+    events.push(...data.events);
+    pageCount = data.page_count;
+  }
+
+  // Events are newest first; apply them oldest first so a later edit wins
+  let published = 0;
+  for (const event of events.reverse()) {
+    const change = await toSourceChange(event, userId, hevy, ctx);
+    if (!change) continue;
+
+    const publisher = new CloudEventPublisher<SourceActivityChange>(
+      ctx.pubsub,
+      TOPICS.SOURCE_ACTIVITY_CHANGE,
+      getCloudEventSource(CloudEventSource.CLOUD_EVENT_SOURCE_HEVY),
+      getCloudEventType(CloudEventType.CLOUD_EVENT_TYPE_SOURCE_ACTIVITY_CHANGED),
+      logger
+    );
+    const messageId = await publisher.publish(change, change.externalId);
+    logger.info(`Published Hevy ${SourceChangeType[change.change]} for workout ${change.externalId}`, { userId, messageId });
+    published++;
+  }
+
+  await ctx.stores.users.updateChangesSyncedAt(userId, 'hevy', polledAt);
+  return published;
+};
+
+/**
+ * toSourceChange builds the SourceActivityChange for an event, or returns null when
+ * the workout wasn't ingested by FitGlue (never processed, or created by the Hevy destination)
+ * or was only created rather than edited.
+ */
+const toSourceChange = async (event: HevyWorkoutEvent, userId: string, hevy: HevyIntegration, ctx: FrameworkContext): Promise<SourceActivityChange | null> => {
+  const deleted = event.type === 'deleted';
+  const workoutId = deleted ? (event as HevyDeletedWorkout).id : (event as HevyUpdatedWorkout).workout.id;
+  if (!workoutId) return null;
+
+  if (!await ctx.services.user.hasProcessedActivity(userId, 'hevy', workoutId)) return null;
+  if (await ctx.services.user.checkDestinationExists(userId, 'hevy', workoutId)) return null;
+
+  const base = {
+    userId,
+    source: ActivitySource.SOURCE_HEVY,
+    externalId: workoutId,
+    pipelineExecutionId: ctx.executionId
+  };
+
+  if (deleted) {
+    return { ...base, change: SourceChangeType.SOURCE_CHANGE_TYPE_DELETED };
+  }
+
+  const workout = (event as HevyUpdatedWorkout).workout;
+  if (workout.updated_at && workout.updated_at === workout.created_at) return null;
+
+  const connector = new HevyConnector(ctx);
+  const [standardizedActivity] = await connector.fetchAndMap(workoutId, { ...hevy, userId });
+  return { ...base, change: SourceChangeType.SOURCE_CHANGE_TYPE_UPDATED, standardizedActivity };
+};
@@ -145,7 +145,10 @@ export class HevyConnector extends BaseConnector<HevyConnectorConfig, HevyWorkou
       totalElapsedTime: durationSeconds,
       totalDistance: totalDistance,
       laps: [],
-      strengthSets: strengthSets
+      strengthSets: strengthSets,
+      pauses: [],
+      poolLength: 0,
+      lengths: []
     };
 
     return {
@@ -1,5 +1,6 @@
 import { createCloudFunction, createWebhookProcessor, ApiKeyStrategy } from '@fitglue/shared';
 import { HevyConnector } from './connector';
+import { handler as changePoller } from './change-poller';
 
 // The HevyConnector encapsulates specific logic (ID extraction, API interaction, Mapping).
 // The createWebhookProcessor encapsulation standardizes the flow:
@@ -14,3 +15,8 @@ export const hevyWebhookHandler = createCloudFunction(
         }
     }
 );
+
+// Invoked by Cloud Scheduler; Cloud Run IAM only lets the scheduler's service account call it.
+export const hevyChangePoller = createCloudFunction(changePoller, {
+    allowUnauthenticated: true
+});
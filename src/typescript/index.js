@@ -8,6 +8,11 @@ exports.hevyWebhookHandler = (req, res) => {
   return hevy.hevyWebhookHandler(req, res);
 };
 
+exports.hevyChangePoller = (req, res) => {
+  const hevy = require('./hevy-handler/build/index');
+  return hevy.hevyChangePoller(req, res);
+};
+
 exports.stravaOAuthHandler = (req, res) => {
   const strava = require('./strava-oauth-handler/build/index');
   return strava.stravaOAuthHandler(req, res);
@@ -54,7 +54,10 @@ export class MockConnector extends BaseConnector<MockConnectorConfig, unknown> {
         totalElapsedTime: 3600, // 1 hour
         totalDistance: 5000,
         laps: [],
-        strengthSets: []
+        strengthSets: [],
+        pauses: [],
+        poolLength: 0,
+        lengths: []
       }],
       tags: ["mock"],
       notes: ""
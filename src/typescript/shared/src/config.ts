@@ -4,7 +4,8 @@ export const TOPICS = {
   JOB_UPLOAD_STRAVA: 'topic-job-upload-strava',
   JOB_UPLOAD_OTHER: 'topic-job-upload-other',
   FITBIT_UPDATES: 'topic-fitbit-updates',
-  ENRICHMENT_LAG: 'topic-enrichment-lag'
+  ENRICHMENT_LAG: 'topic-enrichment-lag',
+  SOURCE_ACTIVITY_CHANGE: 'topic-source-activity-change'
 };
 
 // In a real monorepo, we might inject this via build process or env var,
@@ -144,7 +144,10 @@ export const mapTCXToStandardized = (tcxXml: string, logData: LogData, userId: s
     totalElapsedTime: durationSeconds,
     totalDistance: totalDistanceToCheck,
     laps: generatedLaps,
-    strengthSets: [] // TCX doesn't have strength sets
+    strengthSets: [], // TCX doesn't have strength sets
+    pauses: [],
+    poolLength: 0,
+    lengths: []
   };
 
   // FitGlue Standardized Activity
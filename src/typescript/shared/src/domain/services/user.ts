@@ -1,5 +1,5 @@
 import { UserStore, ActivityStore } from '../../storage/firestore';
-import { UserRecord, UserIntegrations, EnricherConfig, ProcessedActivityRecord } from '../../types/pb/user';
+import { UserRecord, UserIntegrations, EnricherConfig, ProcessedActivityRecord, SourceChangePolicy } from '../../types/pb/user';
 import { FirestoreTokenSource } from '../../infrastructure/oauth/token-source';
 import { Destination } from '../../types/pb/events';
 
@@ -97,6 +97,8 @@ export class UserService {
             syncCountThisMonth: 0,
             syncCountResetAt: now,
             stripeCustomerId: '', // Will be set when user subscribes
+            timezone: '', // UTC until the user sets one
+            sourceChangePolicy: SourceChangePolicy.SOURCE_CHANGE_POLICY_UNSPECIFIED, // Source edits/deletes are ignored until opted in
         });
     }
 
@@ -222,6 +224,9 @@ export class UserService {
     private mapDestinations(dests: string[]): Destination[] {
         return dests.map(d => {
             if (d === 'strava' || d === 'DESTINATION_STRAVA') return Destination.DESTINATION_STRAVA;
+            if (d === 'webhook' || d === 'DESTINATION_WEBHOOK') return Destination.DESTINATION_WEBHOOK;
+            if (d === 'intervals_icu' || d === 'DESTINATION_INTERVALS_ICU') return Destination.DESTINATION_INTERVALS_ICU;
+            if (d === 'hevy' || d === 'DESTINATION_HEVY') return Destination.DESTINATION_HEVY;
             if (d === 'mock' || d === 'DESTINATION_MOCK') return Destination.DESTINATION_MOCK;
             return Destination.DESTINATION_UNSPECIFIED;
         });
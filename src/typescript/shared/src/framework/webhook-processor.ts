@@ -141,7 +141,8 @@ export function createWebhookProcessor<TConfig extends ConnectorConfig, TRaw>(
           'connector': connector.name
         },
         standardizedActivity: standardizedActivity,
-        pipelineExecutionId: ctx.executionId // Root execution ID
+        pipelineExecutionId: ctx.executionId, // Root execution ID
+        resync: false
       };
 
       const publisher = new CloudEventPublisher<ActivityPayload>(
@@ -7,7 +7,7 @@ export * from './framework/auth';
 export * from './framework/auth-strategies';
 
 // Types
-export { ActivityPayload, ActivitySource } from './types/pb/activity';
+export { ActivityPayload, ActivitySource, SourceActivityChange, SourceChangeType } from './types/pb/activity';
 export { StandardizedActivity, Session, Lap, StrengthSet, MuscleGroup, Record, ActivityType } from './types/pb/standardized_activity';
 export { ExecutionRecord, ExecutionStatus } from './types/pb/execution';
 export { CloudEventType, CloudEventSource, Destination } from './types/pb/events';
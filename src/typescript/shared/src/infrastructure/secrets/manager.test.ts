@@ -1,8 +1,11 @@
-import { getSecret } from './manager';
+import { getSecret, setSecret } from './manager';
 
 // Mock dependencies if needed, but getSecret logic is simple fallback
 // We want to test env var fallback and mocked GSM call
 
+const mockCreateSecret = jest.fn();
+const mockAddSecretVersion = jest.fn();
+
 jest.mock('@google-cloud/secret-manager', () => {
     return {
         SecretManagerServiceClient: jest.fn().mockImplementation(() => ({
@@ -10,7 +13,9 @@ jest.mock('@google-cloud/secret-manager', () => {
                 payload: {
                     data: Buffer.from('gsm-secret')
                 }
-            }])
+            }]),
+            createSecret: mockCreateSecret,
+            addSecretVersion: mockAddSecretVersion
         }))
     };
 });
@@ -39,3 +44,31 @@ describe('getSecret', () => {
         expect(val).toBe('gsm-secret');
     });
 });
+
+describe('setSecret', () => {
+    beforeEach(() => {
+        mockCreateSecret.mockReset().mockResolvedValue([{}]);
+        mockAddSecretVersion.mockReset().mockResolvedValue([{}]);
+    });
+
+    it('should create the secret and add a version', async () => {
+        await setSecret('proj', 'MY_SECRET', 'value');
+        expect(mockCreateSecret).toHaveBeenCalledWith(expect.objectContaining({ parent: 'projects/proj', secretId: 'MY_SECRET' }));
+        expect(mockAddSecretVersion).toHaveBeenCalledWith({
+            parent: 'projects/proj/secrets/MY_SECRET',
+            payload: { data: Buffer.from('value', 'utf8') }
+        });
+    });
+
+    it('should add a version when the secret already exists', async () => {
+        mockCreateSecret.mockRejectedValue(Object.assign(new Error('exists'), { code: 6 }));
+        await setSecret('proj', 'MY_SECRET', 'value');
+        expect(mockAddSecretVersion).toHaveBeenCalled();
+    });
+
+    it('should throw other errors', async () => {
+        mockCreateSecret.mockRejectedValue(Object.assign(new Error('denied'), { code: 7 }));
+        await expect(setSecret('proj', 'MY_SECRET', 'value')).rejects.toThrow('denied');
+        expect(mockAddSecretVersion).not.toHaveBeenCalled();
+    });
+});
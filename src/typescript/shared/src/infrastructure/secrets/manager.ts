@@ -41,3 +41,31 @@ export async function getSecret(projectId: string, secretName: string): Promise<
         throw error;
     }
 }
+
+/**
+ * Stores a value as the latest version of a secret, creating the secret if it doesn't exist yet.
+ * @param projectId The Google Cloud Project ID.
+ * @param secretName The name of the secret (not the full path).
+ * @param value The secret string value.
+ */
+export async function setSecret(projectId: string, secretName: string, value: string): Promise<void> {
+    const parent = `projects/${projectId}`;
+    try {
+        await getClient().createSecret({
+            parent,
+            secretId: secretName,
+            secret: { replication: { automatic: {} } },
+        });
+    } catch (error: unknown) {
+        // ALREADY_EXISTS: add a new version to the existing secret
+        if ((error as { code?: number }).code !== 6) {
+            console.error(`Failed to create secret ${secretName}:`, error);
+            throw error;
+        }
+    }
+
+    await getClient().addSecretVersion({
+        parent: `${parent}/secrets/${secretName}`,
+        payload: { data: Buffer.from(value, 'utf8') },
+    });
+}
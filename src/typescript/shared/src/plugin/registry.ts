@@ -179,6 +179,31 @@ FitGlue connects to the Fitbit API and receives notifications when you complete
   useCases: [],
 });
 
+registerSource({
+  id: 'strava',
+  type: PluginType.PLUGIN_TYPE_SOURCE,
+  name: 'Strava',
+  description: 'Import activities recorded on Strava',
+  icon: '🚴',
+  enabled: true,
+  requiredIntegrations: ['strava'],
+  configSchema: [],
+  marketingDescription: `
+### Strava Activity Source
+Use Strava as the start of a pipeline. Activities recorded on Strava, or synced to it from another device, are imported with their full GPS, heart rate, cadence and power streams.
+
+### How it works
+FitGlue subscribes to Strava webhooks and fetches each new activity as it is created. Activities FitGlue uploaded to Strava itself are recognised and skipped, so Strava can be both a source and a destination without loops.
+  `,
+  features: [
+    '✅ Import activities as soon as they are created',
+    '✅ Full GPS, heart rate, cadence and power streams',
+    '✅ Skips activities FitGlue uploaded to avoid loops',
+  ],
+  transformations: [],
+  useCases: [],
+});
+
 registerSource({
   id: 'mock',
   type: PluginType.PLUGIN_TYPE_SOURCE,
@@ -277,6 +302,87 @@ Once activities pass through your enrichment pipeline, FitGlue uploads them to S
   useCases: [],
 });
 
+registerDestination({
+  id: 'webhook',
+  type: PluginType.PLUGIN_TYPE_DESTINATION,
+  name: 'Webhook',
+  description: 'Send activities to your own HTTP endpoint',
+  icon: '🪝',
+  enabled: true,
+  requiredIntegrations: ['webhook'],
+  configSchema: [],
+  destinationType: 2, // DestinationType.DESTINATION_WEBHOOK
+  marketingDescription: `
+### Feed Your Own Services
+Send every enriched activity to an HTTP endpoint you run, for dashboards, spreadsheets, home automation or anything else.
+
+### How it works
+FitGlue POSTs the enriched activity as JSON (optionally with the FIT file) to your URL. Each request is signed with HMAC-SHA256 using your secret, so your service can verify it came from FitGlue. Failed deliveries are retried with backoff.
+  `,
+  features: [
+    '✅ Enriched activity delivered as JSON',
+    '✅ Optional FIT file attachment',
+    '✅ HMAC-SHA256 signed requests',
+    '✅ Automatic retries with backoff',
+  ],
+  transformations: [],
+  useCases: [],
+});
+
+registerDestination({
+  id: 'intervals_icu',
+  type: PluginType.PLUGIN_TYPE_DESTINATION,
+  name: 'intervals.icu',
+  description: 'Upload activities to intervals.icu',
+  icon: '📈',
+  enabled: true,
+  requiredIntegrations: ['intervals_icu'],
+  configSchema: [],
+  destinationType: 3, // DestinationType.DESTINATION_INTERVALS_ICU
+  marketingDescription: `
+### Analyse Your Training
+Send your enriched activities to intervals.icu for fitness, fatigue and form tracking alongside the rest of your training.
+
+### How it works
+FitGlue uploads the generated FIT file through the intervals.icu API using your athlete ID and API key, then sets the activity's name, description and type.
+  `,
+  features: [
+    '✅ Upload activities to intervals.icu automatically',
+    '✅ Name, description and activity type included',
+    '✅ Heart rate and GPS data in the FIT file',
+    '✅ Connect with your intervals.icu API key',
+  ],
+  transformations: [],
+  useCases: [],
+});
+
+registerDestination({
+  id: 'hevy',
+  type: PluginType.PLUGIN_TYPE_DESTINATION,
+  name: 'Hevy',
+  description: 'Log strength workouts in Hevy',
+  icon: '🏋️',
+  enabled: true,
+  requiredIntegrations: ['hevy'],
+  configSchema: [],
+  destinationType: 4, // DestinationType.DESTINATION_HEVY
+  marketingDescription: `
+### Keep Your Lifting Log Complete
+Strength sessions logged elsewhere, such as a Fitbit "Weights" activity with sets added by hand, appear in Hevy as workouts with their exercises and sets.
+
+### How it works
+FitGlue matches each exercise to one of your Hevy exercises by name, understanding common abbreviations and alternative names. Exercises Hevy doesn't know are skipped, unless the pipeline's Hevy options allow creating them as custom exercises. Supersets and set types (warm-up, drop set, failure) are kept.
+  `,
+  features: [
+    '✅ Create Hevy workouts from strength activities',
+    '✅ Exercises matched to your Hevy exercise library',
+    '✅ Supersets and set types preserved',
+    '✅ Connect with your Hevy API key',
+  ],
+  transformations: [],
+  useCases: [],
+});
+
 // ============================================================================
 // Register all known enricher manifests
 // These match the Go plugin registrations in enricher_providers/
@@ -769,7 +875,7 @@ registerEnricher(EnricherProviderType.ENRICHER_PROVIDER_AUTO_INCREMENT, {
   id: 'auto-increment',
   type: PluginType.PLUGIN_TYPE_ENRICHER,
   name: 'Auto Increment',
-  description: 'Appends an incrementing counter number to activity titles',
+  description: 'Numbers activity titles with a counter, optionally reset each week, month or year',
   icon: '🔢',
   enabled: true,
   requiredIntegrations: [],
@@ -777,6 +883,23 @@ registerEnricher(EnricherProviderType.ENRICHER_PROVIDER_AUTO_INCREMENT, {
     { key: 'counter_key', label: 'Counter Key', description: 'Unique identifier for this counter', fieldType: ConfigFieldType.CONFIG_FIELD_TYPE_STRING, required: true, defaultValue: '', options: [] },
     { key: 'title_contains', label: 'Title Filter', description: 'Only increment if title contains this', fieldType: ConfigFieldType.CONFIG_FIELD_TYPE_STRING, required: false, defaultValue: '', options: [] },
     { key: 'initial_value', label: 'Initial Value', description: 'Starting number', fieldType: ConfigFieldType.CONFIG_FIELD_TYPE_STRING, required: false, defaultValue: '1', options: [] },
+    {
+      key: 'period',
+      label: 'Reset Period',
+      description: 'Restart the count each week, month or year (in your timezone)',
+      fieldType: ConfigFieldType.CONFIG_FIELD_TYPE_SELECT,
+      required: false,
+      defaultValue: '',
+      options: [
+        { value: '', label: 'Never' },
+        { value: 'week', label: 'Weekly (Monday)' },
+        { value: 'month', label: 'Monthly' },
+        { value: 'year', label: 'Yearly' },
+      ],
+    },
+    { key: 'per_activity_type', label: 'Count Per Activity Type', description: 'Keep a separate count for each activity type', fieldType: ConfigFieldType.CONFIG_FIELD_TYPE_BOOLEAN, required: false, defaultValue: 'false', options: [] },
+    { key: 'title_template', label: 'Title Template', description: 'Replaces the title, e.g. "Run {{n}} of {{year}}". Placeholders: {{n}}, {{title}}, {{type}}, {{year}}, {{month}}, {{month_name}}, {{week}}, {{week_year}}', fieldType: ConfigFieldType.CONFIG_FIELD_TYPE_STRING, required: false, defaultValue: '', options: [] },
+    { key: 'description_template', label: 'Description Template', description: 'Appended to the description, e.g. "Week {{week}} session {{n}}"', fieldType: ConfigFieldType.CONFIG_FIELD_TYPE_STRING, required: false, defaultValue: '', options: [] },
   ],
   marketingDescription: `
 ### Numbered Activity Series
@@ -784,12 +907,16 @@ Automatically add incrementing numbers to your activity titles. Great for tracki
 
 ### How it works
 Define a counter key and optional title filter. Activities matching the filter get an incrementing number appended, like "Leg Day #1", "Leg Day #2", etc. Each counter key maintains its own sequence.
+
+Counters can restart every week, month or year in your timezone, count each activity type separately, and fill a title template such as "Run {{n}} of {{year}}".
   `,
   features: [
     '✅ Automatic sequential numbering',
     '✅ Multiple independent counters',
     '✅ Title filtering for targeted numbering',
     '✅ Configurable starting value',
+    '✅ Weekly, monthly or yearly resets',
+    '✅ Title and description templates',
   ],
   transformations: [
     { field: 'title', label: 'Activity Title', before: 'Leg Day', after: 'Leg Day #42', visualType: '', afterHtml: '' },
@@ -148,6 +148,8 @@ interface GenericIntegrationData {
   created_at?: Date | Timestamp;
   lastUsedAt?: Date | Timestamp;
   last_used_at?: Date | Timestamp;
+  changesSyncedAt?: Date | Timestamp;
+  changes_synced_at?: Date | Timestamp;
   [key: string]: unknown;
 }
 
@@ -155,6 +157,14 @@ export const mapGenericIntegrationToFirestore = (i: Record<string, unknown>, key
   const def = INTEGRATIONS[key as keyof UserIntegrations];
   if (!def) return {}; // Should not happen if calling safely
 
+  // Refuse to save an enabled integration with invalid fields (e.g. a non-https webhook URL)
+  if (i.enabled) {
+    for (const field of def.configurableFields) {
+      const error = field.validate?.(i[field.field]);
+      if (error) throw new Error(`${def.displayName} ${field.name} ${error}`);
+    }
+  }
+
   const out: Record<string, unknown> = {
     enabled: i.enabled
   };
@@ -189,11 +199,19 @@ export const mapGenericIntegrationToFirestore = (i: Record<string, unknown>, key
     // Current usage for Hevy: apiKey, userId
     if ('apiKey' in i) out.api_key = i.apiKey;
     if ('userId' in i) out.user_id = i.userId;
+    if ('changesSyncedAt' in i) out.changes_synced_at = i.changesSyncedAt;
+    // intervals.icu: athleteId (with apiKey above); Webhook: url, includeFitFile
+    if ('athleteId' in i) out.athlete_id = i.athleteId;
+    if ('url' in i) out.url = i.url;
+    if ('includeFitFile' in i) out.include_fit_file = i.includeFitFile;
   }
 
   return out;
 };
 
+// Integrations are stored under their proto field name (camelCase key -> snake_case, e.g. intervalsIcu -> intervals_icu)
+export const integrationFirestoreKey = (key: string): string => key.replace(/[A-Z]/g, letter => `_${letter.toLowerCase()}`);
+
 const mapUserIntegrationsToFirestore = (i?: UserIntegrations): Record<string, unknown> | undefined => {
   if (!i) return undefined;
   const out: Record<string, unknown> = {};
@@ -202,7 +220,7 @@ const mapUserIntegrationsToFirestore = (i?: UserIntegrations): Record<string, un
   for (const key of Object.keys(INTEGRATIONS)) {
     const k = key as keyof UserIntegrations;
     if (i[k]) {
-      out[key] = mapGenericIntegrationToFirestore(i[k] as unknown as Record<string, unknown>, key);
+      out[integrationFirestoreKey(key)] = mapGenericIntegrationToFirestore(i[k] as unknown as Record<string, unknown>, key);
     }
   }
 
@@ -252,6 +270,15 @@ const mapGenericIntegrationFromFirestore = (data: GenericIntegrationData, key: s
     if (key === 'hevy') {
       out.apiKey = data.api_key || data.apiKey || '';
       out.userId = data.user_id || data.userId || '';
+      out.changesSyncedAt = toDate(data.changes_synced_at || data.changesSyncedAt);
+    }
+    if (key === 'intervalsIcu') {
+      out.athleteId = data.athlete_id || data.athleteId || '';
+      out.apiKey = data.api_key || data.apiKey || '';
+    }
+    if (key === 'webhook') {
+      out.url = data.url || '';
+      out.includeFitFile = !!(data.include_fit_file ?? data.includeFitFile);
     }
   }
 
@@ -264,9 +291,10 @@ const mapUserIntegrationsFromFirestore = (data: Record<string, unknown> | undefi
 
   for (const key of Object.keys(INTEGRATIONS)) {
     const k = key as keyof UserIntegrations;
-    if (data[key]) {
+    const dbKey = integrationFirestoreKey(key);
+    if (data[dbKey]) {
       // eslint-disable-next-line @typescript-eslint/no-explicit-any
-      out[k] = mapGenericIntegrationFromFirestore(data[key] as GenericIntegrationData, key) as any;
+      out[k] = mapGenericIntegrationFromFirestore(data[dbKey] as GenericIntegrationData, key) as any;
     }
   }
 
@@ -293,6 +321,9 @@ export const mapPipelineFromFirestore = (p: Record<string, unknown>): PipelineCo
     if (typeof d === 'string') {
       // Legacy string support
       if (d === 'strava' || d === 'DESTINATION_STRAVA') return Destination.DESTINATION_STRAVA;
+      if (d === 'webhook' || d === 'DESTINATION_WEBHOOK') return Destination.DESTINATION_WEBHOOK;
+      if (d === 'intervals_icu' || d === 'DESTINATION_INTERVALS_ICU') return Destination.DESTINATION_INTERVALS_ICU;
+      if (d === 'hevy' || d === 'DESTINATION_HEVY') return Destination.DESTINATION_HEVY;
       if (d === 'mock' || d === 'DESTINATION_MOCK') return Destination.DESTINATION_MOCK;
     }
     return Destination.DESTINATION_UNSPECIFIED;
@@ -339,6 +370,8 @@ export const userConverter: FirestoreDataConverter<UserRecord> = {
     if (model.syncCountThisMonth !== undefined) data.sync_count_this_month = model.syncCountThisMonth;
     if (model.syncCountResetAt !== undefined) data.sync_count_reset_at = model.syncCountResetAt;
     if (model.stripeCustomerId !== undefined) data.stripe_customer_id = model.stripeCustomerId;
+    if (model.timezone !== undefined) data.timezone = model.timezone;
+    if (model.sourceChangePolicy !== undefined) data.source_change_policy = model.sourceChangePolicy;
     return data;
   },
   fromFirestore(snapshot: QueryDocumentSnapshot): UserRecord {
@@ -356,6 +389,8 @@ export const userConverter: FirestoreDataConverter<UserRecord> = {
       syncCountThisMonth: data.sync_count_this_month || 0,
       syncCountResetAt: toDate(data.sync_count_reset_at),
       stripeCustomerId: data.stripe_customer_id || undefined,
+      timezone: data.timezone || '',
+      sourceChangePolicy: data.source_change_policy || 0,
     };
   }
 };
@@ -428,8 +463,12 @@ export const synchronizedActivityConverter: FirestoreDataConverter<import('../..
       synced_at: model.syncedAt,
       pipeline_id: model.pipelineId,
       destinations: model.destinations,
-      pipeline_execution_id: model.pipelineExecutionId
+      pipeline_execution_id: model.pipelineExecutionId,
+      external_id: model.externalId,
+      source_type: model.sourceType
     };
+    if (model.sourceTitle !== undefined) data.source_title = model.sourceTitle;
+    if (model.sourceDescription !== undefined) data.source_description = model.sourceDescription;
     return data;
   },
   fromFirestore(snapshot: QueryDocumentSnapshot): import('../../types/pb/user').SynchronizedActivity {
@@ -444,7 +483,11 @@ export const synchronizedActivityConverter: FirestoreDataConverter<import('../..
       syncedAt: toDate(data.synced_at),
       pipelineId: data.pipeline_id,
       destinations: data.destinations || {},
-      pipelineExecutionId: data.pipeline_execution_id
+      pipelineExecutionId: data.pipeline_execution_id,
+      externalId: data.external_id || '',
+      sourceTitle: data.source_title,
+      sourceDescription: data.source_description,
+      sourceType: data.source_type || 0
     };
   }
 };
@@ -106,7 +106,7 @@ export class UserStore {
     data: import('../../types/pb/user').UserIntegrations[K]
   ): Promise<void> {
     // Construct the dot-notation key for updating nested field
-    const fieldPath = `integrations.${provider}`;
+    const fieldPath = `integrations.${converters.integrationFirestoreKey(provider)}`;
 
     // Use generic converter to ensure snake_case mapping based on logic
     // We cast data to Record<string, unknown> because we know it matches the generic integration structure
@@ -149,12 +149,22 @@ export class UserStore {
    * Update the last_used_at timestamp for a specific integration.
    */
   async updateLastUsed(userId: string, provider: string): Promise<void> {
-    const fieldPath = `integrations.${provider}.last_used_at`;
+    const fieldPath = `integrations.${converters.integrationFirestoreKey(provider)}.last_used_at`;
     await this.collection().doc(userId).update({
       [fieldPath]: new Date()
     });
   }
 
+  /**
+   * Update the changes_synced_at cursor for a specific integration.
+   */
+  async updateChangesSyncedAt(userId: string, provider: string, syncedAt: Date): Promise<void> {
+    const fieldPath = `integrations.${converters.integrationFirestoreKey(provider)}.changes_synced_at`;
+    await this.collection().doc(userId).update({
+      [fieldPath]: syncedAt
+    });
+  }
+
   /**
    * Add a new FCM token to the user's list.
    */
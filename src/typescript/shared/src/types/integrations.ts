@@ -14,6 +14,8 @@ export interface ConfigurableField {
   name: string;
   type: 'string' | 'boolean' | 'password';
   required: boolean;
+  // Returns an error message if the value can't be saved
+  validate?: (value: unknown) => string | undefined;
 }
 
 export interface OAuthIntegrationDefinition extends BaseIntegrationDefinition {
@@ -57,5 +59,58 @@ export const INTEGRATIONS: Record<keyof UserIntegrations, IntegrationDefinition>
     type: 'oauth',
     externalUserIdField: 'fitbitUserId',
     configurableFields: []
+  },
+  webhook: {
+    key: 'webhook',
+    displayName: 'Webhook',
+    type: 'basic',
+    configurableFields: [
+      { field: 'url', name: 'URL', type: 'string', required: true, validate: validateWebhookUrl },
+      { field: 'includeFitFile', name: 'Include FIT File', type: 'boolean', required: false }
+    ]
+  },
+  intervalsIcu: {
+    key: 'intervalsIcu',
+    displayName: 'intervals.icu',
+    type: 'basic',
+    configurableFields: [
+      { field: 'athleteId', name: 'Athlete ID', type: 'string', required: true },
+      { field: 'apiKey', name: 'API Key', type: 'password', required: true }
+    ]
   }
 };
+
+// Webhook deliveries are signed with this secret ("webhook-signing-secret-{user_id}")
+export const WEBHOOK_SIGNING_SECRET_PREFIX = 'webhook-signing-secret-';
+
+// Loopback, link-local, private and unspecified address literals
+const IPV4_LITERAL = /^\d{1,3}(\.\d{1,3}){3}$/;
+const PRIVATE_IPV4 = [/^0\./, /^10\./, /^127\./, /^169\.254\./, /^172\.(1[6-9]|2\d|3[01])\./, /^192\.168\./];
+const PRIVATE_IPV6 = [/^::1?$/, /^f[cd]/, /^fe[89ab]/, /^::ffff:/];
+
+/**
+ * Checks a webhook endpoint before it is saved: it must be an absolute https URL that doesn't name
+ * a local or private address. The webhook destination checks the resolved address again on every delivery.
+ */
+export function validateWebhookUrl(value: unknown): string | undefined {
+  let url: URL;
+  try {
+    url = new URL(String(value ?? ''));
+  } catch {
+    return 'must be a valid URL';
+  }
+  if (url.protocol !== 'https:') {
+    return 'must use https';
+  }
+  const host = url.hostname.toLowerCase().replace(/^\[|\]$/g, '');
+  if (host === 'localhost' || host.endsWith('.localhost') || host.endsWith('.internal')) {
+    return 'must be a public address';
+  }
+  if (IPV4_LITERAL.test(host) && PRIVATE_IPV4.some(re => re.test(host))) {
+    return 'must be a public address';
+  }
+  if (host.includes(':') && PRIVATE_IPV6.some(re => re.test(host))) {
+    return 'must be a public address';
+  }
+  return undefined;
+}
@@ -14,10 +14,19 @@ export enum ActivitySource {
   SOURCE_UNKNOWN = 0,
   SOURCE_HEVY = 1,
   SOURCE_FITBIT = 3,
+  SOURCE_STRAVA = 4,
   SOURCE_TEST = 99,
   UNRECOGNIZED = -1,
 }
 
+/** SourceChangeType is what happened to an activity at its source */
+export enum SourceChangeType {
+  SOURCE_CHANGE_TYPE_UNSPECIFIED = 0,
+  SOURCE_CHANGE_TYPE_UPDATED = 1,
+  SOURCE_CHANGE_TYPE_DELETED = 2,
+  UNRECOGNIZED = -1,
+}
+
 /** ActivityPayload is the unified message format published to Pub/Sub. */
 export interface ActivityPayload {
   source: ActivitySource;
@@ -34,10 +43,37 @@ export interface ActivityPayload {
     | StandardizedActivity
     | undefined;
   /** Execution tracing */
-  pipelineExecutionId?: string | undefined;
+  pipelineExecutionId?:
+    | string
+    | undefined;
+  /**
+   * Set when the pipeline is re-run for an activity that was already synchronized (a source
+   * update under SOURCE_CHANGE_POLICY_FULL_RESYNC): uploaders update the existing destination
+   * activities instead of creating new ones
+   */
+  resync: boolean;
 }
 
 export interface ActivityPayload_MetadataEntry {
   key: string;
   value: string;
 }
+
+/**
+ * SourceActivityChange reports an update or deletion of an already-ingested activity at its
+ * source, so it can be propagated to the destinations it was synchronized to.
+ * Event payload for CLOUD_EVENT_TYPE_SOURCE_ACTIVITY_CHANGED
+ */
+export interface SourceActivityChange {
+  userId: string;
+  source: ActivitySource;
+  /** Source activity ID (StandardizedActivity.external_id) */
+  externalId: string;
+  change: SourceChangeType;
+  /** The updated activity (SOURCE_CHANGE_TYPE_UPDATED only) */
+  standardizedActivity?:
+    | StandardizedActivity
+    | undefined;
+  /** Execution tracing */
+  pipelineExecutionId?: string | undefined;
+}
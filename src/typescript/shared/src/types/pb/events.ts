@@ -25,6 +25,12 @@ export enum CloudEventType {
   CLOUD_EVENT_TYPE_ENRICHMENT_LAG = 5,
   /** CLOUD_EVENT_TYPE_INPUT_RESOLVED - Input Resolved: Payload is ActivityPayload (resumed) */
   CLOUD_EVENT_TYPE_INPUT_RESOLVED = 6,
+  /** CLOUD_EVENT_TYPE_UPLOAD_STATUS_CHECK - Upload Status Check: Payload is UploadStatusCheck */
+  CLOUD_EVENT_TYPE_UPLOAD_STATUS_CHECK = 7,
+  /** CLOUD_EVENT_TYPE_STRAVA_WEBHOOK - Strava Webhook: Payload is a Strava webhook event (raw JSON) */
+  CLOUD_EVENT_TYPE_STRAVA_WEBHOOK = 8,
+  /** CLOUD_EVENT_TYPE_SOURCE_ACTIVITY_CHANGED - Source Activity Changed: Payload is SourceActivityChange */
+  CLOUD_EVENT_TYPE_SOURCE_ACTIVITY_CHANGED = 9,
   UNRECOGNIZED = -1,
 }
 
@@ -37,6 +43,9 @@ export enum CloudEventSource {
   CLOUD_EVENT_SOURCE_ENRICHER = 4,
   CLOUD_EVENT_SOURCE_ROUTER = 5,
   CLOUD_EVENT_SOURCE_INPUTS_HANDLER = 6,
+  CLOUD_EVENT_SOURCE_UPLOADER = 7,
+  CLOUD_EVENT_SOURCE_STRAVA = 8,
+  CLOUD_EVENT_SOURCE_CHANGE_PROPAGATOR = 9,
   CLOUD_EVENT_SOURCE_MOCK = 99,
   UNRECOGNIZED = -1,
 }
@@ -45,10 +54,77 @@ export enum CloudEventSource {
 export enum Destination {
   DESTINATION_UNSPECIFIED = 0,
   DESTINATION_STRAVA = 1,
+  DESTINATION_WEBHOOK = 2,
+  DESTINATION_INTERVALS_ICU = 3,
+  DESTINATION_HEVY = 4,
   DESTINATION_MOCK = 99,
   UNRECOGNIZED = -1,
 }
 
+/**
+ * DuplicatePolicy controls what an uploader does when the destination rejects an upload as a
+ * duplicate of an activity it already has (e.g. the watch synced to Strava first).
+ */
+export enum DuplicatePolicy {
+  /** DUPLICATE_POLICY_UNSPECIFIED - Same as FAIL */
+  DUPLICATE_POLICY_UNSPECIFIED = 0,
+  /** DUPLICATE_POLICY_FAIL - Fail the upload */
+  DUPLICATE_POLICY_FAIL = 1,
+  /** DUPLICATE_POLICY_LINK - Link the existing activity without changing it */
+  DUPLICATE_POLICY_LINK = 2,
+  /** DUPLICATE_POLICY_UPDATE - Update the existing activity's name, description, type and gear, then link it */
+  DUPLICATE_POLICY_UPDATE = 3,
+  UNRECOGNIZED = -1,
+}
+
+/** DestinationMode selects how a destination receives a pipeline's activities */
+export enum DestinationMode {
+  /** DESTINATION_MODE_UNSPECIFIED - Same as UPLOAD */
+  DESTINATION_MODE_UNSPECIFIED = 0,
+  /** DESTINATION_MODE_UPLOAD - Upload the FIT file as a new activity */
+  DESTINATION_MODE_UPLOAD = 1,
+  /** DESTINATION_MODE_UPDATE_EXISTING - Update the matching activity already on the destination (e.g. synced by the watch) */
+  DESTINATION_MODE_UPDATE_EXISTING = 2,
+  UNRECOGNIZED = -1,
+}
+
+/** ActivityVisibility is who can see the activity on the destination */
+export enum ActivityVisibility {
+  /** ACTIVITY_VISIBILITY_UNSPECIFIED - Destination's default for the athlete */
+  ACTIVITY_VISIBILITY_UNSPECIFIED = 0,
+  ACTIVITY_VISIBILITY_EVERYONE = 1,
+  ACTIVITY_VISIBILITY_FOLLOWERS_ONLY = 2,
+  ACTIVITY_VISIBILITY_ONLY_ME = 3,
+  UNRECOGNIZED = -1,
+}
+
+/**
+ * DestinationOptions configures how a pipeline delivers to a single destination.
+ * Unset fields leave the destination's default.
+ */
+export interface DestinationOptions {
+  duplicatePolicy: DuplicatePolicy;
+  /** Destination-specific gear ID (e.g. Strava "b1234567") */
+  gearId: string;
+  mode: DestinationMode;
+  /**
+   * Activity flags
+   * Recorded on a training machine
+   */
+  trainer?: boolean | undefined;
+  commute?:
+    | boolean
+    | undefined;
+  /** Muted from followers' feeds */
+  hideFromHome?: boolean | undefined;
+  visibility: ActivityVisibility;
+  /**
+   * Hevy: create custom exercises for exercise names with no matching template.
+   * Unmatched exercises are left out of the workout otherwise.
+   */
+  createCustomExercises?: boolean | undefined;
+}
+
 /**
  * Event payload for CLOUD_EVENT_TYPE_ACTIVITY_ENRICHED
  * Also used contextually for Upload Trigger
@@ -72,7 +148,17 @@ export interface EnrichedActivityEvent {
   destinations: Destination[];
   tags: string[];
   /** Execution tracing */
-  pipelineExecutionId?: string | undefined;
+  pipelineExecutionId?:
+    | string
+    | undefined;
+  /** Per-destination options from the pipeline, keyed by destination name (e.g. "strava") */
+  destinationOptions: { [key: string]: DestinationOptions };
+  /** Copied from ActivityPayload.resync: update the activity's existing destination copies */
+  resync: boolean;
+  /** The source activity's own name, description and type, before enrichment */
+  sourceName: string;
+  sourceDescription: string;
+  sourceType: ActivityType;
 }
 
 export interface EnrichedActivityEvent_EnrichmentMetadataEntry {
@@ -80,6 +166,11 @@ export interface EnrichedActivityEvent_EnrichmentMetadataEntry {
   value: string;
 }
 
+export interface EnrichedActivityEvent_DestinationOptionsEntry {
+  key: string;
+  value?: DestinationOptions | undefined;
+}
+
 export interface MessagePublishedData {
   data: Uint8Array;
   attributes: { [key: string]: string };
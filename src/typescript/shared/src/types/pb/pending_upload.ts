@@ -0,0 +1,53 @@
+// Code generated by protoc-gen-ts_proto. DO NOT EDIT.
+// versions:
+//   protoc-gen-ts_proto  v2.10.0
+//   protoc               v6.33.4
+// source: pending_upload.proto
+
+/* eslint-disable */
+import type { EnrichedActivityEvent } from "./events";
+
+export const protobufPackage = "fitglue";
+
+/**
+ * PendingUpload tracks an upload a destination accepted but has not finished processing
+ * (e.g. a Strava upload without an activity ID yet). The upload-status-poller resolves it.
+ */
+export interface PendingUpload {
+  /** "{destination}-{upload_id}" */
+  id: string;
+  userId: string;
+  /** Destination name (e.g. "strava") */
+  destination: string;
+  uploadId: string;
+  status: PendingUpload_Status;
+  /** Set once COMPLETED (e.g. the Strava activity ID) */
+  externalId: string;
+  /** Set once FAILED */
+  error: string;
+  /** Number of status checks made so far */
+  attempts: number;
+  /** The uploader execution to update with the final result */
+  executionId: string;
+  pipelineExecutionId: string;
+  /** The event that was uploaded, used to record the SynchronizedActivity */
+  originalEvent?: EnrichedActivityEvent | undefined;
+  createdAt?: Date | undefined;
+  updatedAt?: Date | undefined;
+  completedAt?: Date | undefined;
+}
+
+export enum PendingUpload_Status {
+  STATUS_UNSPECIFIED = 0,
+  STATUS_PROCESSING = 1,
+  STATUS_COMPLETED = 2,
+  STATUS_FAILED = 3,
+  UNRECOGNIZED = -1,
+}
+
+/** Event payload for CLOUD_EVENT_TYPE_UPLOAD_STATUS_CHECK */
+export interface UploadStatusCheck {
+  pendingUploadId: string;
+  userId: string;
+  pipelineExecutionId: string;
+}
@@ -68,6 +68,17 @@ export enum ActivityType {
   UNRECOGNIZED = -1,
 }
 
+export enum SwimStroke {
+  SWIM_STROKE_UNSPECIFIED = 0,
+  SWIM_STROKE_FREESTYLE = 1,
+  SWIM_STROKE_BACKSTROKE = 2,
+  SWIM_STROKE_BREASTSTROKE = 3,
+  SWIM_STROKE_BUTTERFLY = 4,
+  SWIM_STROKE_DRILL = 5,
+  SWIM_STROKE_MIXED = 6,
+  UNRECOGNIZED = -1,
+}
+
 export enum MuscleGroup {
   MUSCLE_GROUP_UNSPECIFIED = 0,
   MUSCLE_GROUP_ABDOMINALS = 1,
@@ -121,6 +132,20 @@ export interface StandardizedActivity {
   description: string;
   tags: string[];
   notes: string;
+  /** Recording device (e.g. from an imported FIT file). Unset for app sources. */
+  device?: DeviceInfo | undefined;
+}
+
+/** DeviceInfo identifies the device or app that recorded an activity. */
+export interface DeviceInfo {
+  /** FIT manufacturer name, e.g. "garmin" */
+  manufacturer: string;
+  /** Manufacturer-specific product ID */
+  product: number;
+  productName: string;
+  serialNumber: number;
+  /** e.g. "12.34" */
+  softwareVersion: string;
 }
 
 export interface Session {
@@ -134,6 +159,36 @@ export interface Session {
   laps: Lap[];
   /** High-fidelity strength data (not just 1Hz streams) */
   strengthSets: StrengthSet[];
+  /**
+   * Explicit timer pauses (auto-pause, manual stop/resume).
+   * Time spent paused is excluded from the total timer (moving) time.
+   */
+  pauses: Pause[];
+  /**
+   * Swimming: pool length in meters (0 = open water / unknown) and
+   * per-length data for pool swims.
+   */
+  poolLength: number;
+  lengths: SwimLength[];
+}
+
+/** Pause represents a period during which the activity timer was stopped. */
+export interface Pause {
+  startTime?: Date | undefined;
+  endTime?: Date | undefined;
+}
+
+/** SwimLength represents a single pool length (or a rest period at the wall). */
+export interface SwimLength {
+  startTime?:
+    | Date
+    | undefined;
+  /** seconds */
+  totalElapsedTime: number;
+  stroke: SwimStroke;
+  totalStrokes: number;
+  /** Rest period with no strokes */
+  idle: boolean;
 }
 
 export interface Lap {
@@ -5,11 +5,24 @@
 // source: user.proto
 
 /* eslint-disable */
-import type { Destination } from "./events";
+import type { Destination, DestinationOptions } from "./events";
 import type { ActivityType } from "./standardized_activity";
 
 export const protobufPackage = "fitglue";
 
+/** SourceChangePolicy controls how updates and deletions at a source reach the destinations */
+export enum SourceChangePolicy {
+  /** SOURCE_CHANGE_POLICY_UNSPECIFIED - Same as IGNORE */
+  SOURCE_CHANGE_POLICY_UNSPECIFIED = 0,
+  /** SOURCE_CHANGE_POLICY_IGNORE - Leave destination activities unchanged */
+  SOURCE_CHANGE_POLICY_IGNORE = 1,
+  /** SOURCE_CHANGE_POLICY_UPDATE_METADATA - Copy name, description and type; delete on source deletion */
+  SOURCE_CHANGE_POLICY_UPDATE_METADATA = 2,
+  /** SOURCE_CHANGE_POLICY_FULL_RESYNC - Re-run the pipeline and update the destinations; delete on source deletion */
+  SOURCE_CHANGE_POLICY_FULL_RESYNC = 3,
+  UNRECOGNIZED = -1,
+}
+
 export enum EnricherProviderType {
   ENRICHER_PROVIDER_UNSPECIFIED = 0,
   ENRICHER_PROVIDER_FITBIT_HEART_RATE = 1,
@@ -100,6 +113,10 @@ export interface UserRecord {
     | undefined;
   /** Stripe customer ID for billing */
   stripeCustomerId: string;
+  /** IANA timezone (e.g. "Europe/London") for calendar-based features; empty = UTC */
+  timezone: string;
+  /** What happens to synchronized destination activities when the source activity changes */
+  sourceChangePolicy: SourceChangePolicy;
 }
 
 export interface PipelineConfig {
@@ -109,6 +126,13 @@ export interface PipelineConfig {
   source: string;
   enrichers: EnricherConfig[];
   destinations: Destination[];
+  /** Per-destination options, keyed by destination name (e.g. "strava") */
+  destinationOptions: { [key: string]: DestinationOptions };
+}
+
+export interface PipelineConfig_DestinationOptionsEntry {
+  key: string;
+  value?: DestinationOptions | undefined;
 }
 
 export interface UserIntegrations {
@@ -116,6 +140,8 @@ export interface UserIntegrations {
   fitbit?: FitbitIntegration | undefined;
   strava?: StravaIntegration | undefined;
   mock?: MockIntegration | undefined;
+  webhook?: WebhookIntegration | undefined;
+  intervalsIcu?: IntervalsIcuIntegration | undefined;
 }
 
 export interface MockIntegration {
@@ -124,12 +150,37 @@ export interface MockIntegration {
   lastUsedAt?: Date | undefined;
 }
 
+/**
+ * WebhookIntegration delivers activities to a user-run HTTP endpoint.
+ * Requests are signed with the user's secret, "webhook-signing-secret-{user_id}" in the SecretStore.
+ */
+export interface WebhookIntegration {
+  enabled: boolean;
+  url: string;
+  /** Send the FIT file alongside the event (multipart/form-data) */
+  includeFitFile: boolean;
+  createdAt?: Date | undefined;
+  lastUsedAt?: Date | undefined;
+}
+
+/** IntervalsIcuIntegration authenticates with an intervals.icu API key (Settings > Developer Settings) */
+export interface IntervalsIcuIntegration {
+  enabled: boolean;
+  /** e.g. "i12345" */
+  athleteId: string;
+  apiKey: string;
+  createdAt?: Date | undefined;
+  lastUsedAt?: Date | undefined;
+}
+
 export interface HevyIntegration {
   enabled: boolean;
   apiKey: string;
   userId: string;
   createdAt?: Date | undefined;
   lastUsedAt?: Date | undefined;
+  /** Time up to which the Hevy change poller has read workout events */
+  changesSyncedAt?: Date | undefined;
 }
 
 export interface FitbitIntegration {
@@ -183,6 +234,8 @@ export interface Counter {
   id: string;
   count: number;
   lastUpdated?: Date | undefined;
+  /** Period the count belongs to, e.g. "2026", "2026-03", "2026-W11"; empty = all-time */
+  period: string;
 }
 
 export interface SynchronizedActivity {
@@ -200,6 +253,15 @@ export interface SynchronizedActivity {
   syncedAt?: Date | undefined;
   pipelineId: string;
   pipelineExecutionId: string;
+  /** Source activity ID, for propagating source updates and deletes */
+  externalId: string;
+  /**
+   * The source activity's own metadata that title, description and type were enriched from.
+   * Unset on activities synced before it was recorded.
+   */
+  sourceTitle?: string | undefined;
+  sourceDescription?: string | undefined;
+  sourceType: ActivityType;
 }
 
 export interface SynchronizedActivity_DestinationsEntry {
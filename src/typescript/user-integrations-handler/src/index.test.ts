@@ -1,4 +1,5 @@
 import { handler } from './index';
+import { setSecret } from '@fitglue/shared';
 
 // Mock shared dependencies
 jest.mock('@fitglue/shared', () => {
@@ -6,6 +7,7 @@ jest.mock('@fitglue/shared', () => {
   return {
     ...original,
     getSecret: jest.fn().mockResolvedValue('mock-client-id'),
+    setSecret: jest.fn().mockResolvedValue(undefined),
     generateOAuthState: jest.fn().mockResolvedValue('mock-state-token'),
   };
 });
@@ -160,4 +162,52 @@ describe('user-integrations-handler', () => {
       expect(res.status).toHaveBeenCalledWith(200);
     });
   });
+
+  describe('PUT /webhook', () => {
+    beforeEach(() => {
+      req.method = 'PUT';
+      req.path = '/webhook';
+      req.body = { url: 'https://hooks.example.com/fitglue', includeFitFile: true };
+      mockUserService.get.mockResolvedValue({ userId: 'user-1', integrations: {} });
+    });
+
+    it('generates and returns the signing secret when enabling', async () => {
+      await handler(req, res, ctx);
+      expect(res.status).toHaveBeenCalledWith(200);
+      const { signingSecret } = res.json.mock.calls[0][0];
+      expect(signingSecret).toMatch(/^[0-9a-f]{64}$/);
+      expect(setSecret).toHaveBeenCalledWith('fitglue-server-dev', 'webhook-signing-secret-user-1', signingSecret);
+      expect(mockUserStore.setIntegration).toHaveBeenCalledWith(
+        'user-1',
+        'webhook',
+        expect.objectContaining({ enabled: true, url: 'https://hooks.example.com/fitglue', includeFitFile: true })
+      );
+    });
+
+    it('keeps the secret when updating an enabled webhook', async () => {
+      mockUserService.get.mockResolvedValue({
+        userId: 'user-1',
+        integrations: { webhook: { enabled: true, url: 'https://old.example.com', includeFitFile: false } }
+      });
+      await handler(req, res, ctx);
+      expect(res.status).toHaveBeenCalledWith(200);
+      expect(res.json.mock.calls[0][0].signingSecret).toBeUndefined();
+      expect(setSecret).not.toHaveBeenCalled();
+      expect(mockUserStore.setIntegration).toHaveBeenCalled();
+    });
+
+    it.each([
+      'http://hooks.example.com/fitglue',
+      'https://127.0.0.1/hook',
+      'https://169.254.169.254/computeMetadata/v1',
+      'https://localhost:8443',
+      'not a url',
+    ])('rejects %s', async (url) => {
+      req.body = { url };
+      await handler(req, res, ctx);
+      expect(res.status).toHaveBeenCalledWith(400);
+      expect(setSecret).not.toHaveBeenCalled();
+      expect(mockUserStore.setIntegration).not.toHaveBeenCalled();
+    });
+  });
 });
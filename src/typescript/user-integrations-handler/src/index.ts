@@ -1,5 +1,6 @@
-import { createCloudFunction, FrameworkContext, FirebaseAuthStrategy, generateOAuthState, getSecret, canAddConnection, countActiveConnections } from '@fitglue/shared';
+import { createCloudFunction, FrameworkContext, FirebaseAuthStrategy, generateOAuthState, getSecret, setSecret, canAddConnection, countActiveConnections, validateWebhookUrl, WEBHOOK_SIGNING_SECRET_PREFIX } from '@fitglue/shared';
 import { Request, Response } from 'express';
+import crypto from 'crypto';
 
 
 export const handler = async (req: Request, res: Response, ctx: FrameworkContext) => {
@@ -42,6 +43,11 @@ export const handler = async (req: Request, res: Response, ctx: FrameworkContext
       return await handleDisconnect(userId, provider, res, ctx);
     }
 
+    // PUT /users/me/integrations/webhook - Configure webhook endpoint
+    if (req.method === 'PUT' && pathParts[0] === 'webhook') {
+      return await handleConfigureWebhook(userId, req.body, res, ctx);
+    }
+
     // PUT /users/me/integrations/{provider} - Configure API key integration
     if (req.method === 'PUT' && pathParts.length >= 1) {
       const provider = pathParts[0];
@@ -91,6 +97,13 @@ async function handleListIntegrations(userId: string, res: Response, ctx: Framew
     };
   }
 
+  if (integrations.webhook) {
+    summary.webhook = {
+      connected: !!integrations.webhook.enabled,
+      lastUsedAt: integrations.webhook.lastUsedAt
+    };
+  }
+
   res.status(200).json(summary);
 }
 
@@ -158,7 +171,7 @@ async function handleDisconnect(userId: string, provider: string, res: Response,
   const { logger } = ctx;
 
   // Validate provider
-  if (!['strava', 'fitbit', 'hevy'].includes(provider)) {
+  if (!['strava', 'fitbit', 'hevy', 'webhook'].includes(provider)) {
     res.status(400).json({ error: `Invalid provider: ${provider}` });
     return;
   }
@@ -205,6 +218,15 @@ async function handleDisconnect(userId: string, provider: string, res: Response,
         createdAt: current?.createdAt,
         lastUsedAt: current?.lastUsedAt,
       });
+    } else if (provider === 'webhook') {
+      const current = integrations.webhook;
+      await ctx.stores.users.setIntegration(userId, 'webhook', {
+        enabled: false,
+        url: current?.url || '',
+        includeFitFile: !!current?.includeFitFile,
+        createdAt: current?.createdAt,
+        lastUsedAt: current?.lastUsedAt,
+      });
     }
 
     logger.info('Disconnected integration', { userId, provider });
@@ -282,6 +304,63 @@ async function handleConfigure(
   }
 }
 
+async function handleConfigureWebhook(
+  userId: string,
+  body: { url?: string; includeFitFile?: boolean },
+  res: Response,
+  ctx: FrameworkContext
+) {
+  const { logger } = ctx;
+
+  const url = body?.url?.trim();
+  if (!url) {
+    res.status(400).json({ error: 'URL is required' });
+    return;
+  }
+  const urlError = validateWebhookUrl(url);
+  if (urlError) {
+    res.status(400).json({ error: `URL ${urlError}` });
+    return;
+  }
+
+  const user = await ctx.services.user.get(userId);
+  if (!user) {
+    res.status(404).json({ error: 'User not found' });
+    return;
+  }
+  const current = user.integrations?.webhook;
+
+  try {
+    // Enabling the integration generates a new signing secret. It is stored before the
+    // integration is enabled, so deliveries never run without one, and only returned here.
+    let signingSecret: string | undefined;
+    if (!current?.enabled) {
+      signingSecret = crypto.randomBytes(32).toString('hex');
+      const projectId = process.env.GOOGLE_CLOUD_PROJECT || '';
+      await setSecret(projectId, `${WEBHOOK_SIGNING_SECRET_PREFIX}${userId}`, signingSecret);
+    }
+
+    await ctx.stores.users.setIntegration(userId, 'webhook', {
+      enabled: true,
+      url,
+      includeFitFile: !!body.includeFitFile,
+      createdAt: current?.createdAt || new Date(),
+      lastUsedAt: current?.lastUsedAt,
+    });
+
+    logger.info('Configured webhook integration', { userId, newSecret: !!signingSecret });
+    res.status(200).json({
+      message: 'Webhook connected successfully',
+      // Shown once; disconnect and reconnect the webhook to rotate it
+      ...(signingSecret ? { signingSecret } : {})
+    });
+
+  } catch (err) {
+    logger.error('Failed to configure webhook integration', { error: err });
+    res.status(500).json({ error: 'Failed to configure integration' });
+  }
+}
+
 async function validateHevyApiKey(apiKey: string): Promise<boolean> {
   try {
     // Make a simple API call to validate the key
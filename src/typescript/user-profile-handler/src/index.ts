@@ -116,6 +116,7 @@ export const handler = async (req: Request, res: Response, ctx: FrameworkContext
         trialEndsAt: user.trialEndsAt?.toISOString(),
         isAdmin: user.isAdmin || false,
         syncCountThisMonth: user.syncCountThisMonth || 0,
+        timezone: user.timezone || '',
         integrations: getIntegrationsSummary(user),
         pipelines: (user.pipelines || []).map(mapPipelineToResponse)
       };
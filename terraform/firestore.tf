@@ -118,12 +118,24 @@ resource "google_firestore_index" "executions_pipeline_timestamp_asc" {
   }
 }
 
-# Loop Prevention Indexes - check if external ID exists as destination
-# Note: These are collection group indexes on subcollection 'activities'
-# under users/{userId}/activities
-
-
-
+# Source change lookups - find the synchronized copies of a source activity
+# (users/{userId}/activities where source == X and external_id == Y)
+resource "google_firestore_index" "activities_source_external_id" {
+  project    = var.project_id
+  database   = google_firestore_database.database.name
+  collection = "activities"
 
+  fields {
+    field_path = "source"
+    order      = "ASCENDING"
+  }
 
+  fields {
+    field_path = "external_id"
+    order      = "ASCENDING"
+  }
+}
 
+# Loop Prevention Indexes - check if external ID exists as destination
+# Note: These are collection group indexes on subcollection 'activities'
+# under users/{userId}/activities
@@ -27,6 +27,54 @@ resource "google_storage_bucket_object" "strava_uploader_zip" {
 }
 
 
+# Webhook Uploader uses pre-built zip with correct structure
+resource "google_storage_bucket_object" "webhook_uploader_zip" {
+  name   = "webhook-uploader-${filemd5("/tmp/fitglue-function-zips/webhook-uploader.zip")}.zip"
+  bucket = google_storage_bucket.source_bucket.name
+  source = "/tmp/fitglue-function-zips/webhook-uploader.zip"
+}
+
+
+# intervals.icu Uploader uses pre-built zip with correct structure
+resource "google_storage_bucket_object" "intervals_icu_uploader_zip" {
+  name   = "intervals-icu-uploader-${filemd5("/tmp/fitglue-function-zips/intervals-icu-uploader.zip")}.zip"
+  bucket = google_storage_bucket.source_bucket.name
+  source = "/tmp/fitglue-function-zips/intervals-icu-uploader.zip"
+}
+
+
+# Hevy Uploader uses pre-built zip with correct structure
+resource "google_storage_bucket_object" "hevy_uploader_zip" {
+  name   = "hevy-uploader-${filemd5("/tmp/fitglue-function-zips/hevy-uploader.zip")}.zip"
+  bucket = google_storage_bucket.source_bucket.name
+  source = "/tmp/fitglue-function-zips/hevy-uploader.zip"
+}
+
+
+# Upload Status Poller uses pre-built zip with correct structure
+resource "google_storage_bucket_object" "upload_status_poller_zip" {
+  name   = "upload-status-poller-${filemd5("/tmp/fitglue-function-zips/upload-status-poller.zip")}.zip"
+  bucket = google_storage_bucket.source_bucket.name
+  source = "/tmp/fitglue-function-zips/upload-status-poller.zip"
+}
+
+
+# Strava Handler uses pre-built zip with correct structure
+resource "google_storage_bucket_object" "strava_handler_zip" {
+  name   = "strava-handler-${filemd5("/tmp/fitglue-function-zips/strava-handler.zip")}.zip"
+  bucket = google_storage_bucket.source_bucket.name
+  source = "/tmp/fitglue-function-zips/strava-handler.zip"
+}
+
+
+# Change Propagator uses pre-built zip with correct structure
+resource "google_storage_bucket_object" "change_propagator_zip" {
+  name   = "change-propagator-${filemd5("/tmp/fitglue-function-zips/change-propagator.zip")}.zip"
+  bucket = google_storage_bucket.source_bucket.name
+  source = "/tmp/fitglue-function-zips/change-propagator.zip"
+}
+
+
 # -------------- TypeScript Source Archive --------------
 data "archive_file" "typescript_source_zip" {
   type        = "zip"
@@ -193,6 +241,7 @@ resource "google_cloudfunctions2_function" "strava_uploader" {
     environment_variables = {
       GOOGLE_CLOUD_PROJECT = var.project_id
       GCS_ARTIFACT_BUCKET  = "${var.project_id}-artifacts"
+      ENABLE_PUBLISH       = "true"
       LOG_LEVEL            = var.log_level
     }
     service_account_email = google_service_account.cloud_function_sa.email
@@ -206,6 +255,240 @@ resource "google_cloudfunctions2_function" "strava_uploader" {
   }
 }
 
+# ----------------- Webhook Uploader -----------------
+# Delivers activities to users' own endpoints. Signing secrets are read from
+# webhook-signing-secret-{user_id} at runtime; the user-integrations handler creates them.
+resource "google_cloudfunctions2_function" "webhook_uploader" {
+  name     = "webhook-uploader"
+  location = var.region
+
+  build_config {
+    runtime     = "go125"
+    entry_point = "UploadToWebhook"
+    source {
+      storage_source {
+        bucket = google_storage_bucket.source_bucket.name
+        object = google_storage_bucket_object.webhook_uploader_zip.name
+      }
+    }
+    environment_variables = {}
+  }
+
+  service_config {
+    available_memory = "512Mi"
+    timeout_seconds  = 300
+    environment_variables = {
+      GOOGLE_CLOUD_PROJECT = var.project_id
+      GCS_ARTIFACT_BUCKET  = "${var.project_id}-artifacts"
+      ENABLE_PUBLISH       = "true"
+      LOG_LEVEL            = var.log_level
+    }
+    service_account_email = google_service_account.cloud_function_sa.email
+  }
+
+  event_trigger {
+    trigger_region = var.region
+    event_type     = "google.cloud.pubsub.topic.v1.messagePublished"
+    pubsub_topic   = google_pubsub_topic.job_upload_webhook.id
+    retry_policy   = var.retry_policy
+  }
+}
+
+# ----------------- intervals.icu Uploader -----------------
+resource "google_cloudfunctions2_function" "intervals_icu_uploader" {
+  name     = "intervals-icu-uploader"
+  location = var.region
+
+  build_config {
+    runtime     = "go125"
+    entry_point = "UploadToIntervalsIcu"
+    source {
+      storage_source {
+        bucket = google_storage_bucket.source_bucket.name
+        object = google_storage_bucket_object.intervals_icu_uploader_zip.name
+      }
+    }
+    environment_variables = {}
+  }
+
+  service_config {
+    available_memory = "512Mi"
+    timeout_seconds  = 300
+    environment_variables = {
+      GOOGLE_CLOUD_PROJECT = var.project_id
+      GCS_ARTIFACT_BUCKET  = "${var.project_id}-artifacts"
+      ENABLE_PUBLISH       = "true"
+      LOG_LEVEL            = var.log_level
+    }
+    service_account_email = google_service_account.cloud_function_sa.email
+  }
+
+  event_trigger {
+    trigger_region = var.region
+    event_type     = "google.cloud.pubsub.topic.v1.messagePublished"
+    pubsub_topic   = google_pubsub_topic.job_upload_intervals_icu.id
+    retry_policy   = var.retry_policy
+  }
+}
+
+# ----------------- Hevy Uploader -----------------
+resource "google_cloudfunctions2_function" "hevy_uploader" {
+  name     = "hevy-uploader"
+  location = var.region
+
+  build_config {
+    runtime     = "go125"
+    entry_point = "UploadToHevy"
+    source {
+      storage_source {
+        bucket = google_storage_bucket.source_bucket.name
+        object = google_storage_bucket_object.hevy_uploader_zip.name
+      }
+    }
+    environment_variables = {}
+  }
+
+  service_config {
+    available_memory = "512Mi"
+    timeout_seconds  = 300
+    environment_variables = {
+      GOOGLE_CLOUD_PROJECT = var.project_id
+      GCS_ARTIFACT_BUCKET  = "${var.project_id}-artifacts"
+      ENABLE_PUBLISH       = "true"
+      LOG_LEVEL            = var.log_level
+    }
+    service_account_email = google_service_account.cloud_function_sa.email
+  }
+
+  event_trigger {
+    trigger_region = var.region
+    event_type     = "google.cloud.pubsub.topic.v1.messagePublished"
+    pubsub_topic   = google_pubsub_topic.job_upload_hevy.id
+    retry_policy   = var.retry_policy
+  }
+}
+
+# ----------------- Upload Status Poller -----------------
+# HTTP-triggered, fed by the sub-upload-status-check push subscription (see pubsub.tf).
+# Resolves uploads that were still processing when the uploader finished.
+resource "google_cloudfunctions2_function" "upload_status_poller" {
+  name     = "upload-status-poller"
+  location = var.region
+
+  build_config {
+    runtime     = "go125"
+    entry_point = "CheckUploadStatusHTTP"
+    source {
+      storage_source {
+        bucket = google_storage_bucket.source_bucket.name
+        object = google_storage_bucket_object.upload_status_poller_zip.name
+      }
+    }
+    environment_variables = {}
+  }
+
+  service_config {
+    available_memory = "256Mi"
+    timeout_seconds  = 60
+    environment_variables = {
+      GOOGLE_CLOUD_PROJECT = var.project_id
+      LOG_LEVEL            = var.log_level
+    }
+    service_account_email = google_service_account.cloud_function_sa.email
+  }
+
+  # No event_trigger - this is an HTTP-triggered function
+}
+
+resource "google_cloud_run_service_iam_member" "upload_status_poller_invoker" {
+  project  = google_cloudfunctions2_function.upload_status_poller.project
+  location = google_cloudfunctions2_function.upload_status_poller.location
+  service  = google_cloudfunctions2_function.upload_status_poller.name
+  role     = "roles/run.invoker"
+  member   = "serviceAccount:${google_service_account.cloud_function_sa.email}"
+}
+
+# ----------------- Change Propagator -----------------
+# Applies source activity updates and deletions to destination copies,
+# according to each user's source_change_policy.
+resource "google_cloudfunctions2_function" "change_propagator" {
+  name     = "change-propagator"
+  location = var.region
+
+  build_config {
+    runtime     = "go125"
+    entry_point = "PropagateSourceChange"
+    source {
+      storage_source {
+        bucket = google_storage_bucket.source_bucket.name
+        object = google_storage_bucket_object.change_propagator_zip.name
+      }
+    }
+    environment_variables = {}
+  }
+
+  service_config {
+    available_memory = "256Mi"
+    timeout_seconds  = 300
+    environment_variables = {
+      GOOGLE_CLOUD_PROJECT = var.project_id
+      GCS_ARTIFACT_BUCKET  = "${var.project_id}-artifacts"
+      ENABLE_PUBLISH       = "true"
+      LOG_LEVEL            = var.log_level
+    }
+    service_account_email = google_service_account.cloud_function_sa.email
+  }
+
+  event_trigger {
+    trigger_region = var.region
+    event_type     = "google.cloud.pubsub.topic.v1.messagePublished"
+    pubsub_topic   = google_pubsub_topic.source_activity_change.id
+    retry_policy   = var.retry_policy
+  }
+}
+
+# ----------------- Strava Handler -----------------
+# HTTP-triggered by the Strava webhook subscription. Validates the subscription (GET) and
+# ingests created activities into Strava-sourced pipelines (POST).
+# The verify token and subscription ID are read from the strava-webhook-verify-token and
+# strava-webhook-subscription-id secrets at runtime.
+resource "google_cloudfunctions2_function" "strava_handler" {
+  name        = "strava-handler"
+  location    = var.region
+  description = "Ingests Strava webhooks and activities"
+
+  build_config {
+    runtime     = "go125"
+    entry_point = "StravaWebhook"
+    source {
+      storage_source {
+        bucket = google_storage_bucket.source_bucket.name
+        object = google_storage_bucket_object.strava_handler_zip.name
+      }
+    }
+    environment_variables = {}
+  }
+
+  service_config {
+    available_memory = "256Mi"
+    timeout_seconds  = 60
+    environment_variables = {
+      GOOGLE_CLOUD_PROJECT = var.project_id
+      ENABLE_PUBLISH       = "true"
+      LOG_LEVEL            = var.log_level
+    }
+    service_account_email = google_service_account.cloud_function_sa.email
+  }
+}
+
+resource "google_cloud_run_service_iam_member" "strava_handler_invoker" {
+  project  = google_cloudfunctions2_function.strava_handler.project
+  location = google_cloudfunctions2_function.strava_handler.location
+  service  = google_cloudfunctions2_function.strava_handler.name
+  role     = "roles/run.invoker"
+  member   = "allUsers"
+}
+
 # ----------------- Mock Uploader (Dev Only) -----------------
 resource "google_storage_bucket_object" "mock_uploader_zip" {
   count  = var.environment == "dev" ? 1 : 0
@@ -326,6 +609,65 @@ resource "google_cloud_run_service_iam_member" "hevy_handler_invoker" {
   member   = "allUsers"
 }
 
+# ----------------- Hevy Change Poller -----------------
+# HTTP-triggered by the hevy-change-poll scheduler job. Hevy's webhook only reports new
+# workouts, so edits and deletions are read from GET /v1/workouts/events and published
+# to topic-source-activity-change.
+resource "google_cloudfunctions2_function" "hevy_change_poller" {
+  name        = "hevy-change-poller"
+  location    = var.region
+  description = "Polls Hevy for workout edits and deletions"
+
+  build_config {
+    runtime     = "nodejs20"
+    entry_point = "hevyChangePoller"
+    source {
+      storage_source {
+        bucket = google_storage_bucket.source_bucket.name
+        object = google_storage_bucket_object.typescript_source_zip.name
+      }
+    }
+    environment_variables = {}
+  }
+
+  service_config {
+    available_memory = "256Mi"
+    timeout_seconds  = 540
+    environment_variables = {
+      LOG_LEVEL            = var.log_level
+      GOOGLE_CLOUD_PROJECT = var.project_id
+    }
+    service_account_email = google_service_account.cloud_function_sa.email
+  }
+}
+
+resource "google_cloud_run_service_iam_member" "hevy_change_poller_invoker" {
+  project  = google_cloudfunctions2_function.hevy_change_poller.project
+  location = google_cloudfunctions2_function.hevy_change_poller.location
+  service  = google_cloudfunctions2_function.hevy_change_poller.name
+  role     = "roles/run.invoker"
+  member   = "serviceAccount:${google_service_account.cloud_function_sa.email}"
+}
+
+resource "google_cloud_scheduler_job" "hevy_change_poll" {
+  name        = "hevy-change-poll"
+  region      = var.region
+  description = "Triggers the Hevy change poller"
+  schedule    = "*/15 * * * *"
+  time_zone   = "Etc/UTC"
+
+  attempt_deadline = "540s"
+
+  http_target {
+    http_method = "POST"
+    uri         = google_cloudfunctions2_function.hevy_change_poller.service_config[0].uri
+
+    oidc_token {
+      service_account_email = google_service_account.cloud_function_sa.email
+    }
+  }
+}
+
 # ----------------- Fitbit Handler -----------------
 resource "google_cloudfunctions2_function" "fitbit_handler" {
   name        = "fitbit-handler"
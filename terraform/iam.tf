@@ -16,6 +16,31 @@ resource "google_project_iam_member" "cloud_function_sa_secret_accessor" {
   member  = "serviceAccount:${google_service_account.cloud_function_sa.email}"
 }
 
+# The user-integrations handler creates each user's webhook-signing-secret-{user_id} and adds
+# its versions; nothing else. Secret creation is checked against the project, so that is
+# allowed too; versions can only be added to the webhook signing secrets.
+resource "google_project_iam_custom_role" "webhook_secret_writer" {
+  project     = var.project_id
+  role_id     = "webhookSecretWriter"
+  title       = "Webhook Secret Writer"
+  description = "Creates webhook signing secrets and adds their versions"
+  permissions = [
+    "secretmanager.secrets.create",
+    "secretmanager.versions.add",
+  ]
+}
+
+resource "google_project_iam_member" "cloud_function_sa_webhook_secret_writer" {
+  project = var.project_id
+  role    = google_project_iam_custom_role.webhook_secret_writer.name
+  member  = "serviceAccount:${google_service_account.cloud_function_sa.email}"
+
+  condition {
+    title      = "webhook-signing-secrets"
+    expression = "resource.type == \"cloudresourcemanager.googleapis.com/Project\" || resource.name.startsWith(\"projects/${data.google_project.project.number}/secrets/webhook-signing-secret-\")"
+  }
+}
+
 resource "google_project_iam_member" "cloud_function_sa_storage_admin" {
   project = var.project_id
   role    = "roles/storage.objectAdmin"
@@ -22,11 +22,45 @@ resource "google_pubsub_topic" "job_upload_strava" {
   message_retention_duration = "3600s"
 }
 
+resource "google_pubsub_topic" "job_upload_webhook" {
+  name    = "topic-job-upload-webhook"
+  project = var.project_id
+
+  # Enable message retention for replay (1 hour)
+  message_retention_duration = "3600s"
+}
+
+resource "google_pubsub_topic" "job_upload_intervals_icu" {
+  name    = "topic-job-upload-intervals-icu"
+  project = var.project_id
+
+  # Enable message retention for replay (1 hour)
+  message_retention_duration = "3600s"
+}
+
+resource "google_pubsub_topic" "job_upload_hevy" {
+  name    = "topic-job-upload-hevy"
+  project = var.project_id
+
+  # Enable message retention for replay (1 hour)
+  message_retention_duration = "3600s"
+}
+
 resource "google_pubsub_topic" "enrichment_lag" {
   name    = "topic-enrichment-lag"
   project = var.project_id
 }
 
+resource "google_pubsub_topic" "upload_status_check" {
+  name    = "topic-upload-status-check"
+  project = var.project_id
+}
+
+resource "google_pubsub_topic" "source_activity_change" {
+  name    = "topic-source-activity-change"
+  project = var.project_id
+}
+
 # Mock topic for testing (dev only)
 resource "google_pubsub_topic" "job_upload_mock" {
   count   = var.environment == "dev" ? 1 : 0
@@ -62,3 +96,29 @@ resource "google_pubsub_subscription" "enrichment_lag_sub" {
     }
   }
 }
+
+resource "google_pubsub_subscription" "upload_status_check_sub" {
+  name    = "sub-upload-status-check"
+  topic   = google_pubsub_topic.upload_status_check.name
+  project = var.project_id
+
+  # Slightly longer than the poller's longest deadline, Strava's MatchPendingDeadline (24h),
+  # after which it marks uploads FAILED
+  message_retention_duration = "90000s"
+
+  retry_policy {
+    # Strava usually finishes processing within a minute or two
+    minimum_backoff = "30s"
+    maximum_backoff = "600s"
+  }
+
+  # Push subscription for the same reason as sub-enrichment-lag: the poller returns
+  # HTTP 500 while an upload is still processing, and Pub/Sub redelivers with backoff.
+  push_config {
+    push_endpoint = google_cloudfunctions2_function.upload_status_poller.service_config[0].uri
+
+    oidc_token {
+      service_account_email = google_service_account.cloud_function_sa.email
+    }
+  }
+}
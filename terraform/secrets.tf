@@ -32,6 +32,21 @@ resource "google_secret_manager_secret" "strava_client_secret" {
 }
 
 # Fitbit OAuth Credentials
+resource "google_secret_manager_secret" "strava_webhook_verify_token" {
+  secret_id = "strava-webhook-verify-token"
+  replication {
+    auto {}
+  }
+}
+
+# ID of the Strava webhook subscription; events for other subscriptions are rejected
+resource "google_secret_manager_secret" "strava_webhook_subscription_id" {
+  secret_id = "strava-webhook-subscription-id"
+  replication {
+    auto {}
+  }
+}
+
 resource "google_secret_manager_secret" "fitbit_client_id" {
   secret_id = "fitbit-client-id"
   replication {